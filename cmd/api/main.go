@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,14 +13,23 @@ import (
 	"github.com/redukasquad/be-reduka/database/migrations"
 	"github.com/redukasquad/be-reduka/middleware"
 	"github.com/redukasquad/be-reduka/modules/auth"
+	"github.com/redukasquad/be-reduka/modules/bundles"
+	"github.com/redukasquad/be-reduka/modules/calendar"
 	"github.com/redukasquad/be-reduka/modules/classes"
 	"github.com/redukasquad/be-reduka/modules/courses"
+	"github.com/redukasquad/be-reduka/modules/groups"
 	"github.com/redukasquad/be-reduka/modules/health"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"github.com/redukasquad/be-reduka/modules/payments"
 	"github.com/redukasquad/be-reduka/modules/programs"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
 	"github.com/redukasquad/be-reduka/modules/tryouts"
+	"github.com/redukasquad/be-reduka/modules/tryouts/registrations"
 	"github.com/redukasquad/be-reduka/modules/universities"
 	"github.com/redukasquad/be-reduka/modules/uploads"
 	"github.com/redukasquad/be-reduka/modules/users"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -76,8 +86,23 @@ func main() {
 		tryouts.TryOutsRouter(v1, middleware.RequireAuth(), middleware.RequireAdminOrTutor())
 		universities.UniversityRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin())
 		uploads.UploadRouter(v1, middleware.RequireAuth(), middleware.RequireAdminOrTutorOrUser())
+		payments.PaymentRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin())
+		promos.PromoRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin())
+		bundles.BundleRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin())
+		ledger.LedgerRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin())
+		receipts.ReceiptRouter(v1, middleware.RequireAuth())
+		groups.GroupRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin(), middleware.RequireCoordinator())
+		waitlists.WaitlistRouter(v1, middleware.RequireAuth(), middleware.RequireAdmin(), middleware.RequireAdminOrTutor())
+		calendar.CalendarRouter(v1, middleware.RequireAuth(), middleware.RequireAdminOrTutor())
 	}
 
+	// Expire unpaid try out registrations and send payment reminders
+	deadlineInterval := 15 * time.Minute
+	if minutes, err := strconv.Atoi(os.Getenv("PAYMENT_DEADLINE_CHECK_MINUTES")); err == nil && minutes > 0 {
+		deadlineInterval = time.Duration(minutes) * time.Minute
+	}
+	registrations.StartPaymentDeadlineWorker(deadlineInterval)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = os.Getenv("GOLANG_PORT")
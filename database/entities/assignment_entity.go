@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Submission statuses
+const (
+	SubmissionSubmitted = "submitted"
+	SubmissionGraded    = "graded"
+)
+
+// Assignment is homework given in a class, optionally tied to one of its lessons
+type Assignment struct {
+	gorm.Model
+
+	ClassID         uint       `json:"classId" gorm:"index;not null"`
+	LessonID        *uint      `json:"lessonId" gorm:"index"`
+	CreatedByUserID uint       `json:"createdByUserId"`
+	Title           string     `json:"title" gorm:"size:255;not null"`
+	Instructions    string     `json:"instructions" gorm:"type:text"`
+	DueAt           *time.Time `json:"dueAt"`
+	AllowLate       bool       `json:"allowLate" gorm:"default:false"`
+	MaxScore        float64    `json:"maxScore" gorm:"default:100"`
+
+	// Relations
+	Class       Class                  `json:"class,omitempty" gorm:"foreignKey:ClassID"`
+	Lesson      *Lesson                `json:"lesson,omitempty" gorm:"foreignKey:LessonID"`
+	Attachments []AssignmentAttachment `json:"attachments,omitempty" gorm:"foreignKey:AssignmentID"`
+}
+
+// AssignmentAttachment is a file from the uploads module handed out with an assignment
+type AssignmentAttachment struct {
+	gorm.Model
+
+	AssignmentID uint   `json:"assignmentId" gorm:"index;not null"`
+	Name         string `json:"name" gorm:"size:255"`
+	URL          string `json:"url" gorm:"not null"`
+}
+
+// AssignmentSubmission is a student's answer to an assignment; resubmitting replaces it until it is graded
+type AssignmentSubmission struct {
+	gorm.Model
+
+	AssignmentID   uint       `json:"assignmentId" gorm:"uniqueIndex:idx_submission_assignment_user;not null"`
+	UserID         uint       `json:"userId" gorm:"uniqueIndex:idx_submission_assignment_user;not null"`
+	RegistrationID uint       `json:"registrationId" gorm:"index;not null"`
+	Text           string     `json:"text" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"size:15;not null"`
+	SubmittedAt    time.Time  `json:"submittedAt"`
+	IsLate         bool       `json:"isLate" gorm:"default:false"`
+	Score          *float64   `json:"score"`
+	Feedback       string     `json:"feedback" gorm:"type:text"`
+	GradedByUserID *uint      `json:"gradedByUserId"`
+	GradedAt       *time.Time `json:"gradedAt"`
+
+	// Relations
+	Assignment Assignment       `json:"assignment,omitempty" gorm:"foreignKey:AssignmentID"`
+	User       User             `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Files      []SubmissionFile `json:"files,omitempty" gorm:"foreignKey:SubmissionID"`
+}
+
+// SubmissionFile is an uploaded file attached to a submission
+type SubmissionFile struct {
+	gorm.Model
+
+	SubmissionID uint   `json:"submissionId" gorm:"index;not null"`
+	Name         string `json:"name" gorm:"size:255"`
+	URL          string `json:"url" gorm:"not null"`
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BundleType represents how a bundle decides which Try Outs it unlocks.
+type BundleType string
+
+const (
+	// BundleTypeBundle unlocks a fixed list of Try Outs (e.g. 5 try outs for the price of 4)
+	BundleTypeBundle BundleType = "bundle"
+	// BundleTypePass unlocks every published Try Out whose registration period overlaps the pass period
+	BundleTypePass BundleType = "pass"
+)
+
+// Bundle is a package of Try Outs and/or Courses sold with a single payment.
+// Access is granted as TryOutRegistration / CourseRegistration records once a purchase is approved,
+// and again whenever a covered Try Out is published later.
+type Bundle struct {
+	gorm.Model
+
+	Name        string     `json:"name" gorm:"size:100;not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	ImageURL    string     `json:"imageUrl" gorm:"size:500"`
+	Type        BundleType `json:"type" gorm:"size:20;not null"`
+
+	// Pricing
+	Price        float64 `json:"price" gorm:"type:decimal(12,2)"`
+	QrisImageURL string  `json:"qrisImageUrl" gorm:"size:500"`
+	PaymentLink  string  `json:"paymentLink" gorm:"size:500"`
+
+	// Sale period
+	SaleStart time.Time `json:"saleStart" gorm:"not null"`
+	SaleEnd   time.Time `json:"saleEnd" gorm:"not null"`
+
+	// Pass period (only for BundleTypePass)
+	PassStart *time.Time `json:"passStart"`
+	PassEnd   *time.Time `json:"passEnd"`
+
+	IsPublished     bool `json:"isPublished" gorm:"default:false"`
+	CreatedByUserID uint `json:"createdByUserId"`
+
+	// Relations
+	TryOuts   []TryOut         `json:"tryOuts,omitempty" gorm:"many2many:bundle_try_outs;"`
+	Courses   []Course         `json:"courses,omitempty" gorm:"many2many:bundle_courses;"`
+	Purchases []BundlePurchase `json:"purchases,omitempty" gorm:"foreignKey:BundleID"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BundlePurchase is a user's purchase of a Bundle, approved the same way as Try Out payments.
+type BundlePurchase struct {
+	gorm.Model
+
+	UserID   uint `json:"userId" gorm:"uniqueIndex:idx_user_bundle;not null"`
+	BundleID uint `json:"bundleId" gorm:"uniqueIndex:idx_user_bundle;not null"`
+
+	Amount          float64       `json:"amount" gorm:"type:decimal(12,2)"`
+	PaymentProofURL string        `json:"paymentProofUrl" gorm:"size:500"`
+	PaymentStatus   PaymentStatus `json:"paymentStatus" gorm:"size:20;default:'pending'"`
+	RejectionReason string        `json:"rejectionReason" gorm:"type:text"`
+
+	ApprovedByUserID *uint      `json:"approvedByUserId"`
+	ApprovedAt       *time.Time `json:"approvedAt"`
+	PurchasedAt      time.Time  `json:"purchasedAt" gorm:"autoCreateTime"`
+
+	// Relations
+	User       User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Bundle     Bundle `json:"bundle,omitempty" gorm:"foreignKey:BundleID"`
+	ApprovedBy *User  `json:"approvedBy,omitempty" gorm:"foreignKey:ApprovedByUserID"`
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "gorm.io/gorm"
+
+// CalendarFeedToken is the secret in a user's personal iCalendar subscription URL.
+// Calendar apps cannot send auth headers, so the token alone identifies the user; rotating it revokes the old URL.
+type CalendarFeedToken struct {
+	gorm.Model
+
+	UserID uint   `json:"userId" gorm:"uniqueIndex;not null"`
+	Token  string `json:"-" gorm:"size:64;uniqueIndex;not null"`
+
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CancellationStatus represents the review state of a cancellation request.
+type CancellationStatus string
+
+const (
+	CancellationStatusPending  CancellationStatus = "pending"
+	CancellationStatusApproved CancellationStatus = "approved"
+	CancellationStatusRejected CancellationStatus = "rejected"
+)
+
+// CancellationRequest is a student's request to cancel a paid Try Out registration.
+// Approving it records the refund in the ledger and cancels the registration.
+type CancellationRequest struct {
+	gorm.Model
+
+	RegistrationID uint               `json:"registrationId" gorm:"index;not null"`
+	UserID         uint               `json:"userId" gorm:"index;not null"`
+	Reason         string             `json:"reason" gorm:"type:text"`
+	Status         CancellationStatus `json:"status" gorm:"size:20;default:'pending';index"`
+
+	RefundAmount     float64    `json:"refundAmount" gorm:"type:decimal(12,2);default:0"`
+	AdminNote        string     `json:"adminNote" gorm:"type:text"`
+	ReviewedByUserID *uint      `json:"reviewedByUserId"`
+	ReviewedAt       *time.Time `json:"reviewedAt"`
+	RequestedAt      time.Time  `json:"requestedAt" gorm:"autoCreateTime"`
+
+	// Relations
+	Registration TryOutRegistration `json:"registration,omitempty" gorm:"foreignKey:RegistrationID"`
+	User         User               `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	ReviewedBy   *User              `json:"reviewedBy,omitempty" gorm:"foreignKey:ReviewedByUserID"`
+}
@@ -9,16 +9,34 @@ import (
 type Lesson struct {
 	gorm.Model
 
-	ClassID         uint `json:"classId" form:"classId" binding:"required"`
+	ClassID         uint `json:"classId" form:"classId" binding:"required" gorm:"uniqueIndex:idx_lesson_class_order,where:deleted_at IS NULL"`
 	CreatedByUserID uint `json:"createdByUserId" form:"createdByUserId" binding:"required"`
 
 	Title       string `json:"title" form:"title" binding:"required"`
 	Description string `json:"description" form:"description"`
-	LessonOrder int    `json:"lessonOrder" form:"lessonOrder" binding:"required,min=1"`
+	LessonOrder int    `json:"lessonOrder" form:"lessonOrder" binding:"required,min=1" gorm:"uniqueIndex:idx_lesson_class_order,where:deleted_at IS NULL"`
 
 	StartTime *time.Time `json:"startTime,omitempty" form:"startTime"`
 	EndTime   *time.Time `json:"endTime,omitempty" form:"endTime"`
 
+	// CompletionRule decides how the lesson gets completed: manual (default), resources or quiz
+	CompletionRule string `json:"completionRule" gorm:"size:20"`
+
+	// Publishing state (draft, published or archived); drafts stay out of public listings
+	Status    string     `json:"status" gorm:"size:20;default:published;index"`
+	PublishAt *time.Time `json:"publishAt,omitempty"` // scheduled publish time of a draft
+
+	// Set on lessons generated from a recurring class schedule. OccurrenceDate (YYYY-MM-DD, in the
+	// schedule's timezone) is the slot the lesson fills; rescheduled lessons are left alone when
+	// the schedule regenerates its lessons.
+	ScheduleID     *uint  `json:"scheduleId,omitempty" gorm:"index"`
+	OccurrenceDate string `json:"occurrenceDate,omitempty" gorm:"size:10"`
+	IsRescheduled  bool   `json:"isRescheduled" gorm:"default:false"`
+
+	// Short-lived code shown during class for student self check-in
+	CheckInCode      string     `json:"-" gorm:"size:12"`
+	CheckInExpiresAt *time.Time `json:"-"`
+
 	Class     Class            `json:"class,omitempty" gorm:"foreignKey:ClassID"`
 	Creator   User             `json:"creator,omitempty" gorm:"foreignKey:CreatedByUserID"`
 	Resources []LessonResource `json:"resources,omitempty" gorm:"foreignKey:LessonID"`
@@ -11,5 +11,8 @@ type LessonResource struct {
 	Title string `json:"title"`
 	URL   string `json:"url" binding:"required"`
 
+	// MinViewPercent is how much of a video or recording must be watched before it counts as done
+	MinViewPercent int `json:"minViewPercent" gorm:"default:0"`
+
 	Lesson Lesson `json:"lesson,omitempty" gorm:"foreignKey:LessonID"`
 }
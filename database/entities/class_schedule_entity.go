@@ -0,0 +1,32 @@
+package entities
+
+import "gorm.io/gorm"
+
+// ClassSchedule is a weekly recurrence rule that generates the lessons of a class.
+// Dates are YYYY-MM-DD and StartTime is HH:MM, both read in Timezone.
+type ClassSchedule struct {
+	gorm.Model
+
+	ClassID         uint   `json:"classId" gorm:"index;not null"`
+	LessonTitle     string `json:"lessonTitle" gorm:"size:255;not null"`
+	Weekdays        string `json:"weekdays" gorm:"size:20;not null"` // comma-separated, 0 = Sunday
+	StartTime       string `json:"startTime" gorm:"size:5;not null"`
+	DurationMinutes int    `json:"durationMinutes" gorm:"not null"`
+	Timezone        string `json:"timezone" gorm:"size:64;not null"`
+	StartDate       string `json:"startDate" gorm:"size:10;not null"`
+	EndDate         string `json:"endDate" gorm:"size:10;not null"`
+	CreatedByUserID uint   `json:"createdByUserId"`
+
+	// Relations
+	Class      Class                    `json:"class,omitempty" gorm:"foreignKey:ClassID"`
+	Exclusions []ClassScheduleExclusion `json:"exclusions,omitempty" gorm:"foreignKey:ScheduleID"`
+}
+
+// ClassScheduleExclusion is a date the schedule skips, such as a public holiday
+type ClassScheduleExclusion struct {
+	gorm.Model
+
+	ScheduleID uint   `json:"scheduleId" gorm:"uniqueIndex:idx_schedule_exclusion_date;not null"`
+	Date       string `json:"date" gorm:"size:10;uniqueIndex:idx_schedule_exclusion_date;not null"`
+	Reason     string `json:"reason" gorm:"size:255"`
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Class struct {
 	gorm.Model
@@ -9,6 +13,10 @@ type Class struct {
 	Name        string `json:"name" form:"name" binding:"required"`
 	Description string `json:"description" form:"description"`
 
+	// Publishing state (draft, published or archived); drafts stay out of public listings
+	Status    string     `json:"status" gorm:"size:20;default:published;index"`
+	PublishAt *time.Time `json:"publishAt,omitempty"` // scheduled publish time of a draft
+
 	// relations
 	Course  Course   `json:"course,omitempty"`
 	Lessons []Lesson `json:"lessons,omitempty" gorm:"foreignKey:ClassID"`
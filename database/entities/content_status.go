@@ -0,0 +1,9 @@
+package entities
+
+// Publishing states shared by courses, classes and lessons. A draft with a PublishAt time goes
+// live on its own once that time passes; archived content is withdrawn from public listings.
+const (
+	ContentDraft     = "draft"
+	ContentPublished = "published"
+	ContentArchived  = "archived"
+)
@@ -17,9 +17,17 @@ type Course struct {
 	StartDate         time.Time `json:"startDate" form:"startDate" binding:"required"`
 	EndDate           time.Time `json:"endDate" form:"endDate" binding:"required"`
 	IsFree            bool      `json:"isFree" form:"isFree" gorm:"default:false"`
+	Price             float64   `json:"price" form:"price" gorm:"type:decimal(12,2);default:0"`
 	WhatsappGroupLink string    `json:"whatsappGroupLink" form:"whatsappGroupLink"`
 	Image             string    `json:"image" form:"image" gorm:"type:text"`
 
+	// Capacity caps approved and pending registrations (0 = unlimited); later registrants join the waitlist
+	Capacity int `json:"capacity" form:"capacity"`
+
+	// Publishing state (draft, published or archived); drafts stay out of public listings
+	Status    string     `json:"status" gorm:"size:20;default:published;index"`
+	PublishAt *time.Time `json:"publishAt,omitempty"` // scheduled publish time of a draft
+
 	// relations — Program has many Courses, Course has many Classes
 	Program   Program                `json:"program,omitempty"`
 	Classes   []Class                `json:"classes,omitempty" gorm:"foreignKey:CourseID"`
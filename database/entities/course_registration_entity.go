@@ -1,6 +1,10 @@
 package entities
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type CourseRegistration struct {
 	gorm.Model
@@ -8,7 +12,24 @@ type CourseRegistration struct {
 	UserID   uint `json:"userId" form:"userId" binding:"required"`
 	CourseID uint `json:"courseId" form:"courseId" binding:"required"`
 
-	Status string `json:"status" form:"status" binding:"required,oneof=pending approved rejected" gorm:"type:varchar(10);default:'pending'"`
+	Status string `json:"status" form:"status" binding:"required,oneof=pending approved rejected waitlisted" gorm:"type:varchar(10);default:'pending'"`
+
+	// Pricing snapshot at registration time
+	OriginalPrice  float64 `json:"originalPrice" gorm:"type:decimal(12,2);default:0"`
+	DiscountAmount float64 `json:"discountAmount" gorm:"type:decimal(12,2);default:0"`
+	FinalAmount    float64 `json:"finalAmount" gorm:"type:decimal(12,2);default:0"`
+	PromoCodeID    *uint   `json:"promoCodeId"`
+	PromoCode      string  `json:"promoCode" gorm:"size:50"`
+
+	// Set when access was granted by a bundle purchase
+	BundlePurchaseID *uint `json:"bundlePurchaseId" gorm:"index"`
+
+	// Set when a school coordinator enrolled the student; the group pays once
+	GroupEnrollmentID *uint `json:"groupEnrollmentId" gorm:"index"`
+
+	// Waitlist queue position is by WaitlistedAt; PromotedAt is set when a seat was given
+	WaitlistedAt *time.Time `json:"waitlistedAt" gorm:"index"`
+	PromotedAt   *time.Time `json:"promotedAt"`
 
 	// relations
 	User   User   `json:"user,omitempty"`
@@ -5,13 +5,54 @@ import "gorm.io/gorm"
 type RegistrationQuestion struct {
 	gorm.Model
 
-	CourseID uint `json:"courseId" form:"courseId" binding:"required"`
+	CourseID uint `json:"courseId" form:"courseId" binding:"required" gorm:"uniqueIndex:idx_registration_question_order,where:deleted_at IS NULL"`
 
 	QuestionText  string `json:"questionText" form:"questionText" binding:"required"`
-	QuestionType  string `json:"questionType" form:"questionType" binding:"required,oneof=text select radio file"`
-	QuestionOrder int    `json:"questionOrder" form:"questionOrder" binding:"required,min=1"`
+	QuestionType  string `json:"questionType" form:"questionType" binding:"required,oneof=text number select radio file"`
+	QuestionOrder int    `json:"questionOrder" form:"questionOrder" binding:"required,min=1" gorm:"uniqueIndex:idx_registration_question_order,where:deleted_at IS NULL"`
+
+	// Validation applied to answers in Register
+	IsRequired        bool     `json:"isRequired" gorm:"not null;default:false"`
+	ValidationPattern string   `json:"validationPattern" gorm:"size:255"` // Regex the whole answer must match (text)
+	PatternMessage    string   `json:"patternMessage" gorm:"size:255"`    // Shown when the pattern does not match
+	MinLength         *int     `json:"minLength"`                         // text
+	MaxLength         *int     `json:"maxLength"`                         // text
+	MinValue          *float64 `json:"minValue"`                          // number
+	MaxValue          *float64 `json:"maxValue"`                          // number
+
+	// Display rules: the question is shown when all (or any) of its conditions hold; no conditions means always shown
+	ShowWhen string `json:"showWhen" gorm:"size:3"` // all (default) or any
 
 	// relations
-	Course  Course               `json:"course,omitempty"`
-	Answers []RegistrationAnswer `json:"answers,omitempty" gorm:"foreignKey:QuestionID"`
+	Course     Course                          `json:"course,omitempty"`
+	Options    []RegistrationQuestionOption    `json:"options,omitempty" gorm:"foreignKey:QuestionID"`
+	Conditions []RegistrationQuestionCondition `json:"conditions,omitempty" gorm:"foreignKey:QuestionID"`
+	Answers    []RegistrationAnswer            `json:"answers,omitempty" gorm:"foreignKey:QuestionID"`
+}
+
+// RegistrationQuestionOption is one choice of a select or radio question
+type RegistrationQuestionOption struct {
+	gorm.Model
+
+	QuestionID  uint   `json:"questionId" gorm:"index;not null"`
+	OptionText  string `json:"optionText" gorm:"size:255;not null"`
+	OptionOrder int    `json:"optionOrder"`
+}
+
+// Condition operators
+const (
+	ConditionEquals      = "equals"
+	ConditionNotEquals   = "not_equals"
+	ConditionAnswered    = "answered"
+	ConditionNotAnswered = "not_answered"
+)
+
+// RegistrationQuestionCondition makes a question depend on the answer to an earlier question of the same course
+type RegistrationQuestionCondition struct {
+	gorm.Model
+
+	QuestionID          uint   `json:"questionId" gorm:"index;not null"`
+	DependsOnQuestionID uint   `json:"dependsOnQuestionId" gorm:"index;not null"`
+	Operator            string `json:"operator" gorm:"size:20;not null"`
+	Value               string `json:"value" gorm:"size:255"` // compared with equals / not_equals
 }
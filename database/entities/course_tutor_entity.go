@@ -0,0 +1,27 @@
+package entities
+
+import "gorm.io/gorm"
+
+// Course tutor roles
+const (
+	TutorRoleLead      = "lead"      // manages the course or class itself as well as its lessons
+	TutorRoleAssistant = "assistant" // manages lessons and resources only
+)
+
+// CourseTutor assigns a tutor to a whole course, or to one of its classes when ClassID is set.
+// The course creator always counts as a lead tutor of the course.
+type CourseTutor struct {
+	gorm.Model
+
+	CourseID         uint   `json:"courseId" gorm:"index;not null"`
+	ClassID          *uint  `json:"classId" gorm:"index"` // nil = the whole course
+	UserID           uint   `json:"userId" gorm:"index;not null"`
+	Role             string `json:"role" gorm:"size:20;not null"`
+	AssignedByUserID uint   `json:"assignedByUserId"`
+
+	// Relations
+	Course     Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+	Class      *Class `json:"class,omitempty" gorm:"foreignKey:ClassID"`
+	User       User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	AssignedBy User   `json:"assignedBy,omitempty" gorm:"foreignKey:AssignedByUserID"`
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GroupEnrollment is a batch of students enrolled into one Try Out or Course by a school coordinator.
+// The coordinator pays once for the whole group; approving the payment approves every member's registration.
+// Cancelling an unpaid group sets PaymentStatus to cancelled and releases every member's seat.
+type GroupEnrollment struct {
+	gorm.Model
+
+	CoordinatorUserID uint   `json:"coordinatorUserId" gorm:"index;not null"`
+	School            string `json:"school" gorm:"size:150;index"`
+	Label             string `json:"label" gorm:"size:100"` // e.g. "XII IPA 1"
+
+	// Exactly one of TryOutID / CourseID is set
+	TryOutID *uint `json:"tryOutId" gorm:"index"`
+	CourseID *uint `json:"courseId" gorm:"index"`
+
+	// Payment for the whole group
+	PricePerSeat     float64       `json:"pricePerSeat" gorm:"type:decimal(12,2);not null"`
+	Seats            int           `json:"seats" gorm:"not null"`
+	Amount           float64       `json:"amount" gorm:"type:decimal(12,2);not null"`
+	PaymentProofURL  string        `json:"paymentProofUrl" gorm:"size:500"`
+	PaymentStatus    PaymentStatus `json:"paymentStatus" gorm:"size:20;default:'pending'"`
+	RejectionReason  string        `json:"rejectionReason" gorm:"type:text"`
+	ApprovedByUserID *uint         `json:"approvedByUserId"`
+	ApprovedAt       *time.Time    `json:"approvedAt"`
+
+	// Relations
+	Coordinator User                    `json:"coordinator,omitempty" gorm:"foreignKey:CoordinatorUserID"`
+	TryOut      *TryOut                 `json:"tryOut,omitempty" gorm:"foreignKey:TryOutID"`
+	Course      *Course                 `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+	Members     []GroupEnrollmentMember `json:"members,omitempty" gorm:"foreignKey:GroupEnrollmentID"`
+}
+
+// GroupEnrollmentMember is one student of a group enrollment and the registration created for them.
+// Existing accounts from another school get an invitation instead; their registration is created
+// once they accept it, so a coordinator cannot enroll an account that does not belong to them.
+type GroupEnrollmentMember struct {
+	gorm.Model
+
+	GroupEnrollmentID    uint  `json:"groupEnrollmentId" gorm:"uniqueIndex:idx_group_member;not null"`
+	UserID               uint  `json:"userId" gorm:"uniqueIndex:idx_group_member;not null"`
+	TryOutRegistrationID *uint `json:"tryOutRegistrationId" gorm:"index"`
+	CourseRegistrationID *uint `json:"courseRegistrationId" gorm:"index"`
+	Invited              bool  `json:"invited"` // The account was created from the roster
+
+	InvitationToken      string     `json:"-" gorm:"size:64;index"` // Set until the student accepts
+	InvitationAcceptedAt *time.Time `json:"invitationAcceptedAt"`
+
+	// Relations
+	User               User                `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	TryOutRegistration *TryOutRegistration `json:"tryOutRegistration,omitempty" gorm:"foreignKey:TryOutRegistrationID"`
+	CourseRegistration *CourseRegistration `json:"courseRegistration,omitempty" gorm:"foreignKey:CourseRegistrationID"`
+}
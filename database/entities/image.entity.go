@@ -7,4 +7,6 @@ type Image struct {
 
 	URL    string `gorm:"type:varchar(2048);not null"`
 	Fileid string `gorm:"type:varchar(255);not null"`
+	// UserID is the uploader; empty for files registered before uploads were tied to a user
+	UserID *uint `gorm:"index"`
 }
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LedgerEntryType represents the kind of money movement recorded in the ledger.
+type LedgerEntryType string
+
+const (
+	LedgerEntryCharge     LedgerEntryType = "charge"     // Money received (positive amount)
+	LedgerEntryRefund     LedgerEntryType = "refund"     // Money returned (negative amount)
+	LedgerEntryAdjustment LedgerEntryType = "adjustment" // Manual correction (either sign)
+)
+
+// LedgerEntry is one money movement linked to a Try Out registration, a course registration
+// or a bundle purchase. Amounts are signed so the balance of a registration is the sum of its entries.
+// TryOutPackageID / CourseID / BundleID are copied from the registration for per-package reporting.
+type LedgerEntry struct {
+	gorm.Model
+
+	Type   LedgerEntryType `json:"type" gorm:"size:20;not null;index"`
+	Amount float64         `json:"amount" gorm:"type:decimal(12,2);not null"`
+
+	UserID           uint  `json:"userId" gorm:"index;not null"`
+	RegistrationID   *uint `json:"registrationId" gorm:"index"`
+	BundlePurchaseID *uint `json:"bundlePurchaseId" gorm:"index"`
+	TryOutPackageID  *uint `json:"tryOutPackageId" gorm:"index"`
+	BundleID         *uint `json:"bundleId" gorm:"index"`
+
+	CourseRegistrationID *uint `json:"courseRegistrationId" gorm:"index"`
+	CourseID             *uint `json:"courseId" gorm:"index"`
+
+	Source           string    `json:"source" gorm:"size:30"`     // manual_proof, gateway, cancellation, admin
+	Reference        string    `json:"reference" gorm:"size:100"` // e.g. gateway order ID
+	Note             string    `json:"note" gorm:"type:text"`
+	RecordedByUserID *uint     `json:"recordedByUserId"`
+	OccurredAt       time.Time `json:"occurredAt" gorm:"index;not null"`
+
+	// Relations
+	User       User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	RecordedBy *User `json:"recordedBy,omitempty" gorm:"foreignKey:RecordedByUserID"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attendance statuses
+const (
+	AttendancePresent = "present"
+	AttendanceLate    = "late"
+	AttendanceAbsent  = "absent"
+)
+
+// How an attendance record was made
+const (
+	AttendanceByTutor   = "tutor"
+	AttendanceByCheckIn = "check_in"
+)
+
+// LessonAttendance records whether an enrolled student attended one lesson
+type LessonAttendance struct {
+	gorm.Model
+
+	LessonID       uint       `json:"lessonId" gorm:"uniqueIndex:idx_lesson_attendance_user;not null"`
+	UserID         uint       `json:"userId" gorm:"uniqueIndex:idx_lesson_attendance_user;not null"`
+	RegistrationID uint       `json:"registrationId" gorm:"index;not null"`
+	Status         string     `json:"status" gorm:"size:10;not null"`
+	Method         string     `json:"method" gorm:"size:10;not null"`
+	MarkedByUserID *uint      `json:"markedByUserId"`
+	CheckedInAt    *time.Time `json:"checkedInAt"`
+	Note           string     `json:"note" gorm:"size:255"`
+
+	// Relations
+	Lesson Lesson `json:"lesson,omitempty" gorm:"foreignKey:LessonID"`
+	User   User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
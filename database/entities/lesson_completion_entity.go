@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Lesson completion rules
+const (
+	CompletionManual    = "manual"    // the student marks the lesson done
+	CompletionResources = "resources" // done once every resource of the lesson is done
+	CompletionQuiz      = "quiz"      // done once every quiz of the lesson is passed
+)
+
+// How a completion was recorded
+const (
+	CompletionSourceManual = "manual"
+	CompletionSourceAuto   = "auto"
+)
+
+// LessonCompletion records that a student finished a lesson
+type LessonCompletion struct {
+	gorm.Model
+
+	UserID      uint      `json:"userId" gorm:"uniqueIndex:idx_lesson_completion_user;not null"`
+	LessonID    uint      `json:"lessonId" gorm:"uniqueIndex:idx_lesson_completion_user;not null"`
+	CompletedAt time.Time `json:"completedAt"`
+	Source      string    `json:"source" gorm:"size:10;not null"`
+
+	Lesson Lesson `json:"lesson,omitempty" gorm:"foreignKey:LessonID"`
+}
+
+// ResourceCompletion records that a student finished one lesson resource
+type ResourceCompletion struct {
+	gorm.Model
+
+	UserID      uint      `json:"userId" gorm:"uniqueIndex:idx_resource_completion_user;not null"`
+	ResourceID  uint      `json:"resourceId" gorm:"uniqueIndex:idx_resource_completion_user;not null"`
+	LessonID    uint      `json:"lessonId" gorm:"index;not null"`
+	CompletedAt time.Time `json:"completedAt"`
+	Source      string    `json:"source" gorm:"size:10;not null"`
+}
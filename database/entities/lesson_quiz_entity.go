@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LessonQuiz is a short multiple-choice quiz inside a lesson, scored as soon as it is submitted
+type LessonQuiz struct {
+	gorm.Model
+
+	LessonID         uint    `json:"lessonId" gorm:"index;not null"`
+	Title            string  `json:"title" gorm:"size:255;not null"`
+	Description      string  `json:"description" gorm:"type:text"`
+	MaxAttempts      int     `json:"maxAttempts" gorm:"default:0"`   // 0 means unlimited
+	PassingScore     float64 `json:"passingScore" gorm:"default:70"` // percent of questions answered correctly
+	ShowExplanations bool    `json:"showExplanations"`
+	CreatedByUserID  uint    `json:"createdByUserId"`
+
+	// Relations
+	Lesson    Lesson               `json:"lesson,omitempty" gorm:"foreignKey:LessonID"`
+	Questions []LessonQuizQuestion `json:"questions,omitempty" gorm:"foreignKey:QuizID"`
+}
+
+// LessonQuizQuestion uses the same five-option format as TryOutQuestion.
+// Questions imported from a try-out are copied, so later edits on either side stay separate.
+type LessonQuizQuestion struct {
+	gorm.Model
+
+	QuizID           uint   `json:"quizId" gorm:"index;not null"`
+	SourceQuestionID *uint  `json:"sourceQuestionId"` // the TryOutQuestion it was copied from
+	OrderNumber      int    `json:"orderNumber" gorm:"not null"`
+	QuestionText     string `json:"questionText" gorm:"type:text;not null"`
+	ImageURL         string `json:"imageUrl" gorm:"size:500"`
+	Explanation      string `json:"explanation" gorm:"type:text"`
+
+	OptionA string `json:"optionA" gorm:"type:text;not null"`
+	OptionB string `json:"optionB" gorm:"type:text;not null"`
+	OptionC string `json:"optionC" gorm:"type:text;not null"`
+	OptionD string `json:"optionD" gorm:"type:text;not null"`
+	OptionE string `json:"optionE" gorm:"type:text;not null"`
+
+	CorrectOption string `json:"correctOption" gorm:"size:1;not null"` // A, B, C, D, or E
+}
+
+// LessonQuizAttempt is one scored submission of a quiz by a student
+type LessonQuizAttempt struct {
+	gorm.Model
+
+	QuizID         uint      `json:"quizId" gorm:"uniqueIndex:idx_quiz_attempt_number;not null"`
+	UserID         uint      `json:"userId" gorm:"uniqueIndex:idx_quiz_attempt_number;index;not null"`
+	AttemptNumber  int       `json:"attemptNumber" gorm:"uniqueIndex:idx_quiz_attempt_number;not null"`
+	CorrectCount   int       `json:"correctCount"`
+	TotalQuestions int       `json:"totalQuestions"`
+	Score          float64   `json:"score"` // percent
+	Passed         bool      `json:"passed"`
+	SubmittedAt    time.Time `json:"submittedAt"`
+
+	// Relations
+	User    User               `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Answers []LessonQuizAnswer `json:"answers,omitempty" gorm:"foreignKey:AttemptID"`
+}
+
+// LessonQuizAnswer is the option picked for one question of an attempt
+type LessonQuizAnswer struct {
+	gorm.Model
+
+	AttemptID      uint    `json:"attemptId" gorm:"uniqueIndex:idx_quiz_answer_question;not null"`
+	QuestionID     uint    `json:"questionId" gorm:"uniqueIndex:idx_quiz_answer_question;not null"`
+	SelectedOption *string `json:"selectedOption" gorm:"size:1"` // NULL when left blank
+	IsCorrect      bool    `json:"isCorrect"`
+}
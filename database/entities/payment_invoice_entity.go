@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvoiceStatus represents the status of a payment gateway invoice.
+type InvoiceStatus string
+
+const (
+	InvoiceStatusPending InvoiceStatus = "pending"
+	InvoiceStatusPaid    InvoiceStatus = "paid"
+	InvoiceStatusFailed  InvoiceStatus = "failed"
+	InvoiceStatusExpired InvoiceStatus = "expired"
+)
+
+// PaymentInvoice is a payment gateway invoice for a Try Out registration.
+// OrderID is the unique reference sent to the provider and echoed back in webhook callbacks.
+type PaymentInvoice struct {
+	gorm.Model
+
+	RegistrationID uint   `json:"registrationId" gorm:"index;not null"`
+	Provider       string `json:"provider" gorm:"size:20;not null"`
+	OrderID        string `json:"orderId" gorm:"uniqueIndex;size:64;not null"`
+	ExternalID     string `json:"externalId" gorm:"size:255"`
+	PaymentURL     string `json:"paymentUrl" gorm:"size:500"`
+
+	Amount        float64       `json:"amount" gorm:"type:decimal(12,2);not null"`
+	Status        InvoiceStatus `json:"status" gorm:"size:20;default:'pending'"`
+	PaymentMethod string        `json:"paymentMethod" gorm:"size:50"`
+	TransactionID string        `json:"transactionId" gorm:"size:100"`
+	PaidAt        *time.Time    `json:"paidAt"`
+
+	// HeldReason is set when a payment settles for a registration that was no longer waiting for it
+	// (waitlisted, expired or cancelled). An admin then admits the registration or refunds the payment.
+	HeldReason string `json:"heldReason,omitempty" gorm:"size:100"`
+
+	// Relations
+	Registration TryOutRegistration `json:"registration,omitempty" gorm:"foreignKey:RegistrationID"`
+}
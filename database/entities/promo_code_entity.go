@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DiscountType represents how a promo code reduces the price.
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// PromoCode is a voucher redeemable when registering for a Try Out or Course.
+// When no TryOuts or Courses are attached the code applies to every paid item.
+// School, when set, restricts the code to users whose verified school matches (cohort codes).
+type PromoCode struct {
+	gorm.Model
+
+	Code        string `json:"code" gorm:"uniqueIndex;size:50;not null"`
+	Description string `json:"description" gorm:"type:text"`
+	BatchLabel  string `json:"batchLabel" gorm:"size:100;index"` // Set for codes generated in bulk
+
+	DiscountType  DiscountType `json:"discountType" gorm:"size:20;not null"`
+	DiscountValue float64      `json:"discountValue" gorm:"type:decimal(12,2);not null"`
+	MaxDiscount   *float64     `json:"maxDiscount" gorm:"type:decimal(12,2)"` // Cap for percentage discounts
+
+	MaxUses        *int `json:"maxUses"`                             // nil = unlimited
+	MaxUsesPerUser int  `json:"maxUsesPerUser"`                      // 0 = unlimited
+	UsedCount      int  `json:"usedCount" gorm:"default:0;not null"` // Maintained on redeem/release
+
+	ValidFrom  *time.Time `json:"validFrom"`
+	ValidUntil *time.Time `json:"validUntil"`
+	IsActive   bool       `json:"isActive"`
+
+	School          string `json:"school" gorm:"size:150"`
+	CreatedByUserID uint   `json:"createdByUserId"`
+
+	// Relations
+	TryOuts     []TryOut          `json:"tryOuts,omitempty" gorm:"many2many:promo_code_try_outs;"`
+	Courses     []Course          `json:"courses,omitempty" gorm:"many2many:promo_code_courses;"`
+	Redemptions []PromoRedemption `json:"redemptions,omitempty" gorm:"foreignKey:PromoCodeID"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromoRedemption records one use of a promo code by a registration.
+// Exactly one of TryOutRegistrationID and CourseRegistrationID is set.
+type PromoRedemption struct {
+	gorm.Model
+
+	PromoCodeID          uint  `json:"promoCodeId" gorm:"index;not null"`
+	UserID               uint  `json:"userId" gorm:"index;not null"`
+	TryOutRegistrationID *uint `json:"tryOutRegistrationId" gorm:"index"`
+	CourseRegistrationID *uint `json:"courseRegistrationId" gorm:"index"`
+
+	OriginalPrice  float64   `json:"originalPrice" gorm:"type:decimal(12,2)"`
+	DiscountAmount float64   `json:"discountAmount" gorm:"type:decimal(12,2)"`
+	RedeemedAt     time.Time `json:"redeemedAt" gorm:"autoCreateTime"`
+
+	// Relations
+	PromoCode PromoCode `json:"promoCode,omitempty" gorm:"foreignKey:PromoCodeID"`
+	User      User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReceiptItemType identifies what a receipt was issued for.
+type ReceiptItemType string
+
+const (
+	ReceiptItemTryOut ReceiptItemType = "tryout"
+	ReceiptItemCourse ReceiptItemType = "course"
+)
+
+// Receipt is the numbered invoice/receipt issued when a paid registration is approved.
+// Buyer, item and price details are snapshotted so the document never changes after issue.
+type Receipt struct {
+	gorm.Model
+
+	Number   string `json:"number" gorm:"uniqueIndex;size:30;not null"` // e.g. INV-2026-000042
+	Year     int    `json:"year" gorm:"not null"`
+	Sequence int    `json:"sequence" gorm:"not null"`
+
+	UserID               uint            `json:"userId" gorm:"index;not null"`
+	ItemType             ReceiptItemType `json:"itemType" gorm:"size:20;not null"`
+	TryOutRegistrationID *uint           `json:"tryOutRegistrationId" gorm:"uniqueIndex"`
+	CourseRegistrationID *uint           `json:"courseRegistrationId" gorm:"uniqueIndex"`
+
+	// Buyer snapshot
+	BuyerName   string `json:"buyerName" gorm:"size:255"`
+	BuyerEmail  string `json:"buyerEmail" gorm:"size:191"`
+	BuyerPhone  string `json:"buyerPhone" gorm:"size:30"`
+	BuyerSchool string `json:"buyerSchool" gorm:"size:150"`
+
+	// Item snapshot
+	ItemName       string  `json:"itemName" gorm:"size:255;not null"`
+	Price          float64 `json:"price" gorm:"type:decimal(12,2);not null"`
+	DiscountAmount float64 `json:"discountAmount" gorm:"type:decimal(12,2);not null"`
+	PromoCode      string  `json:"promoCode" gorm:"size:50"`
+	Total          float64 `json:"total" gorm:"type:decimal(12,2);not null"`
+	PaymentMethod  string  `json:"paymentMethod" gorm:"size:50"`
+
+	IssuedAt  time.Time  `json:"issuedAt" gorm:"not null"`
+	EmailedAt *time.Time `json:"emailedAt"`
+
+	// Relations
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// ReceiptSequence holds the last receipt number used in a year; the row is locked while numbering.
+type ReceiptSequence struct {
+	Year       int `json:"year" gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int `json:"lastNumber" gorm:"not null"`
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScoreReport is the issued PDF result document of a completed Try Out attempt.
+// Nothing printed on the document changes after issue, so the public verification endpoint
+// always confirms what was printed. Rank and percentile are left out while the exam window
+// is still open; a report issued then gets its final ranking once the window has closed.
+type ScoreReport struct {
+	gorm.Model
+
+	AttemptID        uint   `json:"attemptId" gorm:"uniqueIndex;not null"`
+	VerificationCode string `json:"verificationCode" gorm:"uniqueIndex;size:32;not null"`
+
+	StudentName       string    `json:"studentName" gorm:"size:255;not null"`
+	TryOutName        string    `json:"tryOutName" gorm:"size:100;not null"`
+	TotalScore        float64   `json:"totalScore" gorm:"type:decimal(10,2);not null"`
+	Rank              *int      `json:"rank"`
+	TotalParticipants *int      `json:"totalParticipants"`
+	Percentile        *float64  `json:"percentile" gorm:"type:decimal(5,2)"`
+	RankingFinal      bool      `json:"rankingFinal" gorm:"not null;default:false"`
+	IssuedAt          time.Time `json:"issuedAt" gorm:"not null"`
+
+	// Relations
+	Attempt TryOutAttempt `json:"attempt,omitempty" gorm:"foreignKey:AttemptID"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubtestTimeExtension is a one-off grant of extra seconds for a single subtest of an attempt,
+// e.g. after a student lost connection during an outage. Multiple grants are summed.
+type SubtestTimeExtension struct {
+	gorm.Model
+
+	AttemptID    uint   `json:"attemptId" gorm:"index:idx_extension_attempt_subtest;not null"`
+	SubtestID    uint   `json:"subtestId" gorm:"index:idx_extension_attempt_subtest;not null"`
+	ExtraSeconds int    `json:"extraSeconds" gorm:"not null"`
+	Reason       string `json:"reason" gorm:"type:text;not null"`
+
+	GrantedByUserID uint      `json:"grantedByUserId"`
+	GrantedAt       time.Time `json:"grantedAt"`
+
+	// Relations
+	Attempt   TryOutAttempt `json:"attempt,omitempty" gorm:"foreignKey:AttemptID"`
+	Subtest   Subtest       `json:"subtest,omitempty" gorm:"foreignKey:SubtestID"`
+	GrantedBy User          `json:"grantedBy,omitempty" gorm:"foreignKey:GrantedByUserID"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TimeAccommodation scales every subtest time limit for one Try Out registration.
+// Since a registration has exactly one attempt, the multiplier also covers that attempt.
+type TimeAccommodation struct {
+	gorm.Model
+
+	RegistrationID uint    `json:"registrationId" gorm:"uniqueIndex;not null"`
+	Multiplier     float64 `json:"multiplier" gorm:"type:decimal(4,2);not null;default:1"` // e.g. 1.5 = 50% extra time
+	Reason         string  `json:"reason" gorm:"type:text;not null"`
+
+	GrantedByUserID uint      `json:"grantedByUserId"`
+	GrantedAt       time.Time `json:"grantedAt"`
+
+	// Relations
+	Registration TryOutRegistration `json:"registration,omitempty" gorm:"foreignKey:RegistrationID"`
+	GrantedBy    User               `json:"grantedBy,omitempty" gorm:"foreignKey:GrantedByUserID"`
+}
@@ -22,10 +22,22 @@ type TryOut struct {
 	QrisImageURL string  `json:"qrisImageUrl" gorm:"size:500"`
 	PaymentLink  string  `json:"paymentLink" gorm:"size:500"`
 
+	// Payment deadline: unpaid registrations expire this many hours after registering (0 = no deadline).
+	// A reminder email is sent PaymentReminderHours before the deadline (0 = no reminder).
+	PaymentDeadlineHours int `json:"paymentDeadlineHours"`
+	PaymentReminderHours int `json:"paymentReminderHours"`
+
+	// Capacity caps approved and pending registrations (0 = unlimited); later registrants join the waitlist
+	Capacity int `json:"capacity"`
+
 	// Registration period
 	RegistrationStart time.Time `json:"registrationStart" gorm:"not null"`
 	RegistrationEnd   time.Time `json:"registrationEnd" gorm:"not null"`
 
+	// Exam window: when registered students are expected to sit the try-out (shown on their calendars)
+	ExamStart *time.Time `json:"examStart"`
+	ExamEnd   *time.Time `json:"examEnd"`
+
 	IsPublished     bool `json:"isPublished" gorm:"default:false"`
 	CreatedByUserID uint `json:"createdByUserId"`
 
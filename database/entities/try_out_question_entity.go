@@ -16,15 +16,15 @@ const (
 type TryOutQuestion struct {
 	gorm.Model
 
-	TryOutPackageID uint `json:"tryOutPackageId" gorm:"index;not null"`
-	SubtestID       uint `json:"subtestId" gorm:"index;not null"`
+	TryOutPackageID uint `json:"tryOutPackageId" gorm:"index;uniqueIndex:idx_try_out_question_order,where:deleted_at IS NULL;not null"`
+	SubtestID       uint `json:"subtestId" gorm:"index;uniqueIndex:idx_try_out_question_order,where:deleted_at IS NULL;not null"`
 
 	QuestionText string `json:"questionText" gorm:"type:text;not null"`
 	ImageURL     string `json:"imageUrl" gorm:"size:500"`     // Optional, ImageKit URL
 	Explanation  string `json:"explanation" gorm:"type:text"` // Pembahasan
 
 	DifficultyLevel DifficultyLevel `json:"difficultyLevel" gorm:"size:10;not null"` // easy, medium, hard
-	OrderNumber     int             `json:"orderNumber" gorm:"not null"`
+	OrderNumber     int             `json:"orderNumber" gorm:"uniqueIndex:idx_try_out_question_order,where:deleted_at IS NULL;not null"`
 
 	// Options (denormalized - always A-E)
 	OptionA string `json:"optionA" gorm:"type:text;not null"`
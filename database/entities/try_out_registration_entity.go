@@ -10,9 +10,12 @@ import (
 type PaymentStatus string
 
 const (
-	PaymentStatusPending  PaymentStatus = "pending"
-	PaymentStatusApproved PaymentStatus = "approved"
-	PaymentStatusRejected PaymentStatus = "rejected"
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusApproved   PaymentStatus = "approved"
+	PaymentStatusRejected   PaymentStatus = "rejected"
+	PaymentStatusExpired    PaymentStatus = "expired"    // Unpaid past the package deadline; the row is soft-deleted
+	PaymentStatusCancelled  PaymentStatus = "cancelled"  // Cancelled by the student; the row is soft-deleted
+	PaymentStatusWaitlisted PaymentStatus = "waitlisted" // The package was full; promoted to pending when a seat opens
 )
 
 type TryOutRegistration struct {
@@ -21,9 +24,32 @@ type TryOutRegistration struct {
 	UserID          uint `json:"userId" gorm:"uniqueIndex:idx_user_tryout;not null"`
 	TryOutPackageID uint `json:"tryOutPackageId" gorm:"uniqueIndex:idx_user_tryout;not null"`
 
-	PaymentProofURL string        `json:"paymentProofUrl" gorm:"size:500"`
-	PaymentStatus   PaymentStatus `json:"paymentStatus" gorm:"size:20;default:'pending'"`
-	RejectionReason string        `json:"rejectionReason" gorm:"type:text"`
+	PaymentProofURL        string        `json:"paymentProofUrl" gorm:"size:500"`
+	PaymentProofUploadedAt *time.Time    `json:"paymentProofUploadedAt" gorm:"index"`
+	PaymentStatus          PaymentStatus `json:"paymentStatus" gorm:"size:20;default:'pending'"`
+	RejectionReason        string        `json:"rejectionReason" gorm:"type:text"`
+
+	// Payment deadline tracking
+	PaymentDueAt          *time.Time `json:"paymentDueAt" gorm:"index"`
+	PaymentReminderSentAt *time.Time `json:"paymentReminderSentAt"`
+	ExpiredAt             *time.Time `json:"expiredAt"`
+
+	// Pricing snapshot at registration time
+	OriginalPrice  float64 `json:"originalPrice" gorm:"type:decimal(12,2);default:0"`
+	DiscountAmount float64 `json:"discountAmount" gorm:"type:decimal(12,2);default:0"`
+	FinalAmount    float64 `json:"finalAmount" gorm:"type:decimal(12,2);default:0"`
+	PromoCodeID    *uint   `json:"promoCodeId"`
+	PromoCode      string  `json:"promoCode" gorm:"size:50"`
+
+	// Set when access was granted by a bundle or pass purchase
+	BundlePurchaseID *uint `json:"bundlePurchaseId" gorm:"index"`
+
+	// Set when a school coordinator enrolled the student; the group pays once
+	GroupEnrollmentID *uint `json:"groupEnrollmentId" gorm:"index"`
+
+	// Waitlist queue position is by WaitlistedAt; PromotedAt is set when a seat was given
+	WaitlistedAt *time.Time `json:"waitlistedAt" gorm:"index"`
+	PromotedAt   *time.Time `json:"promotedAt"`
 
 	ApprovedByUserID *uint      `json:"approvedByUserId"`
 	ApprovedAt       *time.Time `json:"approvedAt"`
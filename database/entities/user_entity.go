@@ -14,9 +14,13 @@ type User struct {
 	NoTelp       string `json:"noTelp" form:"noTelp"`
 	JenisKelamin *bool  `json:"jenisKelamin" form:"jenisKelamin"`
 
-	Kelas *string `json:"kelas" form:"kelas" binding:"omitempty,oneof='Kelas 10' 'Kelas 11' 'Kelas 12' 'Gapyer (Alumni)'" gorm:"type:varchar(20)"`
+	Kelas  *string `json:"kelas" form:"kelas" binding:"omitempty,oneof='Kelas 10' 'Kelas 11' 'Kelas 12' 'Gapyer (Alumni)'" gorm:"type:varchar(20)"`
+	School string  `json:"school" form:"school" gorm:"type:varchar(150);index"`
+	// VerifiedSchool is confirmed by an admin or through a school coordinator's roster; unlike
+	// School the student cannot edit it, so school-restricted features check this one
+	VerifiedSchool string `json:"verifiedSchool" gorm:"type:varchar(150);index"`
 
-	Role *string `json:"role" gorm:"type:varchar(10);default:'STUDENT'"`
+	Role *string `json:"role" gorm:"type:varchar(20);default:'STUDENT'"`
 
 	// AuthProvider indicates how the user registered (PASSWORD or GOOGLE)
 	AuthProvider string `json:"authProvider" gorm:"type:varchar(10);default:'PASSWORD'"`
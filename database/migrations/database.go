@@ -32,6 +32,10 @@ func ConnectDatabase() {
 
 	log.Println("Running auto-migrations...")
 
+	if err := NormalizeOrders(DB); err != nil {
+		log.Fatalf("Failed to normalize orders: %v", err)
+	}
+
 	log.Println("🚀 Running auto-migrations...")
 	err = DB.AutoMigrate(
 		// ===== USER & AUTH =====
@@ -42,12 +46,29 @@ func ConnectDatabase() {
 		&entities.Course{},
 		&entities.CourseRegistration{},
 		&entities.RegistrationQuestion{},
+		&entities.RegistrationQuestionOption{},
+		&entities.RegistrationQuestionCondition{},
 		&entities.RegistrationAnswer{},
 
 		// ===== CLASS & LESSON =====
 		&entities.Class{},
 		&entities.Lesson{},
 		&entities.LessonResource{},
+		&entities.ClassSchedule{},
+		&entities.ClassScheduleExclusion{},
+		&entities.CourseTutor{},
+		&entities.LessonAttendance{},
+		&entities.LessonCompletion{},
+		&entities.ResourceCompletion{},
+		&entities.Assignment{},
+		&entities.AssignmentAttachment{},
+		&entities.AssignmentSubmission{},
+		&entities.SubmissionFile{},
+		&entities.LessonQuiz{},
+		&entities.LessonQuizQuestion{},
+		&entities.LessonQuizAttempt{},
+		&entities.LessonQuizAnswer{},
+		&entities.CalendarFeedToken{},
 
 		// ===== TRYOUT =====
 		&entities.Subtest{},
@@ -58,6 +79,24 @@ func ConnectDatabase() {
 		&entities.TryOutAttempt{},
 		&entities.SubtestResult{},
 		&entities.UserTryOutAnswer{},
+		&entities.TimeAccommodation{},
+		&entities.SubtestTimeExtension{},
+		&entities.ScoreReport{},
+		&entities.PaymentInvoice{},
+		&entities.LedgerEntry{},
+		&entities.CancellationRequest{},
+		&entities.Receipt{},
+		&entities.ReceiptSequence{},
+
+		// ===== PROMOTIONS =====
+		&entities.PromoCode{},
+		&entities.PromoRedemption{},
+		&entities.Bundle{},
+		&entities.BundlePurchase{},
+
+		// ===== SCHOOLS =====
+		&entities.GroupEnrollment{},
+		&entities.GroupEnrollmentMember{},
 
 		// ===== UNIVERSITY & TARGET =====
 		&entities.University{},
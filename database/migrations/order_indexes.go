@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+// orderedTable describes a table whose rows are ordered within a parent and guarded by a
+// unique (parent, order) index on live rows
+type orderedTable struct {
+	model       any
+	index       string
+	parentCols  string
+	orderColumn string
+}
+
+var orderedTables = []orderedTable{
+	{&entities.Lesson{}, "idx_lesson_class_order", "class_id", "lesson_order"},
+	{&entities.RegistrationQuestion{}, "idx_registration_question_order", "course_id", "question_order"},
+	{&entities.TryOutQuestion{}, "idx_try_out_question_order", "try_out_package_id, subtest_id", "order_number"},
+}
+
+// NormalizeOrders renumbers existing rows 1..n within each parent before the unique order
+// indexes are created, so duplicates and gaps left by older versions do not block AutoMigrate.
+// Tables that already have their index are skipped.
+func NormalizeOrders(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, table := range orderedTables {
+		if !migrator.HasTable(table.model) || migrator.HasIndex(table.model, table.index) {
+			continue
+		}
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(table.model); err != nil {
+			return err
+		}
+		name := stmt.Schema.Table
+
+		sql := fmt.Sprintf(`UPDATE %[1]s SET %[3]s = ranked.position
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY %[2]s ORDER BY %[3]s ASC, id ASC) AS position
+				FROM %[1]s WHERE deleted_at IS NULL
+			) AS ranked
+			WHERE %[1]s.id = ranked.id AND %[1]s.%[3]s <> ranked.position`, name, table.parentCols, table.orderColumn)
+		result := db.Exec(sql)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Renumbered %d rows of %s before adding %s", result.RowsAffected, name, table.index)
+		}
+	}
+	return nil
+}
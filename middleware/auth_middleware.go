@@ -14,9 +14,10 @@ import (
 
 // Role constants
 const (
-	RoleAdmin   = "ADMIN"
-	RoleTutor   = "TUTOR"
-	RoleStudent = "STUDENT"
+	RoleAdmin       = "ADMIN"
+	RoleTutor       = "TUTOR"
+	RoleStudent     = "STUDENT"
+	RoleCoordinator = "COORDINATOR"
 )
 
 func RequireAuth() gin.HandlerFunc {
@@ -146,7 +147,12 @@ func RequireAdminOrTutor() gin.HandlerFunc {
 	return RequireAuthorization(RoleAdmin, RoleTutor)
 }
 
-// RequireAdminOrTutorOrUser is a shortcut for RequireAuthorization(RoleAdmin, RoleTutor, RoleUser)
+// RequireCoordinator is a shortcut for RequireAuthorization(RoleCoordinator)
+func RequireCoordinator() gin.HandlerFunc {
+	return RequireAuthorization(RoleCoordinator)
+}
+
+// RequireAdminOrTutorOrUser is a shortcut for RequireAuthorization(RoleAdmin, RoleTutor, RoleUser, RoleCoordinator)
 func RequireAdminOrTutorOrUser() gin.HandlerFunc {
-	return RequireAuthorization(RoleAdmin, RoleTutor, RoleStudent)
+	return RequireAuthorization(RoleAdmin, RoleTutor, RoleStudent, RoleCoordinator)
 }
@@ -10,6 +10,7 @@ func UserResponseJSON(user entities.User) UserResponse {
 		NoTelp:       user.NoTelp,
 		JenisKelamin: user.JenisKelamin,
 		Kelas:        user.Kelas,
+		School:       user.School,
 		Role:         user.Role,
 		AuthProvider: user.AuthProvider,
 		ProfileImage: user.ProfileImage,
@@ -54,6 +55,7 @@ type UserResponse struct {
 	NoTelp       string  `json:"noTelp,omitempty"`
 	JenisKelamin *bool   `json:"jenisKelamin,omitempty"`
 	Kelas        *string `json:"kelas,omitempty"`
+	School       string  `json:"school,omitempty"`
 	Role         *string `json:"role,omitempty"`
 	AuthProvider string  `json:"authProvider"`
 	ProfileImage string  `json:"profileImage,omitempty"`
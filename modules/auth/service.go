@@ -218,6 +218,9 @@ func (s *authService) ResetPassword(input ResetPasswordInput) error {
 	user.Password = string(passwordHash)
 	user.ResetPasswordToken = ""
 	user.ResetPasswordTokenExpiry = nil
+	// The token was delivered by email, so this also verifies accounts invited by a school coordinator
+	user.IsVerified = true
+	user.VerificationCode = nil
 
 	return s.repo.Update(user)
 }
@@ -0,0 +1,176 @@
+package bundles
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// BUNDLE DTOs
+// ==========================================
+
+// CreateBundleInput is the input for creating a bundle or pass.
+// Bundles list their Try Outs in TryOutIDs; passes cover every published Try Out between PassStart and PassEnd.
+type CreateBundleInput struct {
+	Name         string     `json:"name" binding:"required,max=100"`
+	Description  string     `json:"description"`
+	ImageURL     string     `json:"imageUrl"`
+	Type         string     `json:"type" binding:"required,oneof=bundle pass"`
+	Price        float64    `json:"price" binding:"gte=0"`
+	QrisImageURL string     `json:"qrisImageUrl"`
+	PaymentLink  string     `json:"paymentLink"`
+	SaleStart    time.Time  `json:"saleStart" binding:"required"`
+	SaleEnd      time.Time  `json:"saleEnd" binding:"required"`
+	PassStart    *time.Time `json:"passStart"`
+	PassEnd      *time.Time `json:"passEnd"`
+	IsPublished  bool       `json:"isPublished"`
+	TryOutIDs    []uint     `json:"tryOutIds"`
+	CourseIDs    []uint     `json:"courseIds"`
+}
+
+// UpdateBundleInput is the input for updating a bundle. The type cannot change.
+type UpdateBundleInput struct {
+	Name         *string    `json:"name" binding:"omitempty,max=100"`
+	Description  *string    `json:"description"`
+	ImageURL     *string    `json:"imageUrl"`
+	Price        *float64   `json:"price" binding:"omitempty,gte=0"`
+	QrisImageURL *string    `json:"qrisImageUrl"`
+	PaymentLink  *string    `json:"paymentLink"`
+	SaleStart    *time.Time `json:"saleStart"`
+	SaleEnd      *time.Time `json:"saleEnd"`
+	PassStart    *time.Time `json:"passStart"`
+	PassEnd      *time.Time `json:"passEnd"`
+	IsPublished  *bool      `json:"isPublished"`
+	TryOutIDs    *[]uint    `json:"tryOutIds"`
+	CourseIDs    *[]uint    `json:"courseIds"`
+}
+
+// UploadPaymentProofInput is the input for uploading a bundle payment proof
+type UploadPaymentProofInput struct {
+	PaymentProofURL string `json:"paymentProofUrl" binding:"required"`
+}
+
+// RejectPurchaseInput is the input for rejecting a bundle payment
+type RejectPurchaseInput struct {
+	RejectionReason string `json:"rejectionReason"`
+}
+
+// BundleResponse is the response DTO for a bundle
+type BundleResponse struct {
+	ID           uint                  `json:"id"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	ImageURL     string                `json:"imageUrl,omitempty"`
+	Type         string                `json:"type"`
+	Price        float64               `json:"price"`
+	QrisImageURL string                `json:"qrisImageUrl,omitempty"`
+	PaymentLink  string                `json:"paymentLink,omitempty"`
+	SaleStart    time.Time             `json:"saleStart"`
+	SaleEnd      time.Time             `json:"saleEnd"`
+	PassStart    *time.Time            `json:"passStart,omitempty"`
+	PassEnd      *time.Time            `json:"passEnd,omitempty"`
+	IsPublished  bool                  `json:"isPublished"`
+	TryOuts      []TryOutBriefResponse `json:"tryOuts"`
+	Courses      []CourseBriefResponse `json:"courses"`
+	CreatedAt    time.Time             `json:"createdAt"`
+}
+
+// TryOutBriefResponse is a minimal try out info
+type TryOutBriefResponse struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	IsPublished bool    `json:"isPublished"`
+}
+
+// CourseBriefResponse is a minimal course info
+type CourseBriefResponse struct {
+	ID         uint   `json:"id"`
+	NameCourse string `json:"nameCourse"`
+}
+
+// PurchaseResponse is the response DTO for a bundle purchase
+type PurchaseResponse struct {
+	ID              uint               `json:"id"`
+	BundleID        uint               `json:"bundleId"`
+	BundleName      string             `json:"bundleName,omitempty"`
+	User            *UserBriefResponse `json:"user,omitempty"`
+	Amount          float64            `json:"amount"`
+	PaymentProofURL string             `json:"paymentProofUrl,omitempty"`
+	PaymentStatus   string             `json:"paymentStatus"`
+	RejectionReason string             `json:"rejectionReason,omitempty"`
+	ApprovedAt      *time.Time         `json:"approvedAt,omitempty"`
+	PurchasedAt     time.Time          `json:"purchasedAt"`
+}
+
+// UserBriefResponse is a minimal user info
+type UserBriefResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToBundleResponse(b entities.Bundle) BundleResponse {
+	response := BundleResponse{
+		ID:           b.ID,
+		Name:         b.Name,
+		Description:  b.Description,
+		ImageURL:     b.ImageURL,
+		Type:         string(b.Type),
+		Price:        b.Price,
+		QrisImageURL: b.QrisImageURL,
+		PaymentLink:  b.PaymentLink,
+		SaleStart:    b.SaleStart,
+		SaleEnd:      b.SaleEnd,
+		PassStart:    b.PassStart,
+		PassEnd:      b.PassEnd,
+		IsPublished:  b.IsPublished,
+		TryOuts:      []TryOutBriefResponse{},
+		Courses:      []CourseBriefResponse{},
+		CreatedAt:    b.CreatedAt,
+	}
+	for _, t := range b.TryOuts {
+		response.TryOuts = append(response.TryOuts, TryOutBriefResponse{
+			ID:          t.ID,
+			Name:        t.Name,
+			Price:       t.Price,
+			IsPublished: t.IsPublished,
+		})
+	}
+	for _, c := range b.Courses {
+		response.Courses = append(response.Courses, CourseBriefResponse{
+			ID:         c.ID,
+			NameCourse: c.NameCourse,
+		})
+	}
+	return response
+}
+
+func ToPurchaseResponse(p entities.BundlePurchase) PurchaseResponse {
+	response := PurchaseResponse{
+		ID:              p.ID,
+		BundleID:        p.BundleID,
+		Amount:          p.Amount,
+		PaymentProofURL: p.PaymentProofURL,
+		PaymentStatus:   string(p.PaymentStatus),
+		RejectionReason: p.RejectionReason,
+		ApprovedAt:      p.ApprovedAt,
+		PurchasedAt:     p.PurchasedAt,
+	}
+	if p.Bundle.ID != 0 {
+		response.BundleName = p.Bundle.Name
+	}
+	if p.User.ID != 0 {
+		response.User = &UserBriefResponse{
+			ID:       p.User.ID,
+			Username: p.User.Username,
+			Email:    p.User.Email,
+		}
+	}
+	return response
+}
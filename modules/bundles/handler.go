@@ -0,0 +1,305 @@
+package bundles
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	// Public endpoints
+	GetAllBundlesHandler(c *gin.Context)
+	GetBundleByIDHandler(c *gin.Context)
+
+	// Admin endpoints
+	CreateBundleHandler(c *gin.Context)
+	UpdateBundleHandler(c *gin.Context)
+	DeleteBundleHandler(c *gin.Context)
+	GetPendingPurchasesHandler(c *gin.Context)
+	ApprovePurchaseHandler(c *gin.Context)
+	RejectPurchaseHandler(c *gin.Context)
+
+	// User endpoints
+	PurchaseHandler(c *gin.Context)
+	UploadPaymentProofHandler(c *gin.Context)
+	GetMyPurchasesHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Public Handlers
+// ==========================================
+
+func (h *handler) GetAllBundlesHandler(c *gin.Context) {
+	bundles, err := h.service.GetAll(isAdmin(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch bundles", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundles retrieved successfully", bundles))
+}
+
+func (h *handler) GetBundleByIDHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	bundle, err := h.service.GetByID(id, isAdmin(c), getRequestID(c))
+	if err != nil {
+		if err.Error() == "bundle not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Bundle not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch bundle", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundle retrieved successfully", bundle))
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) CreateBundleHandler(c *gin.Context) {
+	var input CreateBundleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	bundle, err := h.service.Create(input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "sale end date must be after start date", "pass start and end dates are required for a pass",
+			"pass end date must be after start date", "a pass covers try outs by period and cannot list try outs",
+			"pass dates are only allowed for a pass", "one or more try outs not found", "one or more courses not found":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid bundle", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create bundle", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Bundle created successfully", bundle))
+}
+
+func (h *handler) UpdateBundleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateBundleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	bundle, err := h.service.Update(id, input, getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "bundle not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Bundle not found", err.Error(), nil))
+		case "sale end date must be after start date", "pass start and end dates are required for a pass",
+			"pass end date must be after start date", "a pass covers try outs by period and cannot list try outs",
+			"pass dates are only allowed for a pass", "one or more try outs not found", "one or more courses not found":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid bundle", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update bundle", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundle updated successfully", bundle))
+}
+
+func (h *handler) DeleteBundleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, getRequestID(c)); err != nil {
+		if err.Error() == "bundle not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Bundle not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete bundle", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundle deleted successfully", nil))
+}
+
+func (h *handler) GetPendingPurchasesHandler(c *gin.Context) {
+	purchases, err := h.service.GetPendingPurchases(getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch pending purchases", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Pending purchases retrieved successfully", purchases))
+}
+
+func (h *handler) ApprovePurchaseHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	purchase, err := h.service.ApprovePurchase(id, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "purchase not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Purchase not found", err.Error(), nil))
+		case "payment is already approved":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already approved", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to approve purchase", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundle purchase approved successfully", purchase))
+}
+
+func (h *handler) RejectPurchaseHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input RejectPurchaseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	purchase, err := h.service.RejectPurchase(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "purchase not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Purchase not found", err.Error(), nil))
+		case "payment is already approved":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already approved", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reject purchase", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Bundle purchase rejected", purchase))
+}
+
+// ==========================================
+// User Handlers
+// ==========================================
+
+func (h *handler) PurchaseHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	purchase, err := h.service.Purchase(id, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "bundle not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Bundle not found", err.Error(), nil))
+		case "bundle sale has not started yet", "bundle sale has ended":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Sale period invalid", err.Error(), nil))
+		case "you have already purchased this bundle":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already purchased", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to purchase bundle", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Bundle purchase created", purchase))
+}
+
+func (h *handler) UploadPaymentProofHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UploadPaymentProofInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	purchase, err := h.service.UploadPaymentProof(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "purchase not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Purchase not found", err.Error(), nil))
+		case "you can only upload payment proof for your own purchase":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+		case "payment is already approved":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already approved", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to upload payment proof", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payment proof uploaded successfully", purchase))
+}
+
+func (h *handler) GetMyPurchasesHandler(c *gin.Context) {
+	purchases, err := h.service.GetMyPurchases(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch purchases", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Purchases retrieved successfully", purchases))
+}
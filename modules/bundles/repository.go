@@ -0,0 +1,417 @@
+package bundles
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	// Bundles
+	FindAll(publishedOnly bool) ([]entities.Bundle, error)
+	FindByID(id uint) (entities.Bundle, error)
+	Create(bundle *entities.Bundle) error
+	Update(bundle *entities.Bundle) error
+	ReplaceContents(bundle *entities.Bundle, tryOuts []entities.TryOut, courses []entities.Course) error
+	Delete(id uint) error
+	FindTryOutsByIDs(ids []uint) ([]entities.TryOut, error)
+	FindCoursesByIDs(ids []uint) ([]entities.Course, error)
+	FindTryOutByID(id uint) (entities.TryOut, error)
+
+	// Purchases
+	FindPurchaseByID(id uint) (entities.BundlePurchase, error)
+	FindPurchaseByUserAndBundleUnscoped(userID, bundleID uint) (entities.BundlePurchase, error)
+	FindPurchasesByUserID(userID uint) ([]entities.BundlePurchase, error)
+	FindPendingPurchases() ([]entities.BundlePurchase, error)
+	CreatePurchase(purchase *entities.BundlePurchase) error
+	RestorePurchase(id uint) error
+	UpdatePurchase(purchase *entities.BundlePurchase) error
+	// ApprovePurchase saves the approved purchase, records its charge and grants its access in one
+	// transaction. It returns how many registrations were created or changed.
+	ApprovePurchase(purchase *entities.BundlePurchase, adminUserID uint, tryOutIDs []uint, courseIDs []uint) (int, error)
+
+	// Access
+	// FindCoveredTryOutIDs returns the published Try Outs a bundle currently unlocks
+	FindCoveredTryOutIDs(bundle entities.Bundle) ([]uint, error)
+	// FindApprovedPurchasesCoveringTryOut returns approved purchases whose bundle unlocks tryOut
+	FindApprovedPurchasesCoveringTryOut(tryOut entities.TryOut) ([]entities.BundlePurchase, error)
+	// GrantAccess creates or approves the user's registrations for the given Try Outs and Courses;
+	// where a package is full the registration joins its waitlist instead.
+	// It returns how many registrations were created or changed.
+	GrantAccess(purchase entities.BundlePurchase, tryOutIDs []uint, courseIDs []uint) (int, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Bundle Methods
+// ==========================================
+
+func (r *repository) FindAll(publishedOnly bool) ([]entities.Bundle, error) {
+	var bundles []entities.Bundle
+	query := r.db.Preload("TryOuts").Preload("Courses").Order("created_at DESC")
+	if publishedOnly {
+		query = query.Where("is_published = ?", true)
+	}
+	err := query.Find(&bundles).Error
+	return bundles, err
+}
+
+func (r *repository) FindByID(id uint) (entities.Bundle, error) {
+	var bundle entities.Bundle
+	err := r.db.Preload("TryOuts").Preload("Courses").First(&bundle, id).Error
+	return bundle, err
+}
+
+func (r *repository) Create(bundle *entities.Bundle) error {
+	return r.db.Create(bundle).Error
+}
+
+func (r *repository) Update(bundle *entities.Bundle) error {
+	return r.db.Omit("TryOuts", "Courses").Save(bundle).Error
+}
+
+func (r *repository) ReplaceContents(bundle *entities.Bundle, tryOuts []entities.TryOut, courses []entities.Course) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(bundle).Association("TryOuts").Replace(tryOuts); err != nil {
+			return err
+		}
+		return tx.Model(bundle).Association("Courses").Replace(courses)
+	})
+}
+
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entities.Bundle{}, id).Error
+}
+
+func (r *repository) FindTryOutsByIDs(ids []uint) ([]entities.TryOut, error) {
+	var tryOuts []entities.TryOut
+	if len(ids) == 0 {
+		return tryOuts, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tryOuts).Error
+	return tryOuts, err
+}
+
+func (r *repository) FindCoursesByIDs(ids []uint) ([]entities.Course, error) {
+	var courses []entities.Course
+	if len(ids) == 0 {
+		return courses, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&courses).Error
+	return courses, err
+}
+
+func (r *repository) FindTryOutByID(id uint) (entities.TryOut, error) {
+	var tryOut entities.TryOut
+	err := r.db.First(&tryOut, id).Error
+	return tryOut, err
+}
+
+// ==========================================
+// Purchase Methods
+// ==========================================
+
+func (r *repository) FindPurchaseByID(id uint) (entities.BundlePurchase, error) {
+	var purchase entities.BundlePurchase
+	err := r.db.Preload("Bundle").Preload("User").Preload("ApprovedBy").First(&purchase, id).Error
+	return purchase, err
+}
+
+func (r *repository) FindPurchaseByUserAndBundleUnscoped(userID, bundleID uint) (entities.BundlePurchase, error) {
+	var purchase entities.BundlePurchase
+	err := r.db.Unscoped().Where("user_id = ? AND bundle_id = ?", userID, bundleID).First(&purchase).Error
+	return purchase, err
+}
+
+func (r *repository) FindPurchasesByUserID(userID uint) ([]entities.BundlePurchase, error) {
+	var purchases []entities.BundlePurchase
+	err := r.db.Where("user_id = ?", userID).
+		Preload("Bundle").
+		Order("purchased_at DESC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+func (r *repository) FindPendingPurchases() ([]entities.BundlePurchase, error) {
+	var purchases []entities.BundlePurchase
+	err := r.db.Where("payment_status = ? AND payment_proof_url != ''", entities.PaymentStatusPending).
+		Preload("Bundle").
+		Preload("User").
+		Order("purchased_at ASC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+func (r *repository) CreatePurchase(purchase *entities.BundlePurchase) error {
+	return r.db.Create(purchase).Error
+}
+
+func (r *repository) RestorePurchase(id uint) error {
+	return r.db.Unscoped().Model(&entities.BundlePurchase{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *repository) UpdatePurchase(purchase *entities.BundlePurchase) error {
+	return r.db.Omit("Bundle", "User", "ApprovedBy").Save(purchase).Error
+}
+
+func (r *repository) ApprovePurchase(purchase *entities.BundlePurchase, adminUserID uint, tryOutIDs []uint, courseIDs []uint) (int, error) {
+	granted := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Bundle", "User", "ApprovedBy").Save(purchase).Error; err != nil {
+			return err
+		}
+		if purchase.Amount > 0 {
+			purchaseID := purchase.ID
+			bundleID := purchase.BundleID
+			if err := tx.Create(&entities.LedgerEntry{
+				Type:             entities.LedgerEntryCharge,
+				Amount:           purchase.Amount,
+				UserID:           purchase.UserID,
+				BundlePurchaseID: &purchaseID,
+				BundleID:         &bundleID,
+				Source:           ledger.SourceManualProof,
+				RecordedByUserID: &adminUserID,
+				OccurredAt:       time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		granted, err = grantAccess(tx, *purchase, tryOutIDs, courseIDs)
+		return err
+	})
+	return granted, err
+}
+
+// ==========================================
+// Access Methods
+// ==========================================
+
+func (r *repository) FindCoveredTryOutIDs(bundle entities.Bundle) ([]uint, error) {
+	var ids []uint
+	query := r.db.Model(&entities.TryOut{}).Where("is_published = ?", true)
+
+	switch bundle.Type {
+	case entities.BundleTypePass:
+		if bundle.PassStart == nil || bundle.PassEnd == nil {
+			return ids, nil
+		}
+		query = query.Where("registration_end >= ? AND registration_start <= ?", *bundle.PassStart, *bundle.PassEnd)
+	default:
+		query = query.Where("id IN (?)", r.db.Table("bundle_try_outs").Select("try_out_id").Where("bundle_id = ?", bundle.ID))
+	}
+
+	err := query.Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *repository) FindApprovedPurchasesCoveringTryOut(tryOut entities.TryOut) ([]entities.BundlePurchase, error) {
+	var purchases []entities.BundlePurchase
+	err := r.db.Joins("JOIN bundles ON bundles.id = bundle_purchases.bundle_id AND bundles.deleted_at IS NULL").
+		Where("bundle_purchases.payment_status = ?", entities.PaymentStatusApproved).
+		Where(
+			r.db.Where("bundles.type = ? AND bundles.id IN (?)", entities.BundleTypeBundle,
+				r.db.Table("bundle_try_outs").Select("bundle_id").Where("try_out_id = ?", tryOut.ID)).
+				Or("bundles.type = ? AND bundles.pass_start <= ? AND bundles.pass_end >= ?",
+					entities.BundleTypePass, tryOut.RegistrationEnd, tryOut.RegistrationStart),
+		).
+		Find(&purchases).Error
+	return purchases, err
+}
+
+func (r *repository) GrantAccess(purchase entities.BundlePurchase, tryOutIDs []uint, courseIDs []uint) (int, error) {
+	granted := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		granted, err = grantAccess(tx, purchase, tryOutIDs, courseIDs)
+		return err
+	})
+	return granted, err
+}
+
+func grantAccess(tx *gorm.DB, purchase entities.BundlePurchase, tryOutIDs []uint, courseIDs []uint) (int, error) {
+	granted := 0
+	now := time.Now()
+
+	for _, tryOutID := range tryOutIDs {
+		// Seats are counted under the same lock admission takes
+		var tryOut entities.TryOut
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity").First(&tryOut, tryOutID).Error; err != nil {
+			return granted, err
+		}
+
+		var registration entities.TryOutRegistration
+		err := tx.Unscoped().Where("user_id = ? AND try_out_package_id = ?", purchase.UserID, tryOutID).First(&registration).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return granted, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			full, err := waitlists.TryOutIsFull(tx, tryOut, 0)
+			if err != nil {
+				return granted, err
+			}
+			registration = entities.TryOutRegistration{
+				UserID:           purchase.UserID,
+				TryOutPackageID:  tryOutID,
+				PaymentStatus:    entities.PaymentStatusApproved,
+				ApprovedByUserID: purchase.ApprovedByUserID,
+				ApprovedAt:       &now,
+				RegisteredAt:     now,
+				BundlePurchaseID: &purchase.ID,
+			}
+			if full {
+				registration.PaymentStatus = entities.PaymentStatusWaitlisted
+				registration.ApprovedByUserID = nil
+				registration.ApprovedAt = nil
+				registration.WaitlistedAt = &now
+			}
+			if err := tx.Create(&registration).Error; err != nil {
+				return granted, err
+			}
+			granted++
+			continue
+		}
+
+		// Already has active access
+		if !registration.DeletedAt.Valid && registration.PaymentStatus == entities.PaymentStatusApproved {
+			continue
+		}
+
+		// A pending registration already holds its seat and a waitlisted one keeps its place in
+		// the queue; anything else needs a free seat like a new registration
+		status := entities.PaymentStatusApproved
+		switch {
+		case !registration.DeletedAt.Valid && registration.PaymentStatus == entities.PaymentStatusWaitlisted:
+			status = entities.PaymentStatusWaitlisted
+		case registration.DeletedAt.Valid || registration.PaymentStatus != entities.PaymentStatusPending:
+			full, err := waitlists.TryOutIsFull(tx, tryOut, registration.ID)
+			if err != nil {
+				return granted, err
+			}
+			if full {
+				status = entities.PaymentStatusWaitlisted
+				registration.WaitlistedAt = &now
+				registration.PromotedAt = nil
+			}
+		}
+
+		// The bundle covers it, so close out what the registration was still waiting on
+		if err := tx.Model(&entities.PaymentInvoice{}).
+			Where("registration_id = ? AND status = ?", registration.ID, entities.InvoiceStatusPending).
+			Update("status", entities.InvoiceStatusExpired).Error; err != nil {
+			return granted, err
+		}
+		if err := promos.ReleaseRedemptions(tx, "try_out_registration_id", registration.ID); err != nil {
+			return granted, err
+		}
+		registration.DeletedAt = gorm.DeletedAt{}
+		registration.PaymentStatus = status
+		registration.RejectionReason = ""
+		registration.ApprovedByUserID = nil
+		registration.ApprovedAt = nil
+		if status == entities.PaymentStatusApproved {
+			registration.ApprovedByUserID = purchase.ApprovedByUserID
+			registration.ApprovedAt = &now
+		}
+		registration.PaymentDueAt = nil
+		registration.BundlePurchaseID = &purchase.ID
+		registration.PaymentProofURL = ""
+		registration.PaymentProofUploadedAt = nil
+		// Nothing is owed once the bundle covers the registration
+		registration.PromoCodeID = nil
+		registration.PromoCode = ""
+		registration.DiscountAmount = 0
+		registration.FinalAmount = 0
+		if err := tx.Unscoped().Omit("User", "TryOutPackage", "ApprovedBy", "Attempt").Save(&registration).Error; err != nil {
+			return granted, err
+		}
+		granted++
+	}
+
+	for _, courseID := range courseIDs {
+		var course entities.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity").First(&course, courseID).Error; err != nil {
+			return granted, err
+		}
+
+		var registration entities.CourseRegistration
+		err := tx.Unscoped().Where("user_id = ? AND course_id = ?", purchase.UserID, courseID).First(&registration).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return granted, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			full, err := waitlists.CourseIsFull(tx, course, 0)
+			if err != nil {
+				return granted, err
+			}
+			registration = entities.CourseRegistration{
+				UserID:           purchase.UserID,
+				CourseID:         courseID,
+				Status:           "approved",
+				BundlePurchaseID: &purchase.ID,
+			}
+			if full {
+				registration.Status = "waitlisted"
+				registration.WaitlistedAt = &now
+			}
+			if err := tx.Create(&registration).Error; err != nil {
+				return granted, err
+			}
+			granted++
+			continue
+		}
+
+		if !registration.DeletedAt.Valid && registration.Status == "approved" {
+			continue
+		}
+
+		status := "approved"
+		switch {
+		case !registration.DeletedAt.Valid && registration.Status == "waitlisted":
+			status = "waitlisted"
+		case registration.DeletedAt.Valid || registration.Status != "pending":
+			full, err := waitlists.CourseIsFull(tx, course, registration.ID)
+			if err != nil {
+				return granted, err
+			}
+			if full {
+				status = "waitlisted"
+				registration.WaitlistedAt = &now
+				registration.PromotedAt = nil
+			}
+		}
+
+		if err := promos.ReleaseRedemptions(tx, "course_registration_id", registration.ID); err != nil {
+			return granted, err
+		}
+		registration.DeletedAt = gorm.DeletedAt{}
+		registration.Status = status
+		registration.BundlePurchaseID = &purchase.ID
+		// Nothing is owed once the bundle covers the registration
+		registration.PromoCodeID = nil
+		registration.PromoCode = ""
+		registration.DiscountAmount = 0
+		registration.FinalAmount = 0
+		if err := tx.Unscoped().Omit("User", "Course", "Answers").Save(&registration).Error; err != nil {
+			return granted, err
+		}
+		granted++
+	}
+	return granted, nil
+}
@@ -0,0 +1,45 @@
+package bundles
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+)
+
+func BundleRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	service := NewService(repo)
+	handler := NewHandler(service)
+
+	// Public: anyone can view published bundles, admin sees all
+	bundles := router.Group("/bundles")
+	bundles.Use(middleware.OptionalAuth())
+	{
+		bundles.GET("", handler.GetAllBundlesHandler)
+		bundles.GET("/:id", handler.GetBundleByIDHandler)
+	}
+
+	// User: buy a bundle and pay for it
+	router.POST("/bundles/:id/purchase", requireAuth, handler.PurchaseHandler)
+	router.GET("/users/me/bundle-purchases", requireAuth, handler.GetMyPurchasesHandler)
+	router.POST("/bundles/purchases/payment-proof/:id", requireAuth, handler.UploadPaymentProofHandler)
+
+	// Admin: manage bundles
+	adminRoutes := router.Group("/bundles")
+	adminRoutes.Use(requireAuth, requireAdmin)
+	{
+		adminRoutes.POST("", handler.CreateBundleHandler)
+		adminRoutes.PUT("/:id", handler.UpdateBundleHandler)
+		adminRoutes.DELETE("/:id", handler.DeleteBundleHandler)
+	}
+
+	// Admin: review bundle payments
+	purchaseRoutes := router.Group("/bundles/purchases")
+	purchaseRoutes.Use(requireAuth, requireAdmin)
+	{
+		purchaseRoutes.GET("/pending", handler.GetPendingPurchasesHandler)
+		purchaseRoutes.PUT("/:id/approve", handler.ApprovePurchaseHandler)
+		purchaseRoutes.PUT("/:id/reject", handler.RejectPurchaseHandler)
+	}
+}
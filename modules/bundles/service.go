@@ -0,0 +1,596 @@
+package bundles
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type bundleService struct {
+	repo Repository
+}
+
+type Service interface {
+	// Catalogue
+	GetAll(includeUnpublished bool, requestID string) ([]BundleResponse, error)
+	GetByID(id uint, includeUnpublished bool, requestID string) (*BundleResponse, error)
+
+	// Admin actions
+	Create(input CreateBundleInput, adminUserID uint, requestID string) (*BundleResponse, error)
+	Update(id uint, input UpdateBundleInput, requestID string) (*BundleResponse, error)
+	Delete(id uint, requestID string) error
+	GetPendingPurchases(requestID string) ([]PurchaseResponse, error)
+	ApprovePurchase(purchaseID uint, adminUserID uint, requestID string) (*PurchaseResponse, error)
+	RejectPurchase(purchaseID uint, input RejectPurchaseInput, adminUserID uint, requestID string) (*PurchaseResponse, error)
+
+	// User actions
+	Purchase(bundleID uint, userID uint, requestID string) (*PurchaseResponse, error)
+	UploadPaymentProof(purchaseID uint, input UploadPaymentProofInput, userID uint, requestID string) (*PurchaseResponse, error)
+	GetMyPurchases(userID uint, requestID string) ([]PurchaseResponse, error)
+
+	// GrantPublishedTryOut gives access to a newly published Try Out to every approved purchase covering it
+	GrantPublishedTryOut(tryOutID uint, requestID string) error
+}
+
+func NewService(repo Repository) Service {
+	return &bundleService{repo: repo}
+}
+
+// ==========================================
+// Catalogue
+// ==========================================
+
+func (s *bundleService) GetAll(includeUnpublished bool, requestID string) ([]BundleResponse, error) {
+	bundles, err := s.repo.FindAll(!includeUnpublished)
+	if err != nil {
+		utils.LogError("bundles", "get_all", "Failed to fetch bundles: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]BundleResponse, 0, len(bundles))
+	for _, b := range bundles {
+		responses = append(responses, ToBundleResponse(b))
+	}
+	return responses, nil
+}
+
+func (s *bundleService) GetByID(id uint, includeUnpublished bool, requestID string) (*BundleResponse, error) {
+	bundle, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bundle not found")
+		}
+		return nil, err
+	}
+	if !bundle.IsPublished && !includeUnpublished {
+		return nil, errors.New("bundle not found")
+	}
+
+	response := ToBundleResponse(bundle)
+	return &response, nil
+}
+
+// ==========================================
+// Admin Actions
+// ==========================================
+
+func (s *bundleService) Create(input CreateBundleInput, adminUserID uint, requestID string) (*BundleResponse, error) {
+	utils.LogInfo("bundles", "create", "Admin creating bundle", requestID, adminUserID, map[string]any{
+		"name": input.Name,
+		"type": input.Type,
+	})
+
+	bundle := entities.Bundle{
+		Name:            input.Name,
+		Description:     input.Description,
+		ImageURL:        input.ImageURL,
+		Type:            entities.BundleType(input.Type),
+		Price:           input.Price,
+		QrisImageURL:    input.QrisImageURL,
+		PaymentLink:     input.PaymentLink,
+		SaleStart:       input.SaleStart,
+		SaleEnd:         input.SaleEnd,
+		PassStart:       input.PassStart,
+		PassEnd:         input.PassEnd,
+		IsPublished:     input.IsPublished,
+		CreatedByUserID: adminUserID,
+	}
+	if err := validateBundle(bundle, input.TryOutIDs); err != nil {
+		return nil, err
+	}
+
+	tryOuts, courses, err := s.loadContents(input.TryOutIDs, input.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+	bundle.TryOuts = tryOuts
+	bundle.Courses = courses
+
+	if err := s.repo.Create(&bundle); err != nil {
+		utils.LogError("bundles", "create", "Failed to create bundle: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("bundles", "create", "Bundle created", requestID, adminUserID, map[string]any{
+		"bundle_id": bundle.ID,
+	})
+
+	response := ToBundleResponse(bundle)
+	return &response, nil
+}
+
+func (s *bundleService) Update(id uint, input UpdateBundleInput, requestID string) (*BundleResponse, error) {
+	bundle, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bundle not found")
+		}
+		return nil, err
+	}
+
+	if input.Name != nil {
+		bundle.Name = *input.Name
+	}
+	if input.Description != nil {
+		bundle.Description = *input.Description
+	}
+	if input.ImageURL != nil {
+		bundle.ImageURL = *input.ImageURL
+	}
+	if input.Price != nil {
+		bundle.Price = *input.Price
+	}
+	if input.QrisImageURL != nil {
+		bundle.QrisImageURL = *input.QrisImageURL
+	}
+	if input.PaymentLink != nil {
+		bundle.PaymentLink = *input.PaymentLink
+	}
+	if input.SaleStart != nil {
+		bundle.SaleStart = *input.SaleStart
+	}
+	if input.SaleEnd != nil {
+		bundle.SaleEnd = *input.SaleEnd
+	}
+	if input.PassStart != nil {
+		bundle.PassStart = input.PassStart
+	}
+	if input.PassEnd != nil {
+		bundle.PassEnd = input.PassEnd
+	}
+	if input.IsPublished != nil {
+		bundle.IsPublished = *input.IsPublished
+	}
+
+	tryOutIDs := make([]uint, 0, len(bundle.TryOuts))
+	for _, t := range bundle.TryOuts {
+		tryOutIDs = append(tryOutIDs, t.ID)
+	}
+	if input.TryOutIDs != nil {
+		tryOutIDs = *input.TryOutIDs
+	}
+	if err := validateBundle(bundle, tryOutIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&bundle); err != nil {
+		utils.LogError("bundles", "update", "Failed to update bundle: "+err.Error(), requestID, 0, map[string]any{
+			"bundle_id": id,
+		})
+		return nil, err
+	}
+
+	if input.TryOutIDs != nil || input.CourseIDs != nil {
+		courseIDs := make([]uint, 0, len(bundle.Courses))
+		for _, c := range bundle.Courses {
+			courseIDs = append(courseIDs, c.ID)
+		}
+		if input.CourseIDs != nil {
+			courseIDs = *input.CourseIDs
+		}
+		tryOuts, courses, err := s.loadContents(tryOutIDs, courseIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.ReplaceContents(&bundle, tryOuts, courses); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogSuccess("bundles", "update", "Bundle updated", requestID, 0, map[string]any{
+		"bundle_id": id,
+	})
+
+	response := ToBundleResponse(updated)
+	return &response, nil
+}
+
+func (s *bundleService) Delete(id uint, requestID string) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("bundle not found")
+		}
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		utils.LogError("bundles", "delete", "Failed to delete bundle: "+err.Error(), requestID, 0, map[string]any{
+			"bundle_id": id,
+		})
+		return err
+	}
+
+	utils.LogSuccess("bundles", "delete", "Bundle deleted", requestID, 0, map[string]any{
+		"bundle_id": id,
+	})
+	return nil
+}
+
+func (s *bundleService) GetPendingPurchases(requestID string) ([]PurchaseResponse, error) {
+	purchases, err := s.repo.FindPendingPurchases()
+	if err != nil {
+		utils.LogError("bundles", "get_pending", "Failed to fetch pending purchases: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]PurchaseResponse, 0, len(purchases))
+	for _, p := range purchases {
+		responses = append(responses, ToPurchaseResponse(p))
+	}
+	return responses, nil
+}
+
+func (s *bundleService) ApprovePurchase(purchaseID uint, adminUserID uint, requestID string) (*PurchaseResponse, error) {
+	utils.LogInfo("bundles", "approve", "Admin approving bundle purchase", requestID, adminUserID, map[string]any{
+		"purchase_id": purchaseID,
+	})
+
+	purchase, err := s.repo.FindPurchaseByID(purchaseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase not found")
+		}
+		return nil, err
+	}
+	if purchase.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+
+	tryOutIDs, courseIDs, err := s.coveredIDs(purchase.BundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Approval and access land together, so a failed grant leaves the purchase open for another try
+	now := time.Now()
+	purchase.PaymentStatus = entities.PaymentStatusApproved
+	purchase.RejectionReason = ""
+	purchase.ApprovedByUserID = &adminUserID
+	purchase.ApprovedAt = &now
+	granted, err := s.repo.ApprovePurchase(&purchase, adminUserID, tryOutIDs, courseIDs)
+	if err != nil {
+		utils.LogError("bundles", "approve", "Failed to approve purchase: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("bundles", "approve", "Bundle purchase approved", requestID, adminUserID, map[string]any{
+		"purchase_id":   purchaseID,
+		"user_id":       purchase.UserID,
+		"registrations": granted,
+	})
+
+	updated, err := s.repo.FindPurchaseByID(purchaseID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToPurchaseResponse(updated)
+	return &response, nil
+}
+
+func (s *bundleService) RejectPurchase(purchaseID uint, input RejectPurchaseInput, adminUserID uint, requestID string) (*PurchaseResponse, error) {
+	purchase, err := s.repo.FindPurchaseByID(purchaseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase not found")
+		}
+		return nil, err
+	}
+	if purchase.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+
+	purchase.PaymentStatus = entities.PaymentStatusRejected
+	purchase.RejectionReason = input.RejectionReason
+	if err := s.repo.UpdatePurchase(&purchase); err != nil {
+		utils.LogError("bundles", "reject", "Failed to reject purchase: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("bundles", "reject", "Bundle purchase rejected", requestID, adminUserID, map[string]any{
+		"purchase_id": purchaseID,
+		"reason":      input.RejectionReason,
+	})
+
+	response := ToPurchaseResponse(purchase)
+	return &response, nil
+}
+
+// ==========================================
+// User Actions
+// ==========================================
+
+func (s *bundleService) Purchase(bundleID uint, userID uint, requestID string) (*PurchaseResponse, error) {
+	utils.LogInfo("bundles", "purchase", "User purchasing bundle", requestID, userID, map[string]any{
+		"bundle_id": bundleID,
+	})
+
+	bundle, err := s.repo.FindByID(bundleID)
+	if err != nil || !bundle.IsPublished {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bundle not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(bundle.SaleStart) {
+		return nil, errors.New("bundle sale has not started yet")
+	}
+	if now.After(bundle.SaleEnd) {
+		return nil, errors.New("bundle sale has ended")
+	}
+
+	paymentStatus := entities.PaymentStatusPending
+	var approvedAt *time.Time
+	if bundle.Price == 0 {
+		paymentStatus = entities.PaymentStatusApproved
+		approvedAt = &now
+	}
+
+	purchase, err := s.repo.FindPurchaseByUserAndBundleUnscoped(userID, bundleID)
+	switch {
+	case err == nil && !purchase.DeletedAt.Valid:
+		return nil, errors.New("you have already purchased this bundle")
+	case err == nil:
+		// Was soft-deleted — restore it and reset status
+		if err := s.repo.RestorePurchase(purchase.ID); err != nil {
+			return nil, err
+		}
+		purchase.DeletedAt = gorm.DeletedAt{}
+		purchase.Amount = bundle.Price
+		purchase.PaymentStatus = paymentStatus
+		purchase.PaymentProofURL = ""
+		purchase.RejectionReason = ""
+		purchase.ApprovedByUserID = nil
+		purchase.ApprovedAt = approvedAt
+		purchase.PurchasedAt = now
+		if err := s.repo.UpdatePurchase(&purchase); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		purchase = entities.BundlePurchase{
+			UserID:        userID,
+			BundleID:      bundleID,
+			Amount:        bundle.Price,
+			PaymentStatus: paymentStatus,
+			ApprovedAt:    approvedAt,
+			PurchasedAt:   now,
+		}
+		if err := s.repo.CreatePurchase(&purchase); err != nil {
+			utils.LogError("bundles", "purchase", "Failed to create purchase: "+err.Error(), requestID, userID, nil)
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if paymentStatus == entities.PaymentStatusApproved {
+		if err := s.grantPurchase(purchase, requestID); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.LogSuccess("bundles", "purchase", "Bundle purchase created", requestID, userID, map[string]any{
+		"purchase_id": purchase.ID,
+		"status":      paymentStatus,
+	})
+
+	created, err := s.repo.FindPurchaseByID(purchase.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToPurchaseResponse(created)
+	return &response, nil
+}
+
+func (s *bundleService) UploadPaymentProof(purchaseID uint, input UploadPaymentProofInput, userID uint, requestID string) (*PurchaseResponse, error) {
+	purchase, err := s.repo.FindPurchaseByID(purchaseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase not found")
+		}
+		return nil, err
+	}
+
+	if purchase.UserID != userID {
+		return nil, errors.New("you can only upload payment proof for your own purchase")
+	}
+	if purchase.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+
+	purchase.PaymentProofURL = input.PaymentProofURL
+	purchase.PaymentStatus = entities.PaymentStatusPending
+	purchase.RejectionReason = ""
+	if err := s.repo.UpdatePurchase(&purchase); err != nil {
+		utils.LogError("bundles", "upload_proof", "Failed to save payment proof: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("bundles", "upload_proof", "Bundle payment proof uploaded", requestID, userID, map[string]any{
+		"purchase_id": purchaseID,
+	})
+
+	response := ToPurchaseResponse(purchase)
+	return &response, nil
+}
+
+func (s *bundleService) GetMyPurchases(userID uint, requestID string) ([]PurchaseResponse, error) {
+	purchases, err := s.repo.FindPurchasesByUserID(userID)
+	if err != nil {
+		utils.LogError("bundles", "get_my_purchases", "Failed to fetch purchases: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	responses := make([]PurchaseResponse, 0, len(purchases))
+	for _, p := range purchases {
+		responses = append(responses, ToPurchaseResponse(p))
+	}
+	return responses, nil
+}
+
+// ==========================================
+// Access Granting
+// ==========================================
+
+func (s *bundleService) GrantPublishedTryOut(tryOutID uint, requestID string) error {
+	tryOut, err := s.repo.FindTryOutByID(tryOutID)
+	if err != nil {
+		return err
+	}
+	if !tryOut.IsPublished {
+		return nil
+	}
+
+	purchases, err := s.repo.FindApprovedPurchasesCoveringTryOut(tryOut)
+	if err != nil {
+		utils.LogError("bundles", "grant_published", "Failed to find covering purchases: "+err.Error(), requestID, 0, map[string]any{
+			"try_out_id": tryOutID,
+		})
+		return err
+	}
+
+	total := 0
+	for _, purchase := range purchases {
+		granted, err := s.repo.GrantAccess(purchase, []uint{tryOutID}, nil)
+		if err != nil {
+			utils.LogError("bundles", "grant_published", "Failed to grant access: "+err.Error(), requestID, purchase.UserID, map[string]any{
+				"try_out_id":  tryOutID,
+				"purchase_id": purchase.ID,
+			})
+			return err
+		}
+		total += granted
+	}
+
+	utils.LogSuccess("bundles", "grant_published", "Granted bundle access to published try out", requestID, 0, map[string]any{
+		"try_out_id":    tryOutID,
+		"purchases":     len(purchases),
+		"registrations": total,
+	})
+	return nil
+}
+
+// grantPurchase creates registrations for everything the purchased bundle currently unlocks.
+// Try Outs published later are granted by GrantPublishedTryOut.
+func (s *bundleService) grantPurchase(purchase entities.BundlePurchase, requestID string) error {
+	tryOutIDs, courseIDs, err := s.coveredIDs(purchase.BundleID)
+	if err != nil {
+		return err
+	}
+
+	granted, err := s.repo.GrantAccess(purchase, tryOutIDs, courseIDs)
+	if err != nil {
+		utils.LogError("bundles", "grant", "Failed to grant bundle access: "+err.Error(), requestID, purchase.UserID, map[string]any{
+			"purchase_id": purchase.ID,
+		})
+		return err
+	}
+
+	utils.LogInfo("bundles", "grant", "Bundle access granted", requestID, purchase.UserID, map[string]any{
+		"purchase_id":   purchase.ID,
+		"registrations": granted,
+	})
+	return nil
+}
+
+// coveredIDs lists the Try Outs and Courses a bundle currently unlocks
+func (s *bundleService) coveredIDs(bundleID uint) ([]uint, []uint, error) {
+	bundle, err := s.repo.FindByID(bundleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tryOutIDs, err := s.repo.FindCoveredTryOutIDs(bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	courseIDs := make([]uint, 0, len(bundle.Courses))
+	for _, c := range bundle.Courses {
+		courseIDs = append(courseIDs, c.ID)
+	}
+	return tryOutIDs, courseIDs, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *bundleService) loadContents(tryOutIDs, courseIDs []uint) ([]entities.TryOut, []entities.Course, error) {
+	tryOuts, err := s.repo.FindTryOutsByIDs(tryOutIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tryOuts) != len(uniqueIDs(tryOutIDs)) {
+		return nil, nil, errors.New("one or more try outs not found")
+	}
+
+	courses, err := s.repo.FindCoursesByIDs(courseIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(courses) != len(uniqueIDs(courseIDs)) {
+		return nil, nil, errors.New("one or more courses not found")
+	}
+	return tryOuts, courses, nil
+}
+
+func validateBundle(bundle entities.Bundle, tryOutIDs []uint) error {
+	if !bundle.SaleEnd.After(bundle.SaleStart) {
+		return errors.New("sale end date must be after start date")
+	}
+
+	switch bundle.Type {
+	case entities.BundleTypePass:
+		if bundle.PassStart == nil || bundle.PassEnd == nil {
+			return errors.New("pass start and end dates are required for a pass")
+		}
+		if !bundle.PassEnd.After(*bundle.PassStart) {
+			return errors.New("pass end date must be after start date")
+		}
+		if len(tryOutIDs) > 0 {
+			return errors.New("a pass covers try outs by period and cannot list try outs")
+		}
+	case entities.BundleTypeBundle:
+		if bundle.PassStart != nil || bundle.PassEnd != nil {
+			return errors.New("pass dates are only allowed for a pass")
+		}
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
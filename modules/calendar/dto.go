@@ -0,0 +1,64 @@
+package calendar
+
+import "time"
+
+// ==========================================
+// CALENDAR DTOs
+// ==========================================
+
+// CalendarFilter selects the period (YYYY-MM-DD, inclusive); it defaults to the next 90 days
+type CalendarFilter struct {
+	From    string `form:"from"`
+	To      string `form:"to"`
+	TutorID uint   `form:"tutorId"` // teaching schedule only; admins may look at another tutor
+}
+
+// CalendarEvent is one lesson or try-out exam window
+type CalendarEvent struct {
+	UID           string     `json:"uid"`
+	Type          string     `json:"type"` // lesson, tryout or teaching
+	Title         string     `json:"title"`
+	Start         time.Time  `json:"start"`
+	End           *time.Time `json:"end,omitempty"`
+	CourseID      uint       `json:"courseId,omitempty"`
+	CourseName    string     `json:"courseName,omitempty"`
+	ClassID       uint       `json:"classId,omitempty"`
+	ClassName     string     `json:"className,omitempty"`
+	LessonID      uint       `json:"lessonId,omitempty"`
+	TryOutID      uint       `json:"tryOutId,omitempty"`
+	ConflictsWith []uint     `json:"conflictsWith,omitempty"` // other lessons of the same tutor at the same time
+}
+
+type CalendarResponse struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Events []CalendarEvent `json:"events"`
+}
+
+// LessonConflict is a pair of a tutor's lessons whose times overlap
+type LessonConflict struct {
+	LessonID         uint      `json:"lessonId"`
+	LessonTitle      string    `json:"lessonTitle"`
+	OtherLessonID    uint      `json:"otherLessonId"`
+	OtherLessonTitle string    `json:"otherLessonTitle"`
+	OverlapStart     time.Time `json:"overlapStart"`
+	OverlapEnd       time.Time `json:"overlapEnd"`
+}
+
+type TeachingScheduleResponse struct {
+	TutorID   uint             `json:"tutorId"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Events    []CalendarEvent  `json:"events"`
+	Conflicts []LessonConflict `json:"conflicts"`
+}
+
+// ==========================================
+// FEED DTOs
+// ==========================================
+
+// FeedURLResponse is the personal subscription URL; anyone holding it can read the calendar
+type FeedURLResponse struct {
+	URL      string    `json:"url"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
@@ -0,0 +1,157 @@
+package calendar
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetMyCalendarHandler(c *gin.Context)
+	GetTeachingScheduleHandler(c *gin.Context)
+	GetFeedURLHandler(c *gin.Context)
+	RotateFeedURLHandler(c *gin.Context)
+	FeedHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+// isPeriodError reports whether err comes from an invalid from/to filter
+func isPeriodError(err error) bool {
+	switch err.Error() {
+	case "from must be a date in YYYY-MM-DD format", "to must be a date in YYYY-MM-DD format",
+		"to must not be before from", "period must not be longer than one year":
+		return true
+	}
+	return false
+}
+
+// ==========================================
+// Calendar Handlers
+// ==========================================
+
+func (h *handler) GetMyCalendarHandler(c *gin.Context) {
+	var filter CalendarFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		return
+	}
+
+	calendar, err := h.service.GetMyCalendar(getUserID(c), filter, getRequestID(c))
+	if err != nil {
+		if isPeriodError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch calendar", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Calendar retrieved successfully", calendar))
+}
+
+func (h *handler) GetTeachingScheduleHandler(c *gin.Context) {
+	var filter CalendarFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		return
+	}
+
+	schedule, err := h.service.GetTeachingSchedule(getUserID(c), filter, isAdmin(c), getRequestID(c))
+	if err != nil {
+		switch {
+		case isPeriodError(err):
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		case err.Error() == "only admins can view another tutor's schedule":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch teaching schedule", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Teaching schedule retrieved successfully", schedule))
+}
+
+// ==========================================
+// Feed Handlers
+// ==========================================
+
+func (h *handler) GetFeedURLHandler(c *gin.Context) {
+	feed, err := h.service.GetFeedURL(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch calendar feed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Calendar feed retrieved successfully", feed))
+}
+
+func (h *handler) RotateFeedURLHandler(c *gin.Context) {
+	feed, err := h.service.RotateFeedURL(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to rotate calendar feed", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Calendar feed rotated successfully", feed))
+}
+
+// FeedHandler serves the .ics document to calendar apps; the token in the URL is the only credential
+func (h *handler) FeedHandler(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	body, err := h.service.RenderFeed(token, getRequestID(c))
+	if err != nil {
+		if err.Error() == "calendar feed not found" {
+			c.String(http.StatusNotFound, "calendar feed not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "failed to build calendar feed")
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="reduka.ics"`)
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
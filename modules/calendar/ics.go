@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// icsTimeLayout is the UTC date-time form of RFC 5545
+const icsTimeLayout = "20060102T150405Z"
+
+// icsEscaper escapes the characters RFC 5545 reserves in TEXT values
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// writeICS renders the events as an iCalendar document that calendar apps can subscribe to
+func writeICS(name string, events []CalendarEvent, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//Reduka//Calendar//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+icsEscaper.Replace(name))
+	// Ask subscribers to refresh hourly; Google Calendar may still poll less often
+	writeLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeLine(&buf, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format(icsTimeLayout)
+	for _, event := range events {
+		end := event.Start.Add(defaultLessonDuration)
+		if event.End != nil {
+			end = *event.End
+		}
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+event.UID+"@reduka")
+		writeLine(&buf, "DTSTAMP:"+stamp)
+		writeLine(&buf, "DTSTART:"+event.Start.UTC().Format(icsTimeLayout))
+		writeLine(&buf, "DTEND:"+end.UTC().Format(icsTimeLayout))
+		writeLine(&buf, "SUMMARY:"+icsEscaper.Replace(event.Title))
+		if description := describe(event); description != "" {
+			writeLine(&buf, "DESCRIPTION:"+icsEscaper.Replace(description))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func describe(event CalendarEvent) string {
+	var parts []string
+	if event.CourseName != "" {
+		parts = append(parts, "Course: "+event.CourseName)
+	}
+	if event.ClassName != "" {
+		parts = append(parts, "Class: "+event.ClassName)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// writeLine ends the line with CRLF and folds it so no line is longer than 75 octets
+func writeLine(buf *bytes.Buffer, line string) {
+	const limit = 75
+	for len(line) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindStudentLessons(userID uint, from, to time.Time) ([]entities.Lesson, error)
+	FindStudentTryOuts(userID uint, from, to time.Time) ([]entities.TryOut, error)
+	FindTeachingLessons(userID uint, from, to time.Time) ([]entities.Lesson, error)
+
+	FindTokenByUserID(userID uint) (entities.CalendarFeedToken, error)
+	FindTokenByToken(token string) (entities.CalendarFeedToken, error)
+	SaveToken(token *entities.CalendarFeedToken) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Event Methods
+// ==========================================
+
+// lessonsInRange selects scheduled lessons of live classes and courses that touch [from, to)
+func (r *repository) lessonsInRange(from, to time.Time) *gorm.DB {
+	return r.db.Model(&entities.Lesson{}).
+		Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL").
+		Joins("JOIN courses ON courses.id = classes.course_id AND courses.deleted_at IS NULL").
+		Where("lessons.start_time IS NOT NULL AND lessons.start_time < ? AND COALESCE(lessons.end_time, lessons.start_time) >= ?", to, from).
+		Preload("Class.Course").
+		Order("lessons.start_time ASC, lessons.id ASC")
+}
+
+// FindStudentLessons leaves out unpublished lessons; teaching calendars keep them
+func (r *repository) FindStudentLessons(userID uint, from, to time.Time) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	query := access.WhereLive(r.lessonsInRange(from, to), "lessons")
+	query = access.WhereReleased(access.WhereReleased(query, "classes"), "courses")
+	err := query.
+		Where("EXISTS (SELECT 1 FROM course_registrations cr WHERE cr.course_id = courses.id AND cr.user_id = ? AND cr.status = ? AND cr.deleted_at IS NULL)", userID, "approved").
+		Find(&lessons).Error
+	return lessons, err
+}
+
+func (r *repository) FindStudentTryOuts(userID uint, from, to time.Time) ([]entities.TryOut, error) {
+	var tryOuts []entities.TryOut
+	err := r.db.Joins("JOIN try_out_registrations tr ON tr.try_out_package_id = try_outs.id AND tr.deleted_at IS NULL").
+		Where("tr.user_id = ? AND tr.payment_status = ?", userID, entities.PaymentStatusApproved).
+		Where("try_outs.exam_start IS NOT NULL AND try_outs.exam_start < ? AND COALESCE(try_outs.exam_end, try_outs.exam_start) >= ?", to, from).
+		Order("try_outs.exam_start ASC").
+		Find(&tryOuts).Error
+	return tryOuts, err
+}
+
+// FindTeachingLessons returns lessons of courses the user created or is assigned to, whole course or that class
+func (r *repository) FindTeachingLessons(userID uint, from, to time.Time) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	err := r.lessonsInRange(from, to).
+		Where("courses.created_by_user_id = ? OR EXISTS (SELECT 1 FROM course_tutors ct WHERE ct.course_id = courses.id AND ct.user_id = ? AND (ct.class_id IS NULL OR ct.class_id = classes.id) AND ct.deleted_at IS NULL)", userID, userID).
+		Find(&lessons).Error
+	return lessons, err
+}
+
+// ==========================================
+// Feed Token Methods
+// ==========================================
+
+func (r *repository) FindTokenByUserID(userID uint) (entities.CalendarFeedToken, error) {
+	var token entities.CalendarFeedToken
+	err := r.db.Where("user_id = ?", userID).First(&token).Error
+	return token, err
+}
+
+func (r *repository) FindTokenByToken(token string) (entities.CalendarFeedToken, error) {
+	var feedToken entities.CalendarFeedToken
+	err := r.db.Where("token = ?", token).Preload("User").First(&feedToken).Error
+	return feedToken, err
+}
+
+func (r *repository) SaveToken(token *entities.CalendarFeedToken) error {
+	return r.db.Omit("User").Save(token).Error
+}
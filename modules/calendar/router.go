@@ -0,0 +1,21 @@
+package calendar
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func CalendarRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	handler := NewHandler(NewService(NewRepository(migrations.GetDB())))
+
+	calendar := router.Group("/calendar")
+	{
+		calendar.GET("/me", requireAuth, handler.GetMyCalendarHandler)
+		calendar.GET("/teaching", requireAuth, requireAdminOrTutor, handler.GetTeachingScheduleHandler)
+		calendar.GET("/feed-url", requireAuth, handler.GetFeedURLHandler)
+		calendar.POST("/feed-url/rotate", requireAuth, handler.RotateFeedURLHandler)
+
+		// Public: calendar apps subscribe with the tokenized URL, e.g. /calendar/feed/<token>.ics
+		calendar.GET("/feed/:token", handler.FeedHandler)
+	}
+}
@@ -0,0 +1,332 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// dateLayout is the format of the from/to filters
+	dateLayout = "2006-01-02"
+	// defaultRangeDays is how far ahead the calendar looks when no period is given
+	defaultRangeDays = 90
+	// maxRangeDays caps one request
+	maxRangeDays = 366
+	// defaultLessonDuration stands in for lessons scheduled without an end time
+	defaultLessonDuration = time.Hour
+	// feedPastDays and feedFutureDays bound the events published in the subscription feed
+	feedPastDays   = 30
+	feedFutureDays = 180
+)
+
+// Event types
+const (
+	EventLesson   = "lesson"
+	EventTryOut   = "tryout"
+	EventTeaching = "teaching"
+)
+
+type calendarService struct {
+	repo Repository
+}
+
+type Service interface {
+	GetMyCalendar(userID uint, filter CalendarFilter, requestID string) (*CalendarResponse, error)
+	GetTeachingSchedule(userID uint, filter CalendarFilter, isAdmin bool, requestID string) (*TeachingScheduleResponse, error)
+
+	GetFeedURL(userID uint, requestID string) (*FeedURLResponse, error)
+	RotateFeedURL(userID uint, requestID string) (*FeedURLResponse, error)
+	// RenderFeed builds the .ics document for the owner of token
+	RenderFeed(token string, requestID string) ([]byte, error)
+}
+
+func NewService(repo Repository) Service {
+	return &calendarService{repo: repo}
+}
+
+// ==========================================
+// Calendars
+// ==========================================
+
+func (s *calendarService) GetMyCalendar(userID uint, filter CalendarFilter, requestID string) (*CalendarResponse, error) {
+	utils.LogInfo("calendar", "get_my_calendar", "Fetching student calendar", requestID, userID, map[string]any{
+		"from": filter.From,
+		"to":   filter.To,
+	})
+
+	from, to, err := parsePeriod(filter)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.studentEvents(userID, from, to)
+	if err != nil {
+		utils.LogError("calendar", "get_my_calendar", "Failed to load events: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	return &CalendarResponse{From: from, To: to, Events: events}, nil
+}
+
+func (s *calendarService) GetTeachingSchedule(userID uint, filter CalendarFilter, isAdmin bool, requestID string) (*TeachingScheduleResponse, error) {
+	tutorID := userID
+	if filter.TutorID != 0 && filter.TutorID != userID {
+		if !isAdmin {
+			return nil, errors.New("only admins can view another tutor's schedule")
+		}
+		tutorID = filter.TutorID
+	}
+	utils.LogInfo("calendar", "get_teaching", "Fetching teaching schedule", requestID, userID, map[string]any{
+		"tutor_id": tutorID,
+	})
+
+	from, to, err := parsePeriod(filter)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := s.repo.FindTeachingLessons(tutorID, from, to)
+	if err != nil {
+		utils.LogError("calendar", "get_teaching", "Failed to load lessons: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	events := make([]CalendarEvent, 0, len(lessons))
+	for _, lesson := range lessons {
+		events = append(events, lessonEvent(lesson, EventTeaching))
+	}
+	conflicts := findConflicts(lessons)
+	conflicting := make(map[uint][]uint)
+	for _, conflict := range conflicts {
+		conflicting[conflict.LessonID] = append(conflicting[conflict.LessonID], conflict.OtherLessonID)
+		conflicting[conflict.OtherLessonID] = append(conflicting[conflict.OtherLessonID], conflict.LessonID)
+	}
+	for i := range events {
+		events[i].ConflictsWith = conflicting[events[i].LessonID]
+	}
+
+	if len(conflicts) > 0 {
+		utils.LogWarning("calendar", "get_teaching", "Overlapping lessons found", requestID, userID, map[string]any{
+			"tutor_id":  tutorID,
+			"conflicts": len(conflicts),
+		})
+	}
+	return &TeachingScheduleResponse{TutorID: tutorID, From: from, To: to, Events: events, Conflicts: conflicts}, nil
+}
+
+// ==========================================
+// iCalendar Feed
+// ==========================================
+
+func (s *calendarService) GetFeedURL(userID uint, requestID string) (*FeedURLResponse, error) {
+	token, err := s.repo.FindTokenByUserID(userID)
+	if err == nil {
+		return toFeedURLResponse(token), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.RotateFeedURL(userID, requestID)
+}
+
+// RotateFeedURL issues a new token; subscriptions to the previous URL stop updating
+func (s *calendarService) RotateFeedURL(userID uint, requestID string) (*FeedURLResponse, error) {
+	utils.LogInfo("calendar", "rotate_feed", "Issuing calendar feed token", requestID, userID, nil)
+
+	token, err := s.repo.FindTokenByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	token.UserID = userID
+	token.Token = secret
+	if err := s.repo.SaveToken(&token); err != nil {
+		utils.LogError("calendar", "rotate_feed", "Failed to save feed token: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("calendar", "rotate_feed", "Calendar feed token issued", requestID, userID, nil)
+	return toFeedURLResponse(token), nil
+}
+
+func (s *calendarService) RenderFeed(token string, requestID string) ([]byte, error) {
+	feedToken, err := s.repo.FindTokenByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar feed not found")
+		}
+		return nil, err
+	}
+	userID := feedToken.UserID
+
+	now := time.Now()
+	from := now.AddDate(0, 0, -feedPastDays)
+	to := now.AddDate(0, 0, feedFutureDays)
+	events, err := s.studentEvents(userID, from, to)
+	if err != nil {
+		utils.LogError("calendar", "render_feed", "Failed to load events: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	// Tutors also get the lessons they teach; a lesson they are enrolled in appears once
+	teaching, err := s.repo.FindTeachingLessons(userID, from, to)
+	if err != nil {
+		utils.LogError("calendar", "render_feed", "Failed to load teaching lessons: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		seen[event.UID] = true
+	}
+	for _, lesson := range teaching {
+		event := lessonEvent(lesson, EventTeaching)
+		if !seen[event.UID] {
+			events = append(events, event)
+		}
+	}
+	sortEvents(events)
+
+	return writeICS("Reduka - "+feedToken.User.Username, events, now), nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *calendarService) studentEvents(userID uint, from, to time.Time) ([]CalendarEvent, error) {
+	lessons, err := s.repo.FindStudentLessons(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	tryOuts, err := s.repo.FindStudentTryOuts(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]CalendarEvent, 0, len(lessons)+len(tryOuts))
+	for _, lesson := range lessons {
+		events = append(events, lessonEvent(lesson, EventLesson))
+	}
+	for _, tryOut := range tryOuts {
+		events = append(events, CalendarEvent{
+			UID:      fmt.Sprintf("tryout-%d", tryOut.ID),
+			Type:     EventTryOut,
+			Title:    "Try Out: " + tryOut.Name,
+			Start:    *tryOut.ExamStart,
+			End:      tryOut.ExamEnd,
+			TryOutID: tryOut.ID,
+		})
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+func lessonEvent(lesson entities.Lesson, eventType string) CalendarEvent {
+	return CalendarEvent{
+		UID:        fmt.Sprintf("lesson-%d", lesson.ID),
+		Type:       eventType,
+		Title:      lesson.Title,
+		Start:      *lesson.StartTime,
+		End:        lesson.EndTime,
+		CourseID:   lesson.Class.CourseID,
+		CourseName: lesson.Class.Course.NameCourse,
+		ClassID:    lesson.ClassID,
+		ClassName:  lesson.Class.Name,
+		LessonID:   lesson.ID,
+	}
+}
+
+func sortEvents(events []CalendarEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+}
+
+// findConflicts reports every pair of lessons whose times overlap. The lessons come sorted by
+// start time, so each lesson only needs comparing with the ones starting before it ends.
+func findConflicts(lessons []entities.Lesson) []LessonConflict {
+	conflicts := []LessonConflict{}
+	for i, lesson := range lessons {
+		end := lessonEnd(lesson)
+		for _, other := range lessons[i+1:] {
+			if !other.StartTime.Before(end) {
+				break
+			}
+			overlapEnd := lessonEnd(other)
+			if end.Before(overlapEnd) {
+				overlapEnd = end
+			}
+			conflicts = append(conflicts, LessonConflict{
+				LessonID:         lesson.ID,
+				LessonTitle:      lesson.Title,
+				OtherLessonID:    other.ID,
+				OtherLessonTitle: other.Title,
+				OverlapStart:     *other.StartTime,
+				OverlapEnd:       overlapEnd,
+			})
+		}
+	}
+	return conflicts
+}
+
+func lessonEnd(lesson entities.Lesson) time.Time {
+	if lesson.EndTime != nil && lesson.EndTime.After(*lesson.StartTime) {
+		return *lesson.EndTime
+	}
+	return lesson.StartTime.Add(defaultLessonDuration)
+}
+
+// parsePeriod turns the inclusive YYYY-MM-DD filter into a [from, to) range
+func parsePeriod(filter CalendarFilter) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if filter.From != "" {
+		parsed, err := time.ParseInLocation(dateLayout, filter.From, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultRangeDays)
+	if filter.To != "" {
+		parsed, err := time.ParseInLocation(dateLayout, filter.To, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("period must not be longer than one year")
+	}
+	return from, to, nil
+}
+
+func toFeedURLResponse(token entities.CalendarFeedToken) *FeedURLResponse {
+	url := "/api/v1/calendar/feed/" + token.Token + ".ics"
+	if baseURL := strings.TrimRight(os.Getenv("API_BASE_URL"), "/"); baseURL != "" {
+		url = baseURL + url
+	}
+	return &FeedURLResponse{URL: url, IssuedAt: token.UpdatedAt}
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
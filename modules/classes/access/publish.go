@@ -0,0 +1,56 @@
+package access
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+// IsLive reports whether content is public at now: published, or a draft whose scheduled
+// publish time has passed. Rows created before publishing existed have an empty status and
+// count as published.
+func IsLive(status string, publishAt *time.Time, now time.Time) bool {
+	switch status {
+	case entities.ContentPublished, "":
+		return true
+	case entities.ContentDraft:
+		return publishAt != nil && !publishAt.After(now)
+	}
+	return false
+}
+
+// IsReleased reports whether content has ever gone out: live or archived. Archived courses
+// and classes stay readable for students who registered while they were live.
+func IsReleased(status string, publishAt *time.Time, now time.Time) bool {
+	return status == entities.ContentArchived || IsLive(status, publishAt, now)
+}
+
+// WhereLive limits query to live rows of table, the SQL form of IsLive
+func WhereLive(query *gorm.DB, table string) *gorm.DB {
+	return query.Where("("+table+".status = ? OR ("+table+".status = ? AND "+table+".publish_at <= ?))",
+		entities.ContentPublished, entities.ContentDraft, time.Now())
+}
+
+// WhereReleased limits query to released rows of table, the SQL form of IsReleased
+func WhereReleased(query *gorm.DB, table string) *gorm.DB {
+	return query.Where("("+table+".status IN ? OR ("+table+".status = ? AND "+table+".publish_at <= ?))",
+		[]string{entities.ContentPublished, entities.ContentArchived}, entities.ContentDraft, time.Now())
+}
+
+// ApplyPublishing applies a requested status and publish time to content. A new status
+// replaces the publish time along with it; only drafts keep a scheduled publish time.
+func ApplyPublishing(status *string, publishAt **time.Time, newStatus *string, newPublishAt *time.Time) {
+	if newStatus != nil {
+		*status = *newStatus
+		*publishAt = newPublishAt
+	} else if newPublishAt != nil {
+		*publishAt = newPublishAt
+	}
+	if *status == "" {
+		*status = entities.ContentPublished
+	}
+	if *status != entities.ContentDraft {
+		*publishAt = nil
+	}
+}
@@ -0,0 +1,51 @@
+package access
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindClassByID(classID uint) (entities.Class, error)
+	FindLessonByID(lessonID uint) (entities.Lesson, error)
+	HasApprovedRegistration(userID, courseID uint) (bool, error)
+	IsAssignedTutor(userID, courseID, classID uint) (bool, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindClassByID(classID uint) (entities.Class, error) {
+	var class entities.Class
+	err := r.db.Preload("Course").First(&class, classID).Error
+	return class, err
+}
+
+func (r *repository) FindLessonByID(lessonID uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.Preload("Class.Course").First(&lesson, lessonID).Error
+	return lesson, err
+}
+
+func (r *repository) HasApprovedRegistration(userID, courseID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.CourseRegistration{}).
+		Where("user_id = ? AND course_id = ? AND status = ?", userID, courseID, "approved").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// IsAssignedTutor reports whether the user tutors the whole course or the given class
+func (r *repository) IsAssignedTutor(userID, courseID, classID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.CourseTutor{}).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Where("class_id IS NULL OR class_id = ?", classID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,124 @@
+package access
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+// Viewer is the caller asking for class content; UserID is 0 for anonymous callers
+type Viewer struct {
+	UserID uint
+	Role   string
+}
+
+type accessService struct {
+	repo Repository
+}
+
+// Service decides who may see the content of a course's lessons (descriptions and resource URLs).
+// Free courses are open to everyone; paid ones only to admins, the course creator, tutors
+// assigned to the course or class, and students with an approved registration.
+// Everyone else gets the public outline. Staff (admins, the creator and assigned tutors)
+// preview drafts; everyone else only sees live content, and archived content stays open
+// to registered students.
+type Service interface {
+	CanViewClass(classID uint, viewer Viewer) (bool, error)
+	CanViewLesson(lessonID uint, viewer Viewer) (bool, error)
+	// ClassVisibility reports whether the class shows up for the viewer at all and whether
+	// the viewer previews unpublished content of it
+	ClassVisibility(classID uint, viewer Viewer) (visible bool, preview bool, err error)
+}
+
+func NewService(repo Repository) Service {
+	return &accessService{repo: repo}
+}
+
+func (s *accessService) CanViewClass(classID uint, viewer Viewer) (bool, error) {
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("class not found")
+		}
+		return false, err
+	}
+	return s.canView(class, nil, viewer)
+}
+
+func (s *accessService) CanViewLesson(lessonID uint, viewer Viewer) (bool, error) {
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("lesson not found")
+		}
+		return false, err
+	}
+	return s.canView(lesson.Class, &lesson, viewer)
+}
+
+func (s *accessService) ClassVisibility(classID uint, viewer Viewer) (bool, bool, error) {
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, false, errors.New("class not found")
+		}
+		return false, false, err
+	}
+
+	staff, err := s.isStaff(class, viewer)
+	if err != nil || staff {
+		return staff, staff, err
+	}
+
+	course, now := class.Course, time.Now()
+	if IsLive(course.Status, course.PublishAt, now) && IsLive(class.Status, class.PublishAt, now) {
+		return true, false, nil
+	}
+	if !IsReleased(course.Status, course.PublishAt, now) || !IsReleased(class.Status, class.PublishAt, now) || viewer.UserID == 0 {
+		return false, false, nil
+	}
+	registered, err := s.repo.HasApprovedRegistration(viewer.UserID, course.ID)
+	return registered, false, err
+}
+
+// canView checks content access to the class, and to the lesson when one is given
+func (s *accessService) canView(class entities.Class, lesson *entities.Lesson, viewer Viewer) (bool, error) {
+	staff, err := s.isStaff(class, viewer)
+	if err != nil || staff {
+		return staff, err
+	}
+
+	course, now := class.Course, time.Now()
+	if lesson != nil && !IsLive(lesson.Status, lesson.PublishAt, now) {
+		return false, nil
+	}
+	if !IsReleased(course.Status, course.PublishAt, now) || !IsReleased(class.Status, class.PublishAt, now) {
+		return false, nil
+	}
+	if course.IsFree && IsLive(course.Status, course.PublishAt, now) && IsLive(class.Status, class.PublishAt, now) {
+		return true, nil
+	}
+	if viewer.UserID == 0 {
+		return false, nil
+	}
+	return s.repo.HasApprovedRegistration(viewer.UserID, course.ID)
+}
+
+// isStaff reports whether the viewer runs the class: an admin, the course creator or an assigned tutor
+func (s *accessService) isStaff(class entities.Class, viewer Viewer) (bool, error) {
+	if viewer.Role == "ADMIN" {
+		return true, nil
+	}
+	if viewer.UserID == 0 {
+		return false, nil
+	}
+	if class.Course.CreatedByUserID == viewer.UserID {
+		return true, nil
+	}
+	if viewer.Role == "TUTOR" {
+		return s.repo.IsAssignedTutor(viewer.UserID, class.Course.ID, class.ID)
+	}
+	return false, nil
+}
@@ -0,0 +1,129 @@
+package assignments
+
+import "time"
+
+// ==========================================
+// ASSIGNMENT DTOs
+// ==========================================
+
+// FileInput is a file registered through the uploads endpoint
+type FileInput struct {
+	Name string `json:"name" binding:"max=255"`
+	URL  string `json:"url" binding:"required,url"`
+}
+
+type CreateAssignmentInput struct {
+	LessonID     *uint       `json:"lessonId"` // must be a lesson of the same class
+	Title        string      `json:"title" binding:"required,max=255"`
+	Instructions string      `json:"instructions"`
+	DueAt        *time.Time  `json:"dueAt"`
+	AllowLate    bool        `json:"allowLate"`
+	MaxScore     float64     `json:"maxScore" binding:"omitempty,gt=0"` // default 100
+	Attachments  []FileInput `json:"attachments" binding:"omitempty,max=20,dive"`
+}
+
+type UpdateAssignmentInput struct {
+	LessonID     *uint        `json:"lessonId"`
+	Title        *string      `json:"title" binding:"omitempty,max=255"`
+	Instructions *string      `json:"instructions"`
+	DueAt        *time.Time   `json:"dueAt"`
+	AllowLate    *bool        `json:"allowLate"`
+	MaxScore     *float64     `json:"maxScore" binding:"omitempty,gt=0"`
+	Attachments  *[]FileInput `json:"attachments" binding:"omitempty,max=20,dive"` // replaces the current attachments
+}
+
+type FileResponse struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type AssignmentResponse struct {
+	ID           uint           `json:"id"`
+	ClassID      uint           `json:"classId"`
+	LessonID     *uint          `json:"lessonId,omitempty"`
+	Title        string         `json:"title"`
+	Instructions string         `json:"instructions"`
+	DueAt        *time.Time     `json:"dueAt,omitempty"`
+	AllowLate    bool           `json:"allowLate"`
+	MaxScore     float64        `json:"maxScore"`
+	Attachments  []FileResponse `json:"attachments"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+// ==========================================
+// SUBMISSION DTOs
+// ==========================================
+
+type SubmitInput struct {
+	Text  string      `json:"text"`
+	Files []FileInput `json:"files" binding:"omitempty,max=10,dive"`
+}
+
+type GradeInput struct {
+	Score    *float64 `json:"score" binding:"required,min=0"`
+	Feedback string   `json:"feedback"`
+}
+
+type SubmissionResponse struct {
+	ID           uint           `json:"id"`
+	AssignmentID uint           `json:"assignmentId"`
+	UserID       uint           `json:"userId"`
+	Username     string         `json:"username,omitempty"`
+	Email        string         `json:"email,omitempty"`
+	Text         string         `json:"text"`
+	Files        []FileResponse `json:"files"`
+	Status       string         `json:"status"`
+	SubmittedAt  time.Time      `json:"submittedAt"`
+	IsLate       bool           `json:"isLate"`
+	Score        *float64       `json:"score,omitempty"`
+	Feedback     string         `json:"feedback,omitempty"`
+	GradedAt     *time.Time     `json:"gradedAt,omitempty"`
+}
+
+// AssignmentSubmissionsResponse is the tutor's list of submissions for one assignment
+type AssignmentSubmissionsResponse struct {
+	AssignmentID uint                 `json:"assignmentId"`
+	Enrolled     int                  `json:"enrolled"`
+	Submitted    int                  `json:"submitted"`
+	Graded       int                  `json:"graded"`
+	Missing      int                  `json:"missing"`
+	Submissions  []SubmissionResponse `json:"submissions"`
+}
+
+// ==========================================
+// GRADEBOOK DTOs
+// ==========================================
+
+type GradebookAssignment struct {
+	ID       uint       `json:"id"`
+	Title    string     `json:"title"`
+	DueAt    *time.Time `json:"dueAt,omitempty"`
+	MaxScore float64    `json:"maxScore"`
+}
+
+// GradebookCell is one student's result for one assignment; Status is "missing" when nothing was handed in
+type GradebookCell struct {
+	AssignmentID uint     `json:"assignmentId"`
+	Status       string   `json:"status"`
+	IsLate       bool     `json:"isLate"`
+	Score        *float64 `json:"score,omitempty"`
+}
+
+type GradebookRow struct {
+	UserID         uint            `json:"userId"`
+	Username       string          `json:"username"`
+	Email          string          `json:"email"`
+	Cells          []GradebookCell `json:"cells"`
+	TotalScore     float64         `json:"totalScore"`
+	GradedMax      float64         `json:"gradedMax"`
+	Percentage     float64         `json:"percentage"` // over graded assignments only
+	Missing        int             `json:"missing"`
+	AwaitingGrades int             `json:"awaitingGrades"`
+}
+
+type GradebookResponse struct {
+	ClassID     uint                  `json:"classId"`
+	ClassName   string                `json:"className"`
+	Assignments []GradebookAssignment `json:"assignments"`
+	Students    []GradebookRow        `json:"students"`
+}
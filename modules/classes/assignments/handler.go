@@ -0,0 +1,282 @@
+package assignments
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetAssignmentsByClassHandler(c *gin.Context)
+	GetAssignmentByIDHandler(c *gin.Context)
+	CreateAssignmentHandler(c *gin.Context)
+	UpdateAssignmentHandler(c *gin.Context)
+	DeleteAssignmentHandler(c *gin.Context)
+
+	SubmitHandler(c *gin.Context)
+	GetMySubmissionHandler(c *gin.Context)
+	GetSubmissionsHandler(c *gin.Context)
+	GradeSubmissionHandler(c *gin.Context)
+	GetGradebookHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+// getViewer identifies the caller for content access
+func getViewer(c *gin.Context) access.Viewer {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return access.Viewer{UserID: getUserID(c), Role: roleStr}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps the shared lookup, validation and authorization errors
+func respondError(c *gin.Context, title string, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "assignment not found" || msg == "submission not found" || msg == "class not found" || msg == "lesson not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", msg, nil))
+	case tutors.IsAuthorizationError(err) || msg == "you are not enrolled in this course":
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", msg, nil))
+	case msg == "the due date for this assignment has passed" || msg == "submission has already been graded":
+		c.JSON(http.StatusConflict, utils.BuildResponseFailed(title, msg, nil))
+	case msg == "lesson does not belong to this class" || msg == "submission must contain text or files" ||
+		msg == "score exceeds the maximum score of the assignment" || strings.HasPrefix(msg, "file has not been uploaded") ||
+		strings.HasPrefix(msg, "file was not uploaded by you"):
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed(title, msg, nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed(title, msg, nil))
+	}
+}
+
+// ==========================================
+// Assignment Handlers
+// ==========================================
+
+func (h *handler) GetAssignmentsByClassHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.service.GetByClassID(classID, getViewer(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch assignments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Assignments retrieved successfully", assignments))
+}
+
+func (h *handler) GetAssignmentByIDHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	assignment, err := h.service.GetByID(id, getViewer(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch assignment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Assignment retrieved successfully", assignment))
+}
+
+func (h *handler) CreateAssignmentHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CreateAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	assignment, err := h.service.Create(classID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to create assignment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Assignment created successfully", assignment))
+}
+
+func (h *handler) UpdateAssignmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	assignment, err := h.service.Update(id, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to update assignment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Assignment updated successfully", assignment))
+}
+
+func (h *handler) DeleteAssignmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, getRequestID(c), getUserID(c), isAdmin(c)); err != nil {
+		respondError(c, "Failed to delete assignment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Assignment deleted successfully", nil))
+}
+
+// ==========================================
+// Submission Handlers
+// ==========================================
+
+func (h *handler) SubmitHandler(c *gin.Context) {
+	assignmentID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input SubmitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	submission, err := h.service.Submit(assignmentID, input, getRequestID(c), getUserID(c))
+	if err != nil {
+		respondError(c, "Failed to submit assignment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Assignment submitted successfully", submission))
+}
+
+func (h *handler) GetMySubmissionHandler(c *gin.Context) {
+	assignmentID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	submission, err := h.service.GetMySubmission(assignmentID, getRequestID(c), getUserID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch submission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Submission retrieved successfully", submission))
+}
+
+func (h *handler) GetSubmissionsHandler(c *gin.Context) {
+	assignmentID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	submissions, err := h.service.GetSubmissions(assignmentID, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to fetch submissions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Submissions retrieved successfully", submissions))
+}
+
+func (h *handler) GradeSubmissionHandler(c *gin.Context) {
+	submissionID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input GradeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	submission, err := h.service.Grade(submissionID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to grade submission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Submission graded successfully", submission))
+}
+
+func (h *handler) GetGradebookHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	gradebook, err := h.service.GetGradebook(classID, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to build gradebook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Gradebook retrieved successfully", gradebook))
+}
@@ -0,0 +1,180 @@
+package assignments
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindClassByID(id uint) (entities.Class, error)
+	FindLessonByID(id uint) (entities.Lesson, error)
+	FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error)
+	FindApprovedRegistration(courseID, userID uint) (entities.CourseRegistration, error)
+
+	FindByID(id uint) (entities.Assignment, error)
+	FindByClassID(classID uint) ([]entities.Assignment, error)
+	Create(assignment *entities.Assignment) error
+	// Update saves the assignment and, when attachments is not nil, replaces its attachments
+	Update(assignment *entities.Assignment, attachments []entities.AssignmentAttachment) error
+	Delete(id uint) error
+
+	FindSubmissionByID(id uint) (entities.AssignmentSubmission, error)
+	FindSubmission(assignmentID, userID uint) (entities.AssignmentSubmission, error)
+	FindSubmissionsByAssignmentID(assignmentID uint) ([]entities.AssignmentSubmission, error)
+	FindSubmissionsByClassID(classID uint) ([]entities.AssignmentSubmission, error)
+	// SaveSubmission creates or updates the submission and replaces its files
+	SaveSubmission(submission *entities.AssignmentSubmission, files []entities.SubmissionFile) error
+	UpdateSubmission(submission *entities.AssignmentSubmission) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Class & Registration Methods
+// ==========================================
+
+func (r *repository) FindClassByID(id uint) (entities.Class, error) {
+	var class entities.Class
+	err := r.db.First(&class, id).Error
+	return class, err
+}
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.First(&lesson, id).Error
+	return lesson, err
+}
+
+func (r *repository) FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND status = ?", courseID, "approved").
+		Preload("User").
+		Order("id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindApprovedRegistration(courseID, userID uint) (entities.CourseRegistration, error) {
+	var registration entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND user_id = ? AND status = ?", courseID, userID, "approved").
+		First(&registration).Error
+	return registration, err
+}
+
+// ==========================================
+// Assignment Methods
+// ==========================================
+
+func (r *repository) FindByID(id uint) (entities.Assignment, error) {
+	var assignment entities.Assignment
+	err := r.db.Preload("Class").Preload("Attachments").First(&assignment, id).Error
+	return assignment, err
+}
+
+func (r *repository) FindByClassID(classID uint) ([]entities.Assignment, error) {
+	var assignments []entities.Assignment
+	err := r.db.Where("class_id = ?", classID).
+		Preload("Attachments").
+		Order("due_at ASC NULLS LAST, id ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *repository) Create(assignment *entities.Assignment) error {
+	return r.db.Omit("Class", "Lesson").Create(assignment).Error
+}
+
+func (r *repository) Update(assignment *entities.Assignment, attachments []entities.AssignmentAttachment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Class", "Lesson", "Attachments").Save(assignment).Error; err != nil {
+			return err
+		}
+		if attachments == nil {
+			return nil
+		}
+		if err := tx.Where("assignment_id = ?", assignment.ID).Delete(&entities.AssignmentAttachment{}).Error; err != nil {
+			return err
+		}
+		for i := range attachments {
+			attachments[i].AssignmentID = assignment.ID
+		}
+		if len(attachments) > 0 {
+			if err := tx.Create(&attachments).Error; err != nil {
+				return err
+			}
+		}
+		assignment.Attachments = attachments
+		return nil
+	})
+}
+
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entities.Assignment{}, id).Error
+}
+
+// ==========================================
+// Submission Methods
+// ==========================================
+
+func (r *repository) FindSubmissionByID(id uint) (entities.AssignmentSubmission, error) {
+	var submission entities.AssignmentSubmission
+	err := r.db.Preload("Assignment.Class").Preload("User").Preload("Files").First(&submission, id).Error
+	return submission, err
+}
+
+func (r *repository) FindSubmission(assignmentID, userID uint) (entities.AssignmentSubmission, error) {
+	var submission entities.AssignmentSubmission
+	err := r.db.Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		Preload("Files").
+		First(&submission).Error
+	return submission, err
+}
+
+func (r *repository) FindSubmissionsByAssignmentID(assignmentID uint) ([]entities.AssignmentSubmission, error) {
+	var submissions []entities.AssignmentSubmission
+	err := r.db.Where("assignment_id = ?", assignmentID).
+		Preload("User").
+		Preload("Files").
+		Order("submitted_at ASC").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+func (r *repository) FindSubmissionsByClassID(classID uint) ([]entities.AssignmentSubmission, error) {
+	var submissions []entities.AssignmentSubmission
+	err := r.db.Joins("JOIN assignments ON assignments.id = assignment_submissions.assignment_id AND assignments.deleted_at IS NULL").
+		Where("assignments.class_id = ?", classID).
+		Find(&submissions).Error
+	return submissions, err
+}
+
+func (r *repository) SaveSubmission(submission *entities.AssignmentSubmission, files []entities.SubmissionFile) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Assignment", "User", "Files").Save(submission).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("submission_id = ?", submission.ID).Delete(&entities.SubmissionFile{}).Error; err != nil {
+			return err
+		}
+		for i := range files {
+			files[i].SubmissionID = submission.ID
+		}
+		if len(files) > 0 {
+			if err := tx.Create(&files).Error; err != nil {
+				return err
+			}
+		}
+		submission.Files = files
+		return nil
+	})
+}
+
+func (r *repository) UpdateSubmission(submission *entities.AssignmentSubmission) error {
+	return r.db.Omit("Assignment", "User", "Files").Save(submission).Error
+}
@@ -0,0 +1,36 @@
+package assignments
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/modules/uploads"
+)
+
+func AssignmentRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	db := migrations.GetDB()
+	service := NewService(NewRepository(db), access.NewService(access.NewRepository(db)), tutors.NewService(tutors.NewRepository(db)), uploads.NewService(uploads.NewRepository(db)))
+	handler := NewHandler(service)
+
+	classAssignments := router.Group("/classes/:id")
+	classAssignments.Use(requireAuth)
+	{
+		classAssignments.GET("/assignments", handler.GetAssignmentsByClassHandler)
+		classAssignments.POST("/assignments", requireAdminOrTutor, handler.CreateAssignmentHandler)
+		classAssignments.GET("/gradebook", requireAdminOrTutor, handler.GetGradebookHandler)
+	}
+
+	assignments := router.Group("/assignments/:id")
+	assignments.Use(requireAuth)
+	{
+		assignments.GET("", handler.GetAssignmentByIDHandler)
+		assignments.PUT("", requireAdminOrTutor, handler.UpdateAssignmentHandler)
+		assignments.DELETE("", requireAdminOrTutor, handler.DeleteAssignmentHandler)
+		assignments.POST("/submissions", handler.SubmitHandler)
+		assignments.GET("/submissions", requireAdminOrTutor, handler.GetSubmissionsHandler)
+		assignments.GET("/submissions/me", handler.GetMySubmissionHandler)
+	}
+
+	router.PUT("/submissions/:id/grade", requireAuth, requireAdminOrTutor, handler.GradeSubmissionHandler)
+}
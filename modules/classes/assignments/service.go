@@ -0,0 +1,600 @@
+package assignments
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/modules/uploads"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+// defaultMaxScore is used when the tutor does not set a maximum score
+const defaultMaxScore = 100.0
+
+type assignmentService struct {
+	repo    Repository
+	access  access.Service
+	tutors  tutors.Service
+	uploads uploads.Service
+}
+
+type Service interface {
+	GetByClassID(classID uint, viewer access.Viewer, requestID string) ([]AssignmentResponse, error)
+	GetByID(id uint, viewer access.Viewer, requestID string) (*AssignmentResponse, error)
+	Create(classID uint, input CreateAssignmentInput, requestID string, userID uint, isAdmin bool) (*AssignmentResponse, error)
+	Update(id uint, input UpdateAssignmentInput, requestID string, userID uint, isAdmin bool) (*AssignmentResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
+
+	Submit(assignmentID uint, input SubmitInput, requestID string, userID uint) (*SubmissionResponse, error)
+	GetMySubmission(assignmentID uint, requestID string, userID uint) (*SubmissionResponse, error)
+	GetSubmissions(assignmentID uint, requestID string, userID uint, isAdmin bool) (*AssignmentSubmissionsResponse, error)
+	Grade(submissionID uint, input GradeInput, requestID string, userID uint, isAdmin bool) (*SubmissionResponse, error)
+	GetGradebook(classID uint, requestID string, userID uint, isAdmin bool) (*GradebookResponse, error)
+}
+
+func NewService(repo Repository, accessService access.Service, tutorService tutors.Service, uploadService uploads.Service) Service {
+	return &assignmentService{repo: repo, access: accessService, tutors: tutorService, uploads: uploadService}
+}
+
+// ==========================================
+// Assignments
+// ==========================================
+
+func (s *assignmentService) GetByClassID(classID uint, viewer access.Viewer, requestID string) ([]AssignmentResponse, error) {
+	utils.LogInfo("assignments", "get_by_class", "Fetching assignments for class", requestID, viewer.UserID, map[string]any{
+		"class_id": classID,
+	})
+
+	canView, err := s.access.CanViewClass(classID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.New("you are not enrolled in this course")
+	}
+
+	assignments, err := s.repo.FindByClassID(classID)
+	if err != nil {
+		utils.LogError("assignments", "get_by_class", "Failed to fetch assignments: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+
+	responses := make([]AssignmentResponse, 0, len(assignments))
+	for _, assignment := range assignments {
+		responses = append(responses, toAssignmentResponse(assignment))
+	}
+	return responses, nil
+}
+
+func (s *assignmentService) GetByID(id uint, viewer access.Viewer, requestID string) (*AssignmentResponse, error) {
+	utils.LogInfo("assignments", "get_by_id", "Fetching assignment by ID", requestID, viewer.UserID, map[string]any{
+		"assignment_id": id,
+	})
+
+	assignment, err := s.findAssignment(id)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.access.CanViewClass(assignment.ClassID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.New("you are not enrolled in this course")
+	}
+
+	response := toAssignmentResponse(assignment)
+	return &response, nil
+}
+
+func (s *assignmentService) Create(classID uint, input CreateAssignmentInput, requestID string, userID uint, isAdmin bool) (*AssignmentResponse, error) {
+	utils.LogInfo("assignments", "create", "Creating new assignment", requestID, userID, map[string]any{
+		"class_id": classID,
+		"title":    input.Title,
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		utils.LogWarning("assignments", "create", "Assignment creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": classID,
+		})
+		return nil, err
+	}
+	if err := s.checkLesson(classID, input.LessonID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.checkFiles(input.Attachments, 0, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &entities.Assignment{
+		ClassID:         classID,
+		LessonID:        input.LessonID,
+		CreatedByUserID: userID,
+		Title:           input.Title,
+		Instructions:    input.Instructions,
+		DueAt:           input.DueAt,
+		AllowLate:       input.AllowLate,
+		MaxScore:        input.MaxScore,
+	}
+	if assignment.MaxScore == 0 {
+		assignment.MaxScore = defaultMaxScore
+	}
+	for _, file := range attachments {
+		assignment.Attachments = append(assignment.Attachments, entities.AssignmentAttachment{Name: file.Name, URL: file.URL})
+	}
+
+	if err := s.repo.Create(assignment); err != nil {
+		utils.LogError("assignments", "create", "Failed to create assignment: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("assignments", "create", "Assignment created successfully", requestID, userID, map[string]any{
+		"assignment_id": assignment.ID,
+	})
+	response := toAssignmentResponse(*assignment)
+	return &response, nil
+}
+
+func (s *assignmentService) Update(id uint, input UpdateAssignmentInput, requestID string, userID uint, isAdmin bool) (*AssignmentResponse, error) {
+	utils.LogInfo("assignments", "update", "Updating assignment", requestID, userID, map[string]any{
+		"assignment_id": id,
+	})
+
+	assignment, err := s.findAssignment(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tutors.AuthorizeClassContent(assignment.ClassID, userID, isAdmin); err != nil {
+		utils.LogWarning("assignments", "update", "Assignment update refused: "+err.Error(), requestID, userID, map[string]any{
+			"assignment_id": id,
+		})
+		return nil, err
+	}
+
+	if input.LessonID != nil {
+		if *input.LessonID == 0 {
+			assignment.LessonID = nil
+		} else {
+			if err := s.checkLesson(assignment.ClassID, input.LessonID); err != nil {
+				return nil, err
+			}
+			assignment.LessonID = input.LessonID
+		}
+	}
+	if input.Title != nil {
+		assignment.Title = *input.Title
+	}
+	if input.Instructions != nil {
+		assignment.Instructions = *input.Instructions
+	}
+	if input.DueAt != nil {
+		assignment.DueAt = input.DueAt
+	}
+	if input.AllowLate != nil {
+		assignment.AllowLate = *input.AllowLate
+	}
+	if input.MaxScore != nil {
+		assignment.MaxScore = *input.MaxScore
+	}
+
+	var attachments []entities.AssignmentAttachment
+	if input.Attachments != nil {
+		files, err := s.checkFiles(*input.Attachments, 0, requestID)
+		if err != nil {
+			return nil, err
+		}
+		attachments = make([]entities.AssignmentAttachment, 0, len(files))
+		for _, file := range files {
+			attachments = append(attachments, entities.AssignmentAttachment{Name: file.Name, URL: file.URL})
+		}
+	}
+
+	if err := s.repo.Update(&assignment, attachments); err != nil {
+		utils.LogError("assignments", "update", "Failed to update assignment: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("assignments", "update", "Assignment updated successfully", requestID, userID, map[string]any{
+		"assignment_id": id,
+	})
+	response := toAssignmentResponse(assignment)
+	return &response, nil
+}
+
+func (s *assignmentService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
+	utils.LogInfo("assignments", "delete", "Deleting assignment", requestID, userID, map[string]any{
+		"assignment_id": id,
+	})
+
+	assignment, err := s.findAssignment(id)
+	if err != nil {
+		return err
+	}
+	if err := s.tutors.AuthorizeClassContent(assignment.ClassID, userID, isAdmin); err != nil {
+		utils.LogWarning("assignments", "delete", "Assignment deletion refused: "+err.Error(), requestID, userID, map[string]any{
+			"assignment_id": id,
+		})
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		utils.LogError("assignments", "delete", "Failed to delete assignment: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("assignments", "delete", "Assignment deleted successfully", requestID, userID, map[string]any{
+		"assignment_id": id,
+	})
+	return nil
+}
+
+// ==========================================
+// Submissions
+// ==========================================
+
+func (s *assignmentService) Submit(assignmentID uint, input SubmitInput, requestID string, userID uint) (*SubmissionResponse, error) {
+	utils.LogInfo("assignments", "submit", "Student submitting assignment", requestID, userID, map[string]any{
+		"assignment_id": assignmentID,
+		"files":         len(input.Files),
+	})
+
+	assignment, err := s.findAssignment(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	registration, err := s.repo.FindApprovedRegistration(assignment.Class.CourseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("you are not enrolled in this course")
+		}
+		return nil, err
+	}
+
+	text := strings.TrimSpace(input.Text)
+	if text == "" && len(input.Files) == 0 {
+		return nil, errors.New("submission must contain text or files")
+	}
+
+	now := time.Now()
+	late := assignment.DueAt != nil && now.After(*assignment.DueAt)
+	if late && !assignment.AllowLate {
+		utils.LogWarning("assignments", "submit", "Submission after the due date", requestID, userID, map[string]any{
+			"assignment_id": assignmentID,
+		})
+		return nil, errors.New("the due date for this assignment has passed")
+	}
+
+	submission, err := s.repo.FindSubmission(assignmentID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if submission.Status == entities.SubmissionGraded {
+		return nil, errors.New("submission has already been graded")
+	}
+
+	files, err := s.checkFiles(input.Files, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	submissionFiles := make([]entities.SubmissionFile, 0, len(files))
+	for _, file := range files {
+		submissionFiles = append(submissionFiles, entities.SubmissionFile{Name: file.Name, URL: file.URL})
+	}
+
+	submission.AssignmentID = assignmentID
+	submission.UserID = userID
+	submission.RegistrationID = registration.ID
+	submission.Text = text
+	submission.Status = entities.SubmissionSubmitted
+	submission.SubmittedAt = now
+	submission.IsLate = late
+	if err := s.repo.SaveSubmission(&submission, submissionFiles); err != nil {
+		utils.LogError("assignments", "submit", "Failed to save submission: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("assignments", "submit", "Assignment submitted", requestID, userID, map[string]any{
+		"assignment_id": assignmentID,
+		"submission_id": submission.ID,
+		"late":          late,
+	})
+	response := toSubmissionResponse(submission)
+	return &response, nil
+}
+
+func (s *assignmentService) GetMySubmission(assignmentID uint, requestID string, userID uint) (*SubmissionResponse, error) {
+	utils.LogInfo("assignments", "get_my_submission", "Fetching own submission", requestID, userID, map[string]any{
+		"assignment_id": assignmentID,
+	})
+
+	if _, err := s.findAssignment(assignmentID); err != nil {
+		return nil, err
+	}
+	submission, err := s.repo.FindSubmission(assignmentID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("submission not found")
+		}
+		return nil, err
+	}
+
+	response := toSubmissionResponse(submission)
+	return &response, nil
+}
+
+func (s *assignmentService) GetSubmissions(assignmentID uint, requestID string, userID uint, isAdmin bool) (*AssignmentSubmissionsResponse, error) {
+	utils.LogInfo("assignments", "get_submissions", "Fetching assignment submissions", requestID, userID, map[string]any{
+		"assignment_id": assignmentID,
+	})
+
+	assignment, err := s.findAssignment(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tutors.AuthorizeClassContent(assignment.ClassID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	submissions, err := s.repo.FindSubmissionsByAssignmentID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.FindApprovedRegistrations(assignment.Class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AssignmentSubmissionsResponse{
+		AssignmentID: assignmentID,
+		Enrolled:     len(registrations),
+		Submitted:    len(submissions),
+		Submissions:  make([]SubmissionResponse, 0, len(submissions)),
+	}
+	for _, submission := range submissions {
+		if submission.Status == entities.SubmissionGraded {
+			response.Graded++
+		}
+		response.Submissions = append(response.Submissions, toSubmissionResponse(submission))
+	}
+	if response.Enrolled > response.Submitted {
+		response.Missing = response.Enrolled - response.Submitted
+	}
+	return response, nil
+}
+
+func (s *assignmentService) Grade(submissionID uint, input GradeInput, requestID string, userID uint, isAdmin bool) (*SubmissionResponse, error) {
+	utils.LogInfo("assignments", "grade", "Grading submission", requestID, userID, map[string]any{
+		"submission_id": submissionID,
+	})
+
+	submission, err := s.repo.FindSubmissionByID(submissionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("submission not found")
+		}
+		return nil, err
+	}
+	if err := s.tutors.AuthorizeClassContent(submission.Assignment.ClassID, userID, isAdmin); err != nil {
+		utils.LogWarning("assignments", "grade", "Grading refused: "+err.Error(), requestID, userID, map[string]any{
+			"submission_id": submissionID,
+		})
+		return nil, err
+	}
+	if *input.Score > submission.Assignment.MaxScore {
+		return nil, errors.New("score exceeds the maximum score of the assignment")
+	}
+
+	now := time.Now()
+	submission.Score = input.Score
+	submission.Feedback = strings.TrimSpace(input.Feedback)
+	submission.Status = entities.SubmissionGraded
+	submission.GradedByUserID = &userID
+	submission.GradedAt = &now
+	if err := s.repo.UpdateSubmission(&submission); err != nil {
+		utils.LogError("assignments", "grade", "Failed to save grade: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("assignments", "grade", "Submission graded", requestID, userID, map[string]any{
+		"submission_id": submissionID,
+		"score":         *input.Score,
+	})
+	response := toSubmissionResponse(submission)
+	return &response, nil
+}
+
+// ==========================================
+// Gradebook
+// ==========================================
+
+func (s *assignmentService) GetGradebook(classID uint, requestID string, userID uint, isAdmin bool) (*GradebookResponse, error) {
+	utils.LogInfo("assignments", "gradebook", "Building class gradebook", requestID, userID, map[string]any{
+		"class_id": classID,
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	assignments, err := s.repo.FindByClassID(classID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.repo.FindSubmissionsByClassID(classID)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.FindApprovedRegistrations(class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint]map[uint]entities.AssignmentSubmission)
+	for _, submission := range submissions {
+		if byUser[submission.UserID] == nil {
+			byUser[submission.UserID] = make(map[uint]entities.AssignmentSubmission)
+		}
+		byUser[submission.UserID][submission.AssignmentID] = submission
+	}
+
+	response := &GradebookResponse{
+		ClassID:     class.ID,
+		ClassName:   class.Name,
+		Assignments: make([]GradebookAssignment, 0, len(assignments)),
+		Students:    make([]GradebookRow, 0, len(registrations)),
+	}
+	for _, assignment := range assignments {
+		response.Assignments = append(response.Assignments, GradebookAssignment{
+			ID:       assignment.ID,
+			Title:    assignment.Title,
+			DueAt:    assignment.DueAt,
+			MaxScore: assignment.MaxScore,
+		})
+	}
+
+	now := time.Now()
+	for _, reg := range registrations {
+		row := GradebookRow{
+			UserID:   reg.UserID,
+			Username: reg.User.Username,
+			Email:    reg.User.Email,
+			Cells:    make([]GradebookCell, 0, len(assignments)),
+		}
+		for _, assignment := range assignments {
+			cell := GradebookCell{AssignmentID: assignment.ID, Status: "missing"}
+			submission, ok := byUser[reg.UserID][assignment.ID]
+			switch {
+			case !ok:
+				// Only overdue work counts as missing; open assignments are simply not handed in yet
+				if assignment.DueAt != nil && now.After(*assignment.DueAt) {
+					row.Missing++
+				}
+			case submission.Status == entities.SubmissionGraded && submission.Score != nil:
+				cell.Status = submission.Status
+				cell.IsLate = submission.IsLate
+				cell.Score = submission.Score
+				row.TotalScore += *submission.Score
+				row.GradedMax += assignment.MaxScore
+			default:
+				cell.Status = submission.Status
+				cell.IsLate = submission.IsLate
+				row.AwaitingGrades++
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		if row.GradedMax > 0 {
+			row.Percentage = math.Round(row.TotalScore/row.GradedMax*1000) / 10
+		}
+		response.Students = append(response.Students, row)
+	}
+	return response, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *assignmentService) findAssignment(id uint) (entities.Assignment, error) {
+	assignment, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return assignment, errors.New("assignment not found")
+		}
+		return assignment, err
+	}
+	return assignment, nil
+}
+
+// checkLesson makes sure an assignment is only tied to a lesson of its own class
+func (s *assignmentService) checkLesson(classID uint, lessonID *uint) error {
+	if lessonID == nil {
+		return nil
+	}
+	lesson, err := s.repo.FindLessonByID(*lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("lesson not found")
+		}
+		return err
+	}
+	if lesson.ClassID != classID {
+		return errors.New("lesson does not belong to this class")
+	}
+	return nil
+}
+
+// checkFiles only accepts files registered through the uploads endpoint. A non-zero uploaderID
+// also requires every file to be uploaded by that user, so a student cannot submit someone else's work.
+func (s *assignmentService) checkFiles(files []FileInput, uploaderID uint, requestID string) ([]FileInput, error) {
+	checked := make([]FileInput, 0, len(files))
+	for _, file := range files {
+		url := strings.TrimSpace(file.URL)
+		uploaded, err := s.uploads.GetByURL(url, requestID)
+		if err != nil {
+			if err.Error() == "image not found" {
+				return nil, errors.New("file has not been uploaded: " + url)
+			}
+			return nil, err
+		}
+		if uploaderID != 0 && (uploaded.UserID == nil || *uploaded.UserID != uploaderID) {
+			return nil, errors.New("file was not uploaded by you: " + url)
+		}
+		checked = append(checked, FileInput{Name: strings.TrimSpace(file.Name), URL: url})
+	}
+	return checked, nil
+}
+
+func toAssignmentResponse(assignment entities.Assignment) AssignmentResponse {
+	response := AssignmentResponse{
+		ID:           assignment.ID,
+		ClassID:      assignment.ClassID,
+		LessonID:     assignment.LessonID,
+		Title:        assignment.Title,
+		Instructions: assignment.Instructions,
+		DueAt:        assignment.DueAt,
+		AllowLate:    assignment.AllowLate,
+		MaxScore:     assignment.MaxScore,
+		Attachments:  make([]FileResponse, 0, len(assignment.Attachments)),
+		CreatedAt:    assignment.CreatedAt,
+	}
+	for _, file := range assignment.Attachments {
+		response.Attachments = append(response.Attachments, FileResponse{Name: file.Name, URL: file.URL})
+	}
+	return response
+}
+
+func toSubmissionResponse(submission entities.AssignmentSubmission) SubmissionResponse {
+	response := SubmissionResponse{
+		ID:           submission.ID,
+		AssignmentID: submission.AssignmentID,
+		UserID:       submission.UserID,
+		Username:     submission.User.Username,
+		Email:        submission.User.Email,
+		Text:         submission.Text,
+		Files:        make([]FileResponse, 0, len(submission.Files)),
+		Status:       submission.Status,
+		SubmittedAt:  submission.SubmittedAt,
+		IsLate:       submission.IsLate,
+		Score:        submission.Score,
+		Feedback:     submission.Feedback,
+		GradedAt:     submission.GradedAt,
+	}
+	for _, file := range submission.Files {
+		response.Files = append(response.Files, FileResponse{Name: file.Name, URL: file.URL})
+	}
+	return response
+}
@@ -0,0 +1,143 @@
+package attendance
+
+import (
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ==========================================
+// MARKING DTOs
+// ==========================================
+
+// MarkAttendanceInput marks many students of one lesson at once
+type MarkAttendanceInput struct {
+	Records []AttendanceRecordInput `json:"records" binding:"required,min=1,max=500,dive"`
+}
+
+type AttendanceRecordInput struct {
+	UserID uint   `json:"userId" binding:"required"`
+	Status string `json:"status" binding:"required,oneof=present late absent"`
+	Note   string `json:"note" binding:"max=255"`
+}
+
+// MarkAttendanceResult is the outcome for one student of a bulk marking
+type MarkAttendanceResult struct {
+	UserID  uint   `json:"userId"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MarkAttendanceResponse summarises a bulk marking
+type MarkAttendanceResponse struct {
+	Total     int                    `json:"total"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []MarkAttendanceResult `json:"results"`
+}
+
+// ==========================================
+// CHECK-IN DTOs
+// ==========================================
+
+type CheckInCodeInput struct {
+	ValidMinutes int `json:"validMinutes" binding:"omitempty,min=1,max=240"` // default 15
+}
+
+type CheckInCodeResponse struct {
+	LessonID  uint      `json:"lessonId"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type CheckInInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type AttendanceResponse struct {
+	LessonID    uint       `json:"lessonId"`
+	UserID      uint       `json:"userId"`
+	Status      string     `json:"status"`
+	Method      string     `json:"method"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty"`
+	Note        string     `json:"note,omitempty"`
+}
+
+// ==========================================
+// REPORT DTOs
+// ==========================================
+
+// LessonRosterEntry is one enrolled student of a lesson; Status is empty until marked
+type LessonRosterEntry struct {
+	UserID         uint       `json:"userId"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	RegistrationID uint       `json:"registrationId"`
+	Status         string     `json:"status"`
+	Method         string     `json:"method,omitempty"`
+	CheckedInAt    *time.Time `json:"checkedInAt,omitempty"`
+	Note           string     `json:"note,omitempty"`
+}
+
+type LessonAttendanceResponse struct {
+	LessonID    uint                `json:"lessonId"`
+	LessonTitle string              `json:"lessonTitle"`
+	StartTime   *time.Time          `json:"startTime,omitempty"`
+	Present     int                 `json:"present"`
+	Late        int                 `json:"late"`
+	Absent      int                 `json:"absent"`
+	Unmarked    int                 `json:"unmarked"`
+	Students    []LessonRosterEntry `json:"students"`
+}
+
+// SummaryFilter sets the attendance rate (in percent) below which students are flagged
+type SummaryFilter struct {
+	Threshold *float64 `form:"threshold" binding:"omitempty,min=0,max=100"`
+}
+
+// StudentSummary counts one student's attendance over the lessons held so far
+type StudentSummary struct {
+	UserID         uint    `json:"userId"`
+	Username       string  `json:"username"`
+	Email          string  `json:"email"`
+	Present        int     `json:"present"`
+	Late           int     `json:"late"`
+	Absent         int     `json:"absent"`
+	Unmarked       int     `json:"unmarked"`
+	AttendanceRate float64 `json:"attendanceRate"` // present or late, in percent of lessons held
+	BelowThreshold bool    `json:"belowThreshold"`
+}
+
+type CourseSummaryResponse struct {
+	CourseID     uint             `json:"courseId"`
+	CourseName   string           `json:"courseName"`
+	LessonsHeld  int              `json:"lessonsHeld"`
+	Threshold    float64          `json:"threshold"`
+	FlaggedCount int              `json:"flaggedCount"`
+	Students     []StudentSummary `json:"students"`
+}
+
+type MyLessonAttendance struct {
+	LessonID    uint       `json:"lessonId"`
+	LessonTitle string     `json:"lessonTitle"`
+	ClassName   string     `json:"className"`
+	StartTime   *time.Time `json:"startTime,omitempty"`
+	Held        bool       `json:"held"`
+	Status      string     `json:"status"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty"`
+}
+
+type MyAttendanceResponse struct {
+	CourseID    uint                 `json:"courseId"`
+	LessonsHeld int                  `json:"lessonsHeld"`
+	Threshold   float64              `json:"threshold"`
+	Summary     StudentSummary       `json:"summary"`
+	Lessons     []MyLessonAttendance `json:"lessons"`
+}
+
+// AttendanceExport is a generated workbook ready to be written to the client
+type AttendanceExport struct {
+	FileName string
+	File     *excelize.File
+}
@@ -0,0 +1,98 @@
+package attendance
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+var nonFileNameChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ExportCourseAttendance writes a workbook with a per-student summary sheet and a
+// student-by-lesson sheet holding every status
+func (s *attendanceService) ExportCourseAttendance(courseID uint, filter SummaryFilter, userID uint, isAdmin bool, requestID string) (*AttendanceExport, error) {
+	utils.LogInfo("attendance", "export", "Exporting course attendance", requestID, userID, map[string]any{
+		"course_id": courseID,
+	})
+
+	if err := s.tutors.AuthorizeCourseView(courseID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	data, err := s.loadCourseAttendance(courseID)
+	if err != nil {
+		utils.LogError("attendance", "export", "Failed to load attendance: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+	threshold := thresholdOf(filter)
+
+	f := excelize.NewFile()
+	summarySheet := "Summary"
+	lessonsSheet := "Lessons"
+	f.SetSheetName(f.GetSheetName(0), summarySheet)
+	if _, err := f.NewSheet(lessonsSheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	summaryHeader := []any{"No", "Name", "Email", "Present", "Late", "Absent", "Unmarked", "Attendance Rate (%)", fmt.Sprintf("Below %.0f%%", threshold)}
+	if err := f.SetSheetRow(summarySheet, "A1", &summaryHeader); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	lessonsHeader := []any{"No", "Name", "Email"}
+	for _, lesson := range data.lessons {
+		title := lesson.Title
+		if lesson.StartTime != nil {
+			title += " (" + lesson.StartTime.Format("2006-01-02") + ")"
+		}
+		lessonsHeader = append(lessonsHeader, title)
+	}
+	if err := f.SetSheetRow(lessonsSheet, "A1", &lessonsHeader); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	for i, reg := range data.registrations {
+		rowNum := i + 2
+		summary := data.summarise(reg.User, threshold)
+		flag := ""
+		if summary.BelowThreshold {
+			flag = "YES"
+		}
+		summaryRow := []any{i + 1, reg.User.Username, reg.User.Email, summary.Present, summary.Late, summary.Absent, summary.Unmarked, summary.AttendanceRate, flag}
+		cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+		if err := f.SetSheetRow(summarySheet, cell, &summaryRow); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		lessonRow := []any{i + 1, reg.User.Username, reg.User.Email}
+		for _, lesson := range data.lessons {
+			status := data.records[reg.UserID][lesson.ID].Status
+			if status == "" && data.held[lesson.ID] {
+				status = "unmarked"
+			}
+			lessonRow = append(lessonRow, status)
+		}
+		if err := f.SetSheetRow(lessonsSheet, cell, &lessonRow); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	utils.LogSuccess("attendance", "export", "Course attendance exported", requestID, userID, map[string]any{
+		"course_id": courseID,
+		"students":  len(data.registrations),
+		"lessons":   len(data.lessons),
+	})
+
+	name := strings.Trim(nonFileNameChars.ReplaceAllString(data.course.NameCourse, "-"), "-")
+	return &AttendanceExport{
+		FileName: fmt.Sprintf("course-%d-%s-attendance.xlsx", data.course.ID, strings.ToLower(name)),
+		File:     f,
+	}, nil
+}
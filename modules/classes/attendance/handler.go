@@ -0,0 +1,244 @@
+package attendance
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetLessonAttendanceHandler(c *gin.Context)
+	MarkAttendanceHandler(c *gin.Context)
+	GenerateCheckInCodeHandler(c *gin.Context)
+	CheckInHandler(c *gin.Context)
+	GetCourseSummaryHandler(c *gin.Context)
+	GetMyAttendanceHandler(c *gin.Context)
+	ExportCourseAttendanceHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps the shared lookup and authorization errors
+func respondError(c *gin.Context, title string, err error) {
+	switch {
+	case err.Error() == "lesson not found" || err.Error() == "course not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+	case tutors.IsAuthorizationError(err) || err.Error() == "you are not enrolled in this course":
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+	case err.Error() == "invalid or expired check-in code":
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed(title, err.Error(), nil))
+	case err.Error() == "attendance already recorded":
+		c.JSON(http.StatusConflict, utils.BuildResponseFailed(title, err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed(title, err.Error(), nil))
+	}
+}
+
+// ==========================================
+// Lesson Handlers
+// ==========================================
+
+func (h *handler) GetLessonAttendanceHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	attendance, err := h.service.GetLessonAttendance(lessonID, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch attendance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Attendance retrieved successfully", attendance))
+}
+
+func (h *handler) MarkAttendanceHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input MarkAttendanceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	result, err := h.service.MarkAttendance(lessonID, input, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to mark attendance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Attendance marked", result))
+}
+
+func (h *handler) GenerateCheckInCodeHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CheckInCodeInput
+	// The body is optional; an empty one keeps the default validity
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	code, err := h.service.GenerateCheckInCode(lessonID, input, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to generate check-in code", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Check-in code generated", code))
+}
+
+func (h *handler) CheckInHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	attendance, err := h.service.CheckIn(lessonID, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to check in", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Checked in successfully", attendance))
+}
+
+// ==========================================
+// Course Handlers
+// ==========================================
+
+func (h *handler) GetCourseSummaryHandler(c *gin.Context) {
+	courseID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var filter SummaryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid query parameters", err.Error(), nil))
+		return
+	}
+
+	summary, err := h.service.GetCourseSummary(courseID, filter, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch attendance summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Attendance summary retrieved successfully", summary))
+}
+
+func (h *handler) GetMyAttendanceHandler(c *gin.Context) {
+	courseID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var filter SummaryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid query parameters", err.Error(), nil))
+		return
+	}
+
+	attendance, err := h.service.GetMyAttendance(courseID, filter, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch attendance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Attendance retrieved successfully", attendance))
+}
+
+func (h *handler) ExportCourseAttendanceHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	courseID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var filter SummaryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid query parameters", err.Error(), nil))
+		return
+	}
+
+	export, err := h.service.ExportCourseAttendance(courseID, filter, getUserID(c), isAdmin(c), requestID)
+	if err != nil {
+		respondError(c, "Failed to export attendance", err)
+		return
+	}
+	defer export.File.Close()
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	c.Status(http.StatusOK)
+	if err := export.File.Write(c.Writer); err != nil {
+		utils.LogError("attendance", "export", "Failed to write export: "+err.Error(), requestID, 0, nil)
+	}
+}
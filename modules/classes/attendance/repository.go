@@ -0,0 +1,128 @@
+package attendance
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindLessonByID(id uint) (entities.Lesson, error)
+	UpdateCheckInCode(lessonID uint, code string, expiresAt time.Time) error
+	FindCourseByID(id uint) (entities.Course, error)
+	FindLessonsByCourseID(courseID uint) ([]entities.Lesson, error)
+
+	FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error)
+	FindApprovedRegistration(courseID, userID uint) (entities.CourseRegistration, error)
+
+	FindByLessonID(lessonID uint) ([]entities.LessonAttendance, error)
+	FindByCourseID(courseID uint) ([]entities.LessonAttendance, error)
+	ExistsForLessonAndUser(lessonID, userID uint) (bool, error)
+	Create(record *entities.LessonAttendance) error
+	// Upsert writes tutor markings, replacing any earlier status of the same lesson and student
+	Upsert(records []entities.LessonAttendance) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Lesson & Course Methods
+// ==========================================
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.Preload("Class.Course").First(&lesson, id).Error
+	return lesson, err
+}
+
+func (r *repository) UpdateCheckInCode(lessonID uint, code string, expiresAt time.Time) error {
+	return r.db.Model(&entities.Lesson{}).Where("id = ?", lessonID).Updates(map[string]any{
+		"check_in_code":       code,
+		"check_in_expires_at": expiresAt,
+	}).Error
+}
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+func (r *repository) FindLessonsByCourseID(courseID uint) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	err := r.db.Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL").
+		Where("classes.course_id = ?", courseID).
+		Preload("Class").
+		Order("lessons.start_time ASC NULLS LAST, classes.id ASC, lessons.lesson_order ASC").
+		Find(&lessons).Error
+	return lessons, err
+}
+
+// ==========================================
+// Registration Methods
+// ==========================================
+
+func (r *repository) FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND status = ?", courseID, "approved").
+		Preload("User").
+		Order("id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindApprovedRegistration(courseID, userID uint) (entities.CourseRegistration, error) {
+	var registration entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND user_id = ? AND status = ?", courseID, userID, "approved").
+		First(&registration).Error
+	return registration, err
+}
+
+// ==========================================
+// Attendance Methods
+// ==========================================
+
+func (r *repository) FindByLessonID(lessonID uint) ([]entities.LessonAttendance, error) {
+	var records []entities.LessonAttendance
+	err := r.db.Where("lesson_id = ?", lessonID).Find(&records).Error
+	return records, err
+}
+
+func (r *repository) FindByCourseID(courseID uint) ([]entities.LessonAttendance, error) {
+	var records []entities.LessonAttendance
+	err := r.db.Joins("JOIN lessons ON lessons.id = lesson_attendances.lesson_id AND lessons.deleted_at IS NULL").
+		Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL").
+		Where("classes.course_id = ?", courseID).
+		Find(&records).Error
+	return records, err
+}
+
+func (r *repository) ExistsForLessonAndUser(lessonID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.LessonAttendance{}).
+		Where("lesson_id = ? AND user_id = ?", lessonID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) Create(record *entities.LessonAttendance) error {
+	return r.db.Create(record).Error
+}
+
+func (r *repository) Upsert(records []entities.LessonAttendance) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "lesson_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "method", "marked_by_user_id", "note", "updated_at", "deleted_at"}),
+	}).Omit("Lesson", "User").Create(&records).Error
+}
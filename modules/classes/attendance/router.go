@@ -0,0 +1,29 @@
+package attendance
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+)
+
+func AttendanceRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	db := migrations.GetDB()
+	handler := NewHandler(NewService(NewRepository(db), tutors.NewService(tutors.NewRepository(db))))
+
+	lessonAttendance := router.Group("/lessons/:id")
+	lessonAttendance.Use(requireAuth)
+	{
+		lessonAttendance.GET("/attendance", requireAdminOrTutor, handler.GetLessonAttendanceHandler)
+		lessonAttendance.POST("/attendance", requireAdminOrTutor, handler.MarkAttendanceHandler)
+		lessonAttendance.POST("/check-in-code", requireAdminOrTutor, handler.GenerateCheckInCodeHandler)
+		lessonAttendance.POST("/check-in", handler.CheckInHandler)
+	}
+
+	courseAttendance := router.Group("/courses/:id/attendance")
+	courseAttendance.Use(requireAuth)
+	{
+		courseAttendance.GET("", requireAdminOrTutor, handler.GetCourseSummaryHandler)
+		courseAttendance.GET("/export", requireAdminOrTutor, handler.ExportCourseAttendanceHandler)
+		courseAttendance.GET("/me", handler.GetMyAttendanceHandler)
+	}
+}
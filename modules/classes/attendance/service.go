@@ -0,0 +1,481 @@
+package attendance
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultCodeMinutes is how long a check-in code stays valid unless the tutor says otherwise
+	defaultCodeMinutes = 15
+	// lateGracePeriod is how long after the lesson starts a check-in still counts as present
+	lateGracePeriod = 15 * time.Minute
+	// defaultThreshold is the attendance rate (percent) below which students are flagged
+	defaultThreshold = 75.0
+)
+
+// codeAlphabet leaves out characters that are easy to misread on a projected screen
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type attendanceService struct {
+	repo   Repository
+	tutors tutors.Service
+}
+
+type Service interface {
+	GetLessonAttendance(lessonID uint, userID uint, isAdmin bool, requestID string) (*LessonAttendanceResponse, error)
+	MarkAttendance(lessonID uint, input MarkAttendanceInput, userID uint, isAdmin bool, requestID string) (*MarkAttendanceResponse, error)
+	GenerateCheckInCode(lessonID uint, input CheckInCodeInput, userID uint, isAdmin bool, requestID string) (*CheckInCodeResponse, error)
+	CheckIn(lessonID uint, input CheckInInput, userID uint, requestID string) (*AttendanceResponse, error)
+
+	GetCourseSummary(courseID uint, filter SummaryFilter, userID uint, isAdmin bool, requestID string) (*CourseSummaryResponse, error)
+	GetMyAttendance(courseID uint, filter SummaryFilter, userID uint, requestID string) (*MyAttendanceResponse, error)
+	ExportCourseAttendance(courseID uint, filter SummaryFilter, userID uint, isAdmin bool, requestID string) (*AttendanceExport, error)
+}
+
+func NewService(repo Repository, tutorService tutors.Service) Service {
+	return &attendanceService{repo: repo, tutors: tutorService}
+}
+
+// ==========================================
+// Lesson Attendance
+// ==========================================
+
+func (s *attendanceService) GetLessonAttendance(lessonID uint, userID uint, isAdmin bool, requestID string) (*LessonAttendanceResponse, error) {
+	utils.LogInfo("attendance", "get_lesson", "Fetching lesson attendance", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.repo.FindApprovedRegistrations(lesson.Class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.FindByLessonID(lessonID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint]entities.LessonAttendance, len(records))
+	for _, record := range records {
+		byUser[record.UserID] = record
+	}
+
+	response := &LessonAttendanceResponse{
+		LessonID:    lesson.ID,
+		LessonTitle: lesson.Title,
+		StartTime:   lesson.StartTime,
+		Students:    make([]LessonRosterEntry, 0, len(registrations)),
+	}
+	for _, reg := range registrations {
+		entry := LessonRosterEntry{
+			UserID:         reg.UserID,
+			Username:       reg.User.Username,
+			Email:          reg.User.Email,
+			RegistrationID: reg.ID,
+		}
+		if record, ok := byUser[reg.UserID]; ok {
+			entry.Status = record.Status
+			entry.Method = record.Method
+			entry.CheckedInAt = record.CheckedInAt
+			entry.Note = record.Note
+		}
+		switch entry.Status {
+		case entities.AttendancePresent:
+			response.Present++
+		case entities.AttendanceLate:
+			response.Late++
+		case entities.AttendanceAbsent:
+			response.Absent++
+		default:
+			response.Unmarked++
+		}
+		response.Students = append(response.Students, entry)
+	}
+	return response, nil
+}
+
+func (s *attendanceService) MarkAttendance(lessonID uint, input MarkAttendanceInput, userID uint, isAdmin bool, requestID string) (*MarkAttendanceResponse, error) {
+	utils.LogInfo("attendance", "mark", "Marking lesson attendance", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+		"count":     len(input.Records),
+	})
+
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		utils.LogWarning("attendance", "mark", "Marking refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, err
+	}
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.repo.FindApprovedRegistrations(lesson.Class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uint]uint, len(registrations))
+	for _, reg := range registrations {
+		enrolled[reg.UserID] = reg.ID
+	}
+
+	response := &MarkAttendanceResponse{Total: len(input.Records), Results: make([]MarkAttendanceResult, 0, len(input.Records))}
+	var records []entities.LessonAttendance
+	seen := make(map[uint]bool, len(input.Records))
+	for _, item := range input.Records {
+		result := MarkAttendanceResult{UserID: item.UserID}
+		registrationID, ok := enrolled[item.UserID]
+		switch {
+		case seen[item.UserID]:
+			result.Error = "student was listed more than once"
+		case !ok:
+			result.Error = "student is not enrolled in this course"
+		default:
+			records = append(records, entities.LessonAttendance{
+				LessonID:       lessonID,
+				UserID:         item.UserID,
+				RegistrationID: registrationID,
+				Status:         item.Status,
+				Method:         entities.AttendanceByTutor,
+				MarkedByUserID: &userID,
+				Note:           strings.TrimSpace(item.Note),
+			})
+			result.Success = true
+			result.Status = item.Status
+		}
+		seen[item.UserID] = true
+		response.Results = append(response.Results, result)
+	}
+
+	if err := s.repo.Upsert(records); err != nil {
+		utils.LogError("attendance", "mark", "Failed to save attendance: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, err
+	}
+	response.Succeeded = len(records)
+	response.Failed = response.Total - response.Succeeded
+
+	utils.LogSuccess("attendance", "mark", "Lesson attendance marked", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+		"succeeded": response.Succeeded,
+		"failed":    response.Failed,
+	})
+	return response, nil
+}
+
+// ==========================================
+// Self Check-in
+// ==========================================
+
+func (s *attendanceService) GenerateCheckInCode(lessonID uint, input CheckInCodeInput, userID uint, isAdmin bool, requestID string) (*CheckInCodeResponse, error) {
+	utils.LogInfo("attendance", "generate_code", "Generating check-in code", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	minutes := input.ValidMinutes
+	if minutes == 0 {
+		minutes = defaultCodeMinutes
+	}
+	code, err := randomCode(6)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(time.Duration(minutes) * time.Minute)
+
+	// A new code replaces the previous one straight away
+	if err := s.repo.UpdateCheckInCode(lessonID, code, expiresAt); err != nil {
+		utils.LogError("attendance", "generate_code", "Failed to save check-in code: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("attendance", "generate_code", "Check-in code generated", requestID, userID, map[string]any{
+		"lesson_id":  lessonID,
+		"expires_at": expiresAt,
+	})
+	return &CheckInCodeResponse{LessonID: lessonID, Code: code, ExpiresAt: expiresAt}, nil
+}
+
+func (s *attendanceService) CheckIn(lessonID uint, input CheckInInput, userID uint, requestID string) (*AttendanceResponse, error) {
+	utils.LogInfo("attendance", "check_in", "Student checking in", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lesson not found")
+		}
+		return nil, err
+	}
+
+	registration, err := s.repo.FindApprovedRegistration(lesson.Class.CourseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("you are not enrolled in this course")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	if lesson.CheckInCode == "" || lesson.CheckInExpiresAt == nil || now.After(*lesson.CheckInExpiresAt) || code != lesson.CheckInCode {
+		utils.LogWarning("attendance", "check_in", "Invalid or expired check-in code", requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, errors.New("invalid or expired check-in code")
+	}
+
+	exists, err := s.repo.ExistsForLessonAndUser(lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("attendance already recorded")
+	}
+
+	status := entities.AttendancePresent
+	if lesson.StartTime != nil && now.After(lesson.StartTime.Add(lateGracePeriod)) {
+		status = entities.AttendanceLate
+	}
+	record := &entities.LessonAttendance{
+		LessonID:       lessonID,
+		UserID:         userID,
+		RegistrationID: registration.ID,
+		Status:         status,
+		Method:         entities.AttendanceByCheckIn,
+		CheckedInAt:    &now,
+	}
+	if err := s.repo.Create(record); err != nil {
+		utils.LogError("attendance", "check_in", "Failed to record check-in: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("attendance", "check_in", "Student checked in", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+		"status":    status,
+	})
+	return toAttendanceResponse(*record), nil
+}
+
+// ==========================================
+// Summaries
+// ==========================================
+
+// courseAttendance is everything needed to summarise a course's attendance
+type courseAttendance struct {
+	course        entities.Course
+	lessons       []entities.Lesson
+	held          map[uint]bool
+	heldCount     int
+	registrations []entities.CourseRegistration
+	records       map[uint]map[uint]entities.LessonAttendance // user -> lesson -> record
+}
+
+func (s *attendanceService) loadCourseAttendance(courseID uint) (*courseAttendance, error) {
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+	lessons, err := s.repo.FindLessonsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.FindApprovedRegistrations(courseID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.FindByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &courseAttendance{
+		course:        course,
+		lessons:       lessons,
+		held:          make(map[uint]bool, len(lessons)),
+		registrations: registrations,
+		records:       make(map[uint]map[uint]entities.LessonAttendance),
+	}
+	for _, record := range records {
+		if data.records[record.UserID] == nil {
+			data.records[record.UserID] = make(map[uint]entities.LessonAttendance)
+		}
+		data.records[record.UserID][record.LessonID] = record
+		// A lesson without a schedule counts once someone has been marked
+		data.held[record.LessonID] = true
+	}
+	now := time.Now()
+	for _, lesson := range lessons {
+		if lesson.StartTime != nil && !lesson.StartTime.After(now) {
+			data.held[lesson.ID] = true
+		}
+		if data.held[lesson.ID] {
+			data.heldCount++
+		}
+	}
+	return data, nil
+}
+
+// summarise counts one student's attendance over the lessons held
+func (d *courseAttendance) summarise(user entities.User, threshold float64) StudentSummary {
+	summary := StudentSummary{UserID: user.ID, Username: user.Username, Email: user.Email}
+	for _, lesson := range d.lessons {
+		if !d.held[lesson.ID] {
+			continue
+		}
+		switch d.records[user.ID][lesson.ID].Status {
+		case entities.AttendancePresent:
+			summary.Present++
+		case entities.AttendanceLate:
+			summary.Late++
+		case entities.AttendanceAbsent:
+			summary.Absent++
+		default:
+			summary.Unmarked++
+		}
+	}
+	if d.heldCount > 0 {
+		rate := float64(summary.Present+summary.Late) / float64(d.heldCount) * 100
+		summary.AttendanceRate = math.Round(rate*10) / 10
+		summary.BelowThreshold = summary.AttendanceRate < threshold
+	}
+	return summary
+}
+
+func (s *attendanceService) GetCourseSummary(courseID uint, filter SummaryFilter, userID uint, isAdmin bool, requestID string) (*CourseSummaryResponse, error) {
+	utils.LogInfo("attendance", "course_summary", "Building course attendance summary", requestID, userID, map[string]any{
+		"course_id": courseID,
+	})
+
+	if err := s.tutors.AuthorizeCourseView(courseID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	data, err := s.loadCourseAttendance(courseID)
+	if err != nil {
+		utils.LogError("attendance", "course_summary", "Failed to load attendance: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	threshold := thresholdOf(filter)
+	response := &CourseSummaryResponse{
+		CourseID:    data.course.ID,
+		CourseName:  data.course.NameCourse,
+		LessonsHeld: data.heldCount,
+		Threshold:   threshold,
+		Students:    make([]StudentSummary, 0, len(data.registrations)),
+	}
+	for _, reg := range data.registrations {
+		summary := data.summarise(reg.User, threshold)
+		if summary.BelowThreshold {
+			response.FlaggedCount++
+		}
+		response.Students = append(response.Students, summary)
+	}
+	return response, nil
+}
+
+func (s *attendanceService) GetMyAttendance(courseID uint, filter SummaryFilter, userID uint, requestID string) (*MyAttendanceResponse, error) {
+	utils.LogInfo("attendance", "my_attendance", "Fetching own attendance", requestID, userID, map[string]any{
+		"course_id": courseID,
+	})
+
+	if _, err := s.repo.FindApprovedRegistration(courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("you are not enrolled in this course")
+		}
+		return nil, err
+	}
+	data, err := s.loadCourseAttendance(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var user entities.User
+	for _, reg := range data.registrations {
+		if reg.UserID == userID {
+			user = reg.User
+		}
+	}
+
+	threshold := thresholdOf(filter)
+	response := &MyAttendanceResponse{
+		CourseID:    courseID,
+		LessonsHeld: data.heldCount,
+		Threshold:   threshold,
+		Summary:     data.summarise(user, threshold),
+		Lessons:     make([]MyLessonAttendance, 0, len(data.lessons)),
+	}
+	for _, lesson := range data.lessons {
+		record := data.records[userID][lesson.ID]
+		response.Lessons = append(response.Lessons, MyLessonAttendance{
+			LessonID:    lesson.ID,
+			LessonTitle: lesson.Title,
+			ClassName:   lesson.Class.Name,
+			StartTime:   lesson.StartTime,
+			Held:        data.held[lesson.ID],
+			Status:      record.Status,
+			CheckedInAt: record.CheckedInAt,
+		})
+	}
+	return response, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func thresholdOf(filter SummaryFilter) float64 {
+	if filter.Threshold != nil {
+		return *filter.Threshold
+	}
+	return defaultThreshold
+}
+
+func randomCode(length int) (string, error) {
+	var sb strings.Builder
+	size := big.NewInt(int64(len(codeAlphabet)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func toAttendanceResponse(record entities.LessonAttendance) *AttendanceResponse {
+	return &AttendanceResponse{
+		LessonID:    record.LessonID,
+		UserID:      record.UserID,
+		Status:      record.Status,
+		Method:      record.Method,
+		CheckedInAt: record.CheckedInAt,
+		Note:        record.Note,
+	}
+}
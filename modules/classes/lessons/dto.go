@@ -8,32 +8,61 @@ type CreateLessonInput struct {
 	LessonOrder int        `json:"lessonOrder" binding:"required,min=1"`
 	StartTime   *time.Time `json:"startTime"`
 	EndTime     *time.Time `json:"endTime"`
+
+	CompletionRule string `json:"completionRule" binding:"omitempty,oneof=manual resources quiz"`
+
+	// Defaults to published; a draft with publishAt goes live at that time
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type UpdateLessonInput struct {
 	Title       *string    `json:"title"`
 	Description *string    `json:"description"`
-	LessonOrder *int       `json:"lessonOrder"`
+	LessonOrder *int       `json:"lessonOrder" binding:"omitempty,min=1"`
 	StartTime   *time.Time `json:"startTime"`
 	EndTime     *time.Time `json:"endTime"`
+
+	CompletionRule *string `json:"completionRule" binding:"omitempty,oneof=manual resources quiz"`
+
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
+}
+
+// ReorderLessonsInput lists every lesson of the class in its new order
+type ReorderLessonsInput struct {
+	LessonIDs []uint `json:"lessonIds" binding:"required,min=1"`
 }
 
 type LessonResponse struct {
-	ID            uint               `json:"id"`
-	ClassID       uint               `json:"classId"`
-	ClassName     string             `json:"className,omitempty"`
-	Title         string             `json:"title"`
-	Description   string             `json:"description"`
-	LessonOrder   int                `json:"lessonOrder"`
-	StartTime     *time.Time         `json:"startTime,omitempty"`
-	EndTime       *time.Time         `json:"endTime,omitempty"`
-	Resources     []ResourceResponse `json:"resources,omitempty"`
-	ResourceCount int                `json:"resourceCount,omitempty"`
+	ID             uint               `json:"id"`
+	ClassID        uint               `json:"classId"`
+	ClassName      string             `json:"className,omitempty"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	LessonOrder    int                `json:"lessonOrder"`
+	StartTime      *time.Time         `json:"startTime,omitempty"`
+	EndTime        *time.Time         `json:"endTime,omitempty"`
+	CompletionRule string             `json:"completionRule"`
+	Resources      []ResourceResponse `json:"resources,omitempty"`
+	ResourceCount  int                `json:"resourceCount,omitempty"`
+	Locked         bool               `json:"locked"` // only the outline is shown until the viewer is enrolled
+
+	// Set when the lesson was generated by a recurring class schedule
+	ScheduleID     *uint  `json:"scheduleId,omitempty"`
+	OccurrenceDate string `json:"occurrenceDate,omitempty"`
+	IsRescheduled  bool   `json:"isRescheduled,omitempty"`
+
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	IsLive    bool       `json:"isLive"`
 }
 
 type ResourceResponse struct {
-	ID    uint   `json:"id"`
-	Type  string `json:"type"`
-	Title string `json:"title"`
-	URL   string `json:"url"`
+	ID             uint   `json:"id"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+	URL            string `json:"url,omitempty"`
+	MinViewPercent int    `json:"minViewPercent,omitempty"`
+	Locked         bool   `json:"locked"`
 }
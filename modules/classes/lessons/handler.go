@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -19,6 +21,7 @@ type Handler interface {
 	CreateLessonHandler(c *gin.Context)
 	UpdateLessonHandler(c *gin.Context)
 	DeleteLessonHandler(c *gin.Context)
+	ReorderLessonsHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -47,6 +50,22 @@ func getUserID(c *gin.Context) uint {
 	return 0
 }
 
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+// getViewer identifies the caller for content access; anonymous callers have no user ID
+func getViewer(c *gin.Context) access.Viewer {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return access.Viewer{UserID: getUserID(c), Role: roleStr}
+}
+
 func (h *handler) GetLessonsByClassHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	classIDStr := c.Param("id")
@@ -57,8 +76,12 @@ func (h *handler) GetLessonsByClassHandler(c *gin.Context) {
 		return
 	}
 
-	lessons, err := h.service.GetByClassID(uint(classID), requestID)
+	lessons, err := h.service.GetByClassID(uint(classID), getViewer(c), requestID)
 	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Class not found", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch lessons", err.Error(), nil))
 		return
 	}
@@ -76,7 +99,7 @@ func (h *handler) GetLessonByIDHandler(c *gin.Context) {
 		return
 	}
 
-	lesson, err := h.service.GetByID(uint(id), requestID)
+	lesson, err := h.service.GetByID(uint(id), getViewer(c), requestID)
 	if err != nil {
 		if err.Error() == "lesson not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Lesson not found", err.Error(), nil))
@@ -106,8 +129,16 @@ func (h *handler) CreateLessonHandler(c *gin.Context) {
 		return
 	}
 
-	lesson, err := h.service.Create(uint(classID), input, requestID, userID)
+	lesson, err := h.service.Create(uint(classID), input, requestID, userID, isAdmin(c))
 	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Class not found", err.Error(), nil))
+			return
+		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create lesson", err.Error(), nil))
 		return
 	}
@@ -132,12 +163,16 @@ func (h *handler) UpdateLessonHandler(c *gin.Context) {
 		return
 	}
 
-	lesson, err := h.service.Update(uint(id), input, requestID, userID)
+	lesson, err := h.service.Update(uint(id), input, requestID, userID, isAdmin(c))
 	if err != nil {
 		if err.Error() == "lesson not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Lesson not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update lesson", err.Error(), nil))
 		return
 	}
@@ -156,14 +191,56 @@ func (h *handler) DeleteLessonHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(id), requestID, userID); err != nil {
+	if err := h.service.Delete(uint(id), requestID, userID, isAdmin(c)); err != nil {
 		if err.Error() == "lesson not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Lesson not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete lesson", err.Error(), nil))
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lesson deleted successfully", nil))
 }
+
+func (h *handler) ReorderLessonsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	classIDStr := c.Param("id")
+
+	classID, err := strconv.ParseUint(classIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Class ID", "Class ID must be a valid number", nil))
+		return
+	}
+
+	var input ReorderLessonsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	lessons, err := h.service.Reorder(uint(classID), input, requestID, userID, isAdmin(c))
+	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Class not found", err.Error(), nil))
+			return
+		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
+		if err.Error() == "lesson list must contain every lesson of the class exactly once" {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to reorder lessons", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reorder lessons", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lessons reordered successfully", lessons))
+}
@@ -1,8 +1,12 @@
 package lessons
 
 import (
+	"errors"
+
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/ordering"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -12,9 +16,13 @@ type repository struct {
 type Repository interface {
 	FindByClassID(classID uint) ([]entities.Lesson, error)
 	FindByID(id uint) (entities.Lesson, error)
+	// Create, Update and Delete keep the class lessons numbered 1..n: the lesson takes the
+	// position in LessonOrder (clamped to the end) and its siblings shift around it
 	Create(lesson *entities.Lesson) error
 	Update(lesson *entities.Lesson) error
 	Delete(id uint) error
+	// Reorder renumbers the class lessons in the given order; ids must list every lesson once
+	Reorder(classID uint, ids []uint) error
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -34,13 +42,95 @@ func (r *repository) FindByID(id uint) (entities.Lesson, error) {
 }
 
 func (r *repository) Create(lesson *entities.Lesson) error {
-	return r.db.Create(lesson).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, lesson.ClassID)
+		if err != nil {
+			return err
+		}
+		position := lesson.LessonOrder
+
+		// Append first so the insert cannot collide with the unique order index
+		lesson.LessonOrder = ordering.Next(siblings)
+		if err := tx.Create(lesson).Error; err != nil {
+			return err
+		}
+
+		ordered := ordering.InsertAt(siblings, ordering.Slot{ID: lesson.ID, Position: lesson.LessonOrder}, position)
+		if err := lessonList(lesson.ClassID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		lesson.LessonOrder = ordering.PositionOf(ordered, lesson.ID)
+		return nil
+	})
 }
 
 func (r *repository) Update(lesson *entities.Lesson) error {
-	return r.db.Save(lesson).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, lesson.ClassID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("LessonOrder").Save(lesson).Error; err != nil {
+			return err
+		}
+
+		ordered := ordering.Move(siblings, lesson.ID, lesson.LessonOrder)
+		if err := lessonList(lesson.ClassID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		lesson.LessonOrder = ordering.PositionOf(ordered, lesson.ID)
+		return nil
+	})
 }
 
 func (r *repository) Delete(id uint) error {
-	return r.db.Delete(&entities.Lesson{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var lesson entities.Lesson
+		if err := tx.Select("id", "class_id").First(&lesson, id).Error; err != nil {
+			return err
+		}
+		if _, err := lockSiblings(tx, lesson.ClassID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.Lesson{}, id).Error; err != nil {
+			return err
+		}
+
+		// Close the gap the lesson leaves behind
+		list := lessonList(lesson.ClassID)
+		remaining, err := list.Load(tx)
+		if err != nil {
+			return err
+		}
+		return list.Resequence(tx, remaining)
+	})
+}
+
+func (r *repository) Reorder(classID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, classID)
+		if err != nil {
+			return err
+		}
+		ordered, ok := ordering.Arrange(siblings, ids)
+		if !ok {
+			return errors.New("lesson list must contain every lesson of the class exactly once")
+		}
+		return lessonList(classID).Resequence(tx, ordered)
+	})
+}
+
+// lessonList is the ordered list of lessons of a class
+func lessonList(classID uint) ordering.List {
+	return ordering.List{Model: &entities.Lesson{}, Column: "lesson_order", Scope: "class_id = ?", Args: []any{classID}}
+}
+
+// lockSiblings locks the class row, so concurrent changes to its lessons run one after
+// another, and returns the lessons in their current order
+func lockSiblings(tx *gorm.DB, classID uint) ([]ordering.Slot, error) {
+	var class entities.Class
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error; err != nil {
+		return nil, err
+	}
+	return lessonList(classID).Load(tx)
 }
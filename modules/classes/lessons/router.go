@@ -3,22 +3,27 @@ package lessons
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 )
 
 func LessonRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
-	lessonRepo := NewRepository(migrations.GetDB())
-	lessonService := NewService(lessonRepo)
+	db := migrations.GetDB()
+	lessonRepo := NewRepository(db)
+	lessonService := NewService(lessonRepo, access.NewService(access.NewRepository(db)), tutors.NewService(tutors.NewRepository(db)))
 	lessonHandler := NewHandler(lessonService)
 
 	classLessons := router.Group("/classes/:id")
 	{
-		classLessons.GET("/lessons", lessonHandler.GetLessonsByClassHandler)
+		classLessons.GET("/lessons", middleware.OptionalAuth(), lessonHandler.GetLessonsByClassHandler)
 		classLessons.POST("/lessons", requireAuth, requireAdminOrTutor, lessonHandler.CreateLessonHandler)
+		classLessons.PUT("/lessons/order", requireAuth, requireAdminOrTutor, lessonHandler.ReorderLessonsHandler)
 	}
 
 	lessons := router.Group("/lessons")
 	{
-		lessons.GET("/:id", lessonHandler.GetLessonByIDHandler)
+		lessons.GET("/:id", middleware.OptionalAuth(), lessonHandler.GetLessonByIDHandler)
 		lessons.PUT("/:id", requireAuth, requireAdminOrTutor, lessonHandler.UpdateLessonHandler)
 		lessons.DELETE("/:id", requireAuth, requireAdminOrTutor, lessonHandler.DeleteLessonHandler)
 	}
@@ -2,33 +2,56 @@ package lessons
 
 import (
 	"errors"
+	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
 type lessonService struct {
-	repo Repository
+	repo   Repository
+	access access.Service
+	tutors tutors.Service
 }
 
 type Service interface {
-	GetByClassID(classID uint, requestID string) ([]LessonResponse, error)
-	GetByID(id uint, requestID string) (*LessonResponse, error)
-	Create(classID uint, input CreateLessonInput, requestID string, userID uint) (*LessonResponse, error)
-	Update(id uint, input UpdateLessonInput, requestID string, userID uint) (*LessonResponse, error)
-	Delete(id uint, requestID string, userID uint) error
+	GetByClassID(classID uint, viewer access.Viewer, requestID string) ([]LessonResponse, error)
+	GetByID(id uint, viewer access.Viewer, requestID string) (*LessonResponse, error)
+	Create(classID uint, input CreateLessonInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error)
+	Update(id uint, input UpdateLessonInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
+	Reorder(classID uint, input ReorderLessonsInput, requestID string, userID uint, isAdmin bool) ([]LessonResponse, error)
 }
 
-func NewService(repo Repository) Service {
-	return &lessonService{repo: repo}
+func NewService(repo Repository, accessService access.Service, tutorService tutors.Service) Service {
+	return &lessonService{repo: repo, access: accessService, tutors: tutorService}
 }
 
-func (s *lessonService) GetByClassID(classID uint, requestID string) ([]LessonResponse, error) {
-	utils.LogInfo("lessons", "get_by_class", "Fetching lessons for class", requestID, 0, map[string]any{
+func (s *lessonService) GetByClassID(classID uint, viewer access.Viewer, requestID string) ([]LessonResponse, error) {
+	utils.LogInfo("lessons", "get_by_class", "Fetching lessons for class", requestID, viewer.UserID, map[string]any{
 		"class_id": classID,
 	})
 
+	visible, preview, err := s.access.ClassVisibility(classID, viewer)
+	if err != nil {
+		if err.Error() != "class not found" {
+			utils.LogError("lessons", "get_by_class", "Failed to check visibility: "+err.Error(), requestID, viewer.UserID, nil)
+		}
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.New("class not found")
+	}
+
+	canView, err := s.access.CanViewClass(classID, viewer)
+	if err != nil {
+		utils.LogError("lessons", "get_by_class", "Failed to check access: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+
 	lessons, err := s.repo.FindByClassID(classID)
 	if err != nil {
 		utils.LogError("lessons", "get_by_class", "Failed to fetch lessons: "+err.Error(), requestID, 0, nil)
@@ -36,19 +59,25 @@ func (s *lessonService) GetByClassID(classID uint, requestID string) ([]LessonRe
 	}
 
 	var responses []LessonResponse
+	now := time.Now()
 	for _, lesson := range lessons {
-		responses = append(responses, s.toResponse(lesson, true))
+		// Drafts are only listed for staff previewing the class
+		if !preview && !access.IsLive(lesson.Status, lesson.PublishAt, now) {
+			continue
+		}
+		responses = append(responses, s.toResponse(lesson, true, !canView))
 	}
 
-	utils.LogSuccess("lessons", "get_by_class", "Successfully fetched lessons", requestID, 0, map[string]any{
+	utils.LogSuccess("lessons", "get_by_class", "Successfully fetched lessons", requestID, viewer.UserID, map[string]any{
 		"class_id": classID,
 		"count":    len(responses),
+		"locked":   !canView,
 	})
 	return responses, nil
 }
 
-func (s *lessonService) GetByID(id uint, requestID string) (*LessonResponse, error) {
-	utils.LogInfo("lessons", "get_by_id", "Fetching lesson by ID", requestID, 0, map[string]any{
+func (s *lessonService) GetByID(id uint, viewer access.Viewer, requestID string) (*LessonResponse, error) {
+	utils.LogInfo("lessons", "get_by_id", "Fetching lesson by ID", requestID, viewer.UserID, map[string]any{
 		"lesson_id": id,
 	})
 
@@ -57,20 +86,48 @@ func (s *lessonService) GetByID(id uint, requestID string) (*LessonResponse, err
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("lesson not found")
 		}
-		utils.LogError("lessons", "get_by_id", "Failed to fetch lesson: "+err.Error(), requestID, 0, nil)
+		utils.LogError("lessons", "get_by_id", "Failed to fetch lesson: "+err.Error(), requestID, viewer.UserID, nil)
 		return nil, err
 	}
 
-	response := s.toResponse(lesson, true)
+	visible, preview, err := s.access.ClassVisibility(lesson.ClassID, viewer)
+	if err != nil {
+		if err.Error() == "class not found" {
+			return nil, errors.New("lesson not found")
+		}
+		utils.LogError("lessons", "get_by_id", "Failed to check visibility: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+	if !visible || (!preview && !access.IsLive(lesson.Status, lesson.PublishAt, time.Now())) {
+		return nil, errors.New("lesson not found")
+	}
+
+	canView, err := s.access.CanViewLesson(id, viewer)
+	if err != nil {
+		if err.Error() == "lesson not found" {
+			return nil, err
+		}
+		utils.LogError("lessons", "get_by_id", "Failed to check access: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+
+	response := s.toResponse(lesson, true, !canView)
 	return &response, nil
 }
 
-func (s *lessonService) Create(classID uint, input CreateLessonInput, requestID string, userID uint) (*LessonResponse, error) {
+func (s *lessonService) Create(classID uint, input CreateLessonInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error) {
 	utils.LogInfo("lessons", "create", "Creating new lesson", requestID, userID, map[string]any{
 		"class_id": classID,
 		"title":    input.Title,
 	})
 
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		utils.LogWarning("lessons", "create", "Lesson creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": classID,
+		})
+		return nil, err
+	}
+
 	lesson := &entities.Lesson{
 		ClassID:         classID,
 		CreatedByUserID: userID,
@@ -79,7 +136,9 @@ func (s *lessonService) Create(classID uint, input CreateLessonInput, requestID
 		LessonOrder:     input.LessonOrder,
 		StartTime:       input.StartTime,
 		EndTime:         input.EndTime,
+		CompletionRule:  input.CompletionRule,
 	}
+	access.ApplyPublishing(&lesson.Status, &lesson.PublishAt, input.Status, input.PublishAt)
 
 	if err := s.repo.Create(lesson); err != nil {
 		utils.LogError("lessons", "create", "Failed to create lesson: "+err.Error(), requestID, userID, nil)
@@ -91,15 +150,22 @@ func (s *lessonService) Create(classID uint, input CreateLessonInput, requestID
 		"title":     lesson.Title,
 	})
 
-	response := s.toResponse(*lesson, false)
+	response := s.toResponse(*lesson, false, false)
 	return &response, nil
 }
 
-func (s *lessonService) Update(id uint, input UpdateLessonInput, requestID string, userID uint) (*LessonResponse, error) {
+func (s *lessonService) Update(id uint, input UpdateLessonInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error) {
 	utils.LogInfo("lessons", "update", "Updating lesson", requestID, userID, map[string]any{
 		"lesson_id": id,
 	})
 
+	if err := s.tutors.AuthorizeLesson(id, userID, isAdmin); err != nil {
+		utils.LogWarning("lessons", "update", "Lesson update refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": id,
+		})
+		return nil, err
+	}
+
 	lesson, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -118,11 +184,19 @@ func (s *lessonService) Update(id uint, input UpdateLessonInput, requestID strin
 		lesson.LessonOrder = *input.LessonOrder
 	}
 	if input.StartTime != nil {
+		// A lesson moved by hand keeps its new time when its schedule regenerates
+		if lesson.ScheduleID != nil && (lesson.StartTime == nil || !lesson.StartTime.Equal(*input.StartTime)) {
+			lesson.IsRescheduled = true
+		}
 		lesson.StartTime = input.StartTime
 	}
 	if input.EndTime != nil {
 		lesson.EndTime = input.EndTime
 	}
+	if input.CompletionRule != nil {
+		lesson.CompletionRule = *input.CompletionRule
+	}
+	access.ApplyPublishing(&lesson.Status, &lesson.PublishAt, input.Status, input.PublishAt)
 
 	if err := s.repo.Update(&lesson); err != nil {
 		utils.LogError("lessons", "update", "Failed to update lesson: "+err.Error(), requestID, userID, nil)
@@ -133,15 +207,22 @@ func (s *lessonService) Update(id uint, input UpdateLessonInput, requestID strin
 		"lesson_id": id,
 	})
 
-	response := s.toResponse(lesson, true)
+	response := s.toResponse(lesson, true, false)
 	return &response, nil
 }
 
-func (s *lessonService) Delete(id uint, requestID string, userID uint) error {
+func (s *lessonService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
 	utils.LogInfo("lessons", "delete", "Deleting lesson", requestID, userID, map[string]any{
 		"lesson_id": id,
 	})
 
+	if err := s.tutors.AuthorizeLesson(id, userID, isAdmin); err != nil {
+		utils.LogWarning("lessons", "delete", "Lesson deletion refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": id,
+		})
+		return err
+	}
+
 	_, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -161,16 +242,72 @@ func (s *lessonService) Delete(id uint, requestID string, userID uint) error {
 	return nil
 }
 
-func (s *lessonService) toResponse(lesson entities.Lesson, includeResources bool) LessonResponse {
+func (s *lessonService) Reorder(classID uint, input ReorderLessonsInput, requestID string, userID uint, isAdmin bool) ([]LessonResponse, error) {
+	utils.LogInfo("lessons", "reorder", "Reordering lessons", requestID, userID, map[string]any{
+		"class_id": classID,
+		"count":    len(input.LessonIDs),
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		utils.LogWarning("lessons", "reorder", "Lesson reorder refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": classID,
+		})
+		return nil, err
+	}
+
+	if err := s.repo.Reorder(classID, input.LessonIDs); err != nil {
+		if err.Error() != "lesson list must contain every lesson of the class exactly once" {
+			utils.LogError("lessons", "reorder", "Failed to reorder lessons: "+err.Error(), requestID, userID, nil)
+		}
+		return nil, err
+	}
+
+	lessons, err := s.repo.FindByClassID(classID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]LessonResponse, 0, len(lessons))
+	for _, lesson := range lessons {
+		responses = append(responses, s.toResponse(lesson, false, false))
+	}
+
+	utils.LogSuccess("lessons", "reorder", "Lessons reordered successfully", requestID, userID, map[string]any{
+		"class_id": classID,
+	})
+	return responses, nil
+}
+
+// toResponse builds the lesson response; a locked lesson keeps only its outline
+// (title, order and schedule) and lists its resources without their URLs
+func (s *lessonService) toResponse(lesson entities.Lesson, includeResources bool, locked bool) LessonResponse {
 	response := LessonResponse{
-		ID:            lesson.ID,
-		ClassID:       lesson.ClassID,
-		Title:         lesson.Title,
-		Description:   lesson.Description,
-		LessonOrder:   lesson.LessonOrder,
-		StartTime:     lesson.StartTime,
-		EndTime:       lesson.EndTime,
-		ResourceCount: len(lesson.Resources),
+		ID:             lesson.ID,
+		ClassID:        lesson.ClassID,
+		Title:          lesson.Title,
+		Description:    lesson.Description,
+		LessonOrder:    lesson.LessonOrder,
+		StartTime:      lesson.StartTime,
+		EndTime:        lesson.EndTime,
+		ResourceCount:  len(lesson.Resources),
+		CompletionRule: lesson.CompletionRule,
+		Locked:         locked,
+
+		ScheduleID:     lesson.ScheduleID,
+		OccurrenceDate: lesson.OccurrenceDate,
+		IsRescheduled:  lesson.IsRescheduled,
+
+		Status:    lesson.Status,
+		PublishAt: lesson.PublishAt,
+		IsLive:    access.IsLive(lesson.Status, lesson.PublishAt, time.Now()),
+	}
+	if response.Status == "" {
+		response.Status = entities.ContentPublished
+	}
+	if response.CompletionRule == "" {
+		response.CompletionRule = entities.CompletionManual
+	}
+	if locked {
+		response.Description = ""
 	}
 
 	if lesson.Class.ID != 0 {
@@ -179,12 +316,18 @@ func (s *lessonService) toResponse(lesson entities.Lesson, includeResources bool
 
 	if includeResources && len(lesson.Resources) > 0 {
 		for _, res := range lesson.Resources {
-			response.Resources = append(response.Resources, ResourceResponse{
-				ID:    res.ID,
-				Type:  res.Type,
-				Title: res.Title,
-				URL:   res.URL,
-			})
+			resource := ResourceResponse{
+				ID:             res.ID,
+				Type:           res.Type,
+				Title:          res.Title,
+				URL:            res.URL,
+				MinViewPercent: res.MinViewPercent,
+				Locked:         locked,
+			}
+			if locked {
+				resource.URL = ""
+			}
+			response.Resources = append(response.Resources, resource)
 		}
 	}
 
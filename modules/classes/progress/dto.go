@@ -0,0 +1,74 @@
+package progress
+
+import "time"
+
+// ==========================================
+// COMPLETION DTOs
+// ==========================================
+
+// ResourceViewInput reports how much of a video or recording the student has watched so far
+type ResourceViewInput struct {
+	Percent *int `json:"percent" binding:"required,min=0,max=100"`
+}
+
+type LessonCompletionResponse struct {
+	LessonID       uint       `json:"lessonId"`
+	Completed      bool       `json:"completed"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+	Source         string     `json:"source,omitempty"`
+	CourseProgress float64    `json:"courseProgress"` // percent of the course's lessons completed
+}
+
+type ResourceCompletionResponse struct {
+	ResourceID      uint       `json:"resourceId"`
+	LessonID        uint       `json:"lessonId"`
+	Completed       bool       `json:"completed"`
+	CompletedAt     *time.Time `json:"completedAt,omitempty"`
+	LessonCompleted bool       `json:"lessonCompleted"`
+	CourseProgress  float64    `json:"courseProgress"`
+}
+
+// ==========================================
+// PROGRESS DTOs
+// ==========================================
+
+type LessonProgress struct {
+	LessonID           uint       `json:"lessonId"`
+	ClassID            uint       `json:"classId"`
+	ClassName          string     `json:"className"`
+	Title              string     `json:"title"`
+	CompletionRule     string     `json:"completionRule"`
+	TotalResources     int        `json:"totalResources"`
+	CompletedResources int        `json:"completedResources"`
+	Completed          bool       `json:"completed"`
+	CompletedAt        *time.Time `json:"completedAt,omitempty"`
+}
+
+// CourseProgressResponse is a student's own progress through one course
+type CourseProgressResponse struct {
+	CourseID         uint             `json:"courseId"`
+	CourseName       string           `json:"courseName"`
+	TotalLessons     int              `json:"totalLessons"`
+	CompletedLessons int              `json:"completedLessons"`
+	ProgressPercent  float64          `json:"progressPercent"`
+	Lessons          []LessonProgress `json:"lessons"`
+}
+
+type StudentProgress struct {
+	UserID           uint       `json:"userId"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	CompletedLessons int        `json:"completedLessons"`
+	ProgressPercent  float64    `json:"progressPercent"`
+	LastCompletedAt  *time.Time `json:"lastCompletedAt,omitempty"`
+}
+
+// ClassProgressResponse is the tutor's view of every enrolled student in one class
+type ClassProgressResponse struct {
+	ClassID         uint              `json:"classId"`
+	ClassName       string            `json:"className"`
+	CourseID        uint              `json:"courseId"`
+	TotalLessons    int               `json:"totalLessons"`
+	AverageProgress float64           `json:"averageProgress"`
+	Students        []StudentProgress `json:"students"`
+}
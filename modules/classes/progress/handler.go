@@ -0,0 +1,189 @@
+package progress
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	CompleteLessonHandler(c *gin.Context)
+	UncompleteLessonHandler(c *gin.Context)
+	CompleteResourceHandler(c *gin.Context)
+	RecordResourceViewHandler(c *gin.Context)
+	GetMyCourseProgressHandler(c *gin.Context)
+	GetClassProgressHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps the shared lookup and authorization errors
+func respondError(c *gin.Context, title string, err error) {
+	switch err.Error() {
+	case "lesson not found", "resource not found", "course not found", "class not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+	case "you are not enrolled in this course":
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+	case "this lesson completes automatically", "this resource completes once enough of it is viewed":
+		c.JSON(http.StatusConflict, utils.BuildResponseFailed(title, err.Error(), nil))
+	default:
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed(title, err.Error(), nil))
+	}
+}
+
+// ==========================================
+// Completion Handlers
+// ==========================================
+
+func (h *handler) CompleteLessonHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	completion, err := h.service.CompleteLesson(lessonID, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to complete lesson", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lesson marked as completed", completion))
+}
+
+func (h *handler) UncompleteLessonHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UncompleteLesson(lessonID, getUserID(c), getRequestID(c)); err != nil {
+		respondError(c, "Failed to clear lesson completion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lesson completion cleared", nil))
+}
+
+func (h *handler) CompleteResourceHandler(c *gin.Context) {
+	resourceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	completion, err := h.service.CompleteResource(resourceID, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to complete resource", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Resource marked as completed", completion))
+}
+
+func (h *handler) RecordResourceViewHandler(c *gin.Context) {
+	resourceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input ResourceViewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	completion, err := h.service.RecordResourceView(resourceID, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to record view", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("View recorded successfully", completion))
+}
+
+// ==========================================
+// Progress Handlers
+// ==========================================
+
+func (h *handler) GetMyCourseProgressHandler(c *gin.Context) {
+	courseID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.GetMyCourseProgress(courseID, getUserID(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch progress", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Progress retrieved successfully", progress))
+}
+
+func (h *handler) GetClassProgressHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.GetClassProgress(classID, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch class progress", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Class progress retrieved successfully", progress))
+}
@@ -0,0 +1,213 @@
+package progress
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindCourseByID(id uint) (entities.Course, error)
+	FindClassByID(id uint) (entities.Class, error)
+	FindLessonByID(id uint) (entities.Lesson, error)
+	FindResourceByID(id uint) (entities.LessonResource, error)
+	FindLessonsByCourseID(courseID uint) ([]entities.Lesson, error)
+	FindLessonsByClassID(classID uint) ([]entities.Lesson, error)
+	CountLessonsByCourseID(courseID uint) (int64, error)
+	CountQuizzesByLessonID(lessonID uint) (int64, error)
+
+	FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error)
+	HasApprovedRegistration(courseID, userID uint) (bool, error)
+
+	FindLessonCompletion(lessonID, userID uint) (entities.LessonCompletion, error)
+	FindLessonCompletions(lessonIDs []uint) ([]entities.LessonCompletion, error)
+	FindUserLessonCompletions(lessonIDs []uint, userID uint) ([]entities.LessonCompletion, error)
+	CountCourseCompletions(courseID, userID uint) (int64, error)
+	// CreateLessonCompletion keeps the first completion when the lesson was already done
+	CreateLessonCompletion(completion *entities.LessonCompletion) error
+	DeleteLessonCompletion(lessonID, userID uint) error
+
+	FindResourceCompletion(resourceID, userID uint) (entities.ResourceCompletion, error)
+	FindResourceCompletions(lessonIDs []uint, userID uint) ([]entities.ResourceCompletion, error)
+	CountLessonResourceCompletions(lessonID, userID uint) (int64, error)
+	CreateResourceCompletion(completion *entities.ResourceCompletion) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Course Content Methods
+// ==========================================
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+func (r *repository) FindClassByID(id uint) (entities.Class, error) {
+	var class entities.Class
+	err := r.db.First(&class, id).Error
+	return class, err
+}
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.Preload("Class").Preload("Resources").First(&lesson, id).Error
+	return lesson, err
+}
+
+func (r *repository) FindResourceByID(id uint) (entities.LessonResource, error) {
+	var resource entities.LessonResource
+	err := r.db.Preload("Lesson.Class").Preload("Lesson.Resources").First(&resource, id).Error
+	return resource, err
+}
+
+func (r *repository) FindLessonsByCourseID(courseID uint) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	err := r.released(r.db.Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL")).
+		Where("classes.course_id = ?", courseID).
+		Preload("Class").
+		Preload("Resources").
+		Order("classes.id ASC, lessons.lesson_order ASC").
+		Find(&lessons).Error
+	return lessons, err
+}
+
+func (r *repository) FindLessonsByClassID(classID uint) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	err := access.WhereLive(r.db.Where("class_id = ?", classID), "lessons").
+		Order("lesson_order ASC").
+		Find(&lessons).Error
+	return lessons, err
+}
+
+func (r *repository) CountLessonsByCourseID(courseID uint) (int64, error) {
+	var count int64
+	err := r.released(r.db.Model(&entities.Lesson{}).
+		Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL")).
+		Where("classes.course_id = ?", courseID).
+		Count(&count).Error
+	return count, err
+}
+
+// released limits a query joining lessons to classes to live lessons of released classes,
+// the ones students can open; progress counts nothing else
+func (r *repository) released(query *gorm.DB) *gorm.DB {
+	return access.WhereReleased(access.WhereLive(query, "lessons"), "classes")
+}
+
+func (r *repository) CountQuizzesByLessonID(lessonID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.LessonQuiz{}).Where("lesson_id = ?", lessonID).Count(&count).Error
+	return count, err
+}
+
+// ==========================================
+// Registration Methods
+// ==========================================
+
+func (r *repository) FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND status = ?", courseID, "approved").
+		Preload("User").
+		Order("id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) HasApprovedRegistration(courseID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.CourseRegistration{}).
+		Where("course_id = ? AND user_id = ? AND status = ?", courseID, userID, "approved").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ==========================================
+// Lesson Completion Methods
+// ==========================================
+
+func (r *repository) FindLessonCompletion(lessonID, userID uint) (entities.LessonCompletion, error) {
+	var completion entities.LessonCompletion
+	err := r.db.Where("lesson_id = ? AND user_id = ?", lessonID, userID).First(&completion).Error
+	return completion, err
+}
+
+func (r *repository) FindLessonCompletions(lessonIDs []uint) ([]entities.LessonCompletion, error) {
+	var completions []entities.LessonCompletion
+	if len(lessonIDs) == 0 {
+		return completions, nil
+	}
+	err := r.db.Where("lesson_id IN ?", lessonIDs).Find(&completions).Error
+	return completions, err
+}
+
+func (r *repository) FindUserLessonCompletions(lessonIDs []uint, userID uint) ([]entities.LessonCompletion, error) {
+	var completions []entities.LessonCompletion
+	if len(lessonIDs) == 0 {
+		return completions, nil
+	}
+	err := r.db.Where("lesson_id IN ? AND user_id = ?", lessonIDs, userID).Find(&completions).Error
+	return completions, err
+}
+
+func (r *repository) CountCourseCompletions(courseID, userID uint) (int64, error) {
+	var count int64
+	err := r.released(r.db.Model(&entities.LessonCompletion{}).
+		Joins("JOIN lessons ON lessons.id = lesson_completions.lesson_id AND lessons.deleted_at IS NULL").
+		Joins("JOIN classes ON classes.id = lessons.class_id AND classes.deleted_at IS NULL")).
+		Where("classes.course_id = ? AND lesson_completions.user_id = ?", courseID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreateLessonCompletion(completion *entities.LessonCompletion) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit("Lesson").Create(completion).Error
+}
+
+// DeleteLessonCompletion removes the row for good so the unique index allows completing again
+func (r *repository) DeleteLessonCompletion(lessonID, userID uint) error {
+	return r.db.Unscoped().
+		Where("lesson_id = ? AND user_id = ?", lessonID, userID).
+		Delete(&entities.LessonCompletion{}).Error
+}
+
+// ==========================================
+// Resource Completion Methods
+// ==========================================
+
+func (r *repository) FindResourceCompletion(resourceID, userID uint) (entities.ResourceCompletion, error) {
+	var completion entities.ResourceCompletion
+	err := r.db.Where("resource_id = ? AND user_id = ?", resourceID, userID).First(&completion).Error
+	return completion, err
+}
+
+func (r *repository) FindResourceCompletions(lessonIDs []uint, userID uint) ([]entities.ResourceCompletion, error) {
+	var completions []entities.ResourceCompletion
+	if len(lessonIDs) == 0 {
+		return completions, nil
+	}
+	err := r.db.Where("lesson_id IN ? AND user_id = ?", lessonIDs, userID).Find(&completions).Error
+	return completions, err
+}
+
+func (r *repository) CountLessonResourceCompletions(lessonID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.ResourceCompletion{}).
+		Joins("JOIN lesson_resources ON lesson_resources.id = resource_completions.resource_id AND lesson_resources.deleted_at IS NULL").
+		Where("resource_completions.lesson_id = ? AND resource_completions.user_id = ?", lessonID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreateResourceCompletion(completion *entities.ResourceCompletion) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(completion).Error
+}
@@ -0,0 +1,29 @@
+package progress
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+)
+
+func ProgressRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	db := migrations.GetDB()
+	handler := NewHandler(NewService(NewRepository(db), tutors.NewService(tutors.NewRepository(db))))
+
+	lessons := router.Group("/lessons/:id")
+	lessons.Use(requireAuth)
+	{
+		lessons.POST("/complete", handler.CompleteLessonHandler)
+		lessons.DELETE("/complete", handler.UncompleteLessonHandler)
+	}
+
+	resources := router.Group("/resources/:id")
+	resources.Use(requireAuth)
+	{
+		resources.POST("/complete", handler.CompleteResourceHandler)
+		resources.POST("/view", handler.RecordResourceViewHandler)
+	}
+
+	router.GET("/courses/:id/progress/me", requireAuth, handler.GetMyCourseProgressHandler)
+	router.GET("/classes/:id/progress", requireAuth, requireAdminOrTutor, handler.GetClassProgressHandler)
+}
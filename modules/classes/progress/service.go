@@ -0,0 +1,466 @@
+package progress
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type progressService struct {
+	repo   Repository
+	tutors tutors.Service
+}
+
+type Service interface {
+	CompleteLesson(lessonID uint, userID uint, requestID string) (*LessonCompletionResponse, error)
+	UncompleteLesson(lessonID uint, userID uint, requestID string) error
+	CompleteResource(resourceID uint, userID uint, requestID string) (*ResourceCompletionResponse, error)
+	RecordResourceView(resourceID uint, input ResourceViewInput, userID uint, requestID string) (*ResourceCompletionResponse, error)
+
+	GetMyCourseProgress(courseID uint, userID uint, requestID string) (*CourseProgressResponse, error)
+	GetClassProgress(classID uint, userID uint, isAdmin bool, requestID string) (*ClassProgressResponse, error)
+	// CourseProgressPercent is the share of a course's lessons the student has completed
+	CourseProgressPercent(courseID uint, userID uint) (float64, error)
+	// CompleteByRule completes the lesson for the student when the lesson follows that rule,
+	// e.g. once the quizzes module sees every quiz of a "quiz" lesson passed
+	CompleteByRule(lessonID uint, userID uint, rule string, requestID string) error
+}
+
+func NewService(repo Repository, tutorService tutors.Service) Service {
+	return &progressService{repo: repo, tutors: tutorService}
+}
+
+// ==========================================
+// Lesson Completion
+// ==========================================
+
+func (s *progressService) CompleteLesson(lessonID uint, userID uint, requestID string) (*LessonCompletionResponse, error) {
+	utils.LogInfo("progress", "complete_lesson", "Marking lesson as completed", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	lesson, err := s.findEnrolledLesson(lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireManual(lesson); err != nil {
+		return nil, err
+	}
+
+	completion := &entities.LessonCompletion{
+		UserID:      userID,
+		LessonID:    lessonID,
+		CompletedAt: time.Now(),
+		Source:      entities.CompletionSourceManual,
+	}
+	if err := s.repo.CreateLessonCompletion(completion); err != nil {
+		utils.LogError("progress", "complete_lesson", "Failed to save completion: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("progress", "complete_lesson", "Lesson completed", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+	return s.lessonCompletionResponse(lesson, userID)
+}
+
+func (s *progressService) UncompleteLesson(lessonID uint, userID uint, requestID string) error {
+	utils.LogInfo("progress", "uncomplete_lesson", "Clearing lesson completion", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	lesson, err := s.findEnrolledLesson(lessonID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireManual(lesson); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteLessonCompletion(lessonID, userID); err != nil {
+		utils.LogError("progress", "uncomplete_lesson", "Failed to clear completion: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("progress", "uncomplete_lesson", "Lesson completion cleared", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+	return nil
+}
+
+// ==========================================
+// Resource Completion
+// ==========================================
+
+func (s *progressService) CompleteResource(resourceID uint, userID uint, requestID string) (*ResourceCompletionResponse, error) {
+	utils.LogInfo("progress", "complete_resource", "Marking resource as completed", requestID, userID, map[string]any{
+		"resource_id": resourceID,
+	})
+
+	resource, err := s.findEnrolledResource(resourceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if resource.MinViewPercent > 0 {
+		return nil, errors.New("this resource completes once enough of it is viewed")
+	}
+
+	return s.completeResource(resource, userID, entities.CompletionSourceManual, requestID)
+}
+
+func (s *progressService) RecordResourceView(resourceID uint, input ResourceViewInput, userID uint, requestID string) (*ResourceCompletionResponse, error) {
+	utils.LogInfo("progress", "record_view", "Recording resource view", requestID, userID, map[string]any{
+		"resource_id": resourceID,
+		"percent":     *input.Percent,
+	})
+
+	resource, err := s.findEnrolledResource(resourceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *input.Percent < resource.MinViewPercent {
+		return s.resourceCompletionResponse(resource, userID)
+	}
+	return s.completeResource(resource, userID, entities.CompletionSourceAuto, requestID)
+}
+
+// completeResource records the resource as done and, under the "resources" rule,
+// completes the lesson once every one of its resources is done
+func (s *progressService) completeResource(resource entities.LessonResource, userID uint, source string, requestID string) (*ResourceCompletionResponse, error) {
+	completion := &entities.ResourceCompletion{
+		UserID:      userID,
+		ResourceID:  resource.ID,
+		LessonID:    resource.LessonID,
+		CompletedAt: time.Now(),
+		Source:      source,
+	}
+	if err := s.repo.CreateResourceCompletion(completion); err != nil {
+		utils.LogError("progress", "complete_resource", "Failed to save completion: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	lesson := resource.Lesson
+	if lesson.CompletionRule == entities.CompletionResources {
+		done, err := s.repo.CountLessonResourceCompletions(lesson.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if int(done) >= len(lesson.Resources) {
+			if err := s.repo.CreateLessonCompletion(&entities.LessonCompletion{
+				UserID:      userID,
+				LessonID:    lesson.ID,
+				CompletedAt: time.Now(),
+				Source:      entities.CompletionSourceAuto,
+			}); err != nil {
+				utils.LogError("progress", "complete_resource", "Failed to complete lesson: "+err.Error(), requestID, userID, nil)
+				return nil, err
+			}
+		}
+	}
+
+	utils.LogSuccess("progress", "complete_resource", "Resource completed", requestID, userID, map[string]any{
+		"resource_id": resource.ID,
+		"source":      source,
+	})
+	return s.resourceCompletionResponse(resource, userID)
+}
+
+func (s *progressService) CompleteByRule(lessonID uint, userID uint, rule string, requestID string) error {
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("lesson not found")
+		}
+		return err
+	}
+	if lesson.CompletionRule != rule {
+		return nil
+	}
+
+	if err := s.repo.CreateLessonCompletion(&entities.LessonCompletion{
+		UserID:      userID,
+		LessonID:    lessonID,
+		CompletedAt: time.Now(),
+		Source:      entities.CompletionSourceAuto,
+	}); err != nil {
+		utils.LogError("progress", "complete_by_rule", "Failed to complete lesson: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("progress", "complete_by_rule", "Lesson completed automatically", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+		"rule":      rule,
+	})
+	return nil
+}
+
+// ==========================================
+// Progress
+// ==========================================
+
+func (s *progressService) GetMyCourseProgress(courseID uint, userID uint, requestID string) (*CourseProgressResponse, error) {
+	utils.LogInfo("progress", "my_course", "Fetching course progress", requestID, userID, map[string]any{
+		"course_id": courseID,
+	})
+
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+	if err := s.requireEnrollment(courseID, userID); err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.repo.FindLessonsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	lessonIDs := make([]uint, 0, len(lessons))
+	for _, lesson := range lessons {
+		lessonIDs = append(lessonIDs, lesson.ID)
+	}
+	lessonCompletions, err := s.repo.FindUserLessonCompletions(lessonIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	completedAt := make(map[uint]time.Time, len(lessonCompletions))
+	for _, completion := range lessonCompletions {
+		completedAt[completion.LessonID] = completion.CompletedAt
+	}
+	resourceCompletions, err := s.repo.FindResourceCompletions(lessonIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	resourcesDone := make(map[uint]bool, len(resourceCompletions))
+	for _, completion := range resourceCompletions {
+		resourcesDone[completion.ResourceID] = true
+	}
+
+	response := &CourseProgressResponse{
+		CourseID:     course.ID,
+		CourseName:   course.NameCourse,
+		TotalLessons: len(lessons),
+		Lessons:      make([]LessonProgress, 0, len(lessons)),
+	}
+	for _, lesson := range lessons {
+		entry := LessonProgress{
+			LessonID:       lesson.ID,
+			ClassID:        lesson.ClassID,
+			ClassName:      lesson.Class.Name,
+			Title:          lesson.Title,
+			CompletionRule: ruleOf(lesson),
+			TotalResources: len(lesson.Resources),
+		}
+		for _, res := range lesson.Resources {
+			if resourcesDone[res.ID] {
+				entry.CompletedResources++
+			}
+		}
+		if at, ok := completedAt[lesson.ID]; ok {
+			entry.Completed = true
+			entry.CompletedAt = &at
+			response.CompletedLessons++
+		}
+		response.Lessons = append(response.Lessons, entry)
+	}
+	response.ProgressPercent = percent(response.CompletedLessons, response.TotalLessons)
+	return response, nil
+}
+
+func (s *progressService) GetClassProgress(classID uint, userID uint, isAdmin bool, requestID string) (*ClassProgressResponse, error) {
+	utils.LogInfo("progress", "class", "Fetching class progress", requestID, userID, map[string]any{
+		"class_id": classID,
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	lessons, err := s.repo.FindLessonsByClassID(classID)
+	if err != nil {
+		return nil, err
+	}
+	lessonIDs := make([]uint, 0, len(lessons))
+	for _, lesson := range lessons {
+		lessonIDs = append(lessonIDs, lesson.ID)
+	}
+	completions, err := s.repo.FindLessonCompletions(lessonIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint][]entities.LessonCompletion)
+	for _, completion := range completions {
+		byUser[completion.UserID] = append(byUser[completion.UserID], completion)
+	}
+
+	registrations, err := s.repo.FindApprovedRegistrations(class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ClassProgressResponse{
+		ClassID:      class.ID,
+		ClassName:    class.Name,
+		CourseID:     class.CourseID,
+		TotalLessons: len(lessons),
+		Students:     make([]StudentProgress, 0, len(registrations)),
+	}
+	var total float64
+	for _, reg := range registrations {
+		student := StudentProgress{
+			UserID:           reg.UserID,
+			Username:         reg.User.Username,
+			Email:            reg.User.Email,
+			CompletedLessons: len(byUser[reg.UserID]),
+		}
+		for _, completion := range byUser[reg.UserID] {
+			if student.LastCompletedAt == nil || completion.CompletedAt.After(*student.LastCompletedAt) {
+				at := completion.CompletedAt
+				student.LastCompletedAt = &at
+			}
+		}
+		student.ProgressPercent = percent(student.CompletedLessons, response.TotalLessons)
+		total += student.ProgressPercent
+		response.Students = append(response.Students, student)
+	}
+	if len(response.Students) > 0 {
+		response.AverageProgress = math.Round(total/float64(len(response.Students))*10) / 10
+	}
+	return response, nil
+}
+
+func (s *progressService) CourseProgressPercent(courseID uint, userID uint) (float64, error) {
+	total, err := s.repo.CountLessonsByCourseID(courseID)
+	if err != nil {
+		return 0, err
+	}
+	completed, err := s.repo.CountCourseCompletions(courseID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return percent(int(completed), int(total)), nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *progressService) requireEnrollment(courseID, userID uint) error {
+	enrolled, err := s.repo.HasApprovedRegistration(courseID, userID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return errors.New("you are not enrolled in this course")
+	}
+	return nil
+}
+
+// requireManual refuses manual changes on lessons that follow an automatic rule.
+// A lesson with nothing to follow (no resources, no quiz) falls back to manual completion.
+func (s *progressService) requireManual(lesson entities.Lesson) error {
+	switch lesson.CompletionRule {
+	case entities.CompletionResources:
+		if len(lesson.Resources) > 0 {
+			return errors.New("this lesson completes automatically")
+		}
+	case entities.CompletionQuiz:
+		quizzes, err := s.repo.CountQuizzesByLessonID(lesson.ID)
+		if err != nil {
+			return err
+		}
+		if quizzes > 0 {
+			return errors.New("this lesson completes automatically")
+		}
+	}
+	return nil
+}
+
+func (s *progressService) findEnrolledLesson(lessonID, userID uint) (entities.Lesson, error) {
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lesson, errors.New("lesson not found")
+		}
+		return lesson, err
+	}
+	return lesson, s.requireEnrollment(lesson.Class.CourseID, userID)
+}
+
+func (s *progressService) findEnrolledResource(resourceID, userID uint) (entities.LessonResource, error) {
+	resource, err := s.repo.FindResourceByID(resourceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resource, errors.New("resource not found")
+		}
+		return resource, err
+	}
+	return resource, s.requireEnrollment(resource.Lesson.Class.CourseID, userID)
+}
+
+func (s *progressService) lessonCompletionResponse(lesson entities.Lesson, userID uint) (*LessonCompletionResponse, error) {
+	response := &LessonCompletionResponse{LessonID: lesson.ID}
+	completion, err := s.repo.FindLessonCompletion(lesson.ID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		response.Completed = true
+		response.CompletedAt = &completion.CompletedAt
+		response.Source = completion.Source
+	}
+	if response.CourseProgress, err = s.CourseProgressPercent(lesson.Class.CourseID, userID); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *progressService) resourceCompletionResponse(resource entities.LessonResource, userID uint) (*ResourceCompletionResponse, error) {
+	response := &ResourceCompletionResponse{ResourceID: resource.ID, LessonID: resource.LessonID}
+	completion, err := s.repo.FindResourceCompletion(resource.ID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		response.Completed = true
+		response.CompletedAt = &completion.CompletedAt
+	}
+	lesson, err := s.lessonCompletionResponse(resource.Lesson, userID)
+	if err != nil {
+		return nil, err
+	}
+	response.LessonCompleted = lesson.Completed
+	response.CourseProgress = lesson.CourseProgress
+	return response, nil
+}
+
+// ruleOf treats lessons without a rule as manually completed
+func ruleOf(lesson entities.Lesson) string {
+	if lesson.CompletionRule == "" {
+		return entities.CompletionManual
+	}
+	return lesson.CompletionRule
+}
+
+func percent(done, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(done)/float64(total)*1000) / 10
+}
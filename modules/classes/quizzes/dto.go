@@ -0,0 +1,157 @@
+package quizzes
+
+import "time"
+
+// ==========================================
+// QUIZ DTOs
+// ==========================================
+
+type CreateQuizInput struct {
+	Title            string   `json:"title" binding:"required,max=255"`
+	Description      string   `json:"description"`
+	MaxAttempts      int      `json:"maxAttempts" binding:"min=0,max=100"`            // 0 means unlimited
+	PassingScore     *float64 `json:"passingScore" binding:"omitempty,min=0,max=100"` // default 70
+	ShowExplanations *bool    `json:"showExplanations"`                               // default true
+}
+
+type UpdateQuizInput struct {
+	Title            *string  `json:"title" binding:"omitempty,max=255"`
+	Description      *string  `json:"description"`
+	MaxAttempts      *int     `json:"maxAttempts" binding:"omitempty,min=0,max=100"`
+	PassingScore     *float64 `json:"passingScore" binding:"omitempty,min=0,max=100"`
+	ShowExplanations *bool    `json:"showExplanations"`
+}
+
+type QuizResponse struct {
+	ID               uint               `json:"id"`
+	LessonID         uint               `json:"lessonId"`
+	Title            string             `json:"title"`
+	Description      string             `json:"description"`
+	MaxAttempts      int                `json:"maxAttempts"`
+	PassingScore     float64            `json:"passingScore"`
+	ShowExplanations bool               `json:"showExplanations"`
+	QuestionCount    int                `json:"questionCount"`
+	Questions        []QuestionResponse `json:"questions,omitempty"`
+}
+
+// ==========================================
+// QUESTION DTOs
+// ==========================================
+
+// QuestionInput follows the five-option format of try-out questions
+type QuestionInput struct {
+	QuestionText  string `json:"questionText" binding:"required"`
+	ImageURL      string `json:"imageUrl"`
+	Explanation   string `json:"explanation"`
+	OrderNumber   int    `json:"orderNumber" binding:"required,min=1"`
+	OptionA       string `json:"optionA" binding:"required"`
+	OptionB       string `json:"optionB" binding:"required"`
+	OptionC       string `json:"optionC" binding:"required"`
+	OptionD       string `json:"optionD" binding:"required"`
+	OptionE       string `json:"optionE" binding:"required"`
+	CorrectOption string `json:"correctOption" binding:"required,oneof=A B C D E"`
+}
+
+type UpdateQuestionInput struct {
+	QuestionText  *string `json:"questionText"`
+	ImageURL      *string `json:"imageUrl"`
+	Explanation   *string `json:"explanation"`
+	OrderNumber   *int    `json:"orderNumber" binding:"omitempty,min=1"`
+	OptionA       *string `json:"optionA"`
+	OptionB       *string `json:"optionB"`
+	OptionC       *string `json:"optionC"`
+	OptionD       *string `json:"optionD"`
+	OptionE       *string `json:"optionE"`
+	CorrectOption *string `json:"correctOption" binding:"omitempty,oneof=A B C D E"`
+}
+
+// ImportQuestionsInput copies questions from try-outs, the shared question bank, onto the end of the quiz
+type ImportQuestionsInput struct {
+	TryOutQuestionIDs []uint `json:"tryOutQuestionIds" binding:"required,min=1,max=100"`
+}
+
+// QuestionResponse leaves out the answer and explanation for students
+type QuestionResponse struct {
+	ID               uint   `json:"id"`
+	OrderNumber      int    `json:"orderNumber"`
+	QuestionText     string `json:"questionText"`
+	ImageURL         string `json:"imageUrl,omitempty"`
+	OptionA          string `json:"optionA"`
+	OptionB          string `json:"optionB"`
+	OptionC          string `json:"optionC"`
+	OptionD          string `json:"optionD"`
+	OptionE          string `json:"optionE"`
+	CorrectOption    string `json:"correctOption,omitempty"`
+	Explanation      string `json:"explanation,omitempty"`
+	SourceQuestionID *uint  `json:"sourceQuestionId,omitempty"`
+}
+
+// ==========================================
+// ATTEMPT DTOs
+// ==========================================
+
+type SubmitAttemptInput struct {
+	Answers []AnswerInput `json:"answers" binding:"required,dive"`
+}
+
+type AnswerInput struct {
+	QuestionID     uint    `json:"questionId" binding:"required"`
+	SelectedOption *string `json:"selectedOption" binding:"omitempty,oneof=A B C D E"`
+}
+
+// AnswerResult shows the correct option and explanation only when the quiz allows it
+type AnswerResult struct {
+	QuestionID     uint    `json:"questionId"`
+	SelectedOption *string `json:"selectedOption"`
+	IsCorrect      bool    `json:"isCorrect"`
+	CorrectOption  string  `json:"correctOption,omitempty"`
+	Explanation    string  `json:"explanation,omitempty"`
+}
+
+type AttemptResponse struct {
+	ID             uint           `json:"id"`
+	QuizID         uint           `json:"quizId"`
+	AttemptNumber  int            `json:"attemptNumber"`
+	CorrectCount   int            `json:"correctCount"`
+	TotalQuestions int            `json:"totalQuestions"`
+	Score          float64        `json:"score"`
+	Passed         bool           `json:"passed"`
+	SubmittedAt    time.Time      `json:"submittedAt"`
+	AttemptsLeft   *int           `json:"attemptsLeft,omitempty"` // nil when attempts are unlimited
+	Answers        []AnswerResult `json:"answers,omitempty"`
+}
+
+type MyAttemptsResponse struct {
+	QuizID       uint              `json:"quizId"`
+	AttemptsUsed int               `json:"attemptsUsed"`
+	AttemptsLeft *int              `json:"attemptsLeft,omitempty"`
+	BestScore    *float64          `json:"bestScore,omitempty"`
+	Passed       bool              `json:"passed"`
+	Attempts     []AttemptResponse `json:"attempts"`
+}
+
+// ==========================================
+// RESULT DTOs
+// ==========================================
+
+type StudentQuizResult struct {
+	UserID        uint       `json:"userId"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Attempts      int        `json:"attempts"`
+	BestScore     *float64   `json:"bestScore,omitempty"`
+	Passed        bool       `json:"passed"`
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+}
+
+// QuizResultsResponse is the tutor's view of every enrolled student's results on one quiz
+type QuizResultsResponse struct {
+	QuizID           uint                `json:"quizId"`
+	Title            string              `json:"title"`
+	PassingScore     float64             `json:"passingScore"`
+	Enrolled         int                 `json:"enrolled"`
+	Attempted        int                 `json:"attempted"`
+	PassedCount      int                 `json:"passedCount"`
+	AverageBestScore float64             `json:"averageBestScore"`
+	Students         []StudentQuizResult `json:"students"`
+}
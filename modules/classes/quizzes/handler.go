@@ -0,0 +1,347 @@
+package quizzes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetQuizzesByLessonHandler(c *gin.Context)
+	GetQuizByIDHandler(c *gin.Context)
+	CreateQuizHandler(c *gin.Context)
+	UpdateQuizHandler(c *gin.Context)
+	DeleteQuizHandler(c *gin.Context)
+
+	AddQuestionHandler(c *gin.Context)
+	UpdateQuestionHandler(c *gin.Context)
+	DeleteQuestionHandler(c *gin.Context)
+	ImportQuestionsHandler(c *gin.Context)
+
+	SubmitAttemptHandler(c *gin.Context)
+	GetMyAttemptsHandler(c *gin.Context)
+	GetResultsHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+// getViewer identifies the caller for content access; anonymous callers have no user ID
+func getViewer(c *gin.Context) access.Viewer {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return access.Viewer{UserID: getUserID(c), Role: roleStr}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseQuestionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("questionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Question ID", "Question ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps the shared lookup, validation and authorization errors
+func respondError(c *gin.Context, title string, err error) {
+	switch err.Error() {
+	case "quiz not found", "question not found", "lesson not found", "try-out question not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+	case "you are not enrolled in this course", "you don't have permission to use questions from this try out":
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+	case "no attempts left for this quiz":
+		c.JSON(http.StatusConflict, utils.BuildResponseFailed(title, err.Error(), nil))
+	case "quiz has no questions", "a question was answered more than once", "answer refers to a question outside this quiz":
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed(title, err.Error(), nil))
+	default:
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed(title, err.Error(), nil))
+	}
+}
+
+// ==========================================
+// Quiz Handlers
+// ==========================================
+
+func (h *handler) GetQuizzesByLessonHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	quizzes, err := h.service.GetByLessonID(lessonID, getViewer(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch quizzes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Quizzes retrieved successfully", quizzes))
+}
+
+func (h *handler) GetQuizByIDHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	quiz, err := h.service.GetByID(id, getViewer(c), getRequestID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Quiz retrieved successfully", quiz))
+}
+
+func (h *handler) CreateQuizHandler(c *gin.Context) {
+	lessonID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CreateQuizInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	quiz, err := h.service.Create(lessonID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to create quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Quiz created successfully", quiz))
+}
+
+func (h *handler) UpdateQuizHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateQuizInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	quiz, err := h.service.Update(id, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to update quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Quiz updated successfully", quiz))
+}
+
+func (h *handler) DeleteQuizHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, getRequestID(c), getUserID(c), isAdmin(c)); err != nil {
+		respondError(c, "Failed to delete quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Quiz deleted successfully", nil))
+}
+
+// ==========================================
+// Question Handlers
+// ==========================================
+
+func (h *handler) AddQuestionHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input QuestionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	question, err := h.service.AddQuestion(quizID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to add question", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Question added successfully", question))
+}
+
+func (h *handler) UpdateQuestionHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateQuestionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	question, err := h.service.UpdateQuestion(quizID, questionID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to update question", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Question updated successfully", question))
+}
+
+func (h *handler) DeleteQuestionHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteQuestion(quizID, questionID, getRequestID(c), getUserID(c), isAdmin(c)); err != nil {
+		respondError(c, "Failed to delete question", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Question deleted successfully", nil))
+}
+
+func (h *handler) ImportQuestionsHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input ImportQuestionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	questions, err := h.service.ImportQuestions(quizID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to import questions", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Questions imported successfully", questions))
+}
+
+// ==========================================
+// Attempt Handlers
+// ==========================================
+
+func (h *handler) SubmitAttemptHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input SubmitAttemptInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	attempt, err := h.service.SubmitAttempt(quizID, input, getRequestID(c), getUserID(c))
+	if err != nil {
+		respondError(c, "Failed to submit quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Quiz submitted successfully", attempt))
+}
+
+func (h *handler) GetMyAttemptsHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.service.GetMyAttempts(quizID, getRequestID(c), getUserID(c))
+	if err != nil {
+		respondError(c, "Failed to fetch attempts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Attempts retrieved successfully", attempts))
+}
+
+func (h *handler) GetResultsHandler(c *gin.Context) {
+	quizID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.service.GetResults(quizID, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to fetch quiz results", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Quiz results retrieved successfully", results))
+}
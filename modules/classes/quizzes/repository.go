@@ -0,0 +1,191 @@
+package quizzes
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindLessonByID(id uint) (entities.Lesson, error)
+	FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error)
+	HasApprovedRegistration(courseID, userID uint) (bool, error)
+
+	FindByID(id uint) (entities.LessonQuiz, error)
+	FindByLessonID(lessonID uint) ([]entities.LessonQuiz, error)
+	Create(quiz *entities.LessonQuiz) error
+	Update(quiz *entities.LessonQuiz) error
+	Delete(id uint) error
+
+	FindQuestion(quizID, questionID uint) (entities.LessonQuizQuestion, error)
+	CreateQuestions(questions []entities.LessonQuizQuestion) error
+	UpdateQuestion(question *entities.LessonQuizQuestion) error
+	DeleteQuestion(id uint) error
+	MaxQuestionOrder(quizID uint) (int, error)
+
+	FindTryOutQuestions(ids []uint) ([]entities.TryOutQuestion, error)
+	HasTryOutPermission(tryOutID, userID uint) (bool, error)
+
+	CountAttempts(quizID, userID uint) (int64, error)
+	FindAttempts(quizID, userID uint) ([]entities.LessonQuizAttempt, error)
+	FindAttemptsByQuizID(quizID uint) ([]entities.LessonQuizAttempt, error)
+	CreateAttempt(attempt *entities.LessonQuizAttempt) error
+	// CountUnpassedQuizzes counts the lesson's quizzes the student has not passed yet
+	CountUnpassedQuizzes(lessonID, userID uint) (int64, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Lesson & Registration Methods
+// ==========================================
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.Preload("Class").First(&lesson, id).Error
+	return lesson, err
+}
+
+func (r *repository) FindApprovedRegistrations(courseID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Where("course_id = ? AND status = ?", courseID, "approved").
+		Preload("User").
+		Order("id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) HasApprovedRegistration(courseID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.CourseRegistration{}).
+		Where("course_id = ? AND user_id = ? AND status = ?", courseID, userID, "approved").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ==========================================
+// Quiz Methods
+// ==========================================
+
+func (r *repository) FindByID(id uint) (entities.LessonQuiz, error) {
+	var quiz entities.LessonQuiz
+	err := r.db.Preload("Lesson.Class").
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_number ASC, id ASC")
+		}).
+		First(&quiz, id).Error
+	return quiz, err
+}
+
+func (r *repository) FindByLessonID(lessonID uint) ([]entities.LessonQuiz, error) {
+	var quizzes []entities.LessonQuiz
+	err := r.db.Where("lesson_id = ?", lessonID).
+		Preload("Questions").
+		Order("id ASC").
+		Find(&quizzes).Error
+	return quizzes, err
+}
+
+func (r *repository) Create(quiz *entities.LessonQuiz) error {
+	return r.db.Omit("Lesson", "Questions").Create(quiz).Error
+}
+
+func (r *repository) Update(quiz *entities.LessonQuiz) error {
+	return r.db.Omit("Lesson", "Questions").Save(quiz).Error
+}
+
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entities.LessonQuiz{}, id).Error
+}
+
+// ==========================================
+// Question Methods
+// ==========================================
+
+func (r *repository) FindQuestion(quizID, questionID uint) (entities.LessonQuizQuestion, error) {
+	var question entities.LessonQuizQuestion
+	err := r.db.Where("id = ? AND quiz_id = ?", questionID, quizID).First(&question).Error
+	return question, err
+}
+
+func (r *repository) CreateQuestions(questions []entities.LessonQuizQuestion) error {
+	return r.db.Create(&questions).Error
+}
+
+func (r *repository) UpdateQuestion(question *entities.LessonQuizQuestion) error {
+	return r.db.Save(question).Error
+}
+
+func (r *repository) DeleteQuestion(id uint) error {
+	return r.db.Delete(&entities.LessonQuizQuestion{}, id).Error
+}
+
+func (r *repository) MaxQuestionOrder(quizID uint) (int, error) {
+	var order int
+	err := r.db.Model(&entities.LessonQuizQuestion{}).
+		Where("quiz_id = ?", quizID).
+		Select("COALESCE(MAX(order_number), 0)").
+		Scan(&order).Error
+	return order, err
+}
+
+func (r *repository) FindTryOutQuestions(ids []uint) ([]entities.TryOutQuestion, error) {
+	var questions []entities.TryOutQuestion
+	err := r.db.Where("id IN ?", ids).Find(&questions).Error
+	return questions, err
+}
+
+func (r *repository) HasTryOutPermission(tryOutID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.TutorPermission{}).
+		Where("try_out_package_id = ? AND user_id = ?", tryOutID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ==========================================
+// Attempt Methods
+// ==========================================
+
+func (r *repository) CountAttempts(quizID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.LessonQuizAttempt{}).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) FindAttempts(quizID, userID uint) ([]entities.LessonQuizAttempt, error) {
+	var attempts []entities.LessonQuizAttempt
+	err := r.db.Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Preload("Answers").
+		Order("attempt_number ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *repository) FindAttemptsByQuizID(quizID uint) ([]entities.LessonQuizAttempt, error) {
+	var attempts []entities.LessonQuizAttempt
+	err := r.db.Where("quiz_id = ?", quizID).
+		Order("attempt_number ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *repository) CreateAttempt(attempt *entities.LessonQuizAttempt) error {
+	return r.db.Omit("User").Create(attempt).Error
+}
+
+func (r *repository) CountUnpassedQuizzes(lessonID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.LessonQuiz{}).
+		Where("lesson_id = ?", lessonID).
+		Where("NOT EXISTS (SELECT 1 FROM lesson_quiz_attempts a WHERE a.quiz_id = lesson_quizzes.id AND a.user_id = ? AND a.passed AND a.deleted_at IS NULL)", userID).
+		Count(&count).Error
+	return count, err
+}
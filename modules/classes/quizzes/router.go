@@ -0,0 +1,39 @@
+package quizzes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/classes/progress"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+)
+
+func QuizRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	db := migrations.GetDB()
+	tutorService := tutors.NewService(tutors.NewRepository(db))
+	service := NewService(NewRepository(db), access.NewService(access.NewRepository(db)), tutorService, progress.NewService(progress.NewRepository(db), tutorService))
+	handler := NewHandler(service)
+
+	lessonQuizzes := router.Group("/lessons/:id")
+	{
+		lessonQuizzes.GET("/quizzes", middleware.OptionalAuth(), handler.GetQuizzesByLessonHandler)
+		lessonQuizzes.POST("/quizzes", requireAuth, requireAdminOrTutor, handler.CreateQuizHandler)
+	}
+
+	quizzes := router.Group("/quizzes/:id")
+	{
+		quizzes.GET("", middleware.OptionalAuth(), handler.GetQuizByIDHandler)
+		quizzes.PUT("", requireAuth, requireAdminOrTutor, handler.UpdateQuizHandler)
+		quizzes.DELETE("", requireAuth, requireAdminOrTutor, handler.DeleteQuizHandler)
+
+		quizzes.POST("/questions", requireAuth, requireAdminOrTutor, handler.AddQuestionHandler)
+		quizzes.POST("/questions/import", requireAuth, requireAdminOrTutor, handler.ImportQuestionsHandler)
+		quizzes.PUT("/questions/:questionId", requireAuth, requireAdminOrTutor, handler.UpdateQuestionHandler)
+		quizzes.DELETE("/questions/:questionId", requireAuth, requireAdminOrTutor, handler.DeleteQuestionHandler)
+
+		quizzes.POST("/attempts", requireAuth, handler.SubmitAttemptHandler)
+		quizzes.GET("/attempts/me", requireAuth, handler.GetMyAttemptsHandler)
+		quizzes.GET("/results", requireAuth, requireAdminOrTutor, handler.GetResultsHandler)
+	}
+}
@@ -0,0 +1,732 @@
+package quizzes
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/classes/progress"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+// defaultPassingScore is used when the tutor does not set one
+const defaultPassingScore = 70.0
+
+type quizService struct {
+	repo     Repository
+	access   access.Service
+	tutors   tutors.Service
+	progress progress.Service
+}
+
+type Service interface {
+	GetByLessonID(lessonID uint, viewer access.Viewer, requestID string) ([]QuizResponse, error)
+	GetByID(id uint, viewer access.Viewer, requestID string) (*QuizResponse, error)
+	Create(lessonID uint, input CreateQuizInput, requestID string, userID uint, isAdmin bool) (*QuizResponse, error)
+	Update(id uint, input UpdateQuizInput, requestID string, userID uint, isAdmin bool) (*QuizResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
+
+	AddQuestion(quizID uint, input QuestionInput, requestID string, userID uint, isAdmin bool) (*QuestionResponse, error)
+	UpdateQuestion(quizID, questionID uint, input UpdateQuestionInput, requestID string, userID uint, isAdmin bool) (*QuestionResponse, error)
+	DeleteQuestion(quizID, questionID uint, requestID string, userID uint, isAdmin bool) error
+	ImportQuestions(quizID uint, input ImportQuestionsInput, requestID string, userID uint, isAdmin bool) ([]QuestionResponse, error)
+
+	SubmitAttempt(quizID uint, input SubmitAttemptInput, requestID string, userID uint) (*AttemptResponse, error)
+	GetMyAttempts(quizID uint, requestID string, userID uint) (*MyAttemptsResponse, error)
+	GetResults(quizID uint, requestID string, userID uint, isAdmin bool) (*QuizResultsResponse, error)
+}
+
+func NewService(repo Repository, accessService access.Service, tutorService tutors.Service, progressService progress.Service) Service {
+	return &quizService{repo: repo, access: accessService, tutors: tutorService, progress: progressService}
+}
+
+// ==========================================
+// Quizzes
+// ==========================================
+
+func (s *quizService) GetByLessonID(lessonID uint, viewer access.Viewer, requestID string) ([]QuizResponse, error) {
+	utils.LogInfo("quizzes", "get_by_lesson", "Fetching quizzes for lesson", requestID, viewer.UserID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	canView, err := s.access.CanViewLesson(lessonID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.New("you are not enrolled in this course")
+	}
+
+	quizzes, err := s.repo.FindByLessonID(lessonID)
+	if err != nil {
+		utils.LogError("quizzes", "get_by_lesson", "Failed to fetch quizzes: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+
+	responses := make([]QuizResponse, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		responses = append(responses, toQuizResponse(quiz, false, false))
+	}
+	return responses, nil
+}
+
+// GetByID shows the questions to anyone who may view the lesson; only its tutors see the answers
+func (s *quizService) GetByID(id uint, viewer access.Viewer, requestID string) (*QuizResponse, error) {
+	utils.LogInfo("quizzes", "get_by_id", "Fetching quiz by ID", requestID, viewer.UserID, map[string]any{
+		"quiz_id": id,
+	})
+
+	quiz, err := s.findQuiz(id)
+	if err != nil {
+		return nil, err
+	}
+
+	isTutor := false
+	if viewer.UserID != 0 {
+		err := s.tutors.AuthorizeLesson(quiz.LessonID, viewer.UserID, viewer.Role == "ADMIN")
+		if err != nil && !tutors.IsAuthorizationError(err) {
+			return nil, err
+		}
+		isTutor = err == nil
+	}
+	if !isTutor {
+		canView, err := s.access.CanViewLesson(quiz.LessonID, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, errors.New("you are not enrolled in this course")
+		}
+	}
+
+	response := toQuizResponse(quiz, true, isTutor)
+	return &response, nil
+}
+
+func (s *quizService) Create(lessonID uint, input CreateQuizInput, requestID string, userID uint, isAdmin bool) (*QuizResponse, error) {
+	utils.LogInfo("quizzes", "create", "Creating new quiz", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+		"title":     input.Title,
+	})
+
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		utils.LogWarning("quizzes", "create", "Quiz creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, err
+	}
+
+	quiz := &entities.LessonQuiz{
+		LessonID:         lessonID,
+		Title:            input.Title,
+		Description:      input.Description,
+		MaxAttempts:      input.MaxAttempts,
+		PassingScore:     defaultPassingScore,
+		ShowExplanations: true,
+		CreatedByUserID:  userID,
+	}
+	if input.PassingScore != nil {
+		quiz.PassingScore = *input.PassingScore
+	}
+	if input.ShowExplanations != nil {
+		quiz.ShowExplanations = *input.ShowExplanations
+	}
+
+	if err := s.repo.Create(quiz); err != nil {
+		utils.LogError("quizzes", "create", "Failed to create quiz: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("quizzes", "create", "Quiz created successfully", requestID, userID, map[string]any{
+		"quiz_id": quiz.ID,
+	})
+	response := toQuizResponse(*quiz, true, true)
+	return &response, nil
+}
+
+func (s *quizService) Update(id uint, input UpdateQuizInput, requestID string, userID uint, isAdmin bool) (*QuizResponse, error) {
+	utils.LogInfo("quizzes", "update", "Updating quiz", requestID, userID, map[string]any{
+		"quiz_id": id,
+	})
+
+	quiz, err := s.findManagedQuiz(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		quiz.Title = *input.Title
+	}
+	if input.Description != nil {
+		quiz.Description = *input.Description
+	}
+	if input.MaxAttempts != nil {
+		quiz.MaxAttempts = *input.MaxAttempts
+	}
+	if input.PassingScore != nil {
+		quiz.PassingScore = *input.PassingScore
+	}
+	if input.ShowExplanations != nil {
+		quiz.ShowExplanations = *input.ShowExplanations
+	}
+
+	if err := s.repo.Update(&quiz); err != nil {
+		utils.LogError("quizzes", "update", "Failed to update quiz: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("quizzes", "update", "Quiz updated successfully", requestID, userID, map[string]any{
+		"quiz_id": id,
+	})
+	response := toQuizResponse(quiz, true, true)
+	return &response, nil
+}
+
+func (s *quizService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
+	utils.LogInfo("quizzes", "delete", "Deleting quiz", requestID, userID, map[string]any{
+		"quiz_id": id,
+	})
+
+	if _, err := s.findManagedQuiz(id, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		utils.LogError("quizzes", "delete", "Failed to delete quiz: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("quizzes", "delete", "Quiz deleted successfully", requestID, userID, map[string]any{
+		"quiz_id": id,
+	})
+	return nil
+}
+
+// ==========================================
+// Questions
+// ==========================================
+
+func (s *quizService) AddQuestion(quizID uint, input QuestionInput, requestID string, userID uint, isAdmin bool) (*QuestionResponse, error) {
+	utils.LogInfo("quizzes", "add_question", "Adding quiz question", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+	})
+
+	if _, err := s.findManagedQuiz(quizID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	questions := []entities.LessonQuizQuestion{{
+		QuizID:        quizID,
+		OrderNumber:   input.OrderNumber,
+		QuestionText:  input.QuestionText,
+		ImageURL:      input.ImageURL,
+		Explanation:   input.Explanation,
+		OptionA:       input.OptionA,
+		OptionB:       input.OptionB,
+		OptionC:       input.OptionC,
+		OptionD:       input.OptionD,
+		OptionE:       input.OptionE,
+		CorrectOption: input.CorrectOption,
+	}}
+	if err := s.repo.CreateQuestions(questions); err != nil {
+		utils.LogError("quizzes", "add_question", "Failed to add question: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("quizzes", "add_question", "Quiz question added", requestID, userID, map[string]any{
+		"quiz_id":     quizID,
+		"question_id": questions[0].ID,
+	})
+	response := toQuestionResponse(questions[0], true)
+	return &response, nil
+}
+
+func (s *quizService) UpdateQuestion(quizID, questionID uint, input UpdateQuestionInput, requestID string, userID uint, isAdmin bool) (*QuestionResponse, error) {
+	utils.LogInfo("quizzes", "update_question", "Updating quiz question", requestID, userID, map[string]any{
+		"quiz_id":     quizID,
+		"question_id": questionID,
+	})
+
+	question, err := s.findManagedQuestion(quizID, questionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.QuestionText != nil {
+		question.QuestionText = *input.QuestionText
+	}
+	if input.ImageURL != nil {
+		question.ImageURL = *input.ImageURL
+	}
+	if input.Explanation != nil {
+		question.Explanation = *input.Explanation
+	}
+	if input.OrderNumber != nil {
+		question.OrderNumber = *input.OrderNumber
+	}
+	if input.OptionA != nil {
+		question.OptionA = *input.OptionA
+	}
+	if input.OptionB != nil {
+		question.OptionB = *input.OptionB
+	}
+	if input.OptionC != nil {
+		question.OptionC = *input.OptionC
+	}
+	if input.OptionD != nil {
+		question.OptionD = *input.OptionD
+	}
+	if input.OptionE != nil {
+		question.OptionE = *input.OptionE
+	}
+	if input.CorrectOption != nil {
+		question.CorrectOption = *input.CorrectOption
+	}
+
+	if err := s.repo.UpdateQuestion(&question); err != nil {
+		utils.LogError("quizzes", "update_question", "Failed to update question: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("quizzes", "update_question", "Quiz question updated", requestID, userID, map[string]any{
+		"question_id": questionID,
+	})
+	response := toQuestionResponse(question, true)
+	return &response, nil
+}
+
+func (s *quizService) DeleteQuestion(quizID, questionID uint, requestID string, userID uint, isAdmin bool) error {
+	utils.LogInfo("quizzes", "delete_question", "Deleting quiz question", requestID, userID, map[string]any{
+		"quiz_id":     quizID,
+		"question_id": questionID,
+	})
+
+	if _, err := s.findManagedQuestion(quizID, questionID, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteQuestion(questionID); err != nil {
+		utils.LogError("quizzes", "delete_question", "Failed to delete question: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("quizzes", "delete_question", "Quiz question deleted", requestID, userID, map[string]any{
+		"question_id": questionID,
+	})
+	return nil
+}
+
+// ImportQuestions copies try-out questions into the quiz. Tutors may only copy from try-outs
+// they are allowed to write questions for, so a quiz cannot be used to read another try-out's answers.
+func (s *quizService) ImportQuestions(quizID uint, input ImportQuestionsInput, requestID string, userID uint, isAdmin bool) ([]QuestionResponse, error) {
+	utils.LogInfo("quizzes", "import_questions", "Importing try-out questions", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+		"count":   len(input.TryOutQuestionIDs),
+	})
+
+	if _, err := s.findManagedQuiz(quizID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	sources, err := s.repo.FindTryOutQuestions(input.TryOutQuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]entities.TryOutQuestion, len(sources))
+	for _, source := range sources {
+		byID[source.ID] = source
+	}
+
+	permitted := make(map[uint]bool)
+	for _, id := range input.TryOutQuestionIDs {
+		source, ok := byID[id]
+		if !ok {
+			return nil, errors.New("try-out question not found")
+		}
+		if isAdmin {
+			continue
+		}
+		if _, checked := permitted[source.TryOutPackageID]; !checked {
+			allowed, err := s.repo.HasTryOutPermission(source.TryOutPackageID, userID)
+			if err != nil {
+				return nil, err
+			}
+			permitted[source.TryOutPackageID] = allowed
+		}
+		if !permitted[source.TryOutPackageID] {
+			utils.LogWarning("quizzes", "import_questions", "Import refused for try-out", requestID, userID, map[string]any{
+				"try_out_id": source.TryOutPackageID,
+			})
+			return nil, errors.New("you don't have permission to use questions from this try out")
+		}
+	}
+
+	order, err := s.repo.MaxQuestionOrder(quizID)
+	if err != nil {
+		return nil, err
+	}
+	questions := make([]entities.LessonQuizQuestion, 0, len(input.TryOutQuestionIDs))
+	for _, id := range input.TryOutQuestionIDs {
+		source := byID[id]
+		order++
+		questions = append(questions, entities.LessonQuizQuestion{
+			QuizID:           quizID,
+			SourceQuestionID: &source.ID,
+			OrderNumber:      order,
+			QuestionText:     source.QuestionText,
+			ImageURL:         source.ImageURL,
+			Explanation:      source.Explanation,
+			OptionA:          source.OptionA,
+			OptionB:          source.OptionB,
+			OptionC:          source.OptionC,
+			OptionD:          source.OptionD,
+			OptionE:          source.OptionE,
+			CorrectOption:    source.CorrectOption,
+		})
+	}
+	if err := s.repo.CreateQuestions(questions); err != nil {
+		utils.LogError("quizzes", "import_questions", "Failed to import questions: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("quizzes", "import_questions", "Try-out questions imported", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+		"count":   len(questions),
+	})
+	responses := make([]QuestionResponse, 0, len(questions))
+	for _, question := range questions {
+		responses = append(responses, toQuestionResponse(question, true))
+	}
+	return responses, nil
+}
+
+// ==========================================
+// Attempts
+// ==========================================
+
+func (s *quizService) SubmitAttempt(quizID uint, input SubmitAttemptInput, requestID string, userID uint) (*AttemptResponse, error) {
+	utils.LogInfo("quizzes", "submit_attempt", "Student submitting quiz", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+		"answers": len(input.Answers),
+	})
+
+	quiz, err := s.findQuiz(quizID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := s.repo.HasApprovedRegistration(quiz.Lesson.Class.CourseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, errors.New("you are not enrolled in this course")
+	}
+	if len(quiz.Questions) == 0 {
+		return nil, errors.New("quiz has no questions")
+	}
+
+	used, err := s.repo.CountAttempts(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.MaxAttempts > 0 && int(used) >= quiz.MaxAttempts {
+		utils.LogWarning("quizzes", "submit_attempt", "No attempts left", requestID, userID, map[string]any{
+			"quiz_id": quizID,
+		})
+		return nil, errors.New("no attempts left for this quiz")
+	}
+
+	selected := make(map[uint]*string, len(input.Answers))
+	for _, answer := range input.Answers {
+		if _, dup := selected[answer.QuestionID]; dup {
+			return nil, errors.New("a question was answered more than once")
+		}
+		selected[answer.QuestionID] = answer.SelectedOption
+	}
+
+	attempt := &entities.LessonQuizAttempt{
+		QuizID:         quizID,
+		UserID:         userID,
+		AttemptNumber:  int(used) + 1,
+		TotalQuestions: len(quiz.Questions),
+		SubmittedAt:    time.Now(),
+		Answers:        make([]entities.LessonQuizAnswer, 0, len(quiz.Questions)),
+	}
+	// Questions left out of the submission count as unanswered
+	for _, question := range quiz.Questions {
+		option := selected[question.ID]
+		delete(selected, question.ID)
+		correct := option != nil && *option == question.CorrectOption
+		if correct {
+			attempt.CorrectCount++
+		}
+		attempt.Answers = append(attempt.Answers, entities.LessonQuizAnswer{
+			QuestionID:     question.ID,
+			SelectedOption: option,
+			IsCorrect:      correct,
+		})
+	}
+	if len(selected) > 0 {
+		return nil, errors.New("answer refers to a question outside this quiz")
+	}
+	attempt.Score = math.Round(float64(attempt.CorrectCount)/float64(attempt.TotalQuestions)*1000) / 10
+	attempt.Passed = attempt.Score >= quiz.PassingScore
+
+	if err := s.repo.CreateAttempt(attempt); err != nil {
+		utils.LogError("quizzes", "submit_attempt", "Failed to save attempt: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	if attempt.Passed {
+		if err := s.completeLessonIfPassed(quiz.LessonID, userID, requestID); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.LogSuccess("quizzes", "submit_attempt", "Quiz attempt scored", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+		"score":   attempt.Score,
+		"passed":  attempt.Passed,
+	})
+	response := toAttemptResponse(*attempt, quiz)
+	response.AttemptsLeft = attemptsLeft(quiz, attempt.AttemptNumber)
+	return &response, nil
+}
+
+// completeLessonIfPassed hands the lesson to progress tracking once every quiz of it is passed
+func (s *quizService) completeLessonIfPassed(lessonID, userID uint, requestID string) error {
+	unpassed, err := s.repo.CountUnpassedQuizzes(lessonID, userID)
+	if err != nil {
+		return err
+	}
+	if unpassed > 0 {
+		return nil
+	}
+	return s.progress.CompleteByRule(lessonID, userID, entities.CompletionQuiz, requestID)
+}
+
+func (s *quizService) GetMyAttempts(quizID uint, requestID string, userID uint) (*MyAttemptsResponse, error) {
+	utils.LogInfo("quizzes", "get_my_attempts", "Fetching own quiz attempts", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+	})
+
+	quiz, err := s.findQuiz(quizID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.FindAttempts(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MyAttemptsResponse{
+		QuizID:       quizID,
+		AttemptsUsed: len(attempts),
+		AttemptsLeft: attemptsLeft(quiz, len(attempts)),
+		Attempts:     make([]AttemptResponse, 0, len(attempts)),
+	}
+	for _, attempt := range attempts {
+		if response.BestScore == nil || attempt.Score > *response.BestScore {
+			score := attempt.Score
+			response.BestScore = &score
+		}
+		if attempt.Passed {
+			response.Passed = true
+		}
+		response.Attempts = append(response.Attempts, toAttemptResponse(attempt, quiz))
+	}
+	return response, nil
+}
+
+func (s *quizService) GetResults(quizID uint, requestID string, userID uint, isAdmin bool) (*QuizResultsResponse, error) {
+	utils.LogInfo("quizzes", "get_results", "Fetching quiz results", requestID, userID, map[string]any{
+		"quiz_id": quizID,
+	})
+
+	quiz, err := s.findManagedQuiz(quizID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.FindAttemptsByQuizID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.FindApprovedRegistrations(quiz.Lesson.Class.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint][]entities.LessonQuizAttempt)
+	for _, attempt := range attempts {
+		byUser[attempt.UserID] = append(byUser[attempt.UserID], attempt)
+	}
+
+	response := &QuizResultsResponse{
+		QuizID:       quiz.ID,
+		Title:        quiz.Title,
+		PassingScore: quiz.PassingScore,
+		Enrolled:     len(registrations),
+		Students:     make([]StudentQuizResult, 0, len(registrations)),
+	}
+	var bestTotal float64
+	for _, reg := range registrations {
+		result := StudentQuizResult{
+			UserID:   reg.UserID,
+			Username: reg.User.Username,
+			Email:    reg.User.Email,
+			Attempts: len(byUser[reg.UserID]),
+		}
+		for _, attempt := range byUser[reg.UserID] {
+			if result.BestScore == nil || attempt.Score > *result.BestScore {
+				score := attempt.Score
+				result.BestScore = &score
+			}
+			if attempt.Passed {
+				result.Passed = true
+			}
+			if result.LastAttemptAt == nil || attempt.SubmittedAt.After(*result.LastAttemptAt) {
+				at := attempt.SubmittedAt
+				result.LastAttemptAt = &at
+			}
+		}
+		if result.Attempts > 0 {
+			response.Attempted++
+			bestTotal += *result.BestScore
+		}
+		if result.Passed {
+			response.PassedCount++
+		}
+		response.Students = append(response.Students, result)
+	}
+	if response.Attempted > 0 {
+		response.AverageBestScore = math.Round(bestTotal/float64(response.Attempted)*10) / 10
+	}
+	return response, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *quizService) findQuiz(id uint) (entities.LessonQuiz, error) {
+	quiz, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return quiz, errors.New("quiz not found")
+		}
+		return quiz, err
+	}
+	return quiz, nil
+}
+
+// findManagedQuiz loads the quiz and checks the caller is a tutor of its lesson
+func (s *quizService) findManagedQuiz(id, userID uint, isAdmin bool) (entities.LessonQuiz, error) {
+	quiz, err := s.findQuiz(id)
+	if err != nil {
+		return quiz, err
+	}
+	if err := s.tutors.AuthorizeLesson(quiz.LessonID, userID, isAdmin); err != nil {
+		return quiz, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) findManagedQuestion(quizID, questionID, userID uint, isAdmin bool) (entities.LessonQuizQuestion, error) {
+	if _, err := s.findManagedQuiz(quizID, userID, isAdmin); err != nil {
+		return entities.LessonQuizQuestion{}, err
+	}
+	question, err := s.repo.FindQuestion(quizID, questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return question, errors.New("question not found")
+		}
+		return question, err
+	}
+	return question, nil
+}
+
+func attemptsLeft(quiz entities.LessonQuiz, used int) *int {
+	if quiz.MaxAttempts == 0 {
+		return nil
+	}
+	left := quiz.MaxAttempts - used
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+func toQuizResponse(quiz entities.LessonQuiz, withQuestions bool, withAnswers bool) QuizResponse {
+	response := QuizResponse{
+		ID:               quiz.ID,
+		LessonID:         quiz.LessonID,
+		Title:            quiz.Title,
+		Description:      quiz.Description,
+		MaxAttempts:      quiz.MaxAttempts,
+		PassingScore:     quiz.PassingScore,
+		ShowExplanations: quiz.ShowExplanations,
+		QuestionCount:    len(quiz.Questions),
+	}
+	if withQuestions {
+		response.Questions = make([]QuestionResponse, 0, len(quiz.Questions))
+		for _, question := range quiz.Questions {
+			response.Questions = append(response.Questions, toQuestionResponse(question, withAnswers))
+		}
+	}
+	return response
+}
+
+func toQuestionResponse(question entities.LessonQuizQuestion, withAnswer bool) QuestionResponse {
+	response := QuestionResponse{
+		ID:           question.ID,
+		OrderNumber:  question.OrderNumber,
+		QuestionText: question.QuestionText,
+		ImageURL:     question.ImageURL,
+		OptionA:      question.OptionA,
+		OptionB:      question.OptionB,
+		OptionC:      question.OptionC,
+		OptionD:      question.OptionD,
+		OptionE:      question.OptionE,
+	}
+	if withAnswer {
+		response.CorrectOption = question.CorrectOption
+		response.Explanation = question.Explanation
+		response.SourceQuestionID = question.SourceQuestionID
+	}
+	return response
+}
+
+// toAttemptResponse reveals the correct options and explanations only when the quiz allows it
+func toAttemptResponse(attempt entities.LessonQuizAttempt, quiz entities.LessonQuiz) AttemptResponse {
+	response := AttemptResponse{
+		ID:             attempt.ID,
+		QuizID:         attempt.QuizID,
+		AttemptNumber:  attempt.AttemptNumber,
+		CorrectCount:   attempt.CorrectCount,
+		TotalQuestions: attempt.TotalQuestions,
+		Score:          attempt.Score,
+		Passed:         attempt.Passed,
+		SubmittedAt:    attempt.SubmittedAt,
+	}
+
+	questions := make(map[uint]entities.LessonQuizQuestion, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions[question.ID] = question
+	}
+	response.Answers = make([]AnswerResult, 0, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		result := AnswerResult{
+			QuestionID:     answer.QuestionID,
+			SelectedOption: answer.SelectedOption,
+			IsCorrect:      answer.IsCorrect,
+		}
+		if question, ok := questions[answer.QuestionID]; ok && quiz.ShowExplanations {
+			result.CorrectOption = question.CorrectOption
+			result.Explanation = question.Explanation
+		}
+		response.Answers = append(response.Answers, result)
+	}
+	return response
+}
@@ -4,19 +4,25 @@ type CreateResourceInput struct {
 	Type  string `json:"type" binding:"required,oneof=video document link zoom recording"`
 	Title string `json:"title" binding:"required"`
 	URL   string `json:"url" binding:"omitempty,url"`
+
+	MinViewPercent int `json:"minViewPercent" binding:"omitempty,min=0,max=100"` // share of a video or recording to watch before it counts as completed
 }
 
 type UpdateResourceInput struct {
 	Type  *string `json:"type"`
 	Title *string `json:"title"`
 	URL   *string `json:"url"`
+
+	MinViewPercent *int `json:"minViewPercent" binding:"omitempty,min=0,max=100"`
 }
 
 type ResourceResponse struct {
-	ID          uint   `json:"id"`
-	LessonID    uint   `json:"lessonId"`
-	LessonTitle string `json:"lessonTitle,omitempty"`
-	Type        string `json:"type"`
-	Title       string `json:"title"`
-	URL         string `json:"url"`
+	ID             uint   `json:"id"`
+	LessonID       uint   `json:"lessonId"`
+	LessonTitle    string `json:"lessonTitle,omitempty"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+	URL            string `json:"url,omitempty"`
+	MinViewPercent int    `json:"minViewPercent,omitempty"`
+	Locked         bool   `json:"locked"` // the URL is hidden until the viewer is enrolled
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -46,6 +48,22 @@ func getUserID(c *gin.Context) uint {
 	return 0
 }
 
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+// getViewer identifies the caller for content access; anonymous callers have no user ID
+func getViewer(c *gin.Context) access.Viewer {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return access.Viewer{UserID: getUserID(c), Role: roleStr}
+}
+
 func (h *handler) GetResourcesByLessonHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	lessonIDStr := c.Param("id")
@@ -56,8 +74,12 @@ func (h *handler) GetResourcesByLessonHandler(c *gin.Context) {
 		return
 	}
 
-	resources, err := h.service.GetByLessonID(uint(lessonID), requestID)
+	resources, err := h.service.GetByLessonID(uint(lessonID), getViewer(c), requestID)
 	if err != nil {
+		if err.Error() == "lesson not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Lesson not found", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch resources", err.Error(), nil))
 		return
 	}
@@ -82,8 +104,16 @@ func (h *handler) CreateResourceHandler(c *gin.Context) {
 		return
 	}
 
-	resource, err := h.service.Create(uint(lessonID), input, requestID, userID)
+	resource, err := h.service.Create(uint(lessonID), input, requestID, userID, isAdmin(c))
 	if err != nil {
+		if err.Error() == "lesson not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Lesson not found", err.Error(), nil))
+			return
+		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create resource", err.Error(), nil))
 		return
 	}
@@ -108,12 +138,16 @@ func (h *handler) UpdateResourceHandler(c *gin.Context) {
 		return
 	}
 
-	resource, err := h.service.Update(uint(id), input, requestID, userID)
+	resource, err := h.service.Update(uint(id), input, requestID, userID, isAdmin(c))
 	if err != nil {
 		if err.Error() == "resource not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Resource not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update resource", err.Error(), nil))
 		return
 	}
@@ -132,11 +166,15 @@ func (h *handler) DeleteResourceHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(id), requestID, userID); err != nil {
+	if err := h.service.Delete(uint(id), requestID, userID, isAdmin(c)); err != nil {
 		if err.Error() == "resource not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Resource not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete resource", err.Error(), nil))
 		return
 	}
@@ -3,16 +3,20 @@ package resources
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 )
 
 func ResourceRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
-	resourceRepo := NewRepository(migrations.GetDB())
-	resourceService := NewService(resourceRepo)
+	db := migrations.GetDB()
+	resourceRepo := NewRepository(db)
+	resourceService := NewService(resourceRepo, access.NewService(access.NewRepository(db)), tutors.NewService(tutors.NewRepository(db)))
 	resourceHandler := NewHandler(resourceService)
 
 	lessonResources := router.Group("/lessons/:id")
 	{
-		lessonResources.GET("/resources", resourceHandler.GetResourcesByLessonHandler)
+		lessonResources.GET("/resources", middleware.OptionalAuth(), resourceHandler.GetResourcesByLessonHandler)
 		lessonResources.POST("/resources", requireAuth, requireAdminOrTutor, resourceHandler.CreateResourceHandler)
 	}
 
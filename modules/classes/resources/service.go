@@ -4,60 +4,86 @@ import (
 	"errors"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
 type resourceService struct {
-	repo Repository
+	repo   Repository
+	access access.Service
+	tutors tutors.Service
 }
 
 type Service interface {
-	GetByLessonID(lessonID uint, requestID string) ([]ResourceResponse, error)
-	Create(lessonID uint, input CreateResourceInput, requestID string, userID uint) (*ResourceResponse, error)
-	Update(id uint, input UpdateResourceInput, requestID string, userID uint) (*ResourceResponse, error)
-	Delete(id uint, requestID string, userID uint) error
+	GetByLessonID(lessonID uint, viewer access.Viewer, requestID string) ([]ResourceResponse, error)
+	Create(lessonID uint, input CreateResourceInput, requestID string, userID uint, isAdmin bool) (*ResourceResponse, error)
+	Update(id uint, input UpdateResourceInput, requestID string, userID uint, isAdmin bool) (*ResourceResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
 }
 
-func NewService(repo Repository) Service {
-	return &resourceService{repo: repo}
+func NewService(repo Repository, accessService access.Service, tutorService tutors.Service) Service {
+	return &resourceService{repo: repo, access: accessService, tutors: tutorService}
 }
 
-func (s *resourceService) GetByLessonID(lessonID uint, requestID string) ([]ResourceResponse, error) {
-	utils.LogInfo("resources", "get_by_lesson", "Fetching resources for lesson", requestID, 0, map[string]any{
+func (s *resourceService) GetByLessonID(lessonID uint, viewer access.Viewer, requestID string) ([]ResourceResponse, error) {
+	utils.LogInfo("resources", "get_by_lesson", "Fetching resources for lesson", requestID, viewer.UserID, map[string]any{
 		"lesson_id": lessonID,
 	})
 
+	canView, err := s.access.CanViewLesson(lessonID, viewer)
+	if err != nil {
+		if err.Error() != "lesson not found" {
+			utils.LogError("resources", "get_by_lesson", "Failed to check access: "+err.Error(), requestID, viewer.UserID, nil)
+		}
+		return nil, err
+	}
+
 	resources, err := s.repo.FindByLessonID(lessonID)
 	if err != nil {
-		utils.LogError("resources", "get_by_lesson", "Failed to fetch resources: "+err.Error(), requestID, 0, nil)
+		utils.LogError("resources", "get_by_lesson", "Failed to fetch resources: "+err.Error(), requestID, viewer.UserID, nil)
 		return nil, err
 	}
 
 	var responses []ResourceResponse
 	for _, res := range resources {
-		responses = append(responses, s.toResponse(res))
+		response := s.toResponse(res)
+		if !canView {
+			response.URL = ""
+			response.Locked = true
+		}
+		responses = append(responses, response)
 	}
 
-	utils.LogSuccess("resources", "get_by_lesson", "Successfully fetched resources", requestID, 0, map[string]any{
+	utils.LogSuccess("resources", "get_by_lesson", "Successfully fetched resources", requestID, viewer.UserID, map[string]any{
 		"lesson_id": lessonID,
 		"count":     len(responses),
+		"locked":    !canView,
 	})
 	return responses, nil
 }
 
-func (s *resourceService) Create(lessonID uint, input CreateResourceInput, requestID string, userID uint) (*ResourceResponse, error) {
+func (s *resourceService) Create(lessonID uint, input CreateResourceInput, requestID string, userID uint, isAdmin bool) (*ResourceResponse, error) {
 	utils.LogInfo("resources", "create", "Creating new resource", requestID, userID, map[string]any{
 		"lesson_id": lessonID,
 		"type":      input.Type,
 	})
 
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		utils.LogWarning("resources", "create", "Resource creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, err
+	}
+
 	resource := &entities.LessonResource{
 		LessonID: lessonID,
 		Type:     input.Type,
 		Title:    input.Title,
 		URL:      input.URL,
 	}
+	resource.MinViewPercent = input.MinViewPercent
 
 	if err := s.repo.Create(resource); err != nil {
 		utils.LogError("resources", "create", "Failed to create resource: "+err.Error(), requestID, userID, nil)
@@ -72,11 +98,18 @@ func (s *resourceService) Create(lessonID uint, input CreateResourceInput, reque
 	return &response, nil
 }
 
-func (s *resourceService) Update(id uint, input UpdateResourceInput, requestID string, userID uint) (*ResourceResponse, error) {
+func (s *resourceService) Update(id uint, input UpdateResourceInput, requestID string, userID uint, isAdmin bool) (*ResourceResponse, error) {
 	utils.LogInfo("resources", "update", "Updating resource", requestID, userID, map[string]any{
 		"resource_id": id,
 	})
 
+	if err := s.tutors.AuthorizeResource(id, userID, isAdmin); err != nil {
+		utils.LogWarning("resources", "update", "Resource update refused: "+err.Error(), requestID, userID, map[string]any{
+			"resource_id": id,
+		})
+		return nil, err
+	}
+
 	resource, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -94,6 +127,9 @@ func (s *resourceService) Update(id uint, input UpdateResourceInput, requestID s
 	if input.URL != nil {
 		resource.URL = *input.URL
 	}
+	if input.MinViewPercent != nil {
+		resource.MinViewPercent = *input.MinViewPercent
+	}
 
 	if err := s.repo.Update(&resource); err != nil {
 		utils.LogError("resources", "update", "Failed to update resource: "+err.Error(), requestID, userID, nil)
@@ -104,11 +140,18 @@ func (s *resourceService) Update(id uint, input UpdateResourceInput, requestID s
 	return &response, nil
 }
 
-func (s *resourceService) Delete(id uint, requestID string, userID uint) error {
+func (s *resourceService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
 	utils.LogInfo("resources", "delete", "Deleting resource", requestID, userID, map[string]any{
 		"resource_id": id,
 	})
 
+	if err := s.tutors.AuthorizeResource(id, userID, isAdmin); err != nil {
+		utils.LogWarning("resources", "delete", "Resource deletion refused: "+err.Error(), requestID, userID, map[string]any{
+			"resource_id": id,
+		})
+		return err
+	}
+
 	_, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -133,6 +176,7 @@ func (s *resourceService) toResponse(res entities.LessonResource) ResourceRespon
 		Title:    res.Title,
 		URL:      res.URL,
 	}
+	response.MinViewPercent = res.MinViewPercent
 
 	if res.Lesson.ID != 0 {
 		response.LessonTitle = res.Lesson.Title
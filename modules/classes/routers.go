@@ -3,8 +3,13 @@ package classes
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/redukasquad/be-reduka/modules/classes/assignments"
+	"github.com/redukasquad/be-reduka/modules/classes/attendance"
 	"github.com/redukasquad/be-reduka/modules/classes/lessons"
+	"github.com/redukasquad/be-reduka/modules/classes/progress"
+	"github.com/redukasquad/be-reduka/modules/classes/quizzes"
 	"github.com/redukasquad/be-reduka/modules/classes/resources"
+	"github.com/redukasquad/be-reduka/modules/classes/schedules"
 	"github.com/redukasquad/be-reduka/modules/classes/subjects"
 )
 
@@ -12,4 +17,9 @@ func ClassesRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, require
 	subjects.SubjectRouter(router, requireAuth, requireAdminOrTutor)
 	lessons.LessonRouter(router, requireAuth, requireAdminOrTutor)
 	resources.ResourceRouter(router, requireAuth, requireAdminOrTutor)
+	attendance.AttendanceRouter(router, requireAuth, requireAdminOrTutor)
+	progress.ProgressRouter(router, requireAuth, requireAdminOrTutor)
+	assignments.AssignmentRouter(router, requireAuth, requireAdminOrTutor)
+	quizzes.QuizRouter(router, requireAuth, requireAdminOrTutor)
+	schedules.ScheduleRouter(router, requireAuth, requireAdminOrTutor)
 }
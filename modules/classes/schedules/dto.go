@@ -0,0 +1,86 @@
+package schedules
+
+import "time"
+
+type ExclusionInput struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason string `json:"reason"`
+}
+
+type CreateScheduleInput struct {
+	LessonTitle     string           `json:"lessonTitle"` // defaults to the class name
+	Weekdays        []int            `json:"weekdays" binding:"required,min=1,dive,min=0,max=6"`
+	StartTime       string           `json:"startTime" binding:"required"` // HH:MM
+	DurationMinutes int              `json:"durationMinutes" binding:"required,min=1,max=720"`
+	Timezone        string           `json:"timezone"` // defaults to Asia/Jakarta
+	StartDate       string           `json:"startDate" binding:"required"`
+	EndDate         string           `json:"endDate" binding:"required"`
+	Exclusions      []ExclusionInput `json:"exclusions" binding:"dive"`
+}
+
+// UpdateScheduleInput changes the rule; Exclusions, when sent, replaces the whole list
+type UpdateScheduleInput struct {
+	LessonTitle     *string           `json:"lessonTitle"`
+	Weekdays        *[]int            `json:"weekdays" binding:"omitempty,min=1,dive,min=0,max=6"`
+	StartTime       *string           `json:"startTime"`
+	DurationMinutes *int              `json:"durationMinutes" binding:"omitempty,min=1,max=720"`
+	Timezone        *string           `json:"timezone"`
+	StartDate       *string           `json:"startDate"`
+	EndDate         *string           `json:"endDate"`
+	Exclusions      *[]ExclusionInput `json:"exclusions" binding:"omitempty,dive"`
+}
+
+// ShiftInput moves every remaining session from FromDate onward by Slots occurrences of the pattern
+type ShiftInput struct {
+	FromDate string `json:"fromDate" binding:"required"`
+	Slots    int    `json:"slots" binding:"required,min=1,max=52"`
+}
+
+// RescheduleInput moves a single lesson; without EndTime the lesson keeps its length
+type RescheduleInput struct {
+	StartTime time.Time  `json:"startTime" binding:"required"`
+	EndTime   *time.Time `json:"endTime"`
+}
+
+type ExclusionResponse struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type ScheduleResponse struct {
+	ID              uint                `json:"id"`
+	ClassID         uint                `json:"classId"`
+	LessonTitle     string              `json:"lessonTitle"`
+	Weekdays        []int               `json:"weekdays"`
+	StartTime       string              `json:"startTime"`
+	DurationMinutes int                 `json:"durationMinutes"`
+	Timezone        string              `json:"timezone"`
+	StartDate       string              `json:"startDate"`
+	EndDate         string              `json:"endDate"`
+	Exclusions      []ExclusionResponse `json:"exclusions"`
+	LessonCount     int                 `json:"lessonCount"`
+	CreatedAt       time.Time           `json:"createdAt"`
+}
+
+// SyncResponse reports what a schedule change did to the class lessons. Lessons that left the
+// pattern but already hold resources, quizzes, assignments, attendance or completions are kept
+// as regular lessons and listed in Detached.
+type SyncResponse struct {
+	Schedule ScheduleResponse `json:"schedule"`
+	Created  int              `json:"created"`
+	Updated  int              `json:"updated"`
+	Removed  int              `json:"removed"`
+	Detached []uint           `json:"detached"`
+}
+
+type LessonResponse struct {
+	ID             uint       `json:"id"`
+	ClassID        uint       `json:"classId"`
+	Title          string     `json:"title"`
+	LessonOrder    int        `json:"lessonOrder"`
+	StartTime      *time.Time `json:"startTime,omitempty"`
+	EndTime        *time.Time `json:"endTime,omitempty"`
+	ScheduleID     *uint      `json:"scheduleId,omitempty"`
+	OccurrenceDate string     `json:"occurrenceDate,omitempty"`
+	IsRescheduled  bool       `json:"isRescheduled"`
+}
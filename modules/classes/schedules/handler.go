@@ -0,0 +1,226 @@
+package schedules
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetSchedulesByClassHandler(c *gin.Context)
+	CreateScheduleHandler(c *gin.Context)
+	UpdateScheduleHandler(c *gin.Context)
+	RegenerateScheduleHandler(c *gin.Context)
+	ShiftScheduleHandler(c *gin.Context)
+	DeleteScheduleHandler(c *gin.Context)
+
+	RescheduleLessonHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps the shared lookup, validation and authorization errors
+func respondError(c *gin.Context, title string, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "schedule not found" || msg == "class not found" || msg == "lesson not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", msg, nil))
+	case tutors.IsAuthorizationError(err):
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", msg, nil))
+	case msg == "invalid timezone" || msg == "start time must use the HH:MM format" ||
+		msg == "dates must use the YYYY-MM-DD format" || msg == "end date must not be before start date" ||
+		msg == "schedule needs at least one weekday" || strings.HasPrefix(msg, "schedule would create more than") ||
+		msg == "end time must be after start time" || msg == "no remaining lessons to shift" ||
+		msg == "schedule has no free slots to shift into":
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed(title, msg, nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed(title, msg, nil))
+	}
+}
+
+// ==========================================
+// Schedule Handlers
+// ==========================================
+
+func (h *handler) GetSchedulesByClassHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := h.service.GetByClassID(classID, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to fetch schedules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Schedules retrieved successfully", schedules))
+}
+
+func (h *handler) CreateScheduleHandler(c *gin.Context) {
+	classID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CreateScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	result, err := h.service.Create(classID, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to create schedule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Schedule created successfully", result))
+}
+
+func (h *handler) UpdateScheduleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	result, err := h.service.Update(id, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to update schedule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Schedule updated successfully", result))
+}
+
+func (h *handler) RegenerateScheduleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Regenerate(id, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to regenerate lessons", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lessons regenerated successfully", result))
+}
+
+func (h *handler) ShiftScheduleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input ShiftInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	result, err := h.service.Shift(id, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to shift lessons", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lessons shifted successfully", result))
+}
+
+func (h *handler) DeleteScheduleHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, getRequestID(c), getUserID(c), isAdmin(c)); err != nil {
+		respondError(c, "Failed to delete schedule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Schedule deleted successfully", nil))
+}
+
+// ==========================================
+// Lesson Handlers
+// ==========================================
+
+func (h *handler) RescheduleLessonHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input RescheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	lesson, err := h.service.RescheduleLesson(id, input, getRequestID(c), getUserID(c), isAdmin(c))
+	if err != nil {
+		respondError(c, "Failed to reschedule lesson", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Lesson rescheduled successfully", lesson))
+}
@@ -0,0 +1,254 @@
+package schedules
+
+import (
+	"sort"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/ordering"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncPlan is the set of lesson changes a schedule change produces, applied in one transaction
+type SyncPlan struct {
+	Create []entities.Lesson
+	Update []entities.Lesson
+	Delete []uint
+	Detach []uint
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindClassByID(id uint) (entities.Class, error)
+	FindByClassID(classID uint) ([]entities.ClassSchedule, error)
+	FindByID(id uint) (entities.ClassSchedule, error)
+	CountLessons(scheduleID uint) (int64, error)
+	FindLessons(scheduleID uint) ([]entities.Lesson, error)
+	FindLessonByID(id uint) (entities.Lesson, error)
+	FindLessonIDsWithContent(lessonIDs []uint) (map[uint]bool, error)
+
+	Create(schedule *entities.ClassSchedule, plan SyncPlan) error
+	Save(schedule *entities.ClassSchedule, exclusions []entities.ClassScheduleExclusion, plan SyncPlan) error
+	Delete(schedule entities.ClassSchedule) error
+	SaveLesson(lesson *entities.Lesson) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindClassByID(id uint) (entities.Class, error) {
+	var class entities.Class
+	err := r.db.First(&class, id).Error
+	return class, err
+}
+
+func (r *repository) FindByClassID(classID uint) ([]entities.ClassSchedule, error) {
+	var schedules []entities.ClassSchedule
+	err := r.db.Where("class_id = ?", classID).
+		Preload("Exclusions", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") }).
+		Order("start_date ASC, id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *repository) FindByID(id uint) (entities.ClassSchedule, error) {
+	var schedule entities.ClassSchedule
+	err := r.db.Preload("Exclusions", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") }).
+		First(&schedule, id).Error
+	return schedule, err
+}
+
+func (r *repository) CountLessons(scheduleID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Lesson{}).Where("schedule_id = ?", scheduleID).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) FindLessons(scheduleID uint) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	err := r.db.Where("schedule_id = ?", scheduleID).Order("occurrence_date ASC, id ASC").Find(&lessons).Error
+	return lessons, err
+}
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.First(&lesson, id).Error
+	return lesson, err
+}
+
+// FindLessonIDsWithContent returns which of the lessons already hold resources, quizzes,
+// assignments, attendance or completions and therefore must not be deleted by a schedule
+func (r *repository) FindLessonIDsWithContent(lessonIDs []uint) (map[uint]bool, error) {
+	found := make(map[uint]bool)
+	if len(lessonIDs) == 0 {
+		return found, nil
+	}
+
+	models := []any{
+		&entities.LessonResource{},
+		&entities.LessonQuiz{},
+		&entities.Assignment{},
+		&entities.LessonAttendance{},
+		&entities.LessonCompletion{},
+	}
+	for _, model := range models {
+		var ids []uint
+		if err := r.db.Model(model).Where("lesson_id IN ?", lessonIDs).Distinct().Pluck("lesson_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			found[id] = true
+		}
+	}
+	return found, nil
+}
+
+func (r *repository) Create(schedule *entities.ClassSchedule, plan SyncPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(schedule).Error; err != nil {
+			return err
+		}
+		return applyPlan(tx, *schedule, plan)
+	})
+}
+
+// Save updates the rule and applies its lesson changes; a non-nil exclusions slice replaces the stored list
+func (r *repository) Save(schedule *entities.ClassSchedule, exclusions []entities.ClassScheduleExclusion, plan SyncPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Exclusions", "Class").Save(schedule).Error; err != nil {
+			return err
+		}
+		if exclusions != nil {
+			// Unscoped so re-adding a removed date does not hit the unique index
+			if err := tx.Unscoped().Where("schedule_id = ?", schedule.ID).Delete(&entities.ClassScheduleExclusion{}).Error; err != nil {
+				return err
+			}
+			for i := range exclusions {
+				exclusions[i].ScheduleID = schedule.ID
+			}
+			if len(exclusions) > 0 {
+				if err := tx.Create(&exclusions).Error; err != nil {
+					return err
+				}
+			}
+			schedule.Exclusions = exclusions
+		}
+		return applyPlan(tx, *schedule, plan)
+	})
+}
+
+// Delete removes the rule and keeps its lessons as regular lessons of the class
+func (r *repository) Delete(schedule entities.ClassSchedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Lesson{}).Where("schedule_id = ?", schedule.ID).
+			Updates(map[string]any{"schedule_id": nil, "occurrence_date": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("schedule_id = ?", schedule.ID).Delete(&entities.ClassScheduleExclusion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.ClassSchedule{}, schedule.ID).Error
+	})
+}
+
+func (r *repository) SaveLesson(lesson *entities.Lesson) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, lesson.ClassID); err != nil {
+			return err
+		}
+		if err := tx.Omit("LessonOrder", "Class", "Creator", "Resources").Save(lesson).Error; err != nil {
+			return err
+		}
+		if lesson.ScheduleID == nil {
+			return nil
+		}
+		return renumberLessons(tx, lesson.ClassID, *lesson.ScheduleID)
+	})
+}
+
+func applyPlan(tx *gorm.DB, schedule entities.ClassSchedule, plan SyncPlan) error {
+	if err := lockClass(tx, schedule.ClassID); err != nil {
+		return err
+	}
+
+	// New lessons go after the existing ones; renumberLessons then sorts them in among the
+	// schedule's lessons
+	var last int
+	if err := tx.Model(&entities.Lesson{}).Where("class_id = ?", schedule.ClassID).
+		Select("COALESCE(MAX(lesson_order), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	for i := range plan.Create {
+		plan.Create[i].ScheduleID = &schedule.ID
+		plan.Create[i].LessonOrder = last + i + 1
+	}
+	if len(plan.Create) > 0 {
+		if err := tx.Create(&plan.Create).Error; err != nil {
+			return err
+		}
+	}
+	for i := range plan.Update {
+		if err := tx.Omit("LessonOrder", "Class", "Creator", "Resources").Save(&plan.Update[i]).Error; err != nil {
+			return err
+		}
+	}
+	if len(plan.Delete) > 0 {
+		if err := tx.Delete(&entities.Lesson{}, plan.Delete).Error; err != nil {
+			return err
+		}
+	}
+	if len(plan.Detach) > 0 {
+		if err := tx.Model(&entities.Lesson{}).Where("id IN ?", plan.Detach).
+			Updates(map[string]any{"schedule_id": nil, "occurrence_date": ""}).Error; err != nil {
+			return err
+		}
+	}
+	return renumberLessons(tx, schedule.ClassID, schedule.ID)
+}
+
+// lockClass locks the class row so lesson numbering changes of one class run one after another
+func lockClass(tx *gorm.DB, classID uint) error {
+	var class entities.Class
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error
+}
+
+// renumberLessons closes the gaps in the class numbering and sorts the schedule's lessons by
+// start time within the positions they already hold. Every other lesson, hand-made or placed
+// by hand through the reorder endpoint, keeps its place. Moving lessons are parked on
+// negative numbers first to stay clear of the unique order index.
+func renumberLessons(tx *gorm.DB, classID, scheduleID uint) error {
+	var lessons []entities.Lesson
+	if err := tx.Select("id", "lesson_order", "schedule_id", "start_time").Where("class_id = ?", classID).
+		Order("lesson_order ASC, id ASC").Find(&lessons).Error; err != nil {
+		return err
+	}
+
+	var slots []int
+	var own []entities.Lesson
+	for i, lesson := range lessons {
+		if lesson.ScheduleID != nil && *lesson.ScheduleID == scheduleID {
+			slots = append(slots, i)
+			own = append(own, lesson)
+		}
+	}
+	// Lessons without a start time keep their relative order after the dated ones
+	sort.SliceStable(own, func(a, b int) bool {
+		if own[a].StartTime == nil || own[b].StartTime == nil {
+			return own[b].StartTime == nil && own[a].StartTime != nil
+		}
+		return own[a].StartTime.Before(*own[b].StartTime)
+	})
+	for k, slot := range slots {
+		lessons[slot] = own[k]
+	}
+
+	ordered := make([]ordering.Slot, len(lessons))
+	for i, lesson := range lessons {
+		ordered[i] = ordering.Slot{ID: lesson.ID, Position: lesson.LessonOrder}
+	}
+	list := ordering.List{Model: &entities.Lesson{}, Column: "lesson_order", Scope: "class_id = ?", Args: []any{classID}}
+	return list.Resequence(tx, ordered)
+}
@@ -0,0 +1,31 @@
+package schedules
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+)
+
+func ScheduleRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	db := migrations.GetDB()
+	service := NewService(NewRepository(db), tutors.NewService(tutors.NewRepository(db)))
+	handler := NewHandler(service)
+
+	classSchedules := router.Group("/classes/:id/schedules")
+	classSchedules.Use(requireAuth, requireAdminOrTutor)
+	{
+		classSchedules.GET("", handler.GetSchedulesByClassHandler)
+		classSchedules.POST("", handler.CreateScheduleHandler)
+	}
+
+	schedules := router.Group("/schedules/:id")
+	schedules.Use(requireAuth, requireAdminOrTutor)
+	{
+		schedules.PUT("", handler.UpdateScheduleHandler)
+		schedules.DELETE("", handler.DeleteScheduleHandler)
+		schedules.POST("/regenerate", handler.RegenerateScheduleHandler)
+		schedules.POST("/shift", handler.ShiftScheduleHandler)
+	}
+
+	router.POST("/lessons/:id/reschedule", requireAuth, requireAdminOrTutor, handler.RescheduleLessonHandler)
+}
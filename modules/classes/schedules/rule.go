@@ -0,0 +1,145 @@
+package schedules
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // schedules name IANA zones, so do not depend on the host's zoneinfo
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	timeLayout      = "15:04"
+	defaultTimezone = "Asia/Jakarta"
+
+	// maxScheduledLessons caps how many lessons a single schedule may generate
+	maxScheduledLessons = 200
+	// maxShiftSearchDays bounds the search for free slots when shifting a schedule
+	maxShiftSearchDays = 3 * 366
+)
+
+// occurrence is one slot of a schedule: the local date and the lesson times
+type occurrence struct {
+	Date  string
+	Start time.Time
+	End   time.Time
+}
+
+// rule is a parsed ClassSchedule
+type rule struct {
+	weekdays map[time.Weekday]bool
+	hour     int
+	minute   int
+	duration time.Duration
+	loc      *time.Location
+	start    time.Time
+	end      time.Time
+	excluded map[string]bool
+}
+
+func parseRule(schedule entities.ClassSchedule) (rule, error) {
+	r := rule{
+		weekdays: make(map[time.Weekday]bool),
+		duration: time.Duration(schedule.DurationMinutes) * time.Minute,
+		excluded: make(map[string]bool),
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil || schedule.Timezone == "" {
+		return r, errors.New("invalid timezone")
+	}
+	r.loc = loc
+
+	clock, err := time.Parse(timeLayout, schedule.StartTime)
+	if err != nil {
+		return r, errors.New("start time must use the HH:MM format")
+	}
+	r.hour, r.minute = clock.Hour(), clock.Minute()
+
+	if r.start, err = time.ParseInLocation(dateLayout, schedule.StartDate, loc); err != nil {
+		return r, errors.New("dates must use the YYYY-MM-DD format")
+	}
+	if r.end, err = time.ParseInLocation(dateLayout, schedule.EndDate, loc); err != nil {
+		return r, errors.New("dates must use the YYYY-MM-DD format")
+	}
+	if r.end.Before(r.start) {
+		return r, errors.New("end date must not be before start date")
+	}
+
+	for _, day := range parseWeekdays(schedule.Weekdays) {
+		r.weekdays[time.Weekday(day)] = true
+	}
+	if len(r.weekdays) == 0 {
+		return r, errors.New("schedule needs at least one weekday")
+	}
+	for _, exclusion := range schedule.Exclusions {
+		r.excluded[exclusion.Date] = true
+	}
+	return r, nil
+}
+
+// at builds the occurrence on the given local day
+func (r rule) at(day time.Time) occurrence {
+	start := time.Date(day.Year(), day.Month(), day.Day(), r.hour, r.minute, 0, 0, r.loc)
+	return occurrence{Date: day.Format(dateLayout), Start: start, End: start.Add(r.duration)}
+}
+
+func (r rule) matches(day time.Time) bool {
+	return r.weekdays[day.Weekday()] && !r.excluded[day.Format(dateLayout)]
+}
+
+// between lists the occurrences from one local date to another, both inclusive.
+// It stops once it has more than limit occurrences when limit is positive.
+func (r rule) between(from, to time.Time, limit int) []occurrence {
+	var occurrences []occurrence
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !r.matches(day) {
+			continue
+		}
+		occurrences = append(occurrences, r.at(day))
+		if limit > 0 && len(occurrences) > limit {
+			break
+		}
+	}
+	return occurrences
+}
+
+// today is the current date in the schedule's timezone
+func (r rule) today() time.Time {
+	now := time.Now().In(r.loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, r.loc)
+}
+
+// formatWeekdays stores the weekdays sorted and without duplicates, e.g. "1,3,5"
+func formatWeekdays(days []int) string {
+	seen := make(map[int]bool)
+	var unique []int
+	for _, day := range days {
+		if !seen[day] {
+			seen[day] = true
+			unique = append(unique, day)
+		}
+	}
+	sort.Ints(unique)
+
+	parts := make([]string, 0, len(unique))
+	for _, day := range unique {
+		parts = append(parts, strconv.Itoa(day))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseWeekdays(value string) []int {
+	days := []int{}
+	for _, part := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && day >= 0 && day <= 6 {
+			days = append(days, day)
+		}
+	}
+	return days
+}
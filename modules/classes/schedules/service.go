@@ -0,0 +1,566 @@
+package schedules
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type scheduleService struct {
+	repo   Repository
+	tutors tutors.Service
+}
+
+type Service interface {
+	GetByClassID(classID uint, requestID string, userID uint, isAdmin bool) ([]ScheduleResponse, error)
+	Create(classID uint, input CreateScheduleInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error)
+	Update(id uint, input UpdateScheduleInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error)
+	Regenerate(id uint, requestID string, userID uint, isAdmin bool) (*SyncResponse, error)
+	Shift(id uint, input ShiftInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
+
+	RescheduleLesson(lessonID uint, input RescheduleInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error)
+}
+
+func NewService(repo Repository, tutorService tutors.Service) Service {
+	return &scheduleService{repo: repo, tutors: tutorService}
+}
+
+// ==========================================
+// Schedules
+// ==========================================
+
+func (s *scheduleService) GetByClassID(classID uint, requestID string, userID uint, isAdmin bool) ([]ScheduleResponse, error) {
+	utils.LogInfo("schedules", "get_by_class", "Fetching schedules for class", requestID, userID, map[string]any{
+		"class_id": classID,
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.FindByClassID(classID)
+	if err != nil {
+		utils.LogError("schedules", "get_by_class", "Failed to fetch schedules: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	responses := make([]ScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		response, err := s.toResponse(schedule)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *scheduleService) Create(classID uint, input CreateScheduleInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error) {
+	utils.LogInfo("schedules", "create", "Creating class schedule", requestID, userID, map[string]any{
+		"class_id": classID,
+	})
+
+	if err := s.tutors.AuthorizeClassContent(classID, userID, isAdmin); err != nil {
+		utils.LogWarning("schedules", "create", "Schedule creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": classID,
+		})
+		return nil, err
+	}
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	schedule := &entities.ClassSchedule{
+		ClassID:         classID,
+		LessonTitle:     input.LessonTitle,
+		Weekdays:        formatWeekdays(input.Weekdays),
+		StartTime:       input.StartTime,
+		DurationMinutes: input.DurationMinutes,
+		Timezone:        input.Timezone,
+		StartDate:       input.StartDate,
+		EndDate:         input.EndDate,
+		CreatedByUserID: userID,
+		Exclusions:      toExclusions(input.Exclusions),
+	}
+	if schedule.LessonTitle == "" {
+		schedule.LessonTitle = class.Name
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = defaultTimezone
+	}
+
+	r, err := s.checkRule(*schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new schedule owns no lessons yet, so every occurrence becomes a lesson, past ones included
+	plan := s.plan(*schedule, r, nil, nil, r.start, userID)
+	if err := s.repo.Create(schedule, plan); err != nil {
+		utils.LogError("schedules", "create", "Failed to create schedule: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("schedules", "create", "Schedule created successfully", requestID, userID, map[string]any{
+		"schedule_id": schedule.ID,
+		"lessons":     len(plan.Create),
+	})
+	return s.toSyncResponse(*schedule, plan)
+}
+
+func (s *scheduleService) Update(id uint, input UpdateScheduleInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error) {
+	utils.LogInfo("schedules", "update", "Updating class schedule", requestID, userID, map[string]any{
+		"schedule_id": id,
+	})
+
+	schedule, err := s.findAuthorized(id, userID, isAdmin)
+	if err != nil {
+		utils.LogWarning("schedules", "update", "Schedule update refused: "+err.Error(), requestID, userID, map[string]any{
+			"schedule_id": id,
+		})
+		return nil, err
+	}
+
+	if input.LessonTitle != nil && *input.LessonTitle != "" {
+		schedule.LessonTitle = *input.LessonTitle
+	}
+	if input.Weekdays != nil {
+		schedule.Weekdays = formatWeekdays(*input.Weekdays)
+	}
+	if input.StartTime != nil {
+		schedule.StartTime = *input.StartTime
+	}
+	if input.DurationMinutes != nil {
+		schedule.DurationMinutes = *input.DurationMinutes
+	}
+	if input.Timezone != nil && *input.Timezone != "" {
+		schedule.Timezone = *input.Timezone
+	}
+	if input.StartDate != nil {
+		schedule.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		schedule.EndDate = *input.EndDate
+	}
+	var exclusions []entities.ClassScheduleExclusion
+	if input.Exclusions != nil {
+		exclusions = toExclusions(*input.Exclusions)
+		if exclusions == nil {
+			exclusions = []entities.ClassScheduleExclusion{}
+		}
+		schedule.Exclusions = exclusions
+	}
+
+	response, err := s.sync(schedule, exclusions, requestID, userID)
+	if err != nil {
+		utils.LogError("schedules", "update", "Failed to update schedule: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("schedules", "update", "Schedule updated successfully", requestID, userID, map[string]any{
+		"schedule_id": id,
+		"created":     response.Created,
+		"updated":     response.Updated,
+		"removed":     response.Removed,
+	})
+	return response, nil
+}
+
+func (s *scheduleService) Regenerate(id uint, requestID string, userID uint, isAdmin bool) (*SyncResponse, error) {
+	utils.LogInfo("schedules", "regenerate", "Regenerating schedule lessons", requestID, userID, map[string]any{
+		"schedule_id": id,
+	})
+
+	schedule, err := s.findAuthorized(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.sync(schedule, nil, requestID, userID)
+	if err != nil {
+		utils.LogError("schedules", "regenerate", "Failed to regenerate lessons: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("schedules", "regenerate", "Schedule lessons regenerated", requestID, userID, map[string]any{
+		"schedule_id": id,
+		"created":     response.Created,
+		"updated":     response.Updated,
+		"removed":     response.Removed,
+	})
+	return response, nil
+}
+
+// Shift moves every remaining non-rescheduled session from FromDate onward by the given
+// number of slots of the pattern. Lessons keep their IDs, so resources, quizzes and other
+// content move with them; the vacated dates become exclusions and the end date is extended.
+func (s *scheduleService) Shift(id uint, input ShiftInput, requestID string, userID uint, isAdmin bool) (*SyncResponse, error) {
+	utils.LogInfo("schedules", "shift", "Shifting schedule lessons", requestID, userID, map[string]any{
+		"schedule_id": id,
+		"from_date":   input.FromDate,
+		"slots":       input.Slots,
+	})
+
+	schedule, err := s.findAuthorized(id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	r, err := parseRule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	from, err := time.ParseInLocation(dateLayout, input.FromDate, r.loc)
+	if err != nil {
+		return nil, errors.New("dates must use the YYYY-MM-DD format")
+	}
+
+	lessons, err := s.repo.FindLessons(schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[string]bool)
+	var movable []entities.Lesson
+	for _, lesson := range lessons {
+		if lesson.OccurrenceDate < input.FromDate {
+			continue
+		}
+		if lesson.IsRescheduled {
+			held[lesson.OccurrenceDate] = true
+			continue
+		}
+		movable = append(movable, lesson)
+	}
+	if len(movable) == 0 {
+		return nil, errors.New("no remaining lessons to shift")
+	}
+
+	// Slots taken by rescheduled lessons stay theirs; everything else is laid out again
+	needed := len(movable) + input.Slots
+	var slots []occurrence
+	for day, i := from, 0; len(slots) < needed && i < maxShiftSearchDays; day, i = day.AddDate(0, 0, 1), i+1 {
+		if r.matches(day) && !held[day.Format(dateLayout)] {
+			slots = append(slots, r.at(day))
+		}
+	}
+	if len(slots) < needed {
+		return nil, errors.New("schedule has no free slots to shift into")
+	}
+
+	var plan SyncPlan
+	for i, lesson := range movable {
+		slot := slots[i+input.Slots]
+		start, end := slot.Start, slot.End
+		lesson.OccurrenceDate = slot.Date
+		lesson.StartTime = &start
+		lesson.EndTime = &end
+		plan.Update = append(plan.Update, lesson)
+	}
+
+	exclusions := make([]entities.ClassScheduleExclusion, 0, len(schedule.Exclusions)+input.Slots)
+	for _, exclusion := range schedule.Exclusions {
+		exclusions = append(exclusions, entities.ClassScheduleExclusion{Date: exclusion.Date, Reason: exclusion.Reason})
+	}
+	for _, slot := range slots[:input.Slots] {
+		exclusions = append(exclusions, entities.ClassScheduleExclusion{Date: slot.Date, Reason: "shifted"})
+	}
+	if last := slots[needed-1].Date; last > schedule.EndDate {
+		schedule.EndDate = last
+	}
+
+	if err := s.repo.Save(&schedule, exclusions, plan); err != nil {
+		utils.LogError("schedules", "shift", "Failed to shift lessons: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("schedules", "shift", "Schedule lessons shifted", requestID, userID, map[string]any{
+		"schedule_id": id,
+		"lessons":     len(plan.Update),
+		"end_date":    schedule.EndDate,
+	})
+	return s.toSyncResponse(schedule, plan)
+}
+
+// Delete removes the rule; its lessons stay in the class as regular lessons
+func (s *scheduleService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
+	utils.LogInfo("schedules", "delete", "Deleting class schedule", requestID, userID, map[string]any{
+		"schedule_id": id,
+	})
+
+	schedule, err := s.findAuthorized(id, userID, isAdmin)
+	if err != nil {
+		utils.LogWarning("schedules", "delete", "Schedule deletion refused: "+err.Error(), requestID, userID, map[string]any{
+			"schedule_id": id,
+		})
+		return err
+	}
+
+	if err := s.repo.Delete(schedule); err != nil {
+		utils.LogError("schedules", "delete", "Failed to delete schedule: "+err.Error(), requestID, userID, nil)
+		return err
+	}
+
+	utils.LogSuccess("schedules", "delete", "Schedule deleted successfully", requestID, userID, map[string]any{
+		"schedule_id": id,
+	})
+	return nil
+}
+
+// ==========================================
+// Single Lessons
+// ==========================================
+
+// RescheduleLesson moves one lesson. A lesson generated by a schedule is marked as rescheduled
+// so later regenerations leave its times alone.
+func (s *scheduleService) RescheduleLesson(lessonID uint, input RescheduleInput, requestID string, userID uint, isAdmin bool) (*LessonResponse, error) {
+	utils.LogInfo("schedules", "reschedule_lesson", "Rescheduling lesson", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+
+	if err := s.tutors.AuthorizeLesson(lessonID, userID, isAdmin); err != nil {
+		utils.LogWarning("schedules", "reschedule_lesson", "Lesson reschedule refused: "+err.Error(), requestID, userID, map[string]any{
+			"lesson_id": lessonID,
+		})
+		return nil, err
+	}
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lesson not found")
+		}
+		return nil, err
+	}
+
+	start := input.StartTime
+	end := input.EndTime
+	if end == nil {
+		length := time.Hour
+		if lesson.StartTime != nil && lesson.EndTime != nil && lesson.EndTime.After(*lesson.StartTime) {
+			length = lesson.EndTime.Sub(*lesson.StartTime)
+		}
+		moved := start.Add(length)
+		end = &moved
+	}
+	if !end.After(start) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	lesson.StartTime = &start
+	lesson.EndTime = end
+	lesson.IsRescheduled = lesson.ScheduleID != nil
+
+	if err := s.repo.SaveLesson(&lesson); err != nil {
+		utils.LogError("schedules", "reschedule_lesson", "Failed to reschedule lesson: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+	lesson, err = s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogSuccess("schedules", "reschedule_lesson", "Lesson rescheduled successfully", requestID, userID, map[string]any{
+		"lesson_id": lessonID,
+	})
+	return &LessonResponse{
+		ID:             lesson.ID,
+		ClassID:        lesson.ClassID,
+		Title:          lesson.Title,
+		LessonOrder:    lesson.LessonOrder,
+		StartTime:      lesson.StartTime,
+		EndTime:        lesson.EndTime,
+		ScheduleID:     lesson.ScheduleID,
+		OccurrenceDate: lesson.OccurrenceDate,
+		IsRescheduled:  lesson.IsRescheduled,
+	}, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *scheduleService) findAuthorized(id, userID uint, isAdmin bool) (entities.ClassSchedule, error) {
+	schedule, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return schedule, errors.New("schedule not found")
+		}
+		return schedule, err
+	}
+	if err := s.tutors.AuthorizeClassContent(schedule.ClassID, userID, isAdmin); err != nil {
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+// checkRule validates the schedule and its lesson count
+func (s *scheduleService) checkRule(schedule entities.ClassSchedule) (rule, error) {
+	r, err := parseRule(schedule)
+	if err != nil {
+		return r, err
+	}
+	for _, exclusion := range schedule.Exclusions {
+		if _, err := time.Parse(dateLayout, exclusion.Date); err != nil {
+			return r, errors.New("dates must use the YYYY-MM-DD format")
+		}
+	}
+	if len(r.between(r.start, r.end, maxScheduledLessons)) > maxScheduledLessons {
+		return r, errors.New("schedule would create more than " + strconv.Itoa(maxScheduledLessons) + " lessons")
+	}
+	return r, nil
+}
+
+// sync saves the rule and brings its lessons from today onward in line with it. Past lessons
+// are history and never touched.
+func (s *scheduleService) sync(schedule entities.ClassSchedule, exclusions []entities.ClassScheduleExclusion, requestID string, userID uint) (*SyncResponse, error) {
+	r, err := s.checkRule(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.repo.FindLessons(schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	from := r.today()
+	var upcomingIDs []uint
+	for _, lesson := range lessons {
+		if lesson.OccurrenceDate >= from.Format(dateLayout) {
+			upcomingIDs = append(upcomingIDs, lesson.ID)
+		}
+	}
+	withContent, err := s.repo.FindLessonIDsWithContent(upcomingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := s.plan(schedule, r, lessons, withContent, from, userID)
+	if err := s.repo.Save(&schedule, exclusions, plan); err != nil {
+		return nil, err
+	}
+	return s.toSyncResponse(schedule, plan)
+}
+
+// plan matches the schedule's lessons on or after from against the rule's occurrences:
+// matched lessons follow the rule unless they were rescheduled by hand, missing occurrences
+// get new lessons, and lessons that left the pattern are deleted, or detached when they
+// already hold content or were rescheduled
+func (s *scheduleService) plan(schedule entities.ClassSchedule, r rule, lessons []entities.Lesson, withContent map[uint]bool, from time.Time, userID uint) SyncPlan {
+	if from.Before(r.start) {
+		from = r.start
+	}
+	fromDate := from.Format(dateLayout)
+
+	occurrences := r.between(from, r.end, 0)
+	pending := make(map[string]occurrence, len(occurrences))
+	for _, occ := range occurrences {
+		pending[occ.Date] = occ
+	}
+
+	var plan SyncPlan
+	for _, lesson := range lessons {
+		if lesson.OccurrenceDate < fromDate {
+			continue
+		}
+		occ, ok := pending[lesson.OccurrenceDate]
+		if !ok {
+			if lesson.IsRescheduled || withContent[lesson.ID] {
+				plan.Detach = append(plan.Detach, lesson.ID)
+			} else {
+				plan.Delete = append(plan.Delete, lesson.ID)
+			}
+			continue
+		}
+		delete(pending, lesson.OccurrenceDate)
+		if lesson.IsRescheduled {
+			continue
+		}
+		if lesson.StartTime == nil || !lesson.StartTime.Equal(occ.Start) || lesson.EndTime == nil || !lesson.EndTime.Equal(occ.End) {
+			start, end := occ.Start, occ.End
+			lesson.StartTime = &start
+			lesson.EndTime = &end
+			plan.Update = append(plan.Update, lesson)
+		}
+	}
+
+	for _, occ := range occurrences {
+		if _, ok := pending[occ.Date]; !ok {
+			continue
+		}
+		start, end := occ.Start, occ.End
+		plan.Create = append(plan.Create, entities.Lesson{
+			ClassID:         schedule.ClassID,
+			CreatedByUserID: userID,
+			Title:           schedule.LessonTitle,
+			StartTime:       &start,
+			EndTime:         &end,
+			OccurrenceDate:  occ.Date,
+		})
+	}
+	return plan
+}
+
+func (s *scheduleService) toSyncResponse(schedule entities.ClassSchedule, plan SyncPlan) (*SyncResponse, error) {
+	response, err := s.toResponse(schedule)
+	if err != nil {
+		return nil, err
+	}
+	detached := plan.Detach
+	if detached == nil {
+		detached = []uint{}
+	}
+	return &SyncResponse{
+		Schedule: response,
+		Created:  len(plan.Create),
+		Updated:  len(plan.Update),
+		Removed:  len(plan.Delete),
+		Detached: detached,
+	}, nil
+}
+
+func (s *scheduleService) toResponse(schedule entities.ClassSchedule) (ScheduleResponse, error) {
+	count, err := s.repo.CountLessons(schedule.ID)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	response := ScheduleResponse{
+		ID:              schedule.ID,
+		ClassID:         schedule.ClassID,
+		LessonTitle:     schedule.LessonTitle,
+		Weekdays:        parseWeekdays(schedule.Weekdays),
+		StartTime:       schedule.StartTime,
+		DurationMinutes: schedule.DurationMinutes,
+		Timezone:        schedule.Timezone,
+		StartDate:       schedule.StartDate,
+		EndDate:         schedule.EndDate,
+		Exclusions:      make([]ExclusionResponse, 0, len(schedule.Exclusions)),
+		LessonCount:     int(count),
+		CreatedAt:       schedule.CreatedAt,
+	}
+	for _, exclusion := range schedule.Exclusions {
+		response.Exclusions = append(response.Exclusions, ExclusionResponse{Date: exclusion.Date, Reason: exclusion.Reason})
+	}
+	return response, nil
+}
+
+func toExclusions(inputs []ExclusionInput) []entities.ClassScheduleExclusion {
+	var exclusions []entities.ClassScheduleExclusion
+	seen := make(map[string]bool)
+	for _, input := range inputs {
+		if seen[input.Date] {
+			continue
+		}
+		seen[input.Date] = true
+		exclusions = append(exclusions, entities.ClassScheduleExclusion{Date: input.Date, Reason: input.Reason})
+	}
+	return exclusions
+}
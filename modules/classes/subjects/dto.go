@@ -1,13 +1,22 @@
 package subjects
 
+import "time"
+
 type CreateSubjectInput struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+
+	// Defaults to published; a draft with publishAt goes live at that time
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type UpdateSubjectInput struct {
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
+
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type SubjectResponse struct {
@@ -16,4 +25,8 @@ type SubjectResponse struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	LessonCount int    `json:"lessonCount,omitempty"`
+
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	IsLive    bool       `json:"isLive"`
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -47,6 +49,21 @@ func getUserID(c *gin.Context) uint {
 	return 0
 }
 
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func getViewer(c *gin.Context) access.Viewer {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return access.Viewer{UserID: getUserID(c), Role: roleStr}
+}
+
 func (h *handler) GetSubjectsByCourseHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	courseIDStr := c.Param("id")
@@ -57,7 +74,7 @@ func (h *handler) GetSubjectsByCourseHandler(c *gin.Context) {
 		return
 	}
 
-	subjects, err := h.service.GetByCourseID(uint(courseID), requestID)
+	subjects, err := h.service.GetByCourseID(uint(courseID), getViewer(c), requestID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch subjects", err.Error(), nil))
 		return
@@ -76,7 +93,7 @@ func (h *handler) GetSubjectByIDHandler(c *gin.Context) {
 		return
 	}
 
-	subject, err := h.service.GetByID(uint(id), requestID)
+	subject, err := h.service.GetByID(uint(id), getViewer(c), requestID)
 	if err != nil {
 		if err.Error() == "subject not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Subject not found", err.Error(), nil))
@@ -106,8 +123,16 @@ func (h *handler) CreateSubjectHandler(c *gin.Context) {
 		return
 	}
 
-	subject, err := h.service.Create(uint(courseID), input, requestID, userID)
+	subject, err := h.service.Create(uint(courseID), input, requestID, userID, isAdmin(c))
 	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create subject", err.Error(), nil))
 		return
 	}
@@ -132,12 +157,16 @@ func (h *handler) UpdateSubjectHandler(c *gin.Context) {
 		return
 	}
 
-	subject, err := h.service.Update(uint(id), input, requestID, userID)
+	subject, err := h.service.Update(uint(id), input, requestID, userID, isAdmin(c))
 	if err != nil {
 		if err.Error() == "subject not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Subject not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update subject", err.Error(), nil))
 		return
 	}
@@ -156,11 +185,15 @@ func (h *handler) DeleteSubjectHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(id), requestID, userID); err != nil {
+	if err := h.service.Delete(uint(id), requestID, userID, isAdmin(c)); err != nil {
 		if err.Error() == "subject not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Subject not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete subject", err.Error(), nil))
 		return
 	}
@@ -3,18 +3,22 @@ package subjects
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 )
 
 func SubjectRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
-	subjectRepo := NewRepository(migrations.GetDB())
-	subjectService := NewService(subjectRepo)
+	db := migrations.GetDB()
+	subjectRepo := NewRepository(db)
+	subjectService := NewService(subjectRepo, tutors.NewService(tutors.NewRepository(db)), access.NewService(access.NewRepository(db)))
 	subjectHandler := NewHandler(subjectService)
 
 	// NOTE: /courses/:id/classes is registered in CourseIndexRouter to avoid Gin wildcard conflict
 
 	classes := router.Group("/classes")
 	{
-		classes.GET("/:id", subjectHandler.GetSubjectByIDHandler)
+		classes.GET("/:id", middleware.OptionalAuth(), subjectHandler.GetSubjectByIDHandler)
 		classes.PUT("/:id", requireAuth, requireAdminOrTutor, subjectHandler.UpdateSubjectHandler)
 		classes.DELETE("/:id", requireAuth, requireAdminOrTutor, subjectHandler.DeleteSubjectHandler)
 	}
@@ -23,6 +27,6 @@ func SubjectRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, require
 // RegisterCourseSubRoutes registers /courses/:id/classes under an existing courseByID group
 // to avoid Gin wildcard conflicts.
 func RegisterCourseSubRoutes(courseByID *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc, h Handler) {
-	courseByID.GET("/classes", h.GetSubjectsByCourseHandler)
+	courseByID.GET("/classes", middleware.OptionalAuth(), h.GetSubjectsByCourseHandler)
 	courseByID.POST("/classes", requireAuth, requireAdminOrTutor, h.CreateSubjectHandler)
 }
@@ -2,30 +2,36 @@ package subjects
 
 import (
 	"errors"
+	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
 type subjectService struct {
-	repo Repository
+	repo   Repository
+	tutors tutors.Service
+	access access.Service
 }
 
 type Service interface {
-	GetByCourseID(courseID uint, requestID string) ([]SubjectResponse, error)
-	GetByID(id uint, requestID string) (*SubjectResponse, error)
-	Create(courseID uint, input CreateSubjectInput, requestID string, userID uint) (*SubjectResponse, error)
-	Update(id uint, input UpdateSubjectInput, requestID string, userID uint) (*SubjectResponse, error)
-	Delete(id uint, requestID string, userID uint) error
+	// Viewers outside the course staff only get classes (and lesson counts) that are live
+	GetByCourseID(courseID uint, viewer access.Viewer, requestID string) ([]SubjectResponse, error)
+	GetByID(id uint, viewer access.Viewer, requestID string) (*SubjectResponse, error)
+	Create(courseID uint, input CreateSubjectInput, requestID string, userID uint, isAdmin bool) (*SubjectResponse, error)
+	Update(id uint, input UpdateSubjectInput, requestID string, userID uint, isAdmin bool) (*SubjectResponse, error)
+	Delete(id uint, requestID string, userID uint, isAdmin bool) error
 }
 
-func NewService(repo Repository) Service {
-	return &subjectService{repo: repo}
+func NewService(repo Repository, tutorService tutors.Service, accessService access.Service) Service {
+	return &subjectService{repo: repo, tutors: tutorService, access: accessService}
 }
 
-func (s *subjectService) GetByCourseID(courseID uint, requestID string) ([]SubjectResponse, error) {
-	utils.LogInfo("classes", "get_by_course", "Fetching classes for course", requestID, 0, map[string]any{
+func (s *subjectService) GetByCourseID(courseID uint, viewer access.Viewer, requestID string) ([]SubjectResponse, error) {
+	utils.LogInfo("classes", "get_by_course", "Fetching classes for course", requestID, viewer.UserID, map[string]any{
 		"course_id": courseID,
 	})
 
@@ -37,18 +43,25 @@ func (s *subjectService) GetByCourseID(courseID uint, requestID string) ([]Subje
 
 	var responses []SubjectResponse
 	for _, c := range classes {
-		responses = append(responses, s.toResponse(c))
+		visible, preview, err := s.access.ClassVisibility(c.ID, viewer)
+		if err != nil {
+			utils.LogError("classes", "get_by_course", "Failed to check visibility: "+err.Error(), requestID, viewer.UserID, nil)
+			return nil, err
+		}
+		if visible {
+			responses = append(responses, s.toResponse(c, preview))
+		}
 	}
 
-	utils.LogSuccess("classes", "get_by_course", "Successfully fetched classes", requestID, 0, map[string]any{
+	utils.LogSuccess("classes", "get_by_course", "Successfully fetched classes", requestID, viewer.UserID, map[string]any{
 		"course_id": courseID,
 		"count":     len(responses),
 	})
 	return responses, nil
 }
 
-func (s *subjectService) GetByID(id uint, requestID string) (*SubjectResponse, error) {
-	utils.LogInfo("classes", "get_by_id", "Fetching class by ID", requestID, 0, map[string]any{
+func (s *subjectService) GetByID(id uint, viewer access.Viewer, requestID string) (*SubjectResponse, error) {
+	utils.LogInfo("classes", "get_by_id", "Fetching class by ID", requestID, viewer.UserID, map[string]any{
 		"class_id": id,
 	})
 
@@ -57,25 +70,42 @@ func (s *subjectService) GetByID(id uint, requestID string) (*SubjectResponse, e
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("subject not found")
 		}
-		utils.LogError("classes", "get_by_id", "Failed to fetch class: "+err.Error(), requestID, 0, nil)
+		utils.LogError("classes", "get_by_id", "Failed to fetch class: "+err.Error(), requestID, viewer.UserID, nil)
 		return nil, err
 	}
 
-	response := s.toResponse(class)
+	visible, preview, err := s.access.ClassVisibility(id, viewer)
+	if err != nil {
+		utils.LogError("classes", "get_by_id", "Failed to check visibility: "+err.Error(), requestID, viewer.UserID, nil)
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.New("subject not found")
+	}
+
+	response := s.toResponse(class, preview)
 	return &response, nil
 }
 
-func (s *subjectService) Create(courseID uint, input CreateSubjectInput, requestID string, userID uint) (*SubjectResponse, error) {
+func (s *subjectService) Create(courseID uint, input CreateSubjectInput, requestID string, userID uint, isAdmin bool) (*SubjectResponse, error) {
 	utils.LogInfo("classes", "create", "Creating new class", requestID, userID, map[string]any{
 		"course_id": courseID,
 		"name":      input.Name,
 	})
 
+	if err := s.tutors.AuthorizeCourse(courseID, userID, isAdmin); err != nil {
+		utils.LogWarning("classes", "create", "Class creation refused: "+err.Error(), requestID, userID, map[string]any{
+			"course_id": courseID,
+		})
+		return nil, err
+	}
+
 	class := &entities.Class{
 		CourseID:    courseID,
 		Name:        input.Name,
 		Description: input.Description,
 	}
+	access.ApplyPublishing(&class.Status, &class.PublishAt, input.Status, input.PublishAt)
 
 	if err := s.repo.Create(class); err != nil {
 		utils.LogError("classes", "create", "Failed to create class: "+err.Error(), requestID, userID, nil)
@@ -87,15 +117,22 @@ func (s *subjectService) Create(courseID uint, input CreateSubjectInput, request
 		"name":     class.Name,
 	})
 
-	response := s.toResponse(*class)
+	response := s.toResponse(*class, true)
 	return &response, nil
 }
 
-func (s *subjectService) Update(id uint, input UpdateSubjectInput, requestID string, userID uint) (*SubjectResponse, error) {
+func (s *subjectService) Update(id uint, input UpdateSubjectInput, requestID string, userID uint, isAdmin bool) (*SubjectResponse, error) {
 	utils.LogInfo("classes", "update", "Updating class", requestID, userID, map[string]any{
 		"class_id": id,
 	})
 
+	if err := s.tutors.AuthorizeClass(id, userID, isAdmin); err != nil {
+		utils.LogWarning("classes", "update", "Class update refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": id,
+		})
+		return nil, err
+	}
+
 	class, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -110,6 +147,7 @@ func (s *subjectService) Update(id uint, input UpdateSubjectInput, requestID str
 	if input.Description != nil {
 		class.Description = *input.Description
 	}
+	access.ApplyPublishing(&class.Status, &class.PublishAt, input.Status, input.PublishAt)
 
 	if err := s.repo.Update(&class); err != nil {
 		utils.LogError("classes", "update", "Failed to update class: "+err.Error(), requestID, userID, nil)
@@ -120,16 +158,16 @@ func (s *subjectService) Update(id uint, input UpdateSubjectInput, requestID str
 		"class_id": id,
 	})
 
-	response := s.toResponse(class)
+	response := s.toResponse(class, true)
 	return &response, nil
 }
 
-func (s *subjectService) Delete(id uint, requestID string, userID uint) error {
+func (s *subjectService) Delete(id uint, requestID string, userID uint, isAdmin bool) error {
 	utils.LogInfo("classes", "delete", "Deleting class", requestID, userID, map[string]any{
 		"class_id": id,
 	})
 
-	_, err := s.repo.FindByID(id)
+	class, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("subject not found")
@@ -137,6 +175,14 @@ func (s *subjectService) Delete(id uint, requestID string, userID uint) error {
 		return err
 	}
 
+	// Removing a class is a course-level change
+	if err := s.tutors.AuthorizeCourse(class.CourseID, userID, isAdmin); err != nil {
+		utils.LogWarning("classes", "delete", "Class deletion refused: "+err.Error(), requestID, userID, map[string]any{
+			"class_id": id,
+		})
+		return err
+	}
+
 	if err := s.repo.Delete(id); err != nil {
 		utils.LogError("classes", "delete", "Failed to delete class: "+err.Error(), requestID, userID, nil)
 		return err
@@ -148,12 +194,29 @@ func (s *subjectService) Delete(id uint, requestID string, userID uint) error {
 	return nil
 }
 
-func (s *subjectService) toResponse(c entities.Class) SubjectResponse {
-	return SubjectResponse{
+// toResponse builds the class response; outside preview the lesson count skips unpublished lessons
+func (s *subjectService) toResponse(c entities.Class, preview bool) SubjectResponse {
+	now := time.Now()
+	lessonCount := 0
+	for _, lesson := range c.Lessons {
+		if preview || access.IsLive(lesson.Status, lesson.PublishAt, now) {
+			lessonCount++
+		}
+	}
+
+	response := SubjectResponse{
 		ID:          c.ID,
 		CourseID:    c.CourseID,
 		Name:        c.Name,
 		Description: c.Description,
-		LessonCount: len(c.Lessons),
+		LessonCount: lessonCount,
+
+		Status:    c.Status,
+		PublishAt: c.PublishAt,
+		IsLive:    access.IsLive(c.Status, c.PublishAt, now),
+	}
+	if response.Status == "" {
+		response.Status = entities.ContentPublished
 	}
+	return response
 }
@@ -9,8 +9,14 @@ type CreateCourseInput struct {
 	StartDate         time.Time `json:"startDate" binding:"required"`
 	EndDate           time.Time `json:"endDate" binding:"required"`
 	IsFree            bool      `json:"isFree"`
+	Price             float64   `json:"price" binding:"gte=0"`
+	Capacity          int       `json:"capacity" binding:"gte=0"` // 0 = unlimited
 	Image             string    `json:"image,omitempty"`
 	WhatsappGroupLink string    `json:"whatsappGroupLink"`
+
+	// Defaults to published; a draft with publishAt goes live at that time
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type UpdateCourseInput struct {
@@ -20,8 +26,13 @@ type UpdateCourseInput struct {
 	StartDate         *time.Time `json:"startDate"`
 	EndDate           *time.Time `json:"endDate"`
 	IsFree            *bool      `json:"isFree"`
+	Price             *float64   `json:"price" binding:"omitempty,gte=0"`
+	Capacity          *int       `json:"capacity" binding:"omitempty,gte=0"`
 	Image             *string    `json:"image,omitempty"`
 	WhatsappGroupLink *string    `json:"whatsappGroupLink"`
+
+	Status    *string    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type CourseResponse struct {
@@ -32,6 +43,8 @@ type CourseResponse struct {
 	StartDate         time.Time `json:"startDate"`
 	EndDate           time.Time `json:"endDate"`
 	IsFree            bool      `json:"isFree"`
+	Price             float64   `json:"price"`
+	Capacity          int       `json:"capacity"`
 	WhatsappGroupLink string    `json:"whatsappGroupLink,omitempty"`
 	CreatedAt         time.Time `json:"createdAt"`
 	Image             string    `json:"image,omitempty"`
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/modules/dto"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
@@ -53,6 +54,15 @@ func getUserID(c *gin.Context) uint {
 	return 0
 }
 
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
 func (h *handler) GetMyCoursesTutorHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	userID := getUserID(c)
@@ -63,7 +73,7 @@ func (h *handler) GetMyCoursesTutorHandler(c *gin.Context) {
 		return
 	}
 
-	courses, err := h.service.GetByCreator(userID, params, requestID)
+	courses, err := h.service.GetByTutor(userID, params, requestID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch courses", err.Error(), nil))
 		return
@@ -82,7 +92,7 @@ func (h *handler) GetAllCoursesHandler(c *gin.Context) {
 		return
 	}
 
-	courses, err := h.service.GetAll(params, requestID)
+	courses, err := h.service.GetAll(params, requestID, isAdmin(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch courses", err.Error(), nil))
 		return
@@ -101,7 +111,7 @@ func (h *handler) GetCourseByIDHandler(c *gin.Context) {
 		return
 	}
 
-	course, err := h.service.GetByID(uint(id), requestID)
+	course, err := h.service.GetByID(uint(id), requestID, getUserID(c), isAdmin(c))
 	if err != nil {
 		if err.Error() == "course not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
@@ -173,12 +183,16 @@ func (h *handler) UpdateCourseHandler(c *gin.Context) {
 		return
 	}
 
-	course, err := h.service.Update(uint(id), input, requestID, userID)
+	course, err := h.service.Update(uint(id), input, requestID, userID, isAdmin(c))
 	if err != nil {
 		if err.Error() == "course not found" {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
 			return
 		}
+		if tutors.IsAuthorizationError(err) {
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Forbidden", err.Error(), nil))
+			return
+		}
 		if err.Error() == "course with this name already exists" {
 			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Course name conflict", err.Error(), nil))
 			return
@@ -2,6 +2,7 @@ package courses
 
 import (
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
 	"gorm.io/gorm"
 )
 
@@ -11,12 +12,12 @@ type repository struct {
 
 type Repository interface {
 	FindAll() ([]entities.Course, error)
-	FindAllPaginated(offset, limit int, search string) ([]entities.Course, error)
-	FindByCreatorPaginated(creatorID uint, offset, limit int, search string) ([]entities.Course, error)
-	CountByCreator(creatorID uint, search string) (int64, error)
-	CountWithSearch(search string) (int64, error)
+	FindAllPaginated(offset, limit int, search string, liveOnly bool) ([]entities.Course, error)
+	FindByTutorPaginated(tutorID uint, offset, limit int, search string) ([]entities.Course, error)
+	CountByTutor(tutorID uint, search string) (int64, error)
+	CountWithSearch(search string, liveOnly bool) (int64, error)
 	FindByID(id uint) (entities.Course, error)
-	FindByProgramID(programID uint) ([]entities.Course, error)
+	FindLiveByProgramID(programID uint) ([]entities.Course, error)
 	FindByName(name string) (entities.Course, error)
 	Create(course *entities.Course) error
 	Update(course *entities.Course) error
@@ -28,10 +29,16 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
-func (r *repository) FindByCreatorPaginated(creatorID uint, offset, limit int, search string) ([]entities.Course, error) {
+// tutorCourses matches courses the tutor created or is assigned to (course-wide or to one of its classes)
+func (r *repository) tutorCourses(tutorID uint) *gorm.DB {
+	assigned := r.db.Model(&entities.CourseTutor{}).Select("course_id").Where("user_id = ?", tutorID)
+	return r.db.Where("created_by_user_id = ? OR id IN (?)", tutorID, assigned)
+}
+
+func (r *repository) FindByTutorPaginated(tutorID uint, offset, limit int, search string) ([]entities.Course, error) {
 	var courses []entities.Course
 	query := r.db.Preload("Program").Preload("Creator").Preload("Classes").
-		Where("created_by_user_id = ?", creatorID)
+		Where(r.tutorCourses(tutorID))
 	if search != "" {
 		query = query.Where("name_course LIKE ?", "%"+search+"%")
 	}
@@ -39,9 +46,9 @@ func (r *repository) FindByCreatorPaginated(creatorID uint, offset, limit int, s
 	return courses, err
 }
 
-func (r *repository) CountByCreator(creatorID uint, search string) (int64, error) {
+func (r *repository) CountByTutor(tutorID uint, search string) (int64, error) {
 	var count int64
-	query := r.db.Model(&entities.Course{}).Where("created_by_user_id = ?", creatorID)
+	query := r.db.Model(&entities.Course{}).Where(r.tutorCourses(tutorID))
 	if search != "" {
 		query = query.Where("name_course LIKE ?", "%"+search+"%")
 	}
@@ -54,20 +61,27 @@ func (r *repository) FindAll() ([]entities.Course, error) {
 	return courses, err
 }
 
-func (r *repository) FindAllPaginated(offset, limit int, search string) ([]entities.Course, error) {
+func (r *repository) FindAllPaginated(offset, limit int, search string, liveOnly bool) ([]entities.Course, error) {
 	var course []entities.Course
 
-	query := r.db.Preload("Program").Preload("Creator").Preload("Classes")
+	query := r.db.Preload("Program").Preload("Creator")
 
 	if search != "" {
 		query = query.Where("name_course LIKE ?", "%"+search+"%")
 	}
 
+	if liveOnly {
+		query = access.WhereLive(query, "courses").
+			Preload("Classes", func(db *gorm.DB) *gorm.DB { return access.WhereLive(db, "classes") })
+	} else {
+		query = query.Preload("Classes")
+	}
+
 	err := query.Offset(offset).Limit(limit).Find(&course).Error
 	return course, err
 }
 
-func (r *repository) CountWithSearch(search string) (int64, error) {
+func (r *repository) CountWithSearch(search string, liveOnly bool) (int64, error) {
 	var count int64
 
 	query := r.db.Model(&entities.Course{})
@@ -76,6 +90,10 @@ func (r *repository) CountWithSearch(search string) (int64, error) {
 		query = query.Where("name_course LIKE ?", "%"+search+"%")
 	}
 
+	if liveOnly {
+		query = access.WhereLive(query, "courses")
+	}
+
 	err := query.Count(&count).Error
 	return count, err
 }
@@ -86,9 +104,10 @@ func (r *repository) FindByID(id uint) (entities.Course, error) {
 	return course, err
 }
 
-func (r *repository) FindByProgramID(programID uint) ([]entities.Course, error) {
+func (r *repository) FindLiveByProgramID(programID uint) ([]entities.Course, error) {
 	var courses []entities.Course
-	err := r.db.Where("program_id = ?", programID).Preload("Program").Preload("Creator").Find(&courses).Error
+	err := access.WhereLive(r.db.Where("program_id = ?", programID), "courses").
+		Preload("Program").Preload("Creator").Find(&courses).Error
 	return courses, err
 }
 
@@ -3,35 +3,41 @@ package courses
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
 	"github.com/redukasquad/be-reduka/modules/classes/subjects"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 )
 
 func CourseIndexRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
-	courseRepo := NewRepository(migrations.GetDB())
-	courseService := NewService(courseRepo)
+	db := migrations.GetDB()
+	tutorService := tutors.NewService(tutors.NewRepository(db))
+	courseRepo := NewRepository(db)
+	courseService := NewService(courseRepo, waitlists.NewService(waitlists.NewRepository(db)), tutorService)
 	courseHandler := NewHandler(courseService)
 
 	courses := router.Group("/courses")
 	{
-		courses.GET("", courseHandler.GetAllCoursesHandler)
+		courses.GET("", middleware.OptionalAuth(), courseHandler.GetAllCoursesHandler)
 		courses.POST("", requireAuth, requireAdminOrTutor, courseHandler.CreateCourseHandler)
 	}
 
 	// Single group for /courses/:id to avoid Gin wildcard conflicts
 	courseByID := router.Group("/courses/:id")
 	{
-		courseByID.GET("", courseHandler.GetCourseByIDHandler)
-		courseByID.PUT("", requireAuth, requireAdmin, courseHandler.UpdateCourseHandler)
+		courseByID.GET("", middleware.OptionalAuth(), courseHandler.GetCourseByIDHandler)
+		courseByID.PUT("", requireAuth, requireAdminOrTutor, courseHandler.UpdateCourseHandler)
 		courseByID.DELETE("", requireAuth, requireAdmin, courseHandler.DeleteCourseHandler)
 
 		// Register /courses/:id/classes here to share the same wildcard group
-		subjectRepo := subjects.NewRepository(migrations.GetDB())
-		subjectSvc := subjects.NewService(subjectRepo)
+		subjectRepo := subjects.NewRepository(db)
+		subjectSvc := subjects.NewService(subjectRepo, tutorService, access.NewService(access.NewRepository(db)))
 		subjectHandler := subjects.NewHandler(subjectSvc)
 		subjects.RegisterCourseSubRoutes(courseByID, requireAuth, requireAdminOrTutor, subjectHandler)
 	}
 
-	// Tutor: lihat courses milik sendiri
+	// Tutor: lihat courses yang dibuat atau ditugaskan
 	router.GET("/tutor/my-courses", requireAuth, requireAdminOrTutor, courseHandler.GetMyCoursesTutorHandler)
 
 	router.GET("/programs/:id/courses", courseHandler.GetCoursesByProgramIDHandler)
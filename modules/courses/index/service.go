@@ -2,40 +2,49 @@ package courses
 
 import (
 	"errors"
+	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 	"github.com/redukasquad/be-reduka/modules/dto"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
 type courseService struct {
-	repo Repository
+	repo      Repository
+	waitlists waitlists.Service
+	tutors    tutors.Service
 }
 
 type Service interface {
-	GetAll(params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error)
-	GetByCreator(creatorID uint, params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error)
-	GetByID(id uint, requestID string) (*dto.CourseResponse, error)
+	// GetAll lists every course for admins and only live courses for everyone else
+	GetAll(params dto.ListQueryParams, requestID string, isAdmin bool) (*dto.PaginatedResponse[dto.CourseResponse], error)
+	GetByTutor(tutorID uint, params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error)
+	// GetByID hides unreleased courses and unpublished classes from viewers outside the course staff
+	GetByID(id uint, requestID string, userID uint, isAdmin bool) (*dto.CourseResponse, error)
 	GetByProgramID(programID uint, requestID string) ([]dto.CourseResponse, error)
 	Create(input CreateCourseInput, requestID string, userID uint) (*dto.CourseResponse, error)
-	Update(id uint, input UpdateCourseInput, requestID string, userID uint) (*dto.CourseResponse, error)
+	Update(id uint, input UpdateCourseInput, requestID string, userID uint, isAdmin bool) (*dto.CourseResponse, error)
 	Delete(id uint, requestID string, userID uint) error
 }
 
-func NewService(repo Repository) Service {
-	return &courseService{repo: repo}
+func NewService(repo Repository, waitlistService waitlists.Service, tutorService tutors.Service) Service {
+	return &courseService{repo: repo, waitlists: waitlistService, tutors: tutorService}
 }
 
-func (s *courseService) GetByCreator(creatorID uint, params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error) {
+// GetByTutor lists the courses a tutor created or is assigned to
+func (s *courseService) GetByTutor(tutorID uint, params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error) {
 	params.SetDefaults()
 
-	courses, err := s.repo.FindByCreatorPaginated(creatorID, params.GetOffset(), params.PerPage, params.Q)
+	courses, err := s.repo.FindByTutorPaginated(tutorID, params.GetOffset(), params.PerPage, params.Q)
 	if err != nil {
 		return nil, err
 	}
 
-	total, err := s.repo.CountByCreator(creatorID, params.Q)
+	total, err := s.repo.CountByTutor(tutorID, params.Q)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +58,7 @@ func (s *courseService) GetByCreator(creatorID uint, params dto.ListQueryParams,
 	return &result, nil
 }
 
-func (s *courseService) GetAll(params dto.ListQueryParams, requestID string) (*dto.PaginatedResponse[dto.CourseResponse], error) {
+func (s *courseService) GetAll(params dto.ListQueryParams, requestID string, isAdmin bool) (*dto.PaginatedResponse[dto.CourseResponse], error) {
 	params.SetDefaults()
 
 	utils.LogInfo("courses", "get_all", "Fetching courses with pagination", requestID, 0, map[string]any{
@@ -58,13 +67,15 @@ func (s *courseService) GetAll(params dto.ListQueryParams, requestID string) (*d
 		"search":  params.Q,
 	})
 
-	courses, err := s.repo.FindAllPaginated(params.GetOffset(), params.PerPage, params.Q)
+	liveOnly := !isAdmin
+
+	courses, err := s.repo.FindAllPaginated(params.GetOffset(), params.PerPage, params.Q, liveOnly)
 	if err != nil {
 		utils.LogError("courses", "get_all", "Failed to fetch courses: "+err.Error(), requestID, 0, nil)
 		return nil, err
 	}
 
-	totalCount, err := s.repo.CountWithSearch(params.Q)
+	totalCount, err := s.repo.CountWithSearch(params.Q, liveOnly)
 	if err != nil {
 		utils.LogError("courses", "get_all", "Failed to count courses:"+err.Error(), requestID, 0, nil)
 		return nil, err
@@ -85,8 +96,8 @@ func (s *courseService) GetAll(params dto.ListQueryParams, requestID string) (*d
 	return &response, nil
 }
 
-func (s *courseService) GetByID(id uint, requestID string) (*dto.CourseResponse, error) {
-	utils.LogInfo("courses", "get_by_id", "Fetching course by ID", requestID, 0, map[string]any{
+func (s *courseService) GetByID(id uint, requestID string, userID uint, isAdmin bool) (*dto.CourseResponse, error) {
+	utils.LogInfo("courses", "get_by_id", "Fetching course by ID", requestID, userID, map[string]any{
 		"course_id": id,
 	})
 
@@ -104,7 +115,25 @@ func (s *courseService) GetByID(id uint, requestID string) (*dto.CourseResponse,
 		return nil, err
 	}
 
-	utils.LogSuccess("courses", "get_by_id", "Successfully fetched course", requestID, 0, map[string]any{
+	// Staff preview the course as it stands; everyone else only sees what has gone out
+	if err := s.tutors.AuthorizeCourseView(id, userID, isAdmin); err != nil {
+		if !tutors.IsAuthorizationError(err) {
+			return nil, err
+		}
+		now := time.Now()
+		if !access.IsReleased(course.Status, course.PublishAt, now) {
+			return nil, errors.New("course not found")
+		}
+		classes := course.Classes[:0]
+		for _, class := range course.Classes {
+			if access.IsLive(class.Status, class.PublishAt, now) {
+				classes = append(classes, class)
+			}
+		}
+		course.Classes = classes
+	}
+
+	utils.LogSuccess("courses", "get_by_id", "Successfully fetched course", requestID, userID, map[string]any{
 		"course_id":   course.ID,
 		"course_name": course.NameCourse,
 	})
@@ -118,7 +147,7 @@ func (s *courseService) GetByProgramID(programID uint, requestID string) ([]dto.
 		"program_id": programID,
 	})
 
-	courses, err := s.repo.FindByProgramID(programID)
+	courses, err := s.repo.FindLiveByProgramID(programID)
 	if err != nil {
 		utils.LogError("courses", "get_by_program_id", "Failed to fetch courses: "+err.Error(), requestID, 0, map[string]any{
 			"program_id": programID,
@@ -160,9 +189,12 @@ func (s *courseService) Create(input CreateCourseInput, requestID string, userID
 		StartDate:         input.StartDate,
 		EndDate:           input.EndDate,
 		IsFree:            input.IsFree,
+		Price:             input.Price,
+		Capacity:          input.Capacity,
 		WhatsappGroupLink: input.WhatsappGroupLink,
 		Image:             input.Image,
 	}
+	access.ApplyPublishing(&course.Status, &course.PublishAt, input.Status, input.PublishAt)
 
 	if err := s.repo.Create(course); err != nil {
 		utils.LogError("courses", "create", "Failed to create course: "+err.Error(), requestID, userID, map[string]any{
@@ -186,11 +218,20 @@ func (s *courseService) Create(input CreateCourseInput, requestID string, userID
 	return &response, nil
 }
 
-func (s *courseService) Update(id uint, input UpdateCourseInput, requestID string, userID uint) (*dto.CourseResponse, error) {
+func (s *courseService) Update(id uint, input UpdateCourseInput, requestID string, userID uint, isAdmin bool) (*dto.CourseResponse, error) {
 	utils.LogInfo("courses", "update", "Attempting to update course", requestID, userID, map[string]any{
 		"course_id": id,
 	})
 
+	if err := s.tutors.AuthorizeCourse(id, userID, isAdmin); err != nil {
+		if err.Error() != "course not found" {
+			utils.LogWarning("courses", "update", "Course update refused: "+err.Error(), requestID, userID, map[string]any{
+				"course_id": id,
+			})
+		}
+		return nil, err
+	}
+
 	course, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -232,12 +273,20 @@ func (s *courseService) Update(id uint, input UpdateCourseInput, requestID strin
 	if input.IsFree != nil {
 		course.IsFree = *input.IsFree
 	}
+	if input.Price != nil {
+		course.Price = *input.Price
+	}
+	previousCapacity := course.Capacity
+	if input.Capacity != nil {
+		course.Capacity = *input.Capacity
+	}
 	if input.WhatsappGroupLink != nil {
 		course.WhatsappGroupLink = *input.WhatsappGroupLink
 	}
 	if input.Image != nil {
 		course.Image = *input.Image
 	}
+	access.ApplyPublishing(&course.Status, &course.PublishAt, input.Status, input.PublishAt)
 	if err := s.repo.Update(&course); err != nil {
 		utils.LogError("courses", "update", "Failed to update course: "+err.Error(), requestID, userID, map[string]any{
 			"course_id": id,
@@ -256,6 +305,15 @@ func (s *courseService) Update(id uint, input UpdateCourseInput, requestID strin
 		"course_name": course.NameCourse,
 	})
 
+	// More seats (or no limit any more) lets the waitlist move up
+	if course.Capacity != previousCapacity && (course.Capacity == 0 || course.Capacity > previousCapacity) {
+		if _, err := s.waitlists.PromoteCourse(course.ID, requestID); err != nil {
+			utils.LogError("courses", "update", "Failed to promote waitlist: "+err.Error(), requestID, userID, map[string]any{
+				"course_id": course.ID,
+			})
+		}
+	}
+
 	response := dto.ToCourseResponse(updatedCourse)
 	return &response, nil
 }
@@ -1,15 +1,58 @@
 package questions
 
+// ConditionInput shows the question only when the earlier question's answer matches
+type ConditionInput struct {
+	DependsOnQuestionID uint   `json:"dependsOnQuestionId" binding:"required"`
+	Operator            string `json:"operator" binding:"required,oneof=equals not_equals answered not_answered"`
+	Value               string `json:"value" binding:"max=255"` // equals and not_equals only
+}
+
+type ConditionResponse struct {
+	DependsOnQuestionID uint   `json:"dependsOnQuestionId"`
+	Operator            string `json:"operator"`
+	Value               string `json:"value,omitempty"`
+}
+
 type CreateQuestionInput struct {
 	QuestionText  string `json:"questionText" binding:"required"`
-	QuestionType  string `json:"questionType" binding:"required,oneof=text select radio file"`
+	QuestionType  string `json:"questionType" binding:"required,oneof=text number select radio file"`
 	QuestionOrder int    `json:"questionOrder" binding:"required,min=1"`
+
+	IsRequired        bool     `json:"isRequired"`
+	Options           []string `json:"options"` // select and radio only
+	ValidationPattern string   `json:"validationPattern" binding:"max=255"`
+	PatternMessage    string   `json:"patternMessage" binding:"max=255"`
+	MinLength         *int     `json:"minLength" binding:"omitempty,gte=0"`
+	MaxLength         *int     `json:"maxLength" binding:"omitempty,gte=1"`
+	MinValue          *float64 `json:"minValue"`
+	MaxValue          *float64 `json:"maxValue"`
+
+	ShowWhen   string           `json:"showWhen" binding:"omitempty,oneof=all any"`
+	Conditions []ConditionInput `json:"conditions" binding:"omitempty,dive"`
 }
 
+// UpdateQuestionInput changes only the fields sent; Options and Conditions replace the whole list
 type UpdateQuestionInput struct {
 	QuestionText  *string `json:"questionText"`
-	QuestionType  *string `json:"questionType"`
-	QuestionOrder *int    `json:"questionOrder"`
+	QuestionType  *string `json:"questionType" binding:"omitempty,oneof=text number select radio file"`
+	QuestionOrder *int    `json:"questionOrder" binding:"omitempty,min=1"`
+
+	IsRequired        *bool     `json:"isRequired"`
+	Options           *[]string `json:"options"`
+	ValidationPattern *string   `json:"validationPattern" binding:"omitempty,max=255"`
+	PatternMessage    *string   `json:"patternMessage" binding:"omitempty,max=255"`
+	MinLength         *int      `json:"minLength" binding:"omitempty,gte=0"` // 0 removes the limit
+	MaxLength         *int      `json:"maxLength" binding:"omitempty,gte=0"` // 0 removes the limit
+	MinValue          *float64  `json:"minValue"`
+	MaxValue          *float64  `json:"maxValue"`
+
+	ShowWhen   *string           `json:"showWhen" binding:"omitempty,oneof=all any"`
+	Conditions *[]ConditionInput `json:"conditions" binding:"omitempty,dive"`
+}
+
+// ReorderQuestionsInput lists every registration question of the course in its new order
+type ReorderQuestionsInput struct {
+	QuestionIDs []uint `json:"questionIds" binding:"required,min=1"`
 }
 
 type QuestionResponse struct {
@@ -18,4 +61,16 @@ type QuestionResponse struct {
 	QuestionText  string `json:"questionText"`
 	QuestionType  string `json:"questionType"`
 	QuestionOrder int    `json:"questionOrder"`
+
+	IsRequired        bool     `json:"isRequired"`
+	Options           []string `json:"options,omitempty"`
+	ValidationPattern string   `json:"validationPattern,omitempty"`
+	PatternMessage    string   `json:"patternMessage,omitempty"`
+	MinLength         *int     `json:"minLength,omitempty"`
+	MaxLength         *int     `json:"maxLength,omitempty"`
+	MinValue          *float64 `json:"minValue,omitempty"`
+	MaxValue          *float64 `json:"maxValue,omitempty"`
+
+	ShowWhen   string              `json:"showWhen"`
+	Conditions []ConditionResponse `json:"conditions,omitempty"`
 }
@@ -18,6 +18,7 @@ type Handler interface {
 	CreateQuestionHandler(c *gin.Context)
 	UpdateQuestionHandler(c *gin.Context)
 	DeleteQuestionHandler(c *gin.Context)
+	ReorderQuestionsHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -46,6 +47,22 @@ func getUserID(c *gin.Context) uint {
 	return 0
 }
 
+// isDefinitionError reports whether err came from validating the question's options and rules
+func isDefinitionError(err error) bool {
+	switch err.Error() {
+	case "select and radio questions need at least one option", "options are only allowed for select and radio questions",
+		"options must be between 1 and 255 characters", "options must be unique",
+		"pattern and length rules only apply to text questions", "validation pattern is not a valid regular expression",
+		"minLength cannot be greater than maxLength", "min and max values only apply to number questions",
+		"minValue cannot be greater than maxValue",
+		"conditions can only refer to other questions of the same course", "conditions can only depend on earlier questions",
+		"equals and not_equals conditions need a value", "condition value must be one of the referenced question's options",
+		"questions that depend on this one must come after it", "questions that depend on this one use an answer it no longer offers":
+		return true
+	}
+	return false
+}
+
 func (h *handler) GetQuestionsByCourseHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	courseIDStr := c.Param("id")
@@ -84,6 +101,14 @@ func (h *handler) CreateQuestionHandler(c *gin.Context) {
 
 	question, err := h.service.Create(uint(courseID), input, requestID, userID)
 	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		if isDefinitionError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid question", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create question", err.Error(), nil))
 		return
 	}
@@ -114,6 +139,10 @@ func (h *handler) UpdateQuestionHandler(c *gin.Context) {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Question not found", err.Error(), nil))
 			return
 		}
+		if isDefinitionError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid question", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update question", err.Error(), nil))
 		return
 	}
@@ -137,9 +166,47 @@ func (h *handler) DeleteQuestionHandler(c *gin.Context) {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Question not found", err.Error(), nil))
 			return
 		}
+		if err.Error() == "question is used by the display conditions of other questions" {
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Question in use", err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete question", err.Error(), nil))
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Question deleted successfully", nil))
 }
+
+func (h *handler) ReorderQuestionsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	courseIDStr := c.Param("id")
+
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Course ID", "Course ID must be a valid number", nil))
+		return
+	}
+
+	var input ReorderQuestionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	questions, err := h.service.Reorder(uint(courseID), input, requestID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "course not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+		case "question list must contain every question of the course exactly once",
+			"questions must come after the questions their conditions depend on":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid order", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reorder questions", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Questions reordered successfully", questions))
+}
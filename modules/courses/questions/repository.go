@@ -1,8 +1,12 @@
 package questions
 
 import (
+	"errors"
+
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/ordering"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -12,35 +16,166 @@ type repository struct {
 type Repository interface {
 	FindByCourseID(courseID uint) ([]entities.RegistrationQuestion, error)
 	FindByID(id uint) (entities.RegistrationQuestion, error)
+	// Create, Update and Delete keep the course questions numbered 1..n: the question takes the
+	// position in QuestionOrder (clamped to the end) and its siblings shift around it
 	Create(question *entities.RegistrationQuestion) error
+	// Update saves the question and replaces its options and conditions in one transaction
 	Update(question *entities.RegistrationQuestion) error
 	Delete(id uint) error
+	// Reorder renumbers the course questions in the given order; ids must list every question once
+	Reorder(courseID uint, ids []uint) error
+	CountDependents(id uint) (int64, error)
 }
 
 func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+func preloadOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("option_order ASC, id ASC")
+}
+
 func (r *repository) FindByCourseID(courseID uint) ([]entities.RegistrationQuestion, error) {
 	var questions []entities.RegistrationQuestion
-	err := r.db.Where("course_id = ?", courseID).Order("question_order ASC").Find(&questions).Error
+	err := r.db.Where("course_id = ?", courseID).Preload("Options", preloadOptions).Preload("Conditions").Order("question_order ASC, id ASC").Find(&questions).Error
 	return questions, err
 }
 
 func (r *repository) FindByID(id uint) (entities.RegistrationQuestion, error) {
 	var question entities.RegistrationQuestion
-	err := r.db.First(&question, id).Error
+	err := r.db.Preload("Options", preloadOptions).Preload("Conditions").First(&question, id).Error
 	return question, err
 }
 
 func (r *repository) Create(question *entities.RegistrationQuestion) error {
-	return r.db.Create(question).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, question.CourseID)
+		if err != nil {
+			return err
+		}
+		position := question.QuestionOrder
+
+		// Append first so the insert cannot collide with the unique order index
+		question.QuestionOrder = ordering.Next(siblings)
+		if err := tx.Create(question).Error; err != nil {
+			return err
+		}
+
+		ordered := ordering.InsertAt(siblings, ordering.Slot{ID: question.ID, Position: question.QuestionOrder}, position)
+		if err := questionList(question.CourseID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		question.QuestionOrder = ordering.PositionOf(ordered, question.ID)
+		return nil
+	})
 }
 
 func (r *repository) Update(question *entities.RegistrationQuestion) error {
-	return r.db.Save(question).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, question.CourseID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("QuestionOrder", "Course", "Options", "Conditions", "Answers").Save(question).Error; err != nil {
+			return err
+		}
+		ordered := ordering.Move(siblings, question.ID, question.QuestionOrder)
+		if err := questionList(question.CourseID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		question.QuestionOrder = ordering.PositionOf(ordered, question.ID)
+
+		if err := tx.Where("question_id = ?", question.ID).Delete(&entities.RegistrationQuestionCondition{}).Error; err != nil {
+			return err
+		}
+		for i := range question.Conditions {
+			question.Conditions[i].ID = 0
+			question.Conditions[i].QuestionID = question.ID
+		}
+		if len(question.Conditions) > 0 {
+			if err := tx.Create(&question.Conditions).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("question_id = ?", question.ID).Delete(&entities.RegistrationQuestionOption{}).Error; err != nil {
+			return err
+		}
+		for i := range question.Options {
+			question.Options[i].ID = 0
+			question.Options[i].QuestionID = question.ID
+		}
+		if len(question.Options) == 0 {
+			return nil
+		}
+		return tx.Create(&question.Options).Error
+	})
 }
 
 func (r *repository) Delete(id uint) error {
-	return r.db.Delete(&entities.RegistrationQuestion{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var question entities.RegistrationQuestion
+		if err := tx.Select("id", "course_id").First(&question, id).Error; err != nil {
+			return err
+		}
+		if _, err := lockSiblings(tx, question.CourseID); err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&entities.RegistrationQuestionOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&entities.RegistrationQuestionCondition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.RegistrationQuestion{}, id).Error; err != nil {
+			return err
+		}
+
+		// Close the gap the question leaves behind
+		list := questionList(question.CourseID)
+		remaining, err := list.Load(tx)
+		if err != nil {
+			return err
+		}
+		return list.Resequence(tx, remaining)
+	})
+}
+
+func (r *repository) Reorder(courseID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, courseID)
+		if err != nil {
+			return err
+		}
+
+		ordered, ok := ordering.Arrange(siblings, ids)
+		if !ok {
+			return errors.New("question list must contain every question of the course exactly once")
+		}
+		return questionList(courseID).Resequence(tx, ordered)
+	})
+}
+
+// CountDependents counts the conditions of other questions that refer to this one
+func (r *repository) CountDependents(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.RegistrationQuestionCondition{}).Where("depends_on_question_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// questionList is the ordered list of registration questions of a course
+func questionList(courseID uint) ordering.List {
+	return ordering.List{Model: &entities.RegistrationQuestion{}, Column: "question_order", Scope: "course_id = ?", Args: []any{courseID}}
+}
+
+// lockSiblings locks the course row, so concurrent changes to its questions run one after
+// another, and returns the questions in their current order
+func lockSiblings(tx *gorm.DB, courseID uint) ([]ordering.Slot, error) {
+	var course entities.Course
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+	return questionList(courseID).Load(tx)
 }
@@ -14,6 +14,7 @@ func QuestionRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requir
 	{
 		courseQuestions.GET("/questions", questionHandler.GetQuestionsByCourseHandler)
 		courseQuestions.POST("/questions", requireAuth, requireAdmin, questionHandler.CreateQuestionHandler)
+		courseQuestions.PUT("/questions/order", requireAuth, requireAdmin, questionHandler.ReorderQuestionsHandler)
 	}
 
 	questions := router.Group("/questions")
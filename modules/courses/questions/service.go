@@ -2,6 +2,8 @@ package questions
 
 import (
 	"errors"
+	"regexp"
+	"strings"
 
 	"github.com/redukasquad/be-reduka/database/entities"
 	"github.com/redukasquad/be-reduka/packages/utils"
@@ -17,6 +19,7 @@ type Service interface {
 	Create(courseID uint, input CreateQuestionInput, requestID string, userID uint) (*QuestionResponse, error)
 	Update(id uint, input UpdateQuestionInput, requestID string, userID uint) (*QuestionResponse, error)
 	Delete(id uint, requestID string, userID uint) error
+	Reorder(courseID uint, input ReorderQuestionsInput, requestID string, userID uint) ([]QuestionResponse, error)
 }
 
 func NewService(repo Repository) Service {
@@ -53,10 +56,27 @@ func (s *questionService) Create(courseID uint, input CreateQuestionInput, reque
 	})
 
 	question := &entities.RegistrationQuestion{
-		CourseID:      courseID,
-		QuestionText:  input.QuestionText,
-		QuestionType:  input.QuestionType,
-		QuestionOrder: input.QuestionOrder,
+		CourseID:          courseID,
+		QuestionText:      input.QuestionText,
+		QuestionType:      input.QuestionType,
+		QuestionOrder:     input.QuestionOrder,
+		IsRequired:        input.IsRequired,
+		ValidationPattern: input.ValidationPattern,
+		PatternMessage:    input.PatternMessage,
+		MinLength:         input.MinLength,
+		MaxLength:         input.MaxLength,
+		MinValue:          input.MinValue,
+		MaxValue:          input.MaxValue,
+		Options:           toOptions(input.Options),
+		ShowWhen:          input.ShowWhen,
+		Conditions:        toConditions(input.Conditions),
+	}
+
+	if err := validateDefinition(question); err != nil {
+		return nil, err
+	}
+	if err := s.validateConditions(question); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(question); err != nil {
@@ -91,12 +111,62 @@ func (s *questionService) Update(id uint, input UpdateQuestionInput, requestID s
 	if input.QuestionText != nil {
 		question.QuestionText = *input.QuestionText
 	}
-	if input.QuestionType != nil {
+	if input.QuestionType != nil && *input.QuestionType != question.QuestionType {
+		// Rules of the old type would not fit the new one
 		question.QuestionType = *input.QuestionType
+		question.Options = nil
+		question.ValidationPattern = ""
+		question.PatternMessage = ""
+		question.MinLength, question.MaxLength = nil, nil
+		question.MinValue, question.MaxValue = nil, nil
 	}
 	if input.QuestionOrder != nil {
 		question.QuestionOrder = *input.QuestionOrder
 	}
+	if input.IsRequired != nil {
+		question.IsRequired = *input.IsRequired
+	}
+	if input.Options != nil {
+		question.Options = toOptions(*input.Options)
+	}
+	if input.ValidationPattern != nil {
+		question.ValidationPattern = *input.ValidationPattern
+	}
+	if input.PatternMessage != nil {
+		question.PatternMessage = *input.PatternMessage
+	}
+	// A length of 0 removes the limit
+	if input.MinLength != nil {
+		question.MinLength = input.MinLength
+		if *input.MinLength == 0 {
+			question.MinLength = nil
+		}
+	}
+	if input.MaxLength != nil {
+		question.MaxLength = input.MaxLength
+		if *input.MaxLength == 0 {
+			question.MaxLength = nil
+		}
+	}
+	if input.MinValue != nil {
+		question.MinValue = input.MinValue
+	}
+	if input.MaxValue != nil {
+		question.MaxValue = input.MaxValue
+	}
+	if input.ShowWhen != nil {
+		question.ShowWhen = *input.ShowWhen
+	}
+	if input.Conditions != nil {
+		question.Conditions = toConditions(*input.Conditions)
+	}
+
+	if err := validateDefinition(&question); err != nil {
+		return nil, err
+	}
+	if err := s.validateConditions(&question); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Update(&question); err != nil {
 		utils.LogError("questions", "update", "Failed to update question: "+err.Error(), requestID, userID, nil)
@@ -127,6 +197,18 @@ func (s *questionService) Delete(id uint, requestID string, userID uint) error {
 		return err
 	}
 
+	dependents, err := s.repo.CountDependents(id)
+	if err != nil {
+		return err
+	}
+	if dependents > 0 {
+		utils.LogWarning("questions", "delete", "Question is used by display conditions", requestID, userID, map[string]any{
+			"question_id": id,
+			"dependents":  dependents,
+		})
+		return errors.New("question is used by the display conditions of other questions")
+	}
+
 	if err := s.repo.Delete(id); err != nil {
 		utils.LogError("questions", "delete", "Failed to delete question: "+err.Error(), requestID, userID, nil)
 		return err
@@ -138,12 +220,241 @@ func (s *questionService) Delete(id uint, requestID string, userID uint) error {
 	return nil
 }
 
+func (s *questionService) Reorder(courseID uint, input ReorderQuestionsInput, requestID string, userID uint) ([]QuestionResponse, error) {
+	utils.LogInfo("questions", "reorder", "Reordering questions", requestID, userID, map[string]any{
+		"course_id": courseID,
+		"count":     len(input.QuestionIDs),
+	})
+
+	questions, err := s.repo.FindByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[uint]int, len(input.QuestionIDs))
+	for i, id := range input.QuestionIDs {
+		positions[id] = i + 1
+	}
+	for _, q := range questions {
+		for _, condition := range q.Conditions {
+			if positions[condition.DependsOnQuestionID] >= positions[q.ID] {
+				utils.LogWarning("questions", "reorder", "Order breaks display conditions", requestID, userID, map[string]any{
+					"question_id": q.ID,
+				})
+				return nil, errors.New("questions must come after the questions their conditions depend on")
+			}
+		}
+	}
+
+	if err := s.repo.Reorder(courseID, input.QuestionIDs); err != nil {
+		if err.Error() != "question list must contain every question of the course exactly once" && err.Error() != "course not found" {
+			utils.LogError("questions", "reorder", "Failed to reorder questions: "+err.Error(), requestID, userID, nil)
+		}
+		return nil, err
+	}
+
+	reordered, err := s.repo.FindByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]QuestionResponse, 0, len(reordered))
+	for _, q := range reordered {
+		responses = append(responses, s.toQuestionResponse(q))
+	}
+
+	utils.LogSuccess("questions", "reorder", "Questions reordered successfully", requestID, userID, map[string]any{
+		"course_id": courseID,
+	})
+	return responses, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// toOptions turns the submitted option labels into option rows, keeping their order
+func toOptions(labels []string) []entities.RegistrationQuestionOption {
+	options := make([]entities.RegistrationQuestionOption, 0, len(labels))
+	for i, label := range labels {
+		options = append(options, entities.RegistrationQuestionOption{
+			OptionText:  strings.TrimSpace(label),
+			OptionOrder: i + 1,
+		})
+	}
+	return options
+}
+
+// toConditions turns the submitted display conditions into condition rows
+func toConditions(inputs []ConditionInput) []entities.RegistrationQuestionCondition {
+	conditions := make([]entities.RegistrationQuestionCondition, 0, len(inputs))
+	for _, input := range inputs {
+		condition := entities.RegistrationQuestionCondition{
+			DependsOnQuestionID: input.DependsOnQuestionID,
+			Operator:            input.Operator,
+		}
+		if input.Operator == entities.ConditionEquals || input.Operator == entities.ConditionNotEquals {
+			condition.Value = strings.TrimSpace(input.Value)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// validateConditions checks the question's display conditions only refer to earlier questions of
+// the same course, and that questions depending on this one still come after it and still match.
+// Positions are compared as they will be once the question takes its place and siblings shift.
+func (s *questionService) validateConditions(q *entities.RegistrationQuestion) error {
+	siblings, err := s.repo.FindByCourseID(q.CourseID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]entities.RegistrationQuestion, len(siblings))
+	var others []uint
+	for _, sibling := range siblings {
+		if sibling.ID != q.ID {
+			byID[sibling.ID] = sibling
+			others = append(others, sibling.ID)
+		}
+	}
+
+	index := q.QuestionOrder - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(others) {
+		index = len(others)
+	}
+	positions := make(map[uint]int, len(others)+1)
+	for i, id := range others {
+		if i < index {
+			positions[id] = i + 1
+		} else {
+			positions[id] = i + 2
+		}
+	}
+	position := index + 1
+
+	for _, condition := range q.Conditions {
+		target, ok := byID[condition.DependsOnQuestionID]
+		if !ok {
+			return errors.New("conditions can only refer to other questions of the same course")
+		}
+		if positions[target.ID] >= position {
+			return errors.New("conditions can only depend on earlier questions")
+		}
+		if err := checkConditionValue(condition, target); err != nil {
+			return err
+		}
+	}
+
+	if q.ID == 0 {
+		return nil
+	}
+	for _, sibling := range byID {
+		for _, condition := range sibling.Conditions {
+			if condition.DependsOnQuestionID != q.ID {
+				continue
+			}
+			if positions[sibling.ID] <= position {
+				return errors.New("questions that depend on this one must come after it")
+			}
+			if err := checkConditionValue(condition, *q); err != nil {
+				return errors.New("questions that depend on this one use an answer it no longer offers")
+			}
+		}
+	}
+	return nil
+}
+
+// checkConditionValue checks a compared value is present and, for choice questions, is one of the options
+func checkConditionValue(condition entities.RegistrationQuestionCondition, target entities.RegistrationQuestion) error {
+	if condition.Operator != entities.ConditionEquals && condition.Operator != entities.ConditionNotEquals {
+		return nil
+	}
+	if condition.Value == "" {
+		return errors.New("equals and not_equals conditions need a value")
+	}
+	if target.QuestionType != "select" && target.QuestionType != "radio" {
+		return nil
+	}
+	for _, option := range target.Options {
+		if option.OptionText == condition.Value {
+			return nil
+		}
+	}
+	return errors.New("condition value must be one of the referenced question's options")
+}
+
+// validateDefinition checks the question's options and rules fit its type
+func validateDefinition(q *entities.RegistrationQuestion) error {
+	choice := q.QuestionType == "select" || q.QuestionType == "radio"
+	if choice && len(q.Options) == 0 {
+		return errors.New("select and radio questions need at least one option")
+	}
+	if !choice && len(q.Options) > 0 {
+		return errors.New("options are only allowed for select and radio questions")
+	}
+	seen := make(map[string]bool, len(q.Options))
+	for _, option := range q.Options {
+		if option.OptionText == "" || len(option.OptionText) > 255 {
+			return errors.New("options must be between 1 and 255 characters")
+		}
+		if seen[option.OptionText] {
+			return errors.New("options must be unique")
+		}
+		seen[option.OptionText] = true
+	}
+
+	hasTextRules := q.ValidationPattern != "" || q.MinLength != nil || q.MaxLength != nil
+	if hasTextRules && q.QuestionType != "text" {
+		return errors.New("pattern and length rules only apply to text questions")
+	}
+	if q.ValidationPattern != "" {
+		// Compiled in the anchored form registrations match answers with
+		if _, err := regexp.Compile("^(?:" + q.ValidationPattern + ")$"); err != nil {
+			return errors.New("validation pattern is not a valid regular expression")
+		}
+	}
+	if q.MinLength != nil && q.MaxLength != nil && *q.MinLength > *q.MaxLength {
+		return errors.New("minLength cannot be greater than maxLength")
+	}
+
+	if (q.MinValue != nil || q.MaxValue != nil) && q.QuestionType != "number" {
+		return errors.New("min and max values only apply to number questions")
+	}
+	if q.MinValue != nil && q.MaxValue != nil && *q.MinValue > *q.MaxValue {
+		return errors.New("minValue cannot be greater than maxValue")
+	}
+	return nil
+}
+
 func (s *questionService) toQuestionResponse(q entities.RegistrationQuestion) QuestionResponse {
-	return QuestionResponse{
-		ID:            q.ID,
-		CourseID:      q.CourseID,
-		QuestionText:  q.QuestionText,
-		QuestionType:  q.QuestionType,
-		QuestionOrder: q.QuestionOrder,
+	response := QuestionResponse{
+		ID:                q.ID,
+		CourseID:          q.CourseID,
+		QuestionText:      q.QuestionText,
+		QuestionType:      q.QuestionType,
+		QuestionOrder:     q.QuestionOrder,
+		IsRequired:        q.IsRequired,
+		ValidationPattern: q.ValidationPattern,
+		PatternMessage:    q.PatternMessage,
+		MinLength:         q.MinLength,
+		MaxLength:         q.MaxLength,
+		MinValue:          q.MinValue,
+		MaxValue:          q.MaxValue,
+		ShowWhen:          q.ShowWhen,
+	}
+	if response.ShowWhen == "" {
+		response.ShowWhen = "all"
+	}
+	for _, option := range q.Options {
+		response.Options = append(response.Options, option.OptionText)
+	}
+	for _, condition := range q.Conditions {
+		response.Conditions = append(response.Conditions, ConditionResponse{
+			DependsOnQuestionID: condition.DependsOnQuestionID,
+			Operator:            condition.Operator,
+			Value:               condition.Value,
+		})
 	}
+	return response
 }
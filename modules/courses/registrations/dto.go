@@ -1,12 +1,30 @@
 package registrations
 
+import "github.com/xuri/excelize/v2"
+
 type RegisterCourseInput struct {
-	Answers []AnswerInput `json:"answers"`
+	Answers   []AnswerInput `json:"answers"`
+	PromoCode string        `json:"promoCode"`
 }
 
+// AnswerInput is one answer; AnswerText may be blank for optional questions
 type AnswerInput struct {
 	QuestionID uint   `json:"questionId" binding:"required"`
-	AnswerText string `json:"answerText" binding:"required"`
+	AnswerText string `json:"answerText"`
+}
+
+// AnswerError explains why one answer was refused
+type AnswerError struct {
+	QuestionID   uint   `json:"questionId"`
+	QuestionText string `json:"questionText,omitempty"`
+	Message      string `json:"message"`
+}
+
+// AnswerErrors lists every refused answer of a registration
+type AnswerErrors []AnswerError
+
+func (e AnswerErrors) Error() string {
+	return "invalid answers"
 }
 
 type UpdateRegistrationStatusInput struct {
@@ -18,12 +36,17 @@ type RegistrationResponse struct {
 	UserID            uint             `json:"userId"`
 	CourseID          uint             `json:"courseId"`
 	Status            string           `json:"status"`
+	OriginalPrice     float64          `json:"originalPrice"`
+	DiscountAmount    float64          `json:"discountAmount"`
+	FinalAmount       float64          `json:"finalAmount"`
+	PromoCode         string           `json:"promoCode,omitempty"`
 	CourseName        string           `json:"courseName,omitempty"`
 	ProgramName       string           `json:"programName,omitempty"`
 	WhatsappGroupLink string           `json:"whatsappGroupLink,omitempty"`
 	UserName          string           `json:"userName,omitempty"`
 	UserEmail         string           `json:"userEmail,omitempty"`
 	Answers           []AnswerResponse `json:"answers,omitempty"`
+	Progress          *float64         `json:"progress,omitempty"` // percent of lessons completed, approved registrations only
 	CreatedAt         string           `json:"createdAt"`
 }
 
@@ -37,3 +60,34 @@ type RegistrationListResponse struct {
 	Registrations []RegistrationResponse `json:"registrations"`
 	TotalCount    int64                  `json:"totalCount"`
 }
+
+// ExportRegistrationsFilter narrows the registrations export to one status
+type ExportRegistrationsFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected waitlisted"`
+}
+
+// RegistrationsExport is a generated workbook ready to be written to the client
+type RegistrationsExport struct {
+	FileName string
+	File     *excelize.File
+}
+
+// ImportDecisionResult is the outcome for one row of a decisions import
+type ImportDecisionResult struct {
+	Row            int    `json:"row"`
+	RegistrationID uint   `json:"registrationId"`
+	Decision       string `json:"decision"`
+	Success        bool   `json:"success"`
+	Skipped        bool   `json:"skipped,omitempty"` // the registration already had this status
+	Status         string `json:"status,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ImportDecisionsResponse summarises a decisions import
+type ImportDecisionsResponse struct {
+	Total   int                    `json:"total"`
+	Applied int                    `json:"applied"`
+	Skipped int                    `json:"skipped"`
+	Failed  int                    `json:"failed"`
+	Results []ImportDecisionResult `json:"results"`
+}
@@ -0,0 +1,307 @@
+package registrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many registrations are loaded per query while streaming the export
+const exportBatchSize = 500
+
+// maxDecisionRows caps how many rows one decisions import may contain
+const maxDecisionRows = 5000
+
+const (
+	registrationIDHeader = "Registration ID"
+	decisionHeader       = "Decision"
+)
+
+var nonFileNameChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ==========================================
+// Registrations Export
+// ==========================================
+
+func (s *registrationService) ExportRegistrations(courseID uint, filter ExportRegistrationsFilter, requestID string) (*RegistrationsExport, error) {
+	utils.LogInfo("registrations", "export", "Exporting course registrations", requestID, 0, map[string]any{
+		"course_id": courseID,
+		"status":    filter.Status,
+	})
+
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	questions, err := s.repo.FindQuestionsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	sheet := "Registrations"
+	f.SetSheetName(f.GetSheetName(0), sheet)
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header := []any{registrationIDHeader, "Name", "Email", "Phone", "School", "Kelas", "Status", "Final Amount", "Promo Code", "Registered At"}
+	for _, q := range questions {
+		header = append(header, q.QuestionText)
+	}
+	// Left blank for the reviewer to fill with approve or reject before importing the sheet back
+	header = append(header, decisionHeader)
+	if err := sw.SetRow("A1", header); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	rowNum := 1
+	err = s.repo.FindForExportInBatches(courseID, filter, exportBatchSize, func(batch []entities.CourseRegistration) error {
+		for _, reg := range batch {
+			rowNum++
+
+			kelas := ""
+			if reg.User.Kelas != nil {
+				kelas = *reg.User.Kelas
+			}
+
+			row := []any{
+				reg.ID,
+				reg.User.Username,
+				reg.User.Email,
+				reg.User.NoTelp,
+				reg.User.School,
+				kelas,
+				reg.Status,
+				reg.FinalAmount,
+				reg.PromoCode,
+				reg.CreatedAt.Format("2006-01-02 15:04"),
+			}
+
+			answers := make(map[uint]string, len(reg.Answers))
+			for _, answer := range reg.Answers {
+				answers[answer.QuestionID] = answer.AnswerText
+			}
+			for _, q := range questions {
+				row = append(row, answers[q.ID])
+			}
+			row = append(row, nil)
+
+			cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+			if err := sw.SetRow(cell, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError("registrations", "export", "Failed to export registrations: "+err.Error(), requestID, 0, nil)
+		f.Close()
+		return nil, err
+	}
+
+	if err := sw.Flush(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	utils.LogSuccess("registrations", "export", "Course registrations exported", requestID, 0, map[string]any{
+		"course_id": courseID,
+		"rows":      rowNum - 1,
+	})
+
+	name := strings.Trim(nonFileNameChars.ReplaceAllString(course.NameCourse, "-"), "-")
+	return &RegistrationsExport{
+		FileName: fmt.Sprintf("course-%d-%s-registrations.xlsx", course.ID, strings.ToLower(name)),
+		File:     f,
+	}, nil
+}
+
+// ==========================================
+// Decisions Import
+// ==========================================
+
+// ImportDecisions applies the approve/reject decisions filled into an exported sheet.
+// Each decision goes through the regular approve and reject flows, so receipts and
+// waitlist promotion happen as they would for a single review.
+func (s *registrationService) ImportDecisions(courseID uint, fileName string, r io.Reader, adminUserID uint, requestID string) (*ImportDecisionsResponse, error) {
+	utils.LogInfo("registrations", "import_decisions", "Importing registration decisions", requestID, adminUserID, map[string]any{
+		"course_id": courseID,
+		"file_name": fileName,
+	})
+
+	if _, err := s.repo.FindCourseByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	rows, err := parseDecisions(fileName, r)
+	if err != nil {
+		utils.LogWarning("registrations", "import_decisions", "Decisions file rejected: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	response := &ImportDecisionsResponse{Results: make([]ImportDecisionResult, 0, len(rows))}
+	for _, row := range rows {
+		result := s.applyDecision(courseID, row, adminUserID, requestID)
+		switch {
+		case result.Skipped:
+			response.Skipped++
+		case result.Success:
+			response.Applied++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Total = len(rows)
+
+	utils.LogSuccess("registrations", "import_decisions", "Registration decisions imported", requestID, adminUserID, map[string]any{
+		"course_id": courseID,
+		"applied":   response.Applied,
+		"skipped":   response.Skipped,
+		"failed":    response.Failed,
+	})
+	return response, nil
+}
+
+func (s *registrationService) applyDecision(courseID uint, row decisionRow, adminUserID uint, requestID string) ImportDecisionResult {
+	result := ImportDecisionResult{Row: row.Row, RegistrationID: row.RegistrationID, Decision: row.Decision}
+	if row.Err != "" {
+		result.Error = row.Err
+		return result
+	}
+
+	registration, err := s.repo.FindByID(row.RegistrationID)
+	if err != nil || registration.CourseID != courseID {
+		result.Error = "registration not found in this course"
+		return result
+	}
+
+	target := "approved"
+	if row.Decision == "reject" {
+		target = "rejected"
+	}
+	if registration.Status == target {
+		result.Skipped = true
+		result.Status = registration.Status
+		return result
+	}
+
+	var updated *RegistrationResponse
+	if row.Decision == "approve" {
+		updated, err = s.ApproveRegistration(row.RegistrationID, requestID, adminUserID)
+	} else {
+		updated, err = s.RejectRegistration(row.RegistrationID, requestID, adminUserID)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Status = registration.Status
+		return result
+	}
+	result.Success = true
+	result.Status = updated.Status
+	return result
+}
+
+// decisionRow is one filled Decision cell of the imported sheet
+type decisionRow struct {
+	Row            int // 1-based row number in the sheet, header included
+	RegistrationID uint
+	Decision       string // approve or reject
+	Err            string
+}
+
+// parseDecisions reads the rows of an exported .xlsx sheet that have a Decision filled in
+func parseDecisions(fileName string, r io.Reader) ([]decisionRow, error) {
+	if strings.ToLower(filepath.Ext(fileName)) != ".xlsx" {
+		return nil, errors.New("decisions must be an .xlsx file")
+	}
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, errors.New("decisions file could not be read")
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("decisions file is empty")
+	}
+	records, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, errors.New("decisions file could not be read")
+	}
+	if len(records) < 2 {
+		return nil, errors.New("decisions file is empty")
+	}
+	if len(records)-1 > maxDecisionRows {
+		return nil, errors.New("decisions file has too many rows")
+	}
+
+	idCol, decisionCol := -1, -1
+	for i, header := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(header)) {
+		case strings.ToLower(registrationIDHeader):
+			idCol = i
+		case strings.ToLower(decisionHeader):
+			decisionCol = i
+		}
+	}
+	if idCol < 0 || decisionCol < 0 {
+		return nil, errors.New("decisions file must have Registration ID and Decision columns")
+	}
+
+	var rows []decisionRow
+	for i, record := range records[1:] {
+		decision := strings.ToLower(strings.TrimSpace(cellAt(record, decisionCol)))
+		if decision == "" {
+			continue
+		}
+		row := decisionRow{Row: i + 2}
+		switch decision {
+		case "approve", "approved":
+			row.Decision = "approve"
+		case "reject", "rejected":
+			row.Decision = "reject"
+		default:
+			row.Decision = decision
+			row.Err = "decision must be approve or reject"
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(cellAt(record, idCol)), 10, 32)
+		if err != nil || id == 0 {
+			row.Err = "registration ID is missing or invalid"
+		}
+		row.RegistrationID = uint(id)
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("decisions file has no decisions")
+	}
+	return rows, nil
+}
+
+func cellAt(record []string, col int) string {
+	if col < len(record) {
+		return record[col]
+	}
+	return ""
+}
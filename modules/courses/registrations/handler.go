@@ -1,11 +1,13 @@
 package registrations
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/promos"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -19,6 +21,8 @@ type Handler interface {
 	GetRegistrationsByCourseHandler(c *gin.Context)
 	ApproveRegistrationHandler(c *gin.Context)
 	RejectRegistrationHandler(c *gin.Context)
+	ExportRegistrationsHandler(c *gin.Context)
+	ImportDecisionsHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -69,6 +73,23 @@ func (h *handler) RegisterHandler(c *gin.Context) {
 			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already registered", err.Error(), nil))
 			return
 		}
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		if err.Error() == "course is not open for registration" {
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Registration closed", err.Error(), nil))
+			return
+		}
+		if promos.IsCodeError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+			return
+		}
+		var answerErrors AnswerErrors
+		if errors.As(err, &answerErrors) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid answers", err.Error(), answerErrors))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to register", err.Error(), nil))
 		return
 	}
@@ -163,3 +184,79 @@ func (h *handler) RejectRegistrationHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Registration rejected successfully", registration))
 }
+
+func (h *handler) ExportRegistrationsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	courseIDStr := c.Param("id")
+
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Course ID", "Course ID must be a valid number", nil))
+		return
+	}
+
+	var filter ExportRegistrationsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid query parameters", err.Error(), nil))
+		return
+	}
+
+	export, err := h.service.ExportRegistrations(uint(courseID), filter, requestID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to export registrations", err.Error(), nil))
+		return
+	}
+	defer export.File.Close()
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	c.Status(http.StatusOK)
+	if err := export.File.Write(c.Writer); err != nil {
+		utils.LogError("registrations", "export", "Failed to write export: "+err.Error(), requestID, 0, nil)
+	}
+}
+
+func (h *handler) ImportDecisionsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	courseIDStr := c.Param("id")
+
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Course ID", "Course ID must be a valid number", nil))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Decisions file is required", err.Error(), nil))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to open decisions file", err.Error(), nil))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportDecisions(uint(courseID), fileHeader.Filename, file, userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "course not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+		case "decisions must be an .xlsx file", "decisions file could not be read", "decisions file is empty",
+			"decisions file has too many rows", "decisions file must have Registration ID and Decision columns",
+			"decisions file has no decisions":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to import decisions", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to import decisions", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Decisions imported", result))
+}
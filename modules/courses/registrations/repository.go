@@ -1,7 +1,10 @@
 package registrations
 
 import (
+	"time"
+
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
 	"gorm.io/gorm"
 )
 
@@ -16,8 +19,13 @@ type Repository interface {
 	FindByUserAndCourse(userID, courseID uint) (entities.CourseRegistration, error)
 	Create(registration *entities.CourseRegistration) error
 	Update(registration *entities.CourseRegistration) error
+	// Approve saves the approved registration and books its charge in the ledger in one transaction
+	Approve(registration *entities.CourseRegistration, adminUserID uint) error
 	Delete(id uint) error
 	CreateAnswers(answers []entities.RegistrationAnswer) error
+	FindCourseByID(id uint) (entities.Course, error)
+	FindQuestionsByCourseID(courseID uint) ([]entities.RegistrationQuestion, error)
+	FindForExportInBatches(courseID uint, filter ExportRegistrationsFilter, batchSize int, fn func([]entities.CourseRegistration) error) error
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -56,6 +64,40 @@ func (r *repository) Update(registration *entities.CourseRegistration) error {
 	return r.db.Save(registration).Error
 }
 
+func (r *repository) Approve(registration *entities.CourseRegistration, adminUserID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(registration).Error; err != nil {
+			return err
+		}
+		// Bundle and group enrollments are paid once on the purchase, not per registration
+		if registration.FinalAmount <= 0 || registration.BundlePurchaseID != nil || registration.GroupEnrollmentID != nil {
+			return nil
+		}
+		var charged float64
+		if err := tx.Model(&entities.LedgerEntry{}).
+			Where("course_registration_id = ? AND type = ?", registration.ID, entities.LedgerEntryCharge).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&charged).Error; err != nil {
+			return err
+		}
+		if registration.FinalAmount-charged <= 0 {
+			return nil
+		}
+		registrationID := registration.ID
+		courseID := registration.CourseID
+		return tx.Create(&entities.LedgerEntry{
+			Type:                 entities.LedgerEntryCharge,
+			Amount:               registration.FinalAmount - charged,
+			UserID:               registration.UserID,
+			CourseRegistrationID: &registrationID,
+			CourseID:             &courseID,
+			Source:               ledger.SourceManualProof,
+			RecordedByUserID:     &adminUserID,
+			OccurredAt:           time.Now(),
+		}).Error
+	})
+}
+
 func (r *repository) Delete(id uint) error {
 	return r.db.Delete(&entities.CourseRegistration{}, id).Error
 }
@@ -66,3 +108,33 @@ func (r *repository) CreateAnswers(answers []entities.RegistrationAnswer) error
 	}
 	return r.db.Create(&answers).Error
 }
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+func (r *repository) FindQuestionsByCourseID(courseID uint) ([]entities.RegistrationQuestion, error) {
+	var questions []entities.RegistrationQuestion
+	err := r.db.Where("course_id = ?", courseID).
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("option_order ASC, id ASC") }).
+		Preload("Conditions").
+		Order("question_order ASC").
+		Find(&questions).Error
+	return questions, err
+}
+
+func (r *repository) FindForExportInBatches(courseID uint, filter ExportRegistrationsFilter, batchSize int, fn func([]entities.CourseRegistration) error) error {
+	query := r.db.Where("course_id = ?", courseID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var batch []entities.CourseRegistration
+	return query.Preload("User").
+		Preload("Answers").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
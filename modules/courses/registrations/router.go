@@ -3,11 +3,18 @@ package registrations
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/classes/progress"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/uploads"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 )
 
 func RegistrationRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
-	regRepo := NewRepository(migrations.GetDB())
-	regService := NewService(regRepo)
+	db := migrations.GetDB()
+	regRepo := NewRepository(db)
+	regService := NewService(regRepo, promos.NewService(promos.NewRepository(db)), receipts.NewService(receipts.NewRepository(db)), waitlists.NewService(waitlists.NewRepository(db)), uploads.NewService(uploads.NewRepository(db)), progress.NewService(progress.NewRepository(db), tutors.NewService(tutors.NewRepository(db))))
 	regHandler := NewHandler(regService)
 
 	registrations := router.Group("/registrations")
@@ -21,5 +28,7 @@ func RegistrationRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, re
 	{
 		courseRegistrations.POST("/register", requireAuth, regHandler.RegisterHandler)
 		courseRegistrations.GET("/registrations", requireAuth, requireAdminOrTutor, regHandler.GetRegistrationsByCourseHandler)
+		courseRegistrations.GET("/registrations/export", requireAuth, requireAdminOrTutor, regHandler.ExportRegistrationsHandler)
+		courseRegistrations.POST("/registrations/import", requireAuth, requireAdminOrTutor, regHandler.ImportDecisionsHandler)
 	}
 }
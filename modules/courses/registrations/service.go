@@ -2,15 +2,27 @@ package registrations
 
 import (
 	"errors"
+	"io"
 	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
+	"github.com/redukasquad/be-reduka/modules/classes/progress"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/uploads"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
 type registrationService struct {
-	repo Repository
+	repo      Repository
+	promos    promos.Service
+	receipts  receipts.Service
+	waitlists waitlists.Service
+	uploads   uploads.Service
+	progress  progress.Service
 }
 
 type Service interface {
@@ -20,10 +32,12 @@ type Service interface {
 	GetRegistrationByID(id uint, requestID string) (*RegistrationResponse, error)
 	ApproveRegistration(id uint, requestID string, adminUserID uint) (*RegistrationResponse, error)
 	RejectRegistration(id uint, requestID string, adminUserID uint) (*RegistrationResponse, error)
+	ExportRegistrations(courseID uint, filter ExportRegistrationsFilter, requestID string) (*RegistrationsExport, error)
+	ImportDecisions(courseID uint, fileName string, r io.Reader, adminUserID uint, requestID string) (*ImportDecisionsResponse, error)
 }
 
-func NewService(repo Repository) Service {
-	return &registrationService{repo: repo}
+func NewService(repo Repository, promoService promos.Service, receiptService receipts.Service, waitlistService waitlists.Service, uploadService uploads.Service, progressService progress.Service) Service {
+	return &registrationService{repo: repo, promos: promoService, receipts: receiptService, waitlists: waitlistService, uploads: uploadService, progress: progressService}
 }
 
 func (s *registrationService) Register(courseID uint, userID uint, input RegisterCourseInput, requestID string) (*RegistrationResponse, error) {
@@ -39,10 +53,66 @@ func (s *registrationService) Register(courseID uint, userID uint, input Registe
 		return nil, errors.New("you have already registered for this course")
 	}
 
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	// Drafts stay hidden; archived courses no longer take students
+	now := time.Now()
+	if !access.IsReleased(course.Status, course.PublishAt, now) {
+		return nil, errors.New("course not found")
+	}
+	if !access.IsLive(course.Status, course.PublishAt, now) {
+		utils.LogWarning("registrations", "register", "Course is not open for registration", requestID, userID, map[string]any{
+			"course_id": courseID,
+		})
+		return nil, errors.New("course is not open for registration")
+	}
+
+	// Answers are checked before anything is stored
+	questions, err := s.repo.FindQuestionsByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.validateAnswers(questions, input.Answers, userID, requestID)
+	if err != nil {
+		utils.LogWarning("registrations", "register", "Answers refused", requestID, userID, map[string]any{
+			"course_id": courseID,
+		})
+		return nil, err
+	}
+
+	price := course.Price
+	if course.IsFree {
+		price = 0
+	}
+	target := promos.Target{Kind: promos.TargetCourse, ID: courseID, Price: price}
+
 	registration := &entities.CourseRegistration{
-		UserID:   userID,
-		CourseID: courseID,
-		Status:   "pending",
+		UserID:        userID,
+		CourseID:      courseID,
+		Status:        "pending",
+		OriginalPrice: price,
+		FinalAmount:   price,
+	}
+
+	if input.PromoCode != "" {
+		quote, err := s.promos.Quote(input.PromoCode, target, userID)
+		if err != nil {
+			utils.LogWarning("registrations", "register", "Promo code refused: "+err.Error(), requestID, userID, map[string]any{
+				"course_id":  courseID,
+				"promo_code": input.PromoCode,
+			})
+			return nil, err
+		}
+		registration.DiscountAmount = quote.DiscountAmount
+		registration.FinalAmount = quote.FinalAmount
+		registration.PromoCodeID = &quote.PromoCodeID
+		registration.PromoCode = quote.Code
 	}
 
 	if err := s.repo.Create(registration); err != nil {
@@ -52,14 +122,23 @@ func (s *registrationService) Register(courseID uint, userID uint, input Registe
 		return nil, err
 	}
 
-	if len(input.Answers) > 0 {
-		var answers []entities.RegistrationAnswer
-		for _, ans := range input.Answers {
-			answers = append(answers, entities.RegistrationAnswer{
-				RegistrationID: registration.ID,
-				QuestionID:     ans.QuestionID,
-				AnswerText:     ans.AnswerText,
+	// Claim the promo code; limits are rechecked under lock, so undo the registration if it was used up meanwhile
+	if registration.PromoCodeID != nil {
+		if _, err := s.promos.Redeem(input.PromoCode, target, userID, registration.ID); err != nil {
+			utils.LogWarning("registrations", "register", "Failed to redeem promo code: "+err.Error(), requestID, userID, map[string]any{
+				"registration_id": registration.ID,
+				"promo_code":      input.PromoCode,
 			})
+			if delErr := s.repo.Delete(registration.ID); delErr != nil {
+				utils.LogError("registrations", "register", "Failed to roll back registration: "+delErr.Error(), requestID, userID, nil)
+			}
+			return nil, err
+		}
+	}
+
+	if len(answers) > 0 {
+		for i := range answers {
+			answers[i].RegistrationID = registration.ID
 		}
 		if err := s.repo.CreateAnswers(answers); err != nil {
 			utils.LogError("registrations", "register", "Failed to create answers: "+err.Error(), requestID, userID, map[string]any{
@@ -69,10 +148,20 @@ func (s *registrationService) Register(courseID uint, userID uint, input Registe
 		}
 	}
 
+	// A full course puts the registration on the waitlist instead
+	status := "pending"
+	waitlisted, err := s.waitlists.AdmitCourseRegistration(registration.ID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if waitlisted {
+		status = "waitlisted"
+	}
+
 	utils.LogSuccess("registrations", "register", "Registration created successfully", requestID, userID, map[string]any{
 		"course_id":       courseID,
 		"registration_id": registration.ID,
-		"status":          "pending",
+		"status":          status,
 	})
 
 	fullReg, _ := s.repo.FindByID(registration.ID)
@@ -91,7 +180,18 @@ func (s *registrationService) GetMyRegistrations(userID uint, requestID string)
 	var responses []RegistrationResponse
 	for _, reg := range registrations {
 		showWhatsApp := reg.Status == "approved"
-		responses = append(responses, *s.toRegistrationResponse(reg, showWhatsApp))
+		response := s.toRegistrationResponse(reg, showWhatsApp)
+		if reg.Status == "approved" {
+			percent, err := s.progress.CourseProgressPercent(reg.CourseID, userID)
+			if err != nil {
+				utils.LogError("registrations", "get_my_registrations", "Failed to compute progress: "+err.Error(), requestID, userID, map[string]any{
+					"course_id": reg.CourseID,
+				})
+				return nil, err
+			}
+			response.Progress = &percent
+		}
+		responses = append(responses, *response)
 	}
 
 	utils.LogSuccess("registrations", "get_my_registrations", "Successfully fetched user registrations", requestID, userID, map[string]any{
@@ -172,7 +272,7 @@ func (s *registrationService) ApproveRegistration(id uint, requestID string, adm
 	}
 
 	registration.Status = "approved"
-	if err := s.repo.Update(&registration); err != nil {
+	if err := s.repo.Approve(&registration, adminUserID); err != nil {
 		utils.LogError("registrations", "approve", "Failed to approve registration: "+err.Error(), requestID, adminUserID, map[string]any{
 			"registration_id": id,
 		})
@@ -185,6 +285,15 @@ func (s *registrationService) ApproveRegistration(id uint, requestID string, adm
 		"course_id":       registration.CourseID,
 	})
 
+	// Paid registrations get a numbered receipt by email
+	if registration.FinalAmount > 0 && registration.BundlePurchaseID == nil {
+		if _, err := s.receipts.IssueCourseReceipt(id, requestID); err != nil {
+			utils.LogError("registrations", "approve", "Failed to issue receipt: "+err.Error(), requestID, adminUserID, map[string]any{
+				"registration_id": id,
+			})
+		}
+	}
+
 	registration, _ = s.repo.FindByID(id)
 	return s.toRegistrationResponse(registration, true), nil
 }
@@ -227,17 +336,35 @@ func (s *registrationService) RejectRegistration(id uint, requestID string, admi
 		"course_id":       registration.CourseID,
 	})
 
+	// Give the promo code usage back so a limited code is not used up by a rejected registration
+	if err := s.promos.Release(promos.TargetCourse, registration.ID); err != nil {
+		utils.LogError("registrations", "reject", "Failed to release promo code: "+err.Error(), requestID, adminUserID, map[string]any{
+			"registration_id": id,
+		})
+	}
+
+	// The rejected registration no longer holds a seat
+	if _, err := s.waitlists.PromoteCourse(registration.CourseID, requestID); err != nil {
+		utils.LogError("registrations", "reject", "Failed to promote waitlist: "+err.Error(), requestID, adminUserID, map[string]any{
+			"course_id": registration.CourseID,
+		})
+	}
+
 	registration, _ = s.repo.FindByID(id)
 	return s.toRegistrationResponse(registration, false), nil
 }
 
 func (s *registrationService) toRegistrationResponse(reg entities.CourseRegistration, showWhatsApp bool) *RegistrationResponse {
 	response := &RegistrationResponse{
-		ID:        reg.ID,
-		UserID:    reg.UserID,
-		CourseID:  reg.CourseID,
-		Status:    reg.Status,
-		CreatedAt: reg.CreatedAt.Format(time.RFC3339),
+		ID:             reg.ID,
+		UserID:         reg.UserID,
+		CourseID:       reg.CourseID,
+		Status:         reg.Status,
+		OriginalPrice:  reg.OriginalPrice,
+		DiscountAmount: reg.DiscountAmount,
+		FinalAmount:    reg.FinalAmount,
+		PromoCode:      reg.PromoCode,
+		CreatedAt:      reg.CreatedAt.Format(time.RFC3339),
 	}
 
 	if reg.Course.ID != 0 {
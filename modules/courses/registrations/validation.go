@@ -0,0 +1,162 @@
+package registrations
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+// validateAnswers checks the submitted answers against the course's questions and returns the answers to store.
+// Questions hidden by their display conditions are neither required nor stored, and blank optional answers
+// are dropped; every problem found is reported at once as AnswerErrors.
+func (s *registrationService) validateAnswers(questions []entities.RegistrationQuestion, inputs []AnswerInput, userID uint, requestID string) ([]entities.RegistrationAnswer, error) {
+	byID := make(map[uint]entities.RegistrationQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	var problems AnswerErrors
+	given := make(map[uint]string, len(inputs))
+	for _, input := range inputs {
+		if _, ok := byID[input.QuestionID]; !ok {
+			problems = append(problems, AnswerError{QuestionID: input.QuestionID, Message: "question is not part of this course"})
+			continue
+		}
+		if _, dup := given[input.QuestionID]; dup {
+			problems = append(problems, AnswerError{QuestionID: input.QuestionID, Message: "question was answered more than once"})
+			continue
+		}
+		given[input.QuestionID] = strings.TrimSpace(input.AnswerText)
+	}
+
+	// Questions come in display order, so the answers a condition refers to are already settled
+	accepted := make(map[uint]string, len(questions))
+	var answers []entities.RegistrationAnswer
+	for _, q := range questions {
+		if !isVisible(q, accepted) {
+			continue
+		}
+		answer := given[q.ID]
+		if answer == "" {
+			if q.IsRequired {
+				problems = append(problems, AnswerError{QuestionID: q.ID, QuestionText: q.QuestionText, Message: "this question is required"})
+			}
+			continue
+		}
+		if message := s.checkAnswer(q, answer, userID, requestID); message != "" {
+			problems = append(problems, AnswerError{QuestionID: q.ID, QuestionText: q.QuestionText, Message: message})
+			continue
+		}
+		accepted[q.ID] = answer
+		answers = append(answers, entities.RegistrationAnswer{QuestionID: q.ID, AnswerText: answer})
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return answers, nil
+}
+
+// isVisible evaluates the question's display conditions against the answers accepted so far.
+// An earlier question that was hidden or left blank counts as not answered.
+func isVisible(q entities.RegistrationQuestion, accepted map[uint]string) bool {
+	if len(q.Conditions) == 0 {
+		return true
+	}
+	matchAny := q.ShowWhen == "any"
+	for _, condition := range q.Conditions {
+		answer := accepted[condition.DependsOnQuestionID]
+		var holds bool
+		switch condition.Operator {
+		case entities.ConditionEquals:
+			holds = answer != "" && strings.EqualFold(answer, condition.Value)
+		case entities.ConditionNotEquals:
+			holds = !strings.EqualFold(answer, condition.Value)
+		case entities.ConditionAnswered:
+			holds = answer != ""
+		case entities.ConditionNotAnswered:
+			holds = answer == ""
+		}
+		if matchAny && holds {
+			return true
+		}
+		if !matchAny && !holds {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+// checkAnswer returns why a non-empty answer does not fit its question, or "" if it does
+func (s *registrationService) checkAnswer(q entities.RegistrationQuestion, answer string, userID uint, requestID string) string {
+	switch q.QuestionType {
+	case "text":
+		length := utf8.RuneCountInString(answer)
+		if q.MinLength != nil && length < *q.MinLength {
+			return fmt.Sprintf("answer must be at least %d characters", *q.MinLength)
+		}
+		if q.MaxLength != nil && length > *q.MaxLength {
+			return fmt.Sprintf("answer must be at most %d characters", *q.MaxLength)
+		}
+		if q.ValidationPattern != "" {
+			// The whole answer has to match, not just a part of it
+			pattern, err := regexp.Compile("^(?:" + q.ValidationPattern + ")$")
+			if err != nil {
+				// Patterns are checked when the question is saved, so this only hits rows stored before that
+				utils.LogError("registrations", "check_answer", "Invalid validation pattern: "+err.Error(), requestID, userID, map[string]any{
+					"question_id": q.ID,
+				})
+			}
+			if err != nil || !pattern.MatchString(answer) {
+				if q.PatternMessage != "" {
+					return q.PatternMessage
+				}
+				return "answer has an invalid format"
+			}
+		}
+
+	case "number":
+		value, err := strconv.ParseFloat(strings.ReplaceAll(answer, ",", "."), 64)
+		if err != nil {
+			return "answer must be a number"
+		}
+		if q.MinValue != nil && value < *q.MinValue {
+			return "answer must be at least " + strconv.FormatFloat(*q.MinValue, 'f', -1, 64)
+		}
+		if q.MaxValue != nil && value > *q.MaxValue {
+			return "answer must be at most " + strconv.FormatFloat(*q.MaxValue, 'f', -1, 64)
+		}
+
+	case "select", "radio":
+		for _, option := range q.Options {
+			if option.OptionText == answer {
+				return ""
+			}
+		}
+		return "answer must be one of the options"
+
+	case "file":
+		parsed, err := url.Parse(answer)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return "answer must be the URL of an uploaded file"
+		}
+		// Only files the student registered through the uploads endpoint are accepted
+		file, err := s.uploads.GetByURL(answer, requestID)
+		if err != nil {
+			if err.Error() == "image not found" {
+				return "file has not been uploaded"
+			}
+			return "file could not be verified"
+		}
+		if file.UserID == nil || *file.UserID != userID {
+			return "file was not uploaded by you"
+		}
+	}
+	return ""
+}
@@ -7,6 +7,7 @@ import (
 	courses "github.com/redukasquad/be-reduka/modules/courses/index"
 	"github.com/redukasquad/be-reduka/modules/courses/questions"
 	"github.com/redukasquad/be-reduka/modules/courses/registrations"
+	"github.com/redukasquad/be-reduka/modules/courses/tutors"
 )
 
 func CoursesRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
@@ -14,4 +15,5 @@ func CoursesRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, require
 	registrations.RegistrationRouter(router, requireAuth, requireAdmin, requireAdminOrTutor)
 	questions.QuestionRouter(router, requireAuth, requireAdmin)
 	answers.AnswerRouter(router, requireAuth, requireAdmin)
+	tutors.TutorRouter(router, requireAuth, requireAdmin)
 }
@@ -0,0 +1,64 @@
+package tutors
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// COURSE TUTOR DTOs
+// ==========================================
+
+// AssignTutorInput assigns a tutor to the course, or only to one of its classes when ClassID is set
+type AssignTutorInput struct {
+	UserID  uint   `json:"userId" binding:"required"`
+	ClassID *uint  `json:"classId"`
+	Role    string `json:"role" binding:"required,oneof=lead assistant"`
+}
+
+type UpdateAssignmentInput struct {
+	Role string `json:"role" binding:"required,oneof=lead assistant"`
+}
+
+type AssignmentResponse struct {
+	ID         uint       `json:"id"`
+	CourseID   uint       `json:"courseId"`
+	ClassID    *uint      `json:"classId"`
+	ClassName  string     `json:"className,omitempty"`
+	Role       string     `json:"role"`
+	User       *UserBrief `json:"user"`
+	AssignedBy *UserBrief `json:"assignedBy,omitempty"`
+	AssignedAt time.Time  `json:"assignedAt"`
+}
+
+// UserBrief is minimal user info for assignment views
+type UserBrief struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func toAssignmentResponse(a entities.CourseTutor) AssignmentResponse {
+	response := AssignmentResponse{
+		ID:         a.ID,
+		CourseID:   a.CourseID,
+		ClassID:    a.ClassID,
+		Role:       a.Role,
+		AssignedAt: a.CreatedAt,
+	}
+	if a.Class != nil {
+		response.ClassName = a.Class.Name
+	}
+	if a.User.ID != 0 {
+		response.User = &UserBrief{ID: a.User.ID, Username: a.User.Username, Email: a.User.Email}
+	}
+	if a.AssignedBy.ID != 0 {
+		response.AssignedBy = &UserBrief{ID: a.AssignedBy.ID, Username: a.AssignedBy.Username, Email: a.AssignedBy.Email}
+	}
+	return response
+}
@@ -0,0 +1,157 @@
+package tutors
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	GetCourseTutorsHandler(c *gin.Context)
+	AssignTutorHandler(c *gin.Context)
+	UpdateAssignmentHandler(c *gin.Context)
+	RemoveAssignmentHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(int); ok {
+		return uint(id)
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	return 0
+}
+
+// parseIDs reads the course ID and, when present, the assignment ID from the path
+func parseIDs(c *gin.Context, withAssignment bool) (uint, uint, bool) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Course ID", "Course ID must be a valid number", nil))
+		return 0, 0, false
+	}
+	if !withAssignment {
+		return uint(courseID), 0, true
+	}
+	assignmentID, err := strconv.ParseUint(c.Param("assignmentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Assignment ID", "Assignment ID must be a valid number", nil))
+		return 0, 0, false
+	}
+	return uint(courseID), uint(assignmentID), true
+}
+
+func (h *handler) GetCourseTutorsHandler(c *gin.Context) {
+	courseID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.service.GetByCourse(courseID, getRequestID(c))
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch course tutors", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Course tutors retrieved successfully", assignments))
+}
+
+func (h *handler) AssignTutorHandler(c *gin.Context) {
+	courseID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var input AssignTutorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	assignment, err := h.service.Assign(courseID, input, getRequestID(c), getUserID(c))
+	if err != nil {
+		switch err.Error() {
+		case "course not found", "class not found", "user not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+		case "class does not belong to this course", "user is not a tutor":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to assign tutor", err.Error(), nil))
+		case "tutor is already assigned here":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Assignment already exists", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to assign tutor", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Tutor assigned successfully", assignment))
+}
+
+func (h *handler) UpdateAssignmentHandler(c *gin.Context) {
+	courseID, assignmentID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	var input UpdateAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	assignment, err := h.service.UpdateRole(courseID, assignmentID, input, getRequestID(c), getUserID(c))
+	if err != nil {
+		if err.Error() == "assignment not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Assignment not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update assignment", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Tutor role updated successfully", assignment))
+}
+
+func (h *handler) RemoveAssignmentHandler(c *gin.Context) {
+	courseID, assignmentID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Remove(courseID, assignmentID, getRequestID(c), getUserID(c)); err != nil {
+		if err.Error() == "assignment not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Assignment not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to remove tutor", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Tutor removed successfully", nil))
+}
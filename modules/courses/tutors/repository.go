@@ -0,0 +1,113 @@
+package tutors
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	FindByCourseID(courseID uint) ([]entities.CourseTutor, error)
+	FindByID(id uint) (entities.CourseTutor, error)
+	FindByUserAndCourse(userID, courseID uint) ([]entities.CourseTutor, error)
+	Exists(courseID uint, classID *uint, userID uint) (bool, error)
+	Create(assignment *entities.CourseTutor) error
+	Update(assignment *entities.CourseTutor) error
+	Delete(id uint) error
+
+	FindUserByID(id uint) (entities.User, error)
+	FindCourseByID(id uint) (entities.Course, error)
+	FindClassByID(id uint) (entities.Class, error)
+	FindLessonByID(id uint) (entities.Lesson, error)
+	FindResourceByID(id uint) (entities.LessonResource, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Assignment Methods
+// ==========================================
+
+func (r *repository) FindByCourseID(courseID uint) ([]entities.CourseTutor, error) {
+	var assignments []entities.CourseTutor
+	err := r.db.Where("course_id = ?", courseID).
+		Preload("User").Preload("Class").Preload("AssignedBy").
+		Order("class_id NULLS FIRST, role ASC, id ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *repository) FindByID(id uint) (entities.CourseTutor, error) {
+	var assignment entities.CourseTutor
+	err := r.db.Preload("User").Preload("Class").Preload("AssignedBy").First(&assignment, id).Error
+	return assignment, err
+}
+
+func (r *repository) FindByUserAndCourse(userID, courseID uint) ([]entities.CourseTutor, error) {
+	var assignments []entities.CourseTutor
+	err := r.db.Where("user_id = ? AND course_id = ?", userID, courseID).Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *repository) Exists(courseID uint, classID *uint, userID uint) (bool, error) {
+	query := r.db.Model(&entities.CourseTutor{}).Where("course_id = ? AND user_id = ?", courseID, userID)
+	if classID == nil {
+		query = query.Where("class_id IS NULL")
+	} else {
+		query = query.Where("class_id = ?", *classID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) Create(assignment *entities.CourseTutor) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *repository) Update(assignment *entities.CourseTutor) error {
+	return r.db.Omit("Course", "Class", "User", "AssignedBy").Save(assignment).Error
+}
+
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entities.CourseTutor{}, id).Error
+}
+
+// ==========================================
+// Lookup Methods
+// ==========================================
+
+func (r *repository) FindUserByID(id uint) (entities.User, error) {
+	var user entities.User
+	err := r.db.First(&user, id).Error
+	return user, err
+}
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+func (r *repository) FindClassByID(id uint) (entities.Class, error) {
+	var class entities.Class
+	err := r.db.Preload("Course").First(&class, id).Error
+	return class, err
+}
+
+func (r *repository) FindLessonByID(id uint) (entities.Lesson, error) {
+	var lesson entities.Lesson
+	err := r.db.Preload("Class.Course").First(&lesson, id).Error
+	return lesson, err
+}
+
+func (r *repository) FindResourceByID(id uint) (entities.LessonResource, error) {
+	var resource entities.LessonResource
+	err := r.db.Preload("Lesson.Class.Course").First(&resource, id).Error
+	return resource, err
+}
@@ -0,0 +1,19 @@
+package tutors
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func TutorRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+	handler := NewHandler(NewService(NewRepository(migrations.GetDB())))
+
+	courseTutors := router.Group("/courses/:id/tutors")
+	courseTutors.Use(requireAuth, requireAdmin)
+	{
+		courseTutors.GET("", handler.GetCourseTutorsHandler)
+		courseTutors.POST("", handler.AssignTutorHandler)
+		courseTutors.PUT("/:assignmentId", handler.UpdateAssignmentHandler)
+		courseTutors.DELETE("/:assignmentId", handler.RemoveAssignmentHandler)
+	}
+}
@@ -0,0 +1,335 @@
+package tutors
+
+import (
+	"errors"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type tutorService struct {
+	repo Repository
+}
+
+// Service manages course tutor assignments and checks them for the course and class mutating endpoints.
+// Lead tutors (and the course creator) manage the course and its classes; assistants only manage
+// lessons and resources. An assignment limited to one class only covers that class.
+type Service interface {
+	GetByCourse(courseID uint, requestID string) ([]AssignmentResponse, error)
+	Assign(courseID uint, input AssignTutorInput, requestID string, adminUserID uint) (*AssignmentResponse, error)
+	UpdateRole(courseID, assignmentID uint, input UpdateAssignmentInput, requestID string, adminUserID uint) (*AssignmentResponse, error)
+	Remove(courseID, assignmentID uint, requestID string, adminUserID uint) error
+
+	AuthorizeCourse(courseID, userID uint, isAdmin bool) error
+	AuthorizeCourseView(courseID, userID uint, isAdmin bool) error
+	AuthorizeClass(classID, userID uint, isAdmin bool) error
+	AuthorizeClassContent(classID, userID uint, isAdmin bool) error
+	AuthorizeLesson(lessonID, userID uint, isAdmin bool) error
+	AuthorizeResource(resourceID, userID uint, isAdmin bool) error
+}
+
+func NewService(repo Repository) Service {
+	return &tutorService{repo: repo}
+}
+
+// IsAuthorizationError reports whether err means the caller is not assigned to the course or class
+func IsAuthorizationError(err error) bool {
+	switch err.Error() {
+	case "you are not a lead tutor of this course", "you are not a lead tutor of this class",
+		"you are not assigned to this course", "you are not assigned to this class":
+		return true
+	}
+	return false
+}
+
+// ==========================================
+// Assignment Management
+// ==========================================
+
+func (s *tutorService) GetByCourse(courseID uint, requestID string) ([]AssignmentResponse, error) {
+	utils.LogInfo("course_tutors", "get_by_course", "Fetching course tutors", requestID, 0, map[string]any{
+		"course_id": courseID,
+	})
+
+	if _, err := s.repo.FindCourseByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	assignments, err := s.repo.FindByCourseID(courseID)
+	if err != nil {
+		utils.LogError("course_tutors", "get_by_course", "Failed to fetch course tutors: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]AssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		responses = append(responses, toAssignmentResponse(a))
+	}
+	return responses, nil
+}
+
+func (s *tutorService) Assign(courseID uint, input AssignTutorInput, requestID string, adminUserID uint) (*AssignmentResponse, error) {
+	utils.LogInfo("course_tutors", "assign", "Assigning tutor to course", requestID, adminUserID, map[string]any{
+		"course_id": courseID,
+		"class_id":  input.ClassID,
+		"user_id":   input.UserID,
+		"role":      input.Role,
+	})
+
+	if _, err := s.repo.FindCourseByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+	if input.ClassID != nil {
+		class, err := s.repo.FindClassByID(*input.ClassID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("class not found")
+			}
+			return nil, err
+		}
+		if class.CourseID != courseID {
+			return nil, errors.New("class does not belong to this course")
+		}
+	}
+
+	user, err := s.repo.FindUserByID(input.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	if user.Role == nil || *user.Role != "TUTOR" {
+		return nil, errors.New("user is not a tutor")
+	}
+
+	exists, err := s.repo.Exists(courseID, input.ClassID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("tutor is already assigned here")
+	}
+
+	assignment := &entities.CourseTutor{
+		CourseID:         courseID,
+		ClassID:          input.ClassID,
+		UserID:           input.UserID,
+		Role:             input.Role,
+		AssignedByUserID: adminUserID,
+	}
+	if err := s.repo.Create(assignment); err != nil {
+		utils.LogError("course_tutors", "assign", "Failed to assign tutor: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	created, err := s.repo.FindByID(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogSuccess("course_tutors", "assign", "Tutor assigned successfully", requestID, adminUserID, map[string]any{
+		"assignment_id": assignment.ID,
+		"course_id":     courseID,
+		"user_id":       input.UserID,
+	})
+
+	response := toAssignmentResponse(created)
+	return &response, nil
+}
+
+func (s *tutorService) UpdateRole(courseID, assignmentID uint, input UpdateAssignmentInput, requestID string, adminUserID uint) (*AssignmentResponse, error) {
+	utils.LogInfo("course_tutors", "update_role", "Changing tutor role", requestID, adminUserID, map[string]any{
+		"assignment_id": assignmentID,
+		"role":          input.Role,
+	})
+
+	assignment, err := s.repo.FindByID(assignmentID)
+	if err != nil || assignment.CourseID != courseID {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("assignment not found")
+		}
+		return nil, err
+	}
+
+	assignment.Role = input.Role
+	if err := s.repo.Update(&assignment); err != nil {
+		utils.LogError("course_tutors", "update_role", "Failed to change tutor role: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("course_tutors", "update_role", "Tutor role changed successfully", requestID, adminUserID, map[string]any{
+		"assignment_id": assignmentID,
+	})
+
+	response := toAssignmentResponse(assignment)
+	return &response, nil
+}
+
+func (s *tutorService) Remove(courseID, assignmentID uint, requestID string, adminUserID uint) error {
+	utils.LogInfo("course_tutors", "remove", "Removing tutor from course", requestID, adminUserID, map[string]any{
+		"assignment_id": assignmentID,
+	})
+
+	assignment, err := s.repo.FindByID(assignmentID)
+	if err != nil || assignment.CourseID != courseID {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("assignment not found")
+		}
+		return err
+	}
+
+	if err := s.repo.Delete(assignmentID); err != nil {
+		utils.LogError("course_tutors", "remove", "Failed to remove tutor: "+err.Error(), requestID, adminUserID, nil)
+		return err
+	}
+
+	utils.LogSuccess("course_tutors", "remove", "Tutor removed successfully", requestID, adminUserID, map[string]any{
+		"assignment_id": assignmentID,
+		"course_id":     courseID,
+		"user_id":       assignment.UserID,
+	})
+	return nil
+}
+
+// ==========================================
+// Authorization
+// ==========================================
+
+// AuthorizeCourse allows admins and lead tutors of the whole course to manage the course and its classes
+func (s *tutorService) AuthorizeCourse(courseID, userID uint, isAdmin bool) error {
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("course not found")
+		}
+		return err
+	}
+	ok, err := s.isAssigned(course, 0, userID, isAdmin, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("you are not a lead tutor of this course")
+	}
+	return nil
+}
+
+// AuthorizeCourseView allows admins and any tutor assigned to the course or one of its classes
+// to read course-wide reports
+func (s *tutorService) AuthorizeCourseView(courseID, userID uint, isAdmin bool) error {
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("course not found")
+		}
+		return err
+	}
+	if isAdmin || (userID != 0 && course.CreatedByUserID == userID) {
+		return nil
+	}
+	assignments, err := s.repo.FindByUserAndCourse(userID, courseID)
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return errors.New("you are not assigned to this course")
+	}
+	return nil
+}
+
+// AuthorizeClass allows admins and lead tutors of the course or of the class to edit the class
+func (s *tutorService) AuthorizeClass(classID, userID uint, isAdmin bool) error {
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("subject not found")
+		}
+		return err
+	}
+	ok, err := s.isAssigned(class.Course, class.ID, userID, isAdmin, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("you are not a lead tutor of this class")
+	}
+	return nil
+}
+
+// AuthorizeClassContent allows admins and any tutor assigned to the class (or its whole course) to manage its lessons
+func (s *tutorService) AuthorizeClassContent(classID, userID uint, isAdmin bool) error {
+	class, err := s.repo.FindClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("class not found")
+		}
+		return err
+	}
+	return s.authorizeContent(class, userID, isAdmin)
+}
+
+// AuthorizeLesson allows admins and tutors assigned to the lesson's class to manage the lesson and its resources
+func (s *tutorService) AuthorizeLesson(lessonID, userID uint, isAdmin bool) error {
+	lesson, err := s.repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("lesson not found")
+		}
+		return err
+	}
+	return s.authorizeContent(lesson.Class, userID, isAdmin)
+}
+
+// AuthorizeResource allows admins and tutors assigned to the resource's class to manage the resource
+func (s *tutorService) AuthorizeResource(resourceID, userID uint, isAdmin bool) error {
+	resource, err := s.repo.FindResourceByID(resourceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("resource not found")
+		}
+		return err
+	}
+	return s.authorizeContent(resource.Lesson.Class, userID, isAdmin)
+}
+
+func (s *tutorService) authorizeContent(class entities.Class, userID uint, isAdmin bool) error {
+	ok, err := s.isAssigned(class.Course, class.ID, userID, isAdmin, false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("you are not assigned to this class")
+	}
+	return nil
+}
+
+// isAssigned checks the user's assignments on the course. With classID 0 only whole-course
+// assignments count; otherwise an assignment to that class counts too.
+func (s *tutorService) isAssigned(course entities.Course, classID, userID uint, isAdmin bool, leadOnly bool) (bool, error) {
+	if isAdmin || (userID != 0 && course.CreatedByUserID == userID) {
+		return true, nil
+	}
+	if userID == 0 {
+		return false, nil
+	}
+
+	assignments, err := s.repo.FindByUserAndCourse(userID, course.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range assignments {
+		covers := a.ClassID == nil || (classID != 0 && *a.ClassID == classID)
+		if covers && (!leadOnly || a.Role == entities.TutorRoleLead) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
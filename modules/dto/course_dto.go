@@ -13,12 +13,17 @@ type CourseResponse struct {
 	StartDate         time.Time              `json:"startDate"`
 	EndDate           time.Time              `json:"endDate"`
 	IsFree            bool                   `json:"isFree"`
+	Price             float64                `json:"price"`
+	Capacity          int                    `json:"capacity"`
 	WhatsAppGroupLink string                 `json:"whatsAppGroupLink,omitempty"`
 	Program           *ProgramBriefResponse  `json:"program,omitempty"`
 	Creator           *CreatorResponse       `json:"creator,omitempty"`
 	Classes           []SubjectBriefResponse `json:"classes,omitempty"`
 	CreatedAt         time.Time              `json:"createdAt"`
 	Image             string                 `json:"image,omitempty"`
+
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publishAt,omitempty"`
 }
 
 func ToCourseResponse(course entities.Course) CourseResponse {
@@ -29,9 +34,16 @@ func ToCourseResponse(course entities.Course) CourseResponse {
 		StartDate:         course.StartDate,
 		EndDate:           course.EndDate,
 		IsFree:            course.IsFree,
+		Price:             course.Price,
+		Capacity:          course.Capacity,
 		Image:             course.Image,
 		WhatsAppGroupLink: course.WhatsappGroupLink,
 		CreatedAt:         course.CreatedAt,
+		Status:            course.Status,
+		PublishAt:         course.PublishAt,
+	}
+	if response.Status == "" {
+		response.Status = entities.ContentPublished
 	}
 
 	// Map Program jika ada (ID != 0)
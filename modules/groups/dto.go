@@ -0,0 +1,172 @@
+package groups
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// ENROLLMENT DTOs
+// ==========================================
+
+// EnrollInput is the multipart form sent with the roster file. Exactly one of TryOutID / CourseID is required.
+type EnrollInput struct {
+	TryOutID uint   `form:"tryOutId"`
+	CourseID uint   `form:"courseId"`
+	Label    string `form:"label" binding:"max=100"`
+}
+
+// UploadPaymentProofInput is the input for the group payment proof
+type UploadPaymentProofInput struct {
+	PaymentProofURL string `json:"paymentProofUrl" binding:"required"`
+}
+
+// RejectEnrollmentInput is the input for rejecting a group payment
+type RejectEnrollmentInput struct {
+	RejectionReason string `json:"rejectionReason"`
+}
+
+// RosterRowResult is the outcome of one roster row
+type RosterRowResult struct {
+	Row     int    `json:"row"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Status  string `json:"status"` // enrolled, invited, skipped, error
+	Message string `json:"message,omitempty"`
+}
+
+// EnrollResponse is returned after processing a roster
+type EnrollResponse struct {
+	Enrollment *EnrollmentResponse `json:"enrollment,omitempty"`
+	Enrolled   int                 `json:"enrolled"`
+	Invited    int                 `json:"invited"`
+	Skipped    int                 `json:"skipped"`
+	Errors     int                 `json:"errors"`
+	Rows       []RosterRowResult   `json:"rows"`
+}
+
+// EnrollmentResponse is the response DTO for a group enrollment
+type EnrollmentResponse struct {
+	ID              uint               `json:"id"`
+	Label           string             `json:"label,omitempty"`
+	School          string             `json:"school,omitempty"`
+	Coordinator     *UserBriefResponse `json:"coordinator,omitempty"`
+	PackageType     string             `json:"packageType"` // tryout or course
+	PackageID       uint               `json:"packageId"`
+	PackageName     string             `json:"packageName"`
+	PricePerSeat    float64            `json:"pricePerSeat"`
+	Seats           int                `json:"seats"`
+	Amount          float64            `json:"amount"`
+	PaymentProofURL string             `json:"paymentProofUrl,omitempty"`
+	PaymentStatus   string             `json:"paymentStatus"`
+	RejectionReason string             `json:"rejectionReason,omitempty"`
+	ApprovedAt      *time.Time         `json:"approvedAt,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	Members         []MemberResponse   `json:"members,omitempty"`
+}
+
+// MemberResponse is one student of the cohort with their registration status and result
+type MemberResponse struct {
+	UserID             uint       `json:"userId"`
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	Kelas              string     `json:"kelas,omitempty"`
+	Invited            bool       `json:"invited"`
+	AwaitingAcceptance bool       `json:"awaitingAcceptance"`
+	AccountActivated   bool       `json:"accountActivated"`
+	RegistrationID     uint       `json:"registrationId"`
+	RegistrationStatus string     `json:"registrationStatus"`
+	AttemptStatus      string     `json:"attemptStatus,omitempty"`
+	TotalScore         *float64   `json:"totalScore,omitempty"`
+	FinishedAt         *time.Time `json:"finishedAt,omitempty"`
+}
+
+// UserBriefResponse is a minimal user info
+type UserBriefResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	School   string `json:"school,omitempty"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToEnrollmentResponse(e entities.GroupEnrollment, withMembers bool) EnrollmentResponse {
+	response := EnrollmentResponse{
+		ID:              e.ID,
+		Label:           e.Label,
+		School:          e.School,
+		PricePerSeat:    e.PricePerSeat,
+		Seats:           e.Seats,
+		Amount:          e.Amount,
+		PaymentProofURL: e.PaymentProofURL,
+		PaymentStatus:   string(e.PaymentStatus),
+		RejectionReason: e.RejectionReason,
+		ApprovedAt:      e.ApprovedAt,
+		CreatedAt:       e.CreatedAt,
+	}
+
+	if e.TryOutID != nil {
+		response.PackageType = "tryout"
+		response.PackageID = *e.TryOutID
+		if e.TryOut != nil {
+			response.PackageName = e.TryOut.Name
+		}
+	} else if e.CourseID != nil {
+		response.PackageType = "course"
+		response.PackageID = *e.CourseID
+		if e.Course != nil {
+			response.PackageName = e.Course.NameCourse
+		}
+	}
+
+	if e.Coordinator.ID != 0 {
+		response.Coordinator = &UserBriefResponse{
+			ID:       e.Coordinator.ID,
+			Username: e.Coordinator.Username,
+			Email:    e.Coordinator.Email,
+			School:   e.Coordinator.School,
+		}
+	}
+
+	if withMembers {
+		response.Members = make([]MemberResponse, 0, len(e.Members))
+		for _, m := range e.Members {
+			response.Members = append(response.Members, ToMemberResponse(m))
+		}
+	}
+
+	return response
+}
+
+func ToMemberResponse(m entities.GroupEnrollmentMember) MemberResponse {
+	response := MemberResponse{
+		UserID:             m.UserID,
+		Username:           m.User.Username,
+		Email:              m.User.Email,
+		Invited:            m.Invited,
+		AwaitingAcceptance: m.InvitationToken != "",
+		AccountActivated:   m.User.IsVerified,
+	}
+	if m.User.Kelas != nil {
+		response.Kelas = *m.User.Kelas
+	}
+
+	if reg := m.TryOutRegistration; reg != nil {
+		response.RegistrationID = reg.ID
+		response.RegistrationStatus = string(reg.PaymentStatus)
+		if reg.Attempt != nil {
+			response.AttemptStatus = string(reg.Attempt.Status)
+			response.TotalScore = reg.Attempt.TotalScore
+			response.FinishedAt = reg.Attempt.FinishedAt
+		}
+	} else if reg := m.CourseRegistration; reg != nil {
+		response.RegistrationID = reg.ID
+		response.RegistrationStatus = reg.Status
+	}
+
+	return response
+}
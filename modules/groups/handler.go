@@ -0,0 +1,280 @@
+package groups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	// Coordinator endpoints
+	EnrollHandler(c *gin.Context)
+	GetMyEnrollmentsHandler(c *gin.Context)
+	GetEnrollmentHandler(c *gin.Context)
+	UploadPaymentProofHandler(c *gin.Context)
+	CancelEnrollmentHandler(c *gin.Context)
+
+	// Admin endpoints
+	GetEnrollmentsHandler(c *gin.Context)
+	ApproveEnrollmentHandler(c *gin.Context)
+	RejectEnrollmentHandler(c *gin.Context)
+
+	// Student endpoints
+	AcceptInvitationHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Coordinator Handlers
+// ==========================================
+
+func (h *handler) EnrollHandler(c *gin.Context) {
+	var input EnrollInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Roster file is required", err.Error(), nil))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to open roster file", err.Error(), nil))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Enroll(input, fileHeader.Filename, file, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "try out not found", "course not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Package not found", err.Error(), nil))
+		case "exactly one of tryOutId or courseId is required", "registration has not started yet", "registration period has ended",
+			"roster must be an .xlsx or .csv file", "roster file could not be read", "roster file is empty",
+			"roster must have an email column", "roster has too many students":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to enroll roster", err.Error(), nil))
+		case "roster has no students to enroll":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to enroll roster", err.Error(), result))
+		case "not enough seats left for this roster":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Failed to enroll roster", err.Error(), nil))
+		case "student is already registered":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Failed to enroll roster", "a student on the roster registered meanwhile, please try again", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to enroll roster", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Roster enrolled successfully", result))
+}
+
+func (h *handler) GetMyEnrollmentsHandler(c *gin.Context) {
+	enrollments, err := h.service.GetMyEnrollments(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch enrollments", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Enrollments retrieved successfully", enrollments))
+}
+
+func (h *handler) GetEnrollmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.GetEnrollment(id, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Enrollment not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch enrollment", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Enrollment retrieved successfully", enrollment))
+}
+
+func (h *handler) UploadPaymentProofHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UploadPaymentProofInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	enrollment, err := h.service.UploadPaymentProof(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Enrollment not found", err.Error(), nil))
+		case "payment is already approved", "enrollment is cancelled":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to upload payment proof", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to upload payment proof", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payment proof uploaded successfully", enrollment))
+}
+
+func (h *handler) CancelEnrollmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.CancelEnrollment(id, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Enrollment not found", err.Error(), nil))
+		case "cannot cancel an approved enrollment", "enrollment is cancelled":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to cancel enrollment", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to cancel enrollment", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Group enrollment cancelled successfully", enrollment))
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) GetEnrollmentsHandler(c *gin.Context) {
+	enrollments, err := h.service.GetEnrollments(c.Query("status"), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch enrollments", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Enrollments retrieved successfully", enrollments))
+}
+
+func (h *handler) ApproveEnrollmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.ApproveEnrollment(id, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Enrollment not found", err.Error(), nil))
+		case "payment is already approved", "enrollment is cancelled":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to approve payment", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to approve payment", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Group payment approved successfully", enrollment))
+}
+
+func (h *handler) RejectEnrollmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input RejectEnrollmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	enrollment, err := h.service.RejectEnrollment(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Enrollment not found", err.Error(), nil))
+		case "cannot reject an already approved payment", "enrollment is cancelled":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Failed to reject payment", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reject payment", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Group payment rejected successfully", enrollment))
+}
+
+// ==========================================
+// Student Handlers
+// ==========================================
+
+func (h *handler) AcceptInvitationHandler(c *gin.Context) {
+	member, err := h.service.AcceptInvitation(c.Param("token"), getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "invitation not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Invitation not found", err.Error(), nil))
+		case "student is already registered", "no seats left for this package":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Failed to accept invitation", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to accept invitation", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Invitation accepted successfully", member))
+}
@@ -0,0 +1,544 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// memberPlan is one roster student to enroll; User.ID is 0 for accounts created from the roster.
+// A plan with an InvitationToken gets no registration until the student accepts.
+type memberPlan struct {
+	User            entities.User
+	Invited         bool
+	InvitationToken string
+}
+
+type Repository interface {
+	// Packages
+	FindTryOutByID(id uint) (entities.TryOut, error)
+	FindCourseByID(id uint) (entities.Course, error)
+
+	// Users
+	FindUserByID(id uint) (entities.User, error)
+	FindUsersByEmails(emails []string) ([]entities.User, error)
+	// FindRegisteredUserIDs returns which of the users already hold an active registration for the package
+	FindRegisteredUserIDs(tryOutID, courseID uint, userIDs []uint) (map[uint]bool, error)
+
+	// Enrollments
+	// CreateEnrollment creates the invited accounts, the enrollment, every registration and the members in one transaction
+	CreateEnrollment(enrollment *entities.GroupEnrollment, plans []memberPlan) error
+	FindEnrollmentByID(id uint) (entities.GroupEnrollment, error)
+	FindEnrollmentsByCoordinator(coordinatorUserID uint) ([]entities.GroupEnrollment, error)
+	FindEnrollments(status string) ([]entities.GroupEnrollment, error)
+	UpdateEnrollment(enrollment *entities.GroupEnrollment) error
+	// ApproveEnrollment approves the group payment, every member's registration and records the ledger charges
+	ApproveEnrollment(enrollment *entities.GroupEnrollment, adminUserID uint, at time.Time) error
+	// CancelEnrollment cancels an unpaid group, soft-deletes the member registrations and withdraws open invitations
+	CancelEnrollment(enrollmentID uint, at time.Time) error
+
+	// Invitations
+	// AcceptInvitation creates the invited student's registration under the group and returns the member ID
+	AcceptInvitation(token string, userID uint, at time.Time) (uint, error)
+	FindMemberByID(id uint) (entities.GroupEnrollmentMember, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Package Methods
+// ==========================================
+
+func (r *repository) FindTryOutByID(id uint) (entities.TryOut, error) {
+	var tryOut entities.TryOut
+	err := r.db.First(&tryOut, id).Error
+	return tryOut, err
+}
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+// ==========================================
+// User Methods
+// ==========================================
+
+func (r *repository) FindUserByID(id uint) (entities.User, error) {
+	var user entities.User
+	err := r.db.First(&user, id).Error
+	return user, err
+}
+
+func (r *repository) FindUsersByEmails(emails []string) ([]entities.User, error) {
+	var users []entities.User
+	if len(emails) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("LOWER(email) IN ?", emails).Find(&users).Error
+	return users, err
+}
+
+func (r *repository) FindRegisteredUserIDs(tryOutID, courseID uint, userIDs []uint) (map[uint]bool, error) {
+	registered := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return registered, nil
+	}
+
+	var ids []uint
+	var err error
+	if tryOutID != 0 {
+		err = r.db.Model(&entities.TryOutRegistration{}).
+			Where("try_out_package_id = ? AND user_id IN ?", tryOutID, userIDs).
+			Pluck("user_id", &ids).Error
+	} else {
+		err = r.db.Model(&entities.CourseRegistration{}).
+			Where("course_id = ? AND user_id IN ?", courseID, userIDs).
+			Pluck("user_id", &ids).Error
+	}
+	for _, id := range ids {
+		registered[id] = true
+	}
+	return registered, err
+}
+
+// ==========================================
+// Enrollment Methods
+// ==========================================
+
+func (r *repository) CreateEnrollment(enrollment *entities.GroupEnrollment, plans []memberPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Invitations hold no seat until they are accepted
+		seats := 0
+		for _, plan := range plans {
+			if plan.InvitationToken == "" {
+				seats++
+			}
+		}
+		if err := reserveSeats(tx, enrollment, seats); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		status := entities.PaymentStatusPending
+		if enrollment.Amount == 0 {
+			status = entities.PaymentStatusApproved
+			enrollment.ApprovedAt = &now
+		}
+		enrollment.PaymentStatus = status
+
+		if err := tx.Omit("Coordinator", "TryOut", "Course", "Members").Create(enrollment).Error; err != nil {
+			return err
+		}
+
+		for i := range plans {
+			plan := &plans[i]
+			if plan.User.ID == 0 {
+				if err := tx.Create(&plan.User).Error; err != nil {
+					return err
+				}
+			}
+
+			member := entities.GroupEnrollmentMember{
+				GroupEnrollmentID: enrollment.ID,
+				UserID:            plan.User.ID,
+				Invited:           plan.Invited,
+				InvitationToken:   plan.InvitationToken,
+			}
+
+			if plan.InvitationToken == "" {
+				if err := enrollMember(tx, enrollment, &member, status, now); err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Omit("User", "TryOutRegistration", "CourseRegistration").Create(&member).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reserveSeats locks the package row and checks the whole roster fits; a cohort is never split onto the waitlist
+func reserveSeats(tx *gorm.DB, enrollment *entities.GroupEnrollment, seats int) error {
+	var capacity int
+	var taken int64
+	if enrollment.TryOutID != nil {
+		var tryOut entities.TryOut
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tryOut, *enrollment.TryOutID).Error; err != nil {
+			return err
+		}
+		capacity = tryOut.Capacity
+		if err := tx.Model(&entities.TryOutRegistration{}).
+			Where("try_out_package_id = ? AND payment_status IN ?", tryOut.ID,
+				[]entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusPending}).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+	} else {
+		var course entities.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, *enrollment.CourseID).Error; err != nil {
+			return err
+		}
+		capacity = course.Capacity
+		if err := tx.Model(&entities.CourseRegistration{}).
+			Where("course_id = ? AND status IN ?", course.ID, []string{"approved", "pending"}).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+	}
+
+	if capacity > 0 && taken+int64(seats) > int64(capacity) {
+		return errors.New("not enough seats left for this roster")
+	}
+	return nil
+}
+
+// enrollMember creates the registration of the member for the group's package
+func enrollMember(tx *gorm.DB, enrollment *entities.GroupEnrollment, member *entities.GroupEnrollmentMember, status entities.PaymentStatus, now time.Time) error {
+	if enrollment.TryOutID != nil {
+		registrationID, err := enrollTryOut(tx, enrollment, member.UserID, status, now)
+		if err != nil {
+			return err
+		}
+		member.TryOutRegistrationID = &registrationID
+		return nil
+	}
+
+	registration := entities.CourseRegistration{
+		UserID:            member.UserID,
+		CourseID:          *enrollment.CourseID,
+		Status:            string(status),
+		OriginalPrice:     enrollment.PricePerSeat,
+		FinalAmount:       enrollment.PricePerSeat,
+		GroupEnrollmentID: &enrollment.ID,
+	}
+	if err := tx.Omit("User", "Course", "Answers").Create(&registration).Error; err != nil {
+		return err
+	}
+	member.CourseRegistrationID = &registration.ID
+	return nil
+}
+
+// enrollTryOut creates the member's registration, restoring a cancelled or expired one since (user, try out) is unique
+func enrollTryOut(tx *gorm.DB, enrollment *entities.GroupEnrollment, userID uint, status entities.PaymentStatus, now time.Time) (uint, error) {
+	registration := entities.TryOutRegistration{}
+	err := tx.Unscoped().Where("user_id = ? AND try_out_package_id = ?", userID, *enrollment.TryOutID).First(&registration).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	if err == nil && !registration.DeletedAt.Valid {
+		return 0, errors.New("student is already registered")
+	}
+
+	registration.UserID = userID
+	registration.TryOutPackageID = *enrollment.TryOutID
+	registration.DeletedAt = gorm.DeletedAt{}
+	registration.PaymentStatus = status
+	registration.PaymentProofURL = ""
+	registration.PaymentProofUploadedAt = nil
+	registration.RejectionReason = ""
+	registration.PaymentDueAt = nil
+	registration.PaymentReminderSentAt = nil
+	registration.ExpiredAt = nil
+	registration.OriginalPrice = enrollment.PricePerSeat
+	registration.DiscountAmount = 0
+	registration.FinalAmount = enrollment.PricePerSeat
+	registration.PromoCodeID = nil
+	registration.PromoCode = ""
+	registration.BundlePurchaseID = nil
+	registration.GroupEnrollmentID = &enrollment.ID
+	registration.ApprovedByUserID = nil
+	registration.ApprovedAt = nil
+	if status == entities.PaymentStatusApproved {
+		registration.ApprovedAt = &now
+	}
+	registration.RegisteredAt = now
+
+	if err := tx.Unscoped().Omit("User", "TryOutPackage", "ApprovedBy", "Attempt").Save(&registration).Error; err != nil {
+		return 0, err
+	}
+	return registration.ID, nil
+}
+
+func (r *repository) FindEnrollmentByID(id uint) (entities.GroupEnrollment, error) {
+	var enrollment entities.GroupEnrollment
+	err := r.db.Preload("Coordinator").
+		Preload("TryOut").
+		Preload("Course").
+		Preload("Members.User").
+		Preload("Members.TryOutRegistration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Members.TryOutRegistration.Attempt").
+		Preload("Members.CourseRegistration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		First(&enrollment, id).Error
+	return enrollment, err
+}
+
+func (r *repository) FindEnrollmentsByCoordinator(coordinatorUserID uint) ([]entities.GroupEnrollment, error) {
+	var enrollments []entities.GroupEnrollment
+	err := r.db.Where("coordinator_user_id = ?", coordinatorUserID).
+		Preload("TryOut").
+		Preload("Course").
+		Order("created_at DESC").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *repository) FindEnrollments(status string) ([]entities.GroupEnrollment, error) {
+	var enrollments []entities.GroupEnrollment
+	query := r.db.Preload("Coordinator").
+		Preload("TryOut").
+		Preload("Course").
+		Order("created_at ASC")
+	if status != "" {
+		query = query.Where("payment_status = ?", status)
+	}
+	err := query.Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *repository) UpdateEnrollment(enrollment *entities.GroupEnrollment) error {
+	return r.db.Omit("Coordinator", "TryOut", "Course", "Members").Save(enrollment).Error
+}
+
+func (r *repository) ApproveEnrollment(enrollment *entities.GroupEnrollment, adminUserID uint, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current entities.GroupEnrollment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, enrollment.ID).Error; err != nil {
+			return err
+		}
+		if current.PaymentStatus == entities.PaymentStatusApproved {
+			return errors.New("payment is already approved")
+		}
+		if current.PaymentStatus == entities.PaymentStatusCancelled {
+			return errors.New("enrollment is cancelled")
+		}
+
+		if err := tx.Model(&entities.GroupEnrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]any{
+			"payment_status":      entities.PaymentStatusApproved,
+			"rejection_reason":    "",
+			"approved_by_user_id": adminUserID,
+			"approved_at":         at,
+		}).Error; err != nil {
+			return err
+		}
+
+		if enrollment.CourseID != nil {
+			var registrations []entities.CourseRegistration
+			if err := tx.Where("group_enrollment_id = ? AND status = ?", enrollment.ID, "pending").
+				Find(&registrations).Error; err != nil {
+				return err
+			}
+			for _, registration := range registrations {
+				if err := tx.Model(&entities.CourseRegistration{}).Where("id = ?", registration.ID).
+					Update("status", "approved").Error; err != nil {
+					return err
+				}
+				if err := recordGroupCharge(tx, enrollment.ID, registration.UserID, nil, &registration, adminUserID, at); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		var registrations []entities.TryOutRegistration
+		if err := tx.Where("group_enrollment_id = ? AND payment_status = ?", enrollment.ID, entities.PaymentStatusPending).
+			Find(&registrations).Error; err != nil {
+			return err
+		}
+		for _, registration := range registrations {
+			if err := tx.Model(&entities.TryOutRegistration{}).Where("id = ?", registration.ID).Updates(map[string]any{
+				"payment_status":      entities.PaymentStatusApproved,
+				"rejection_reason":    "",
+				"approved_by_user_id": adminUserID,
+				"approved_at":         at,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := recordGroupCharge(tx, enrollment.ID, registration.UserID, &registration, nil, adminUserID, at); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) CancelEnrollment(enrollmentID uint, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current entities.GroupEnrollment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, enrollmentID).Error; err != nil {
+			return err
+		}
+		switch current.PaymentStatus {
+		case entities.PaymentStatusApproved:
+			return errors.New("cannot cancel an approved enrollment")
+		case entities.PaymentStatusCancelled:
+			return errors.New("enrollment is cancelled")
+		}
+
+		if err := tx.Model(&entities.GroupEnrollment{}).Where("id = ?", enrollmentID).
+			Update("payment_status", entities.PaymentStatusCancelled).Error; err != nil {
+			return err
+		}
+
+		// Soft-deleted like any cancelled registration, so the students can register on their own
+		if current.TryOutID != nil {
+			if err := tx.Model(&entities.TryOutRegistration{}).
+				Where("group_enrollment_id = ?", enrollmentID).
+				UpdateColumns(map[string]any{
+					"payment_status": entities.PaymentStatusCancelled,
+					"deleted_at":     at,
+				}).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Model(&entities.CourseRegistration{}).
+				Where("group_enrollment_id = ?", enrollmentID).
+				UpdateColumns(map[string]any{
+					"status":     "rejected",
+					"deleted_at": at,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entities.GroupEnrollmentMember{}).
+			Where("group_enrollment_id = ? AND invitation_token <> ?", enrollmentID, "").
+			Update("invitation_token", "").Error
+	})
+}
+
+// recordGroupCharge books one seat of a paid group against the member's registration; one charge
+// per seat keeps per-registration balances and per-package revenue correct
+func recordGroupCharge(tx *gorm.DB, enrollmentID, userID uint, tryOutRegistration *entities.TryOutRegistration,
+	courseRegistration *entities.CourseRegistration, adminUserID uint, at time.Time) error {
+	entry := entities.LedgerEntry{
+		Type:             entities.LedgerEntryCharge,
+		UserID:           userID,
+		Source:           ledger.SourceGroup,
+		Reference:        fmt.Sprintf("GROUP-%d", enrollmentID),
+		RecordedByUserID: &adminUserID,
+		OccurredAt:       at,
+	}
+	if tryOutRegistration != nil {
+		registrationID := tryOutRegistration.ID
+		tryOutID := tryOutRegistration.TryOutPackageID
+		entry.Amount = tryOutRegistration.FinalAmount
+		entry.RegistrationID = &registrationID
+		entry.TryOutPackageID = &tryOutID
+	} else {
+		registrationID := courseRegistration.ID
+		courseID := courseRegistration.CourseID
+		entry.Amount = courseRegistration.FinalAmount
+		entry.CourseRegistrationID = &registrationID
+		entry.CourseID = &courseID
+	}
+	if entry.Amount <= 0 {
+		return nil
+	}
+	return tx.Create(&entry).Error
+}
+
+// ==========================================
+// Invitation Methods
+// ==========================================
+
+func (r *repository) AcceptInvitation(token string, userID uint, at time.Time) (uint, error) {
+	var memberID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var member entities.GroupEnrollmentMember
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("invitation_token = ? AND user_id = ?", token, userID).
+			First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invitation not found")
+			}
+			return err
+		}
+
+		var enrollment entities.GroupEnrollment
+		if err := tx.First(&enrollment, member.GroupEnrollmentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("invitation not found")
+			}
+			return err
+		}
+
+		// The course path has no unique index to stop a second registration
+		if enrollment.CourseID != nil {
+			var count int64
+			if err := tx.Model(&entities.CourseRegistration{}).
+				Where("course_id = ? AND user_id = ?", *enrollment.CourseID, userID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.New("student is already registered")
+			}
+		}
+		if err := reserveSeats(tx, &enrollment, 1); err != nil {
+			if err.Error() == "not enough seats left for this roster" {
+				return errors.New("no seats left for this package")
+			}
+			return err
+		}
+
+		// The seat was paid for with the group; it is approved once the group payment is
+		status := entities.PaymentStatusPending
+		if enrollment.PaymentStatus == entities.PaymentStatusApproved {
+			status = entities.PaymentStatusApproved
+		}
+		if err := enrollMember(tx, &enrollment, &member, status, at); err != nil {
+			return err
+		}
+		if status == entities.PaymentStatusApproved && enrollment.ApprovedByUserID != nil {
+			var err error
+			if member.TryOutRegistrationID != nil {
+				var registration entities.TryOutRegistration
+				if err = tx.First(&registration, *member.TryOutRegistrationID).Error; err == nil {
+					err = recordGroupCharge(tx, enrollment.ID, userID, &registration, nil, *enrollment.ApprovedByUserID, at)
+				}
+			} else {
+				var registration entities.CourseRegistration
+				if err = tx.First(&registration, *member.CourseRegistrationID).Error; err == nil {
+					err = recordGroupCharge(tx, enrollment.ID, userID, nil, &registration, *enrollment.ApprovedByUserID, at)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		member.InvitationToken = ""
+		member.InvitationAcceptedAt = &at
+		if err := tx.Omit("User", "TryOutRegistration", "CourseRegistration").Save(&member).Error; err != nil {
+			return err
+		}
+		memberID = member.ID
+		return nil
+	})
+	return memberID, err
+}
+
+func (r *repository) FindMemberByID(id uint) (entities.GroupEnrollmentMember, error) {
+	var member entities.GroupEnrollmentMember
+	err := r.db.Preload("User").
+		Preload("TryOutRegistration").
+		Preload("CourseRegistration").
+		First(&member, id).Error
+	return member, err
+}
@@ -0,0 +1,146 @@
+package groups
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/mail"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxRosterRows caps how many students one roster may enroll
+const maxRosterRows = 500
+
+// kelasOptions are the class values accepted on User.Kelas
+var kelasOptions = []string{"Kelas 10", "Kelas 11", "Kelas 12", "Gapyer (Alumni)"}
+
+// rosterRow is one student read from the roster file
+type rosterRow struct {
+	Row   int // 1-based row number in the file, header included
+	Name  string
+	Email string
+	Kelas *string
+	Phone string
+}
+
+// rosterColumns maps accepted header names (lower case) to a field
+var rosterColumns = map[string]string{
+	"name":      "name",
+	"nama":      "name",
+	"full name": "name",
+	"email":     "email",
+	"e-mail":    "email",
+	"class":     "kelas",
+	"kelas":     "kelas",
+	"phone":     "phone",
+	"no telp":   "phone",
+	"notelp":    "phone",
+	"whatsapp":  "phone",
+}
+
+// parseRoster reads an .xlsx or .csv roster whose first row is a header with at least an Email column
+func parseRoster(fileName string, r io.Reader) ([]rosterRow, error) {
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, errors.New("roster file could not be read")
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("roster file is empty")
+		}
+		records, err = f.GetRows(sheets[0])
+		if err != nil {
+			return nil, errors.New("roster file could not be read")
+		}
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		var err error
+		records, err = reader.ReadAll()
+		if err != nil {
+			return nil, errors.New("roster file could not be read")
+		}
+	default:
+		return nil, errors.New("roster must be an .xlsx or .csv file")
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("roster file is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if field, ok := rosterColumns[strings.ToLower(strings.TrimSpace(header))]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("roster must have an email column")
+	}
+
+	cell := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []rosterRow
+	for i, record := range records[1:] {
+		row := rosterRow{
+			Row:   i + 2,
+			Name:  cell(record, "name"),
+			Email: strings.ToLower(cell(record, "email")),
+			Phone: cell(record, "phone"),
+		}
+		if row.Email == "" && row.Name == "" {
+			continue // blank line
+		}
+		if kelas := normalizeKelas(cell(record, "kelas")); kelas != "" {
+			row.Kelas = &kelas
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("roster file is empty")
+	}
+	if len(rows) > maxRosterRows {
+		return nil, errors.New("roster has too many students")
+	}
+	return rows, nil
+}
+
+// validEmail reports whether s is a bare email address
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+// normalizeKelas matches "12", "XII", "kelas 12" or "alumni" to a User.Kelas value; unknown values are dropped
+func normalizeKelas(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	v = strings.TrimPrefix(v, "kelas ")
+	switch v {
+	case "10", "x":
+		return kelasOptions[0]
+	case "11", "xi":
+		return kelasOptions[1]
+	case "12", "xii":
+		return kelasOptions[2]
+	case "alumni", "gapyer", "gapyer (alumni)":
+		return kelasOptions[3]
+	}
+	return ""
+}
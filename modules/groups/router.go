@@ -0,0 +1,43 @@
+package groups
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+)
+
+func GroupRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc, requireCoordinator gin.HandlerFunc) {
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	service := NewService(repo, waitlists.NewService(waitlists.NewRepository(db)))
+	handler := NewHandler(service)
+
+	// Coordinator: enroll a school roster and pay for it as one group
+	coordinatorRoutes := router.Group("/coordinator/enrollments")
+	coordinatorRoutes.Use(requireAuth, requireCoordinator)
+	{
+		coordinatorRoutes.POST("", handler.EnrollHandler)
+		coordinatorRoutes.GET("", handler.GetMyEnrollmentsHandler)
+		coordinatorRoutes.GET("/:id", handler.GetEnrollmentHandler)
+		coordinatorRoutes.POST("/:id/payment-proof", handler.UploadPaymentProofHandler)
+		coordinatorRoutes.POST("/:id/cancel", handler.CancelEnrollmentHandler)
+	}
+
+	// Admin: review group payments
+	adminRoutes := router.Group("/group-enrollments")
+	adminRoutes.Use(requireAuth, requireAdmin)
+	{
+		adminRoutes.GET("", handler.GetEnrollmentsHandler)
+		adminRoutes.GET("/:id", handler.GetEnrollmentHandler)
+		adminRoutes.PUT("/:id/approve", handler.ApproveEnrollmentHandler)
+		adminRoutes.PUT("/:id/reject", handler.RejectEnrollmentHandler)
+		adminRoutes.PUT("/:id/cancel", handler.CancelEnrollmentHandler)
+	}
+
+	// Student: accept an invitation sent to an account from another school
+	invitationRoutes := router.Group("/group-invitations")
+	invitationRoutes.Use(requireAuth)
+	{
+		invitationRoutes.POST("/:token/accept", handler.AcceptInvitationHandler)
+	}
+}
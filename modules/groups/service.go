@@ -0,0 +1,578 @@
+package groups
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// invitationValidity is how long the set-password link in an invitation email works
+const invitationValidity = 7 * 24 * time.Hour
+
+type groupService struct {
+	repo      Repository
+	waitlists waitlists.Service
+}
+
+type Service interface {
+	// Coordinator actions
+	Enroll(input EnrollInput, fileName string, roster io.Reader, coordinatorUserID uint, requestID string) (*EnrollResponse, error)
+	GetMyEnrollments(coordinatorUserID uint, requestID string) ([]EnrollmentResponse, error)
+	GetEnrollment(id uint, userID uint, isAdmin bool, requestID string) (*EnrollmentResponse, error)
+	UploadPaymentProof(id uint, input UploadPaymentProofInput, coordinatorUserID uint, requestID string) (*EnrollmentResponse, error)
+	// CancelEnrollment releases the seats of an unpaid group; coordinators cancel their own, admins any
+	CancelEnrollment(id uint, userID uint, isAdmin bool, requestID string) (*EnrollmentResponse, error)
+
+	// Admin actions
+	GetEnrollments(status string, requestID string) ([]EnrollmentResponse, error)
+	ApproveEnrollment(id uint, adminUserID uint, requestID string) (*EnrollmentResponse, error)
+	RejectEnrollment(id uint, input RejectEnrollmentInput, adminUserID uint, requestID string) (*EnrollmentResponse, error)
+
+	// Student actions
+	AcceptInvitation(token string, userID uint, requestID string) (*MemberResponse, error)
+}
+
+func NewService(repo Repository, waitlistService waitlists.Service) Service {
+	return &groupService{repo: repo, waitlists: waitlistService}
+}
+
+// invitation is an email to send once the enrollment is stored
+type invitation struct {
+	User        entities.User
+	Token       string // empty for students who already had an account
+	AcceptToken string // set for existing accounts that must accept before being enrolled
+}
+
+// ==========================================
+// Coordinator Actions
+// ==========================================
+
+func (s *groupService) Enroll(input EnrollInput, fileName string, roster io.Reader, coordinatorUserID uint, requestID string) (*EnrollResponse, error) {
+	utils.LogInfo("groups", "enroll", "Coordinator enrolling a roster", requestID, coordinatorUserID, map[string]any{
+		"try_out_id": input.TryOutID,
+		"course_id":  input.CourseID,
+		"file":       fileName,
+	})
+
+	if (input.TryOutID == 0) == (input.CourseID == 0) {
+		return nil, errors.New("exactly one of tryOutId or courseId is required")
+	}
+
+	coordinator, err := s.repo.FindUserByID(coordinatorUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment := entities.GroupEnrollment{
+		CoordinatorUserID: coordinatorUserID,
+		School:            coordinator.School,
+		Label:             strings.TrimSpace(input.Label),
+	}
+	var packageName string
+	if input.TryOutID != 0 {
+		tryOut, err := s.repo.FindTryOutByID(input.TryOutID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("try out not found")
+			}
+			return nil, err
+		}
+		now := time.Now()
+		if !tryOut.IsPublished {
+			return nil, errors.New("try out not found")
+		}
+		if now.Before(tryOut.RegistrationStart) {
+			return nil, errors.New("registration has not started yet")
+		}
+		if now.After(tryOut.RegistrationEnd) {
+			return nil, errors.New("registration period has ended")
+		}
+		enrollment.TryOutID = &tryOut.ID
+		if !tryOut.IsFree {
+			enrollment.PricePerSeat = tryOut.Price
+		}
+		packageName = tryOut.Name
+	} else {
+		course, err := s.repo.FindCourseByID(input.CourseID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("course not found")
+			}
+			return nil, err
+		}
+		enrollment.CourseID = &course.ID
+		if !course.IsFree {
+			enrollment.PricePerSeat = course.Price
+		}
+		packageName = course.NameCourse
+	}
+
+	rows, err := parseRoster(fileName, roster)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match roster emails with existing accounts
+	var emails []string
+	for _, row := range rows {
+		if validEmail(row.Email) {
+			emails = append(emails, row.Email)
+		}
+	}
+	existingUsers, err := s.repo.FindUsersByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+	usersByEmail := make(map[string]entities.User, len(existingUsers))
+	var existingIDs []uint
+	for _, u := range existingUsers {
+		usersByEmail[strings.ToLower(u.Email)] = u
+		existingIDs = append(existingIDs, u.ID)
+	}
+	registered, err := s.repo.FindRegisteredUserIDs(input.TryOutID, input.CourseID, existingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EnrollResponse{Rows: make([]RosterRowResult, 0, len(rows))}
+	var plans []memberPlan
+	var invitations []invitation
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		result := RosterRowResult{Row: row.Row, Name: row.Name, Email: row.Email}
+
+		switch {
+		case !validEmail(row.Email):
+			result.Status, result.Message = "error", "invalid email address"
+			response.Errors++
+		case seen[row.Email]:
+			result.Status, result.Message = "skipped", "duplicate email in roster"
+			response.Skipped++
+		default:
+			seen[row.Email] = true
+			user, exists := usersByEmail[row.Email]
+			switch {
+			case exists && registered[user.ID]:
+				result.Status, result.Message = "skipped", "already registered"
+				response.Skipped++
+			case exists && sameSchool(user, coordinator):
+				result.Status = "enrolled"
+				response.Enrolled++
+				plans = append(plans, memberPlan{User: user})
+			case exists:
+				// Not verified as a student of this school: the account owner decides
+				token, err := randomToken()
+				if err != nil {
+					return nil, err
+				}
+				result.Status, result.Message = "invited", "enrolled once the student accepts the invitation"
+				response.Invited++
+				plans = append(plans, memberPlan{User: user, InvitationToken: token})
+			default:
+				newUser, token, err := newInvitedUser(row, coordinator)
+				if err != nil {
+					return nil, err
+				}
+				result.Name = newUser.Username
+				result.Status = "invited"
+				response.Invited++
+				plans = append(plans, memberPlan{User: newUser, Invited: true})
+				invitations = append(invitations, invitation{Token: token})
+			}
+		}
+		response.Rows = append(response.Rows, result)
+	}
+
+	if len(plans) == 0 {
+		return response, errors.New("roster has no students to enroll")
+	}
+
+	enrollment.Seats = len(plans)
+	enrollment.Amount = enrollment.PricePerSeat * float64(enrollment.Seats)
+	if err := s.repo.CreateEnrollment(&enrollment, plans); err != nil {
+		utils.LogError("groups", "enroll", "Failed to create enrollment: "+err.Error(), requestID, coordinatorUserID, nil)
+		return nil, err
+	}
+
+	// Pair invitations with the created accounts; existing students get a notice or an invitation to accept
+	var notices []invitation
+	invited := 0
+	for _, plan := range plans {
+		if plan.Invited {
+			invitations[invited].User = plan.User
+			invited++
+			continue
+		}
+		notices = append(notices, invitation{User: plan.User, AcceptToken: plan.InvitationToken})
+	}
+	go sendEnrollmentEmails(append(invitations, notices...), coordinator, packageName, requestID)
+
+	utils.LogSuccess("groups", "enroll", "Roster enrolled", requestID, coordinatorUserID, map[string]any{
+		"enrollment_id": enrollment.ID,
+		"enrolled":      response.Enrolled,
+		"invited":       response.Invited,
+		"skipped":       response.Skipped,
+		"errors":        response.Errors,
+		"amount":        enrollment.Amount,
+	})
+
+	created, err := s.repo.FindEnrollmentByID(enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	enrollmentResponse := ToEnrollmentResponse(created, true)
+	response.Enrollment = &enrollmentResponse
+	return response, nil
+}
+
+func (s *groupService) GetMyEnrollments(coordinatorUserID uint, requestID string) ([]EnrollmentResponse, error) {
+	enrollments, err := s.repo.FindEnrollmentsByCoordinator(coordinatorUserID)
+	if err != nil {
+		utils.LogError("groups", "get_my_enrollments", "Failed to fetch enrollments: "+err.Error(), requestID, coordinatorUserID, nil)
+		return nil, err
+	}
+
+	responses := make([]EnrollmentResponse, 0, len(enrollments))
+	for _, e := range enrollments {
+		responses = append(responses, ToEnrollmentResponse(e, false))
+	}
+	return responses, nil
+}
+
+func (s *groupService) GetEnrollment(id uint, userID uint, isAdmin bool, requestID string) (*EnrollmentResponse, error) {
+	enrollment, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	// Coordinators only ever see their own cohorts
+	if !isAdmin && enrollment.CoordinatorUserID != userID {
+		return nil, errors.New("enrollment not found")
+	}
+
+	response := ToEnrollmentResponse(enrollment, true)
+	return &response, nil
+}
+
+func (s *groupService) UploadPaymentProof(id uint, input UploadPaymentProofInput, coordinatorUserID uint, requestID string) (*EnrollmentResponse, error) {
+	enrollment, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	if enrollment.CoordinatorUserID != coordinatorUserID {
+		return nil, errors.New("enrollment not found")
+	}
+	if enrollment.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+	if enrollment.PaymentStatus == entities.PaymentStatusCancelled {
+		return nil, errors.New("enrollment is cancelled")
+	}
+
+	enrollment.PaymentProofURL = input.PaymentProofURL
+	enrollment.PaymentStatus = entities.PaymentStatusPending
+	enrollment.RejectionReason = ""
+	if err := s.repo.UpdateEnrollment(&enrollment); err != nil {
+		utils.LogError("groups", "upload_payment_proof", "Failed to save payment proof: "+err.Error(), requestID, coordinatorUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("groups", "upload_payment_proof", "Group payment proof uploaded", requestID, coordinatorUserID, map[string]any{
+		"enrollment_id": id,
+	})
+
+	response := ToEnrollmentResponse(enrollment, true)
+	return &response, nil
+}
+
+func (s *groupService) CancelEnrollment(id uint, userID uint, isAdmin bool, requestID string) (*EnrollmentResponse, error) {
+	utils.LogInfo("groups", "cancel", "Cancelling group enrollment", requestID, userID, map[string]any{
+		"enrollment_id": id,
+	})
+
+	enrollment, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	if !isAdmin && enrollment.CoordinatorUserID != userID {
+		return nil, errors.New("enrollment not found")
+	}
+
+	if err := s.repo.CancelEnrollment(id, time.Now()); err != nil {
+		utils.LogError("groups", "cancel", "Failed to cancel group enrollment: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("groups", "cancel", "Group enrollment cancelled", requestID, userID, map[string]any{
+		"enrollment_id": id,
+		"seats":         enrollment.Seats,
+	})
+
+	// The released seats go to the waitlist
+	if enrollment.TryOutID != nil {
+		if _, err := s.waitlists.PromoteTryOut(*enrollment.TryOutID, requestID); err != nil {
+			utils.LogError("groups", "cancel", "Failed to promote waitlist: "+err.Error(), requestID, userID, map[string]any{
+				"try_out_id": *enrollment.TryOutID,
+			})
+		}
+	} else if enrollment.CourseID != nil {
+		if _, err := s.waitlists.PromoteCourse(*enrollment.CourseID, requestID); err != nil {
+			utils.LogError("groups", "cancel", "Failed to promote waitlist: "+err.Error(), requestID, userID, map[string]any{
+				"course_id": *enrollment.CourseID,
+			})
+		}
+	}
+
+	updated, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin && updated.CoordinatorUserID != userID {
+		notifyCoordinator(updated, "Group Enrollment Cancelled",
+			"The enrollment was cancelled because it was not paid. The students' seats have been released.", requestID)
+	}
+
+	response := ToEnrollmentResponse(updated, true)
+	return &response, nil
+}
+
+// ==========================================
+// Admin Actions
+// ==========================================
+
+func (s *groupService) GetEnrollments(status string, requestID string) ([]EnrollmentResponse, error) {
+	enrollments, err := s.repo.FindEnrollments(status)
+	if err != nil {
+		utils.LogError("groups", "get_enrollments", "Failed to fetch enrollments: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]EnrollmentResponse, 0, len(enrollments))
+	for _, e := range enrollments {
+		responses = append(responses, ToEnrollmentResponse(e, false))
+	}
+	return responses, nil
+}
+
+func (s *groupService) ApproveEnrollment(id uint, adminUserID uint, requestID string) (*EnrollmentResponse, error) {
+	utils.LogInfo("groups", "approve", "Admin approving group payment", requestID, adminUserID, map[string]any{
+		"enrollment_id": id,
+	})
+
+	enrollment, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	if enrollment.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+
+	if err := s.repo.ApproveEnrollment(&enrollment, adminUserID, time.Now()); err != nil {
+		utils.LogError("groups", "approve", "Failed to approve group payment: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("groups", "approve", "Group payment approved", requestID, adminUserID, map[string]any{
+		"enrollment_id": id,
+		"seats":         enrollment.Seats,
+	})
+
+	updated, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	notifyCoordinator(updated, "Group Payment Approved",
+		fmt.Sprintf("The payment for %d students has been approved. They now have access.", updated.Seats), requestID)
+
+	response := ToEnrollmentResponse(updated, true)
+	return &response, nil
+}
+
+func (s *groupService) RejectEnrollment(id uint, input RejectEnrollmentInput, adminUserID uint, requestID string) (*EnrollmentResponse, error) {
+	enrollment, err := s.repo.FindEnrollmentByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	if enrollment.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("cannot reject an already approved payment")
+	}
+	if enrollment.PaymentStatus == entities.PaymentStatusCancelled {
+		return nil, errors.New("enrollment is cancelled")
+	}
+
+	enrollment.PaymentStatus = entities.PaymentStatusRejected
+	enrollment.RejectionReason = input.RejectionReason
+	enrollment.PaymentProofURL = "" // Clear proof so the coordinator can re-upload
+	if err := s.repo.UpdateEnrollment(&enrollment); err != nil {
+		utils.LogError("groups", "reject", "Failed to reject group payment: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("groups", "reject", "Group payment rejected", requestID, adminUserID, map[string]any{
+		"enrollment_id": id,
+		"reason":        input.RejectionReason,
+	})
+
+	message := "The payment proof was rejected. Please upload a new one."
+	if input.RejectionReason != "" {
+		message = "The payment proof was rejected: " + input.RejectionReason + ". Please upload a new one."
+	}
+	notifyCoordinator(enrollment, "Group Payment Rejected", message, requestID)
+
+	response := ToEnrollmentResponse(enrollment, true)
+	return &response, nil
+}
+
+// ==========================================
+// Student Actions
+// ==========================================
+
+func (s *groupService) AcceptInvitation(token string, userID uint, requestID string) (*MemberResponse, error) {
+	if token == "" {
+		return nil, errors.New("invitation not found")
+	}
+
+	memberID, err := s.repo.AcceptInvitation(token, userID, time.Now())
+	if err != nil {
+		utils.LogWarning("groups", "accept_invitation", "Failed to accept invitation: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("groups", "accept_invitation", "Group invitation accepted", requestID, userID, map[string]any{
+		"member_id": memberID,
+	})
+
+	member, err := s.repo.FindMemberByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToMemberResponse(member)
+	return &response, nil
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// sameSchool reports whether an admin verified the student at the coordinator's school
+func sameSchool(user, coordinator entities.User) bool {
+	return coordinator.VerifiedSchool != "" && strings.EqualFold(user.VerifiedSchool, coordinator.VerifiedSchool)
+}
+
+// newInvitedUser builds an unverified student account; the returned token lets them set a password
+func newInvitedUser(row rosterRow, coordinator entities.User) (entities.User, string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return entities.User{}, "", err
+	}
+	// Nobody knows this password; the student sets their own through the invitation link
+	placeholder, err := randomToken()
+	if err != nil {
+		return entities.User{}, "", err
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(placeholder), bcrypt.DefaultCost)
+	if err != nil {
+		return entities.User{}, "", err
+	}
+
+	name := row.Name
+	if name == "" {
+		name = strings.Split(row.Email, "@")[0]
+	}
+	role := "STUDENT"
+	expiry := time.Now().Add(invitationValidity)
+	return entities.User{
+		Username:                 name,
+		Email:                    row.Email,
+		Password:                 string(passwordHash),
+		NoTelp:                   row.Phone,
+		Kelas:                    row.Kelas,
+		School:                   coordinator.School,
+		VerifiedSchool:           coordinator.VerifiedSchool,
+		Role:                     &role,
+		AuthProvider:             "PASSWORD",
+		IsVerified:               false,
+		ResetPasswordToken:       token,
+		ResetPasswordTokenExpiry: &expiry,
+	}, token, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// sendEnrollmentEmails invites new accounts and tells existing students they were enrolled
+func sendEnrollmentEmails(invitations []invitation, coordinator entities.User, packageName string, requestID string) {
+	enrolledBy := coordinator.Username
+	if coordinator.School != "" {
+		enrolledBy = coordinator.School
+	}
+	frontendURL := strings.TrimRight(os.Getenv("FRONTEND_URL"), "/")
+
+	for _, inv := range invitations {
+		subject := "You have been enrolled in " + packageName
+		body := fmt.Sprintf("Hi %s, %s has enrolled you in %s on Reduka.", inv.User.Username, enrolledBy, packageName)
+		if inv.AcceptToken != "" {
+			subject = "Invitation to join " + packageName
+			body = fmt.Sprintf(
+				"Hi %s, %s has invited you to %s on Reduka. "+
+					"You will be enrolled once you accept the invitation: %s/group-invitations/%s",
+				inv.User.Username, enrolledBy, packageName, frontendURL, inv.AcceptToken,
+			)
+		}
+		if inv.Token != "" {
+			subject = "Invitation to Reduka - " + packageName
+			body += fmt.Sprintf(
+				" An account has been created for you with this email address. "+
+					"Set your password to activate it: %s/reset-password?token=%s (valid for 7 days).",
+				frontendURL, inv.Token,
+			)
+		}
+		if err := utils.SendEmail(inv.User.Email, subject, body); err != nil {
+			utils.LogWarning("groups", "enroll", "Failed to send enrollment email: "+err.Error(), requestID, inv.User.ID, nil)
+		}
+	}
+}
+
+func notifyCoordinator(enrollment entities.GroupEnrollment, subject, message, requestID string) {
+	if enrollment.Coordinator.Email == "" {
+		return
+	}
+	name := enrollment.Label
+	if name == "" {
+		name = fmt.Sprintf("group #%d", enrollment.ID)
+	}
+	body := fmt.Sprintf("Hi %s, update for %s: %s", enrollment.Coordinator.Username, name, message)
+	if err := utils.SendEmail(enrollment.Coordinator.Email, subject, body); err != nil {
+		utils.LogWarning("groups", "notify", "Failed to notify coordinator: "+err.Error(), requestID, enrollment.CoordinatorUserID, nil)
+	}
+}
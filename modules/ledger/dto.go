@@ -0,0 +1,153 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// LEDGER DTOs
+// ==========================================
+
+// LedgerEntryResponse is the response DTO for a ledger entry
+type LedgerEntryResponse struct {
+	ID                   uint      `json:"id"`
+	Type                 string    `json:"type"`
+	Amount               float64   `json:"amount"`
+	UserID               uint      `json:"userId"`
+	RegistrationID       *uint     `json:"registrationId,omitempty"`
+	BundlePurchaseID     *uint     `json:"bundlePurchaseId,omitempty"`
+	CourseRegistrationID *uint     `json:"courseRegistrationId,omitempty"`
+	Source               string    `json:"source"`
+	Reference            string    `json:"reference,omitempty"`
+	Note                 string    `json:"note,omitempty"`
+	RecordedByUserID     *uint     `json:"recordedByUserId,omitempty"`
+	OccurredAt           time.Time `json:"occurredAt"`
+}
+
+// RegistrationLedgerResponse lists the money movements of one registration
+type RegistrationLedgerResponse struct {
+	RegistrationID uint                  `json:"registrationId"`
+	Balance        float64               `json:"balance"`
+	Entries        []LedgerEntryResponse `json:"entries"`
+}
+
+// CreateAdjustmentInput is the input for a manual ledger correction
+type CreateAdjustmentInput struct {
+	Amount float64 `json:"amount" binding:"required,ne=0"`
+	Note   string  `json:"note" binding:"required"`
+}
+
+// ==========================================
+// CANCELLATION DTOs
+// ==========================================
+
+// RequestCancellationInput is the input for a student cancelling a registration
+type RequestCancellationInput struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ApproveCancellationInput is the input for approving a cancellation.
+// RefundAmount defaults to everything paid; use a smaller amount for a partial refund.
+type ApproveCancellationInput struct {
+	RefundAmount *float64 `json:"refundAmount" binding:"omitempty,gte=0"`
+	AdminNote    string   `json:"adminNote"`
+}
+
+// RejectCancellationInput is the input for rejecting a cancellation
+type RejectCancellationInput struct {
+	AdminNote string `json:"adminNote" binding:"required"`
+}
+
+// CancellationResponse is the response DTO for a cancellation request
+type CancellationResponse struct {
+	ID             uint       `json:"id"`
+	RegistrationID uint       `json:"registrationId"`
+	TryOutID       uint       `json:"tryOutId,omitempty"`
+	TryOutName     string     `json:"tryOutName,omitempty"`
+	UserID         uint       `json:"userId"`
+	Username       string     `json:"username,omitempty"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	AmountPaid     float64    `json:"amountPaid"`
+	RefundAmount   float64    `json:"refundAmount"`
+	AdminNote      string     `json:"adminNote,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewedAt,omitempty"`
+	RequestedAt    time.Time  `json:"requestedAt"`
+}
+
+// ==========================================
+// RECONCILIATION DTOs
+// ==========================================
+
+// ReconciliationFilter selects the period (YYYY-MM-DD, inclusive) and optionally one try out
+type ReconciliationFilter struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	TryOutID uint   `form:"tryOutId"`
+}
+
+// ReconciliationRow is the revenue of one package in the period
+type ReconciliationRow struct {
+	PackageType string  `json:"packageType"` // tryout, course or bundle
+	PackageID   uint    `json:"packageId"`
+	PackageName string  `json:"packageName"`
+	Charges     float64 `json:"charges"`
+	Refunds     float64 `json:"refunds"`
+	Adjustments float64 `json:"adjustments"`
+	Net         float64 `json:"net"`
+	Entries     int64   `json:"entries"`
+}
+
+// ReconciliationResponse totals revenue per package for a period
+type ReconciliationResponse struct {
+	From   time.Time           `json:"from"`
+	To     time.Time           `json:"to"`
+	Rows   []ReconciliationRow `json:"rows"`
+	Totals ReconciliationRow   `json:"totals"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToLedgerEntryResponse(e entities.LedgerEntry) LedgerEntryResponse {
+	return LedgerEntryResponse{
+		ID:                   e.ID,
+		Type:                 string(e.Type),
+		Amount:               e.Amount,
+		UserID:               e.UserID,
+		RegistrationID:       e.RegistrationID,
+		BundlePurchaseID:     e.BundlePurchaseID,
+		CourseRegistrationID: e.CourseRegistrationID,
+		Source:               e.Source,
+		Reference:            e.Reference,
+		Note:                 e.Note,
+		RecordedByUserID:     e.RecordedByUserID,
+		OccurredAt:           e.OccurredAt,
+	}
+}
+
+func ToCancellationResponse(c entities.CancellationRequest, amountPaid float64) CancellationResponse {
+	response := CancellationResponse{
+		ID:             c.ID,
+		RegistrationID: c.RegistrationID,
+		UserID:         c.UserID,
+		Reason:         c.Reason,
+		Status:         string(c.Status),
+		AmountPaid:     amountPaid,
+		RefundAmount:   c.RefundAmount,
+		AdminNote:      c.AdminNote,
+		ReviewedAt:     c.ReviewedAt,
+		RequestedAt:    c.RequestedAt,
+	}
+	if c.Registration.ID != 0 {
+		response.TryOutID = c.Registration.TryOutPackageID
+		response.TryOutName = c.Registration.TryOutPackage.Name
+	}
+	if c.User.ID != 0 {
+		response.Username = c.User.Username
+	}
+	return response
+}
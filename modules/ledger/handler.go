@@ -0,0 +1,260 @@
+package ledger
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	// Admin endpoints
+	GetCancellationsHandler(c *gin.Context)
+	ApproveCancellationHandler(c *gin.Context)
+	RejectCancellationHandler(c *gin.Context)
+	GetRegistrationLedgerHandler(c *gin.Context)
+	CreateAdjustmentHandler(c *gin.Context)
+	GetReconciliationReportHandler(c *gin.Context)
+
+	// User endpoints
+	RequestCancellationHandler(c *gin.Context)
+	GetMyCancellationsHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) GetCancellationsHandler(c *gin.Context) {
+	status := c.Query("status")
+	switch entities.CancellationStatus(status) {
+	case "", entities.CancellationStatusPending, entities.CancellationStatusApproved, entities.CancellationStatusRejected:
+	default:
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", "status must be pending, approved or rejected", nil))
+		return
+	}
+
+	requests, err := h.service.GetCancellations(status, getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch cancellation requests", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Cancellation requests retrieved successfully", requests))
+}
+
+func (h *handler) ApproveCancellationHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input ApproveCancellationInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+			return
+		}
+	}
+
+	request, err := h.service.ApproveCancellation(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "cancellation request not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Cancellation request not found", err.Error(), nil))
+		case "cancellation request is not pending":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already reviewed", err.Error(), nil))
+		case "refund cannot exceed the amount paid":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid refund", err.Error(), nil))
+		case "registration cannot be cancelled", "cannot cancel after starting the try out":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Cannot cancel", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to approve cancellation", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Cancellation approved successfully", request))
+}
+
+func (h *handler) RejectCancellationHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input RejectCancellationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	request, err := h.service.RejectCancellation(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "cancellation request not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Cancellation request not found", err.Error(), nil))
+		case "cancellation request is not pending":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already reviewed", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reject cancellation", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Cancellation rejected successfully", request))
+}
+
+func (h *handler) GetRegistrationLedgerHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.GetRegistrationLedger(id, getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch ledger", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Ledger retrieved successfully", ledger))
+}
+
+func (h *handler) CreateAdjustmentHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input CreateAdjustmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	entry, err := h.service.CreateAdjustment(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "registration not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
+		case "adjustment would make the balance negative":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid adjustment", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to record adjustment", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Adjustment recorded successfully", entry))
+}
+
+func (h *handler) GetReconciliationReportHandler(c *gin.Context) {
+	var filter ReconciliationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		return
+	}
+
+	report, err := h.service.GetReconciliationReport(filter, getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "from must be a date in YYYY-MM-DD format", "to must be a date in YYYY-MM-DD format", "to must not be before from":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to build reconciliation report", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Reconciliation report retrieved successfully", report))
+}
+
+// ==========================================
+// User Handlers
+// ==========================================
+
+func (h *handler) RequestCancellationHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input RequestCancellationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	request, err := h.service.RequestCancellation(id, input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "registration not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
+		case "you can only cancel your own registration":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Access denied", err.Error(), nil))
+		case "a cancellation request is already pending":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Already requested", err.Error(), nil))
+		case "registration cannot be cancelled", "cannot cancel after starting the try out",
+			"registrations granted by a bundle cannot be cancelled individually",
+			"registrations enrolled by a school cannot be cancelled individually":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Cannot cancel", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to request cancellation", err.Error(), nil))
+		}
+		return
+	}
+
+	if request.Status == string(entities.CancellationStatusApproved) {
+		c.JSON(http.StatusOK, utils.BuildResponseSuccess("Registration cancelled successfully", request))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Cancellation request submitted successfully", request))
+}
+
+func (h *handler) GetMyCancellationsHandler(c *gin.Context) {
+	requests, err := h.service.GetMyCancellations(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch cancellation requests", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Cancellation requests retrieved successfully", requests))
+}
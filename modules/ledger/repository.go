@@ -0,0 +1,271 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// packageTotals is one aggregated row of the reconciliation query
+type packageTotals struct {
+	TryOutPackageID *uint
+	CourseID        *uint
+	BundleID        *uint
+	Charges         float64
+	Refunds         float64
+	Adjustments     float64
+	Net             float64
+	Entries         int64
+}
+
+type Repository interface {
+	// Entries
+	CreateEntry(entry *entities.LedgerEntry) error
+	FindEntriesByRegistration(registrationID uint) ([]entities.LedgerEntry, error)
+	RegistrationBalance(registrationID uint) (float64, error)
+	SumByPackage(from, to time.Time, tryOutID uint) ([]packageTotals, error)
+
+	// Registrations
+	FindRegistrationByID(id uint) (entities.TryOutRegistration, error)
+
+	// Cancellation requests
+	FindCancellationByID(id uint) (entities.CancellationRequest, error)
+	FindPendingCancellationByRegistration(registrationID uint) (entities.CancellationRequest, error)
+	FindCancellationsByUserID(userID uint) ([]entities.CancellationRequest, error)
+	FindCancellations(status string) ([]entities.CancellationRequest, error)
+	CreateCancellation(request *entities.CancellationRequest) error
+	UpdateCancellation(request *entities.CancellationRequest) error
+	// CancelWithoutPayment records an auto-approved request and cancels an unpaid registration
+	CancelWithoutPayment(request *entities.CancellationRequest) error
+	// ApproveCancellation locks the request, records the refund and cancels the registration in one transaction
+	ApproveCancellation(request *entities.CancellationRequest, refund *entities.LedgerEntry) error
+
+	// Lookups
+	FindTryOutNames(ids []uint) (map[uint]string, error)
+	FindCourseNames(ids []uint) (map[uint]string, error)
+	FindBundleNames(ids []uint) (map[uint]string, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Entry Methods
+// ==========================================
+
+func (r *repository) CreateEntry(entry *entities.LedgerEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *repository) FindEntriesByRegistration(registrationID uint) ([]entities.LedgerEntry, error) {
+	var entries []entities.LedgerEntry
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("occurred_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *repository) RegistrationBalance(registrationID uint) (float64, error) {
+	var balance float64
+	err := r.db.Model(&entities.LedgerEntry{}).
+		Where("registration_id = ?", registrationID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&balance).Error
+	return balance, err
+}
+
+func (r *repository) SumByPackage(from, to time.Time, tryOutID uint) ([]packageTotals, error) {
+	var rows []packageTotals
+	query := r.db.Model(&entities.LedgerEntry{}).
+		Select(`try_out_package_id, course_id, bundle_id,
+			COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE 0 END), 0) AS charges,
+			COALESCE(-SUM(CASE WHEN type = ? THEN amount ELSE 0 END), 0) AS refunds,
+			COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE 0 END), 0) AS adjustments,
+			COALESCE(SUM(amount), 0) AS net,
+			COUNT(*) AS entries`,
+			entities.LedgerEntryCharge, entities.LedgerEntryRefund, entities.LedgerEntryAdjustment).
+		Where("occurred_at >= ? AND occurred_at < ?", from, to)
+	if tryOutID != 0 {
+		query = query.Where("try_out_package_id = ?", tryOutID)
+	}
+	err := query.Group("try_out_package_id, course_id, bundle_id").
+		Order("try_out_package_id, course_id, bundle_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ==========================================
+// Registration Methods
+// ==========================================
+
+func (r *repository) FindRegistrationByID(id uint) (entities.TryOutRegistration, error) {
+	var registration entities.TryOutRegistration
+	err := r.db.Preload("TryOutPackage").Preload("User").Preload("Attempt").First(&registration, id).Error
+	return registration, err
+}
+
+// cancelRegistration marks the registration cancelled and soft-deletes it so Register can restore it later
+func cancelRegistration(tx *gorm.DB, registrationID uint, at time.Time) error {
+	return tx.Model(&entities.TryOutRegistration{}).
+		Where("id = ?", registrationID).
+		UpdateColumns(map[string]any{
+			"payment_status": entities.PaymentStatusCancelled,
+			"deleted_at":     at,
+		}).Error
+}
+
+// ==========================================
+// Cancellation Methods
+// ==========================================
+
+func (r *repository) FindCancellationByID(id uint) (entities.CancellationRequest, error) {
+	var request entities.CancellationRequest
+	err := r.db.Preload("Registration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Registration.TryOutPackage").
+		Preload("User").
+		First(&request, id).Error
+	return request, err
+}
+
+func (r *repository) FindPendingCancellationByRegistration(registrationID uint) (entities.CancellationRequest, error) {
+	var request entities.CancellationRequest
+	err := r.db.Where("registration_id = ? AND status = ?", registrationID, entities.CancellationStatusPending).
+		First(&request).Error
+	return request, err
+}
+
+func (r *repository) FindCancellationsByUserID(userID uint) ([]entities.CancellationRequest, error) {
+	var requests []entities.CancellationRequest
+	err := r.db.Where("user_id = ?", userID).
+		Preload("Registration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Registration.TryOutPackage").
+		Order("requested_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *repository) FindCancellations(status string) ([]entities.CancellationRequest, error) {
+	var requests []entities.CancellationRequest
+	query := r.db.Preload("Registration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Registration.TryOutPackage").
+		Preload("User").
+		Order("requested_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+func (r *repository) CreateCancellation(request *entities.CancellationRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *repository) UpdateCancellation(request *entities.CancellationRequest) error {
+	return r.db.Omit("Registration", "User", "ReviewedBy").Save(request).Error
+}
+
+func (r *repository) CancelWithoutPayment(request *entities.CancellationRequest) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(request).Error; err != nil {
+			return err
+		}
+		return cancelRegistration(tx, request.RegistrationID, time.Now())
+	})
+}
+
+func (r *repository) ApproveCancellation(request *entities.CancellationRequest, refund *entities.LedgerEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current entities.CancellationRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, request.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != entities.CancellationStatusPending {
+			return errors.New("cancellation request is not pending")
+		}
+
+		// The registration may have changed since the request was made
+		var registration entities.TryOutRegistration
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Attempt").
+			First(&registration, request.RegistrationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("registration cannot be cancelled")
+			}
+			return err
+		}
+		if registration.PaymentStatus != entities.PaymentStatusPending && registration.PaymentStatus != entities.PaymentStatusApproved &&
+			registration.PaymentStatus != entities.PaymentStatusWaitlisted {
+			return errors.New("registration cannot be cancelled")
+		}
+		if registration.Attempt != nil {
+			return errors.New("cannot cancel after starting the try out")
+		}
+
+		if refund != nil {
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Registration", "User", "ReviewedBy").Save(request).Error; err != nil {
+			return err
+		}
+		return cancelRegistration(tx, request.RegistrationID, time.Now())
+	})
+}
+
+// ==========================================
+// Lookup Methods
+// ==========================================
+
+func (r *repository) FindTryOutNames(ids []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+	var tryOuts []entities.TryOut
+	if err := r.db.Unscoped().Select("id", "name").Where("id IN ?", ids).Find(&tryOuts).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tryOuts {
+		names[t.ID] = t.Name
+	}
+	return names, nil
+}
+
+func (r *repository) FindCourseNames(ids []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+	var courses []entities.Course
+	if err := r.db.Unscoped().Select("id", "name_course").Where("id IN ?", ids).Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range courses {
+		names[c.ID] = c.NameCourse
+	}
+	return names, nil
+}
+
+func (r *repository) FindBundleNames(ids []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+	var bundles []entities.Bundle
+	if err := r.db.Unscoped().Select("id", "name").Where("id IN ?", ids).Find(&bundles).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range bundles {
+		names[b.ID] = b.Name
+	}
+	return names, nil
+}
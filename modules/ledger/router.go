@@ -0,0 +1,31 @@
+package ledger
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+)
+
+func LedgerRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	service := NewService(repo, promos.NewService(promos.NewRepository(db)), waitlists.NewService(waitlists.NewRepository(db)))
+	handler := NewHandler(service)
+
+	// User: cancel a registration and follow up on requests
+	router.POST("/tryouts/registrations/:id/cancellation", requireAuth, handler.RequestCancellationHandler)
+	router.GET("/users/me/cancellation-requests", requireAuth, handler.GetMyCancellationsHandler)
+
+	// Admin: refunds, adjustments and reporting
+	adminRoutes := router.Group("/ledger")
+	adminRoutes.Use(requireAuth, requireAdmin)
+	{
+		adminRoutes.GET("/cancellations", handler.GetCancellationsHandler)
+		adminRoutes.PUT("/cancellations/:id/approve", handler.ApproveCancellationHandler)
+		adminRoutes.PUT("/cancellations/:id/reject", handler.RejectCancellationHandler)
+		adminRoutes.GET("/registrations/:id", handler.GetRegistrationLedgerHandler)
+		adminRoutes.POST("/registrations/:id/adjustments", handler.CreateAdjustmentHandler)
+		adminRoutes.GET("/reports/reconciliation", handler.GetReconciliationReportHandler)
+	}
+}
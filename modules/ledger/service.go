@@ -0,0 +1,489 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+// Ledger entry sources
+const (
+	SourceManualProof  = "manual_proof"
+	SourceGateway      = "gateway"
+	SourceCancellation = "cancellation"
+	SourceAdmin        = "admin"
+	SourceGroup        = "group"
+)
+
+// reportDateLayout is the date format accepted by the reconciliation filter
+const reportDateLayout = "2006-01-02"
+
+type ledgerService struct {
+	repo      Repository
+	promos    promos.Service
+	waitlists waitlists.Service
+}
+
+type Service interface {
+	// Balances (charges are written by the approving flows inside their own transactions)
+	RegistrationBalance(registrationID uint) (float64, error)
+
+	// Admin actions
+	GetRegistrationLedger(registrationID uint, requestID string) (*RegistrationLedgerResponse, error)
+	CreateAdjustment(registrationID uint, input CreateAdjustmentInput, adminUserID uint, requestID string) (*LedgerEntryResponse, error)
+	GetCancellations(status string, requestID string) ([]CancellationResponse, error)
+	ApproveCancellation(id uint, input ApproveCancellationInput, adminUserID uint, requestID string) (*CancellationResponse, error)
+	RejectCancellation(id uint, input RejectCancellationInput, adminUserID uint, requestID string) (*CancellationResponse, error)
+	GetReconciliationReport(filter ReconciliationFilter, requestID string) (*ReconciliationResponse, error)
+
+	// User actions
+	RequestCancellation(registrationID uint, input RequestCancellationInput, userID uint, requestID string) (*CancellationResponse, error)
+	GetMyCancellations(userID uint, requestID string) ([]CancellationResponse, error)
+}
+
+func NewService(repo Repository, promoService promos.Service, waitlistService waitlists.Service) Service {
+	return &ledgerService{repo: repo, promos: promoService, waitlists: waitlistService}
+}
+
+// ==========================================
+// Balances
+// ==========================================
+
+func (s *ledgerService) RegistrationBalance(registrationID uint) (float64, error) {
+	balance, err := s.repo.RegistrationBalance(registrationID)
+	if err != nil {
+		return 0, err
+	}
+	return roundAmount(balance), nil
+}
+
+// ==========================================
+// Admin Actions
+// ==========================================
+
+func (s *ledgerService) GetRegistrationLedger(registrationID uint, requestID string) (*RegistrationLedgerResponse, error) {
+	entries, err := s.repo.FindEntriesByRegistration(registrationID)
+	if err != nil {
+		utils.LogError("ledger", "get_registration", "Failed to fetch ledger entries: "+err.Error(), requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+		return nil, err
+	}
+
+	response := &RegistrationLedgerResponse{
+		RegistrationID: registrationID,
+		Entries:        make([]LedgerEntryResponse, 0, len(entries)),
+	}
+	for _, e := range entries {
+		response.Balance += e.Amount
+		response.Entries = append(response.Entries, ToLedgerEntryResponse(e))
+	}
+	response.Balance = roundAmount(response.Balance)
+	return response, nil
+}
+
+func (s *ledgerService) CreateAdjustment(registrationID uint, input CreateAdjustmentInput, adminUserID uint, requestID string) (*LedgerEntryResponse, error) {
+	registration, err := s.repo.FindRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+
+	balance, err := s.RegistrationBalance(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if balance+input.Amount < 0 {
+		return nil, errors.New("adjustment would make the balance negative")
+	}
+
+	tryOutID := registration.TryOutPackageID
+	entry := entities.LedgerEntry{
+		Type:             entities.LedgerEntryAdjustment,
+		Amount:           roundAmount(input.Amount),
+		UserID:           registration.UserID,
+		RegistrationID:   &registrationID,
+		TryOutPackageID:  &tryOutID,
+		Source:           SourceAdmin,
+		Note:             input.Note,
+		RecordedByUserID: &adminUserID,
+		OccurredAt:       time.Now(),
+	}
+	if err := s.repo.CreateEntry(&entry); err != nil {
+		utils.LogError("ledger", "adjustment", "Failed to record adjustment: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("ledger", "adjustment", "Ledger adjustment recorded", requestID, adminUserID, map[string]any{
+		"registration_id": registrationID,
+		"amount":          entry.Amount,
+	})
+
+	response := ToLedgerEntryResponse(entry)
+	return &response, nil
+}
+
+func (s *ledgerService) GetCancellations(status string, requestID string) ([]CancellationResponse, error) {
+	requests, err := s.repo.FindCancellations(status)
+	if err != nil {
+		utils.LogError("ledger", "get_cancellations", "Failed to fetch cancellations: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+	return s.toCancellationResponses(requests)
+}
+
+func (s *ledgerService) ApproveCancellation(id uint, input ApproveCancellationInput, adminUserID uint, requestID string) (*CancellationResponse, error) {
+	utils.LogInfo("ledger", "approve_cancellation", "Admin approving cancellation", requestID, adminUserID, map[string]any{
+		"cancellation_id": id,
+	})
+
+	request, err := s.repo.FindCancellationByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cancellation request not found")
+		}
+		return nil, err
+	}
+	if request.Status != entities.CancellationStatusPending {
+		return nil, errors.New("cancellation request is not pending")
+	}
+
+	paid, err := s.RegistrationBalance(request.RegistrationID)
+	if err != nil {
+		return nil, err
+	}
+	refundAmount := paid
+	if input.RefundAmount != nil {
+		refundAmount = roundAmount(*input.RefundAmount)
+	}
+	if refundAmount > paid {
+		return nil, errors.New("refund cannot exceed the amount paid")
+	}
+
+	now := time.Now()
+	request.Status = entities.CancellationStatusApproved
+	request.RefundAmount = refundAmount
+	request.AdminNote = input.AdminNote
+	request.ReviewedByUserID = &adminUserID
+	request.ReviewedAt = &now
+
+	var refund *entities.LedgerEntry
+	if refundAmount > 0 {
+		registrationID := request.RegistrationID
+		tryOutID := request.Registration.TryOutPackageID
+		refund = &entities.LedgerEntry{
+			Type:             entities.LedgerEntryRefund,
+			Amount:           -refundAmount,
+			UserID:           request.UserID,
+			RegistrationID:   &registrationID,
+			TryOutPackageID:  &tryOutID,
+			Source:           SourceCancellation,
+			Reference:        fmt.Sprintf("CANCEL-%d", request.ID),
+			Note:             input.AdminNote,
+			RecordedByUserID: &adminUserID,
+			OccurredAt:       now,
+		}
+	}
+
+	if err := s.repo.ApproveCancellation(&request, refund); err != nil {
+		utils.LogError("ledger", "approve_cancellation", "Failed to approve cancellation: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	if err := s.promos.Release(promos.TargetTryOut, request.RegistrationID); err != nil {
+		utils.LogError("ledger", "approve_cancellation", "Failed to release promo code: "+err.Error(), requestID, adminUserID, map[string]any{
+			"registration_id": request.RegistrationID,
+		})
+	}
+
+	// The freed seat goes to the next student on the waitlist
+	if _, err := s.waitlists.PromoteTryOut(request.Registration.TryOutPackageID, requestID); err != nil {
+		utils.LogError("ledger", "approve_cancellation", "Failed to promote waitlist: "+err.Error(), requestID, adminUserID, nil)
+	}
+
+	if request.User.Email != "" {
+		body := fmt.Sprintf(
+			"Hi %s, your cancellation for %s has been approved. Refund amount: Rp %.0f.",
+			request.User.Username, request.Registration.TryOutPackage.Name, refundAmount,
+		)
+		if err := utils.SendEmail(request.User.Email, "Cancellation Approved", body); err != nil {
+			utils.LogWarning("ledger", "approve_cancellation", "Failed to send notification: "+err.Error(), requestID, adminUserID, nil)
+		}
+	}
+
+	utils.LogSuccess("ledger", "approve_cancellation", "Cancellation approved", requestID, adminUserID, map[string]any{
+		"cancellation_id": id,
+		"registration_id": request.RegistrationID,
+		"refund_amount":   refundAmount,
+	})
+
+	response := ToCancellationResponse(request, paid)
+	return &response, nil
+}
+
+func (s *ledgerService) RejectCancellation(id uint, input RejectCancellationInput, adminUserID uint, requestID string) (*CancellationResponse, error) {
+	request, err := s.repo.FindCancellationByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cancellation request not found")
+		}
+		return nil, err
+	}
+	if request.Status != entities.CancellationStatusPending {
+		return nil, errors.New("cancellation request is not pending")
+	}
+
+	now := time.Now()
+	request.Status = entities.CancellationStatusRejected
+	request.AdminNote = input.AdminNote
+	request.ReviewedByUserID = &adminUserID
+	request.ReviewedAt = &now
+	if err := s.repo.UpdateCancellation(&request); err != nil {
+		utils.LogError("ledger", "reject_cancellation", "Failed to reject cancellation: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("ledger", "reject_cancellation", "Cancellation rejected", requestID, adminUserID, map[string]any{
+		"cancellation_id": id,
+	})
+
+	paid, err := s.RegistrationBalance(request.RegistrationID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToCancellationResponse(request, paid)
+	return &response, nil
+}
+
+func (s *ledgerService) GetReconciliationReport(filter ReconciliationFilter, requestID string) (*ReconciliationResponse, error) {
+	from, to, err := parsePeriod(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.SumByPackage(from, to, filter.TryOutID)
+	if err != nil {
+		utils.LogError("ledger", "reconciliation", "Failed to aggregate ledger: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	var tryOutIDs, courseIDs, bundleIDs []uint
+	for _, t := range totals {
+		if t.TryOutPackageID != nil {
+			tryOutIDs = append(tryOutIDs, *t.TryOutPackageID)
+		}
+		if t.CourseID != nil {
+			courseIDs = append(courseIDs, *t.CourseID)
+		}
+		if t.BundleID != nil {
+			bundleIDs = append(bundleIDs, *t.BundleID)
+		}
+	}
+	tryOutNames, err := s.repo.FindTryOutNames(tryOutIDs)
+	if err != nil {
+		return nil, err
+	}
+	courseNames, err := s.repo.FindCourseNames(courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	bundleNames, err := s.repo.FindBundleNames(bundleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReconciliationResponse{
+		From:   from,
+		To:     to.Add(-time.Nanosecond),
+		Rows:   make([]ReconciliationRow, 0, len(totals)),
+		Totals: ReconciliationRow{PackageType: "total", PackageName: "Total"},
+	}
+	for _, t := range totals {
+		row := ReconciliationRow{
+			Charges:     roundAmount(t.Charges),
+			Refunds:     roundAmount(t.Refunds),
+			Adjustments: roundAmount(t.Adjustments),
+			Net:         roundAmount(t.Net),
+			Entries:     t.Entries,
+		}
+		switch {
+		case t.TryOutPackageID != nil:
+			row.PackageType = "tryout"
+			row.PackageID = *t.TryOutPackageID
+			row.PackageName = tryOutNames[row.PackageID]
+		case t.CourseID != nil:
+			row.PackageType = "course"
+			row.PackageID = *t.CourseID
+			row.PackageName = courseNames[row.PackageID]
+		case t.BundleID != nil:
+			row.PackageType = "bundle"
+			row.PackageID = *t.BundleID
+			row.PackageName = bundleNames[row.PackageID]
+		default:
+			row.PackageType = "other"
+		}
+		response.Rows = append(response.Rows, row)
+
+		response.Totals.Charges += row.Charges
+		response.Totals.Refunds += row.Refunds
+		response.Totals.Adjustments += row.Adjustments
+		response.Totals.Net += row.Net
+		response.Totals.Entries += row.Entries
+	}
+	response.Totals.Charges = roundAmount(response.Totals.Charges)
+	response.Totals.Refunds = roundAmount(response.Totals.Refunds)
+	response.Totals.Adjustments = roundAmount(response.Totals.Adjustments)
+	response.Totals.Net = roundAmount(response.Totals.Net)
+
+	return response, nil
+}
+
+// ==========================================
+// User Actions
+// ==========================================
+
+func (s *ledgerService) RequestCancellation(registrationID uint, input RequestCancellationInput, userID uint, requestID string) (*CancellationResponse, error) {
+	utils.LogInfo("ledger", "request_cancellation", "User requesting cancellation", requestID, userID, map[string]any{
+		"registration_id": registrationID,
+	})
+
+	registration, err := s.repo.FindRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+	if registration.UserID != userID {
+		return nil, errors.New("you can only cancel your own registration")
+	}
+	if registration.PaymentStatus != entities.PaymentStatusPending && registration.PaymentStatus != entities.PaymentStatusApproved &&
+		registration.PaymentStatus != entities.PaymentStatusWaitlisted {
+		return nil, errors.New("registration cannot be cancelled")
+	}
+	if registration.Attempt != nil {
+		return nil, errors.New("cannot cancel after starting the try out")
+	}
+	if registration.BundlePurchaseID != nil {
+		return nil, errors.New("registrations granted by a bundle cannot be cancelled individually")
+	}
+	if registration.GroupEnrollmentID != nil {
+		return nil, errors.New("registrations enrolled by a school cannot be cancelled individually")
+	}
+	if _, err := s.repo.FindPendingCancellationByRegistration(registrationID); err == nil {
+		return nil, errors.New("a cancellation request is already pending")
+	}
+
+	paid, err := s.RegistrationBalance(registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.CancellationRequest{
+		RegistrationID: registrationID,
+		UserID:         userID,
+		Reason:         input.Reason,
+		Status:         entities.CancellationStatusPending,
+	}
+
+	// Nothing was paid: cancel right away, there is nothing for an admin to refund
+	if paid <= 0 {
+		now := time.Now()
+		request.Status = entities.CancellationStatusApproved
+		request.ReviewedAt = &now
+		if err := s.repo.CancelWithoutPayment(&request); err != nil {
+			utils.LogError("ledger", "request_cancellation", "Failed to cancel registration: "+err.Error(), requestID, userID, nil)
+			return nil, err
+		}
+		if err := s.promos.Release(promos.TargetTryOut, registrationID); err != nil {
+			utils.LogError("ledger", "request_cancellation", "Failed to release promo code: "+err.Error(), requestID, userID, nil)
+		}
+		if registration.PaymentStatus != entities.PaymentStatusWaitlisted {
+			if _, err := s.waitlists.PromoteTryOut(registration.TryOutPackageID, requestID); err != nil {
+				utils.LogError("ledger", "request_cancellation", "Failed to promote waitlist: "+err.Error(), requestID, userID, nil)
+			}
+		}
+
+		utils.LogSuccess("ledger", "request_cancellation", "Unpaid registration cancelled", requestID, userID, map[string]any{
+			"registration_id": registrationID,
+		})
+	} else {
+		if err := s.repo.CreateCancellation(&request); err != nil {
+			utils.LogError("ledger", "request_cancellation", "Failed to create cancellation request: "+err.Error(), requestID, userID, nil)
+			return nil, err
+		}
+
+		utils.LogSuccess("ledger", "request_cancellation", "Cancellation request submitted", requestID, userID, map[string]any{
+			"registration_id": registrationID,
+			"amount_paid":     paid,
+		})
+	}
+
+	request.Registration = registration
+	response := ToCancellationResponse(request, paid)
+	return &response, nil
+}
+
+func (s *ledgerService) GetMyCancellations(userID uint, requestID string) ([]CancellationResponse, error) {
+	requests, err := s.repo.FindCancellationsByUserID(userID)
+	if err != nil {
+		utils.LogError("ledger", "get_my_cancellations", "Failed to fetch cancellations: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+	return s.toCancellationResponses(requests)
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *ledgerService) toCancellationResponses(requests []entities.CancellationRequest) ([]CancellationResponse, error) {
+	responses := make([]CancellationResponse, 0, len(requests))
+	for _, request := range requests {
+		paid, err := s.RegistrationBalance(request.RegistrationID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, ToCancellationResponse(request, paid))
+	}
+	return responses, nil
+}
+
+// parsePeriod turns the inclusive date filter into a [from, to) range; it defaults to the current month
+func parsePeriod(filter ReconciliationFilter) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 1, 0)
+
+	if filter.From != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, filter.From, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+	if filter.To != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, filter.To, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	return from, to, nil
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,51 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// INVOICE DTOs
+// ==========================================
+
+// InvoiceResponse is the response DTO for a payment gateway invoice
+type InvoiceResponse struct {
+	ID             uint       `json:"id"`
+	RegistrationID uint       `json:"registrationId"`
+	Provider       string     `json:"provider"`
+	OrderID        string     `json:"orderId"`
+	PaymentURL     string     `json:"paymentUrl"`
+	Amount         float64    `json:"amount"`
+	Status         string     `json:"status"`
+	PaymentMethod  string     `json:"paymentMethod,omitempty"`
+	PaidAt         *time.Time `json:"paidAt,omitempty"`
+	HeldReason     string     `json:"heldReason,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// RefundHeldPaymentInput is the admin note recorded with the refund of a held payment
+type RefundHeldPaymentInput struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToInvoiceResponse(i entities.PaymentInvoice) InvoiceResponse {
+	return InvoiceResponse{
+		ID:             i.ID,
+		RegistrationID: i.RegistrationID,
+		Provider:       i.Provider,
+		OrderID:        i.OrderID,
+		PaymentURL:     i.PaymentURL,
+		Amount:         i.Amount,
+		Status:         string(i.Status),
+		PaymentMethod:  i.PaymentMethod,
+		PaidAt:         i.PaidAt,
+		HeldReason:     i.HeldReason,
+		CreatedAt:      i.CreatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package payments
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gateway "github.com/redukasquad/be-reduka/packages/payments"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	CreateTryOutInvoiceHandler(c *gin.Context)
+	GetTryOutInvoiceHandler(c *gin.Context)
+	WebhookHandler(c *gin.Context)
+	GetHeldPaymentsHandler(c *gin.Context)
+	AdmitHeldPaymentHandler(c *gin.Context)
+	RefundHeldPaymentHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+// ==========================================
+// User Handlers
+// ==========================================
+
+func (h *handler) CreateTryOutInvoiceHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	registrationIDStr := c.Param("id")
+
+	registrationID, err := strconv.ParseUint(registrationIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return
+	}
+
+	invoice, err := h.service.CreateTryOutInvoice(uint(registrationID), userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "registration not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
+		case "you can only pay for your own registration":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
+		case "payment is already approved", "no payment required for free try out", "payment is handled by your school coordinator",
+			"registration is on the waitlist":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
+		case "payment gateway is not configured":
+			c.JSON(http.StatusServiceUnavailable, utils.BuildResponseFailed("Payment gateway unavailable", err.Error(), nil))
+		default:
+			c.JSON(http.StatusBadGateway, utils.BuildResponseFailed("Failed to create invoice", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Invoice created successfully", invoice))
+}
+
+func (h *handler) GetTryOutInvoiceHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	registrationIDStr := c.Param("id")
+
+	registrationID, err := strconv.ParseUint(registrationIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return
+	}
+
+	invoice, err := h.service.GetTryOutInvoice(uint(registrationID), userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "registration not found", "invoice not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+		case "you can only pay for your own registration":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to get invoice", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Invoice retrieved successfully", invoice))
+}
+
+// ==========================================
+// Provider Callbacks
+// ==========================================
+
+func (h *handler) WebhookHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	providerName := c.Param("provider")
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid payload", err.Error(), nil))
+		return
+	}
+
+	if err := h.service.HandleWebhook(providerName, c.Request.Header, body, requestID); err != nil {
+		switch {
+		case errors.Is(err, gateway.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, utils.BuildResponseFailed("Invalid signature", err.Error(), nil))
+		case err.Error() == "unknown payment provider", err.Error() == "invoice not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+		case err.Error() == "invalid webhook payload", err.Error() == "paid amount does not match invoice":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid notification", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to process notification", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Notification processed", nil))
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) GetHeldPaymentsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+
+	invoices, err := h.service.GetHeldPayments(requestID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to get held payments", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Held payments retrieved successfully", invoices))
+}
+
+func (h *handler) AdmitHeldPaymentHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	adminUserID := getUserID(c)
+
+	invoice, err := h.service.AdmitHeldPayment(c.Param("orderId"), adminUserID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Invoice not found", err.Error(), nil))
+		case "payment is not held", "registration is not pending":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
+		case "try out is full, registration was waitlisted":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Try out is full", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to admit held payment", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Held payment admitted successfully", invoice))
+}
+
+func (h *handler) RefundHeldPaymentHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	adminUserID := getUserID(c)
+
+	var input RefundHeldPaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	invoice, err := h.service.RefundHeldPayment(c.Param("orderId"), input, adminUserID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Invoice not found", err.Error(), nil))
+		case "payment is not held":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to refund held payment", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Held payment refunded successfully", invoice))
+}
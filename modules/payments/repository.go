@@ -0,0 +1,238 @@
+package payments
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	gateway "github.com/redukasquad/be-reduka/packages/payments"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	// Registrations
+	FindRegistrationByID(id uint) (entities.TryOutRegistration, error)
+
+	// Invoices
+	FindPendingInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error)
+	FindLatestInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error)
+	FindInvoiceByOrderID(orderID string) (entities.PaymentInvoice, error)
+	CreateInvoice(invoice *entities.PaymentInvoice) error
+
+	// ApplyWebhookEvent updates the invoice and, on settlement, approves the registration.
+	// It returns false when the event was already applied.
+	ApplyWebhookEvent(event gateway.WebhookEvent) (bool, error)
+
+	// Held payments
+	FindHeldInvoices() ([]entities.PaymentInvoice, error)
+	// ReopenRegistration puts the registration of a held payment back to pending so it can go
+	// through admission again
+	ReopenRegistration(registrationID uint) error
+	// ApproveHeldInvoice approves the admitted registration and releases the hold
+	ApproveHeldInvoice(invoice *entities.PaymentInvoice) error
+	// RefundHeldInvoice books the refund of a held payment and releases the hold
+	RefundHeldInvoice(invoice *entities.PaymentInvoice, note string, adminUserID uint) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Registration Methods
+// ==========================================
+
+func (r *repository) FindRegistrationByID(id uint) (entities.TryOutRegistration, error) {
+	var registration entities.TryOutRegistration
+	err := r.db.Preload("TryOutPackage").Preload("User").First(&registration, id).Error
+	return registration, err
+}
+
+// ==========================================
+// Invoice Methods
+// ==========================================
+
+func (r *repository) FindPendingInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error) {
+	var invoice entities.PaymentInvoice
+	err := r.db.Where("registration_id = ? AND status = ?", registrationID, entities.InvoiceStatusPending).
+		Order("created_at DESC").
+		First(&invoice).Error
+	return invoice, err
+}
+
+func (r *repository) FindLatestInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error) {
+	var invoice entities.PaymentInvoice
+	err := r.db.Where("registration_id = ?", registrationID).
+		Order("created_at DESC").
+		First(&invoice).Error
+	return invoice, err
+}
+
+func (r *repository) FindInvoiceByOrderID(orderID string) (entities.PaymentInvoice, error) {
+	var invoice entities.PaymentInvoice
+	err := r.db.Where("order_id = ?", orderID).First(&invoice).Error
+	return invoice, err
+}
+
+func (r *repository) CreateInvoice(invoice *entities.PaymentInvoice) error {
+	return r.db.Omit("Registration").Create(invoice).Error
+}
+
+func (r *repository) ApplyWebhookEvent(event gateway.WebhookEvent) (bool, error) {
+	applied := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var invoice entities.PaymentInvoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", event.OrderID).
+			First(&invoice).Error; err != nil {
+			return err
+		}
+
+		// A paid invoice is final; replays and late notifications are ignored
+		if invoice.Status == entities.InvoiceStatusPaid || invoice.Status == entities.InvoiceStatus(event.Status) {
+			return nil
+		}
+
+		invoice.Status = entities.InvoiceStatus(event.Status)
+		invoice.TransactionID = event.TransactionID
+		invoice.PaymentMethod = event.PaymentMethod
+
+		if event.Status != gateway.InvoiceStatusPaid {
+			applied = true
+			return tx.Omit("Registration").Save(&invoice).Error
+		}
+
+		var registration entities.TryOutRegistration
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "user_id", "try_out_package_id", "payment_status", "deleted_at").
+			First(&registration, invoice.RegistrationID).Error; err != nil {
+			return err
+		}
+
+		// Only a registration still waiting for its payment is approved. Approving a waitlisted,
+		// expired or cancelled one would skip the capacity checks, so the payment is held instead.
+		status := registration.PaymentStatus
+		approvable := !registration.DeletedAt.Valid &&
+			(status == entities.PaymentStatusPending || status == entities.PaymentStatusRejected)
+		if !approvable {
+			invoice.HeldReason = "paid while the registration was " + string(status)
+		}
+
+		now := time.Now()
+		invoice.PaidAt = &now
+		if err := tx.Omit("Registration").Save(&invoice).Error; err != nil {
+			return err
+		}
+
+		if approvable {
+			if err := tx.Model(&entities.TryOutRegistration{}).Where("id = ?", registration.ID).
+				Updates(map[string]any{
+					"payment_status":   entities.PaymentStatusApproved,
+					"approved_at":      now,
+					"rejection_reason": "",
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		// The money was received either way
+		if invoice.Amount > 0 {
+			if err := tx.Create(&entities.LedgerEntry{
+				Type:            entities.LedgerEntryCharge,
+				Amount:          invoice.Amount,
+				UserID:          registration.UserID,
+				RegistrationID:  &registration.ID,
+				TryOutPackageID: &registration.TryOutPackageID,
+				Source:          ledger.SourceGateway,
+				Reference:       invoice.OrderID,
+				OccurredAt:      now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		applied = true
+		return nil
+	})
+
+	return applied, err
+}
+
+// ==========================================
+// Held Payment Methods
+// ==========================================
+
+func (r *repository) FindHeldInvoices() ([]entities.PaymentInvoice, error) {
+	var invoices []entities.PaymentInvoice
+	err := r.db.Where("held_reason <> ''").
+		Preload("Registration", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("paid_at ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *repository) ReopenRegistration(registrationID uint) error {
+	return r.db.Unscoped().Model(&entities.TryOutRegistration{}).
+		Where("id = ? AND payment_status <> ?", registrationID, entities.PaymentStatusApproved).
+		Updates(map[string]any{
+			"payment_status":   entities.PaymentStatusPending,
+			"rejection_reason": "",
+			"waitlisted_at":    nil,
+			"expired_at":       nil,
+			"deleted_at":       nil,
+		}).Error
+}
+
+func (r *repository) ApproveHeldInvoice(invoice *entities.PaymentInvoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.TryOutRegistration{}).
+			Where("id = ? AND payment_status = ?", invoice.RegistrationID, entities.PaymentStatusPending).
+			Updates(map[string]any{
+				"payment_status": entities.PaymentStatusApproved,
+				"approved_at":    time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("registration is not pending")
+		}
+		invoice.HeldReason = ""
+		return tx.Model(invoice).Update("held_reason", "").Error
+	})
+}
+
+func (r *repository) RefundHeldInvoice(invoice *entities.PaymentInvoice, note string, adminUserID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var registration entities.TryOutRegistration
+		if err := tx.Unscoped().Select("id", "user_id", "try_out_package_id").
+			First(&registration, invoice.RegistrationID).Error; err != nil {
+			return err
+		}
+		if invoice.Amount > 0 {
+			if err := tx.Create(&entities.LedgerEntry{
+				Type:             entities.LedgerEntryRefund,
+				Amount:           -invoice.Amount,
+				UserID:           registration.UserID,
+				RegistrationID:   &registration.ID,
+				TryOutPackageID:  &registration.TryOutPackageID,
+				Source:           ledger.SourceGateway,
+				Reference:        invoice.OrderID,
+				Note:             note,
+				RecordedByUserID: &adminUserID,
+				OccurredAt:       time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		invoice.HeldReason = ""
+		return tx.Model(invoice).Update("held_reason", "").Error
+	})
+}
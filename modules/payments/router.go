@@ -0,0 +1,36 @@
+package payments
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	gateway "github.com/redukasquad/be-reduka/packages/payments"
+)
+
+func PaymentRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	service := NewService(repo, gateway.NewProviderFromEnv(), receipts.NewService(receipts.NewRepository(db)), waitlists.NewService(waitlists.NewRepository(db)))
+	handler := NewHandler(service)
+
+	// User: pay a try out registration through the payment gateway
+	invoiceRoutes := router.Group("/tryouts/registrations")
+	invoiceRoutes.Use(requireAuth)
+	{
+		invoiceRoutes.POST("/:id/invoice", handler.CreateTryOutInvoiceHandler)
+		invoiceRoutes.GET("/:id/invoice", handler.GetTryOutInvoiceHandler)
+	}
+
+	// Admin: payments that settled after their registration stopped waiting for them
+	adminRoutes := router.Group("/payments/held")
+	adminRoutes.Use(requireAuth, requireAdmin)
+	{
+		adminRoutes.GET("", handler.GetHeldPaymentsHandler)
+		adminRoutes.POST("/:orderId/admit", handler.AdmitHeldPaymentHandler)
+		adminRoutes.POST("/:orderId/refund", handler.RefundHeldPaymentHandler)
+	}
+
+	// Provider: signed payment notifications (public, authenticated by signature)
+	router.POST("/payments/webhooks/:provider", handler.WebhookHandler)
+}
@@ -0,0 +1,335 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	gateway "github.com/redukasquad/be-reduka/packages/payments"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type paymentService struct {
+	repo      Repository
+	provider  gateway.Provider
+	receipts  receipts.Service
+	waitlists waitlists.Service
+}
+
+type Service interface {
+	// User actions
+	CreateTryOutInvoice(registrationID uint, userID uint, requestID string) (*InvoiceResponse, error)
+	GetTryOutInvoice(registrationID uint, userID uint, requestID string) (*InvoiceResponse, error)
+
+	// Provider callbacks
+	HandleWebhook(providerName string, header http.Header, body []byte, requestID string) error
+
+	// Admin actions
+	GetHeldPayments(requestID string) ([]InvoiceResponse, error)
+	AdmitHeldPayment(orderID string, adminUserID uint, requestID string) (*InvoiceResponse, error)
+	RefundHeldPayment(orderID string, input RefundHeldPaymentInput, adminUserID uint, requestID string) (*InvoiceResponse, error)
+}
+
+// NewService creates the payment service. provider may be nil when no gateway is configured.
+func NewService(repo Repository, provider gateway.Provider, receiptService receipts.Service, waitlistService waitlists.Service) Service {
+	return &paymentService{repo: repo, provider: provider, receipts: receiptService, waitlists: waitlistService}
+}
+
+// ==========================================
+// User Actions
+// ==========================================
+
+func (s *paymentService) CreateTryOutInvoice(registrationID uint, userID uint, requestID string) (*InvoiceResponse, error) {
+	utils.LogInfo("payments", "create_invoice", "User requesting payment invoice", requestID, userID, map[string]any{
+		"registration_id": registrationID,
+	})
+
+	if s.provider == nil {
+		return nil, errors.New("payment gateway is not configured")
+	}
+
+	registration, err := s.repo.FindRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+
+	if registration.UserID != userID {
+		return nil, errors.New("you can only pay for your own registration")
+	}
+	if registration.TryOutPackage.IsFree {
+		return nil, errors.New("no payment required for free try out")
+	}
+	if registration.GroupEnrollmentID != nil {
+		return nil, errors.New("payment is handled by your school coordinator")
+	}
+	if registration.PaymentStatus == entities.PaymentStatusApproved {
+		return nil, errors.New("payment is already approved")
+	}
+	if registration.PaymentStatus == entities.PaymentStatusWaitlisted {
+		return nil, errors.New("registration is on the waitlist")
+	}
+
+	// One open invoice per registration: reuse it instead of creating a duplicate charge
+	if existing, err := s.repo.FindPendingInvoiceByRegistration(registrationID); err == nil {
+		response := ToInvoiceResponse(existing)
+		return &response, nil
+	}
+
+	amount := registration.TryOutPackage.Price
+	if registration.PromoCodeID != nil {
+		amount = registration.FinalAmount
+	}
+	orderID := fmt.Sprintf("TO-%d-%d", registration.ID, time.Now().Unix())
+
+	invoice, err := s.provider.CreateInvoice(gateway.InvoiceRequest{
+		OrderID:       orderID,
+		Amount:        amount,
+		ItemName:      registration.TryOutPackage.Name,
+		CustomerName:  registration.User.Username,
+		CustomerEmail: registration.User.Email,
+		CustomerPhone: registration.User.NoTelp,
+	})
+	if err != nil {
+		utils.LogError("payments", "create_invoice", "Provider failed to create invoice: "+err.Error(), requestID, userID, map[string]any{
+			"provider": s.provider.Name(),
+		})
+		return nil, err
+	}
+
+	record := entities.PaymentInvoice{
+		RegistrationID: registration.ID,
+		Provider:       s.provider.Name(),
+		OrderID:        orderID,
+		ExternalID:     invoice.ExternalID,
+		PaymentURL:     invoice.PaymentURL,
+		Amount:         amount,
+		Status:         entities.InvoiceStatusPending,
+	}
+	if err := s.repo.CreateInvoice(&record); err != nil {
+		utils.LogError("payments", "create_invoice", "Failed to save invoice: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("payments", "create_invoice", "Payment invoice created", requestID, userID, map[string]any{
+		"registration_id": registration.ID,
+		"order_id":        orderID,
+		"amount":          amount,
+	})
+
+	response := ToInvoiceResponse(record)
+	return &response, nil
+}
+
+func (s *paymentService) GetTryOutInvoice(registrationID uint, userID uint, requestID string) (*InvoiceResponse, error) {
+	registration, err := s.repo.FindRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+
+	if registration.UserID != userID {
+		return nil, errors.New("you can only pay for your own registration")
+	}
+
+	invoice, err := s.repo.FindLatestInvoiceByRegistration(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+
+	response := ToInvoiceResponse(invoice)
+	return &response, nil
+}
+
+// ==========================================
+// Provider Callbacks
+// ==========================================
+
+func (s *paymentService) HandleWebhook(providerName string, header http.Header, body []byte, requestID string) error {
+	if s.provider == nil || s.provider.Name() != providerName {
+		return errors.New("unknown payment provider")
+	}
+
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		utils.LogWarning("payments", "webhook", "Rejected webhook: "+err.Error(), requestID, 0, map[string]any{
+			"provider": providerName,
+		})
+		if errors.Is(err, gateway.ErrInvalidSignature) {
+			return err
+		}
+		return errors.New("invalid webhook payload")
+	}
+
+	utils.LogInfo("payments", "webhook", "Received payment notification", requestID, 0, map[string]any{
+		"provider": providerName,
+		"order_id": event.OrderID,
+		"status":   string(event.Status),
+	})
+
+	invoice, err := s.repo.FindInvoiceByOrderID(event.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invoice not found")
+		}
+		return err
+	}
+
+	if event.Status == gateway.InvoiceStatusPaid && math.Abs(event.Amount-invoice.Amount) >= 1 {
+		utils.LogError("payments", "webhook", "Paid amount does not match invoice", requestID, 0, map[string]any{
+			"order_id":       event.OrderID,
+			"invoice_amount": invoice.Amount,
+			"paid_amount":    event.Amount,
+		})
+		return errors.New("paid amount does not match invoice")
+	}
+
+	applied, err := s.repo.ApplyWebhookEvent(*event)
+	if err != nil {
+		utils.LogError("payments", "webhook", "Failed to apply payment notification: "+err.Error(), requestID, 0, nil)
+		return err
+	}
+
+	if !applied {
+		utils.LogInfo("payments", "webhook", "Duplicate notification ignored", requestID, 0, map[string]any{
+			"order_id": event.OrderID,
+		})
+		return nil
+	}
+
+	utils.LogSuccess("payments", "webhook", "Payment notification applied", requestID, 0, map[string]any{
+		"order_id":        event.OrderID,
+		"registration_id": invoice.RegistrationID,
+		"status":          string(event.Status),
+	})
+
+	if event.Status == gateway.InvoiceStatusPaid {
+		settled, err := s.repo.FindInvoiceByOrderID(event.OrderID)
+		if err != nil {
+			return err
+		}
+		if settled.HeldReason != "" {
+			utils.LogWarning("payments", "webhook", "Payment held for admin review: "+settled.HeldReason, requestID, 0, map[string]any{
+				"order_id":        event.OrderID,
+				"registration_id": invoice.RegistrationID,
+			})
+			return nil
+		}
+		if _, err := s.receipts.IssueTryOutReceipt(invoice.RegistrationID, requestID); err != nil {
+			utils.LogError("payments", "webhook", "Failed to issue receipt: "+err.Error(), requestID, 0, map[string]any{
+				"registration_id": invoice.RegistrationID,
+			})
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// Admin Actions
+// ==========================================
+
+func (s *paymentService) GetHeldPayments(requestID string) ([]InvoiceResponse, error) {
+	invoices, err := s.repo.FindHeldInvoices()
+	if err != nil {
+		utils.LogError("payments", "get_held", "Failed to get held payments: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]InvoiceResponse, 0, len(invoices))
+	for _, invoice := range invoices {
+		responses = append(responses, ToInvoiceResponse(invoice))
+	}
+	return responses, nil
+}
+
+// AdmitHeldPayment runs the registration of a held payment through admission again. It is
+// approved when a seat is free; otherwise it stays on the waitlist and the payment stays held.
+func (s *paymentService) AdmitHeldPayment(orderID string, adminUserID uint, requestID string) (*InvoiceResponse, error) {
+	invoice, err := s.findHeldInvoice(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReopenRegistration(invoice.RegistrationID); err != nil {
+		return nil, err
+	}
+	waitlisted, err := s.waitlists.AdmitTryOutRegistration(invoice.RegistrationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if waitlisted {
+		utils.LogWarning("payments", "admit_held", "Try out is full, registration waitlisted", requestID, adminUserID, map[string]any{
+			"order_id":        orderID,
+			"registration_id": invoice.RegistrationID,
+		})
+		return nil, errors.New("try out is full, registration was waitlisted")
+	}
+
+	if err := s.repo.ApproveHeldInvoice(&invoice); err != nil {
+		utils.LogError("payments", "admit_held", "Failed to approve held payment: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("payments", "admit_held", "Held payment admitted", requestID, adminUserID, map[string]any{
+		"order_id":        orderID,
+		"registration_id": invoice.RegistrationID,
+	})
+
+	if _, err := s.receipts.IssueTryOutReceipt(invoice.RegistrationID, requestID); err != nil {
+		utils.LogError("payments", "admit_held", "Failed to issue receipt: "+err.Error(), requestID, adminUserID, map[string]any{
+			"registration_id": invoice.RegistrationID,
+		})
+	}
+
+	response := ToInvoiceResponse(invoice)
+	return &response, nil
+}
+
+// RefundHeldPayment records that a held payment was returned to the student
+func (s *paymentService) RefundHeldPayment(orderID string, input RefundHeldPaymentInput, adminUserID uint, requestID string) (*InvoiceResponse, error) {
+	invoice, err := s.findHeldInvoice(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RefundHeldInvoice(&invoice, input.Note, adminUserID); err != nil {
+		utils.LogError("payments", "refund_held", "Failed to refund held payment: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("payments", "refund_held", "Held payment refunded", requestID, adminUserID, map[string]any{
+		"order_id":        orderID,
+		"registration_id": invoice.RegistrationID,
+		"amount":          invoice.Amount,
+	})
+
+	response := ToInvoiceResponse(invoice)
+	return &response, nil
+}
+
+func (s *paymentService) findHeldInvoice(orderID string) (entities.PaymentInvoice, error) {
+	invoice, err := s.repo.FindInvoiceByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invoice, errors.New("invoice not found")
+		}
+		return invoice, err
+	}
+	if invoice.HeldReason == "" {
+		return invoice, errors.New("payment is not held")
+	}
+	return invoice, nil
+}
@@ -2,6 +2,7 @@ package programs
 
 import (
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/classes/access"
 	"gorm.io/gorm"
 )
 
@@ -27,16 +28,21 @@ func NewRepository(db *gorm.DB) Repository {
 
 func (r *repository) FindAll() ([]entities.Program, error) {
 	var programs []entities.Program
-	err := r.db.Preload("Courses").Find(&programs).Error
+	err := r.db.Preload("Courses", liveCourses).Find(&programs).Error
 	return programs, err
 }
 
 func (r *repository) FindByID(id uint) (entities.Program, error) {
 	var program entities.Program
-	err := r.db.Preload("Courses").First(&program, id).Error
+	err := r.db.Preload("Courses", liveCourses).First(&program, id).Error
 	return program, err
 }
 
+// liveCourses keeps drafts and archived courses out of the public program pages
+func liveCourses(db *gorm.DB) *gorm.DB {
+	return access.WhereLive(db, "courses")
+}
+
 func (r *repository) FindByName(name string) (entities.Program, error) {
 	var program entities.Program
 	err := r.db.Where("program_name = ?", name).Preload("Courses").First(&program).Error
@@ -0,0 +1,175 @@
+package promos
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// PROMO CODE DTOs
+// ==========================================
+
+// CreatePromoCodeInput is the input for creating a single promo code.
+// Leave TryOutIDs and CourseIDs empty to make the code valid for every paid package.
+type CreatePromoCodeInput struct {
+	Code           string     `json:"code" binding:"required,min=3,max=50"`
+	Description    string     `json:"description"`
+	DiscountType   string     `json:"discountType" binding:"required,oneof=percentage fixed"`
+	DiscountValue  float64    `json:"discountValue" binding:"required,gt=0"`
+	MaxDiscount    *float64   `json:"maxDiscount" binding:"omitempty,gt=0"`
+	MaxUses        *int       `json:"maxUses" binding:"omitempty,min=1"`
+	MaxUsesPerUser *int       `json:"maxUsesPerUser" binding:"omitempty,min=0"`
+	ValidFrom      *time.Time `json:"validFrom"`
+	ValidUntil     *time.Time `json:"validUntil"`
+	IsActive       *bool      `json:"isActive"`
+	School         string     `json:"school" binding:"max=150"`
+	TryOutIDs      []uint     `json:"tryOutIds"`
+	CourseIDs      []uint     `json:"courseIds"`
+}
+
+// UpdatePromoCodeInput is the input for updating a promo code. The code itself cannot change.
+type UpdatePromoCodeInput struct {
+	Description    *string    `json:"description"`
+	DiscountType   *string    `json:"discountType" binding:"omitempty,oneof=percentage fixed"`
+	DiscountValue  *float64   `json:"discountValue" binding:"omitempty,gt=0"`
+	MaxDiscount    *float64   `json:"maxDiscount" binding:"omitempty,gte=0"` // 0 removes the cap
+	MaxUses        *int       `json:"maxUses" binding:"omitempty,min=0"`     // 0 removes the limit
+	MaxUsesPerUser *int       `json:"maxUsesPerUser" binding:"omitempty,min=0"`
+	ValidFrom      *time.Time `json:"validFrom"`
+	ValidUntil     *time.Time `json:"validUntil"`
+	IsActive       *bool      `json:"isActive"`
+	School         *string    `json:"school" binding:"omitempty,max=150"`
+	TryOutIDs      *[]uint    `json:"tryOutIds"`
+	CourseIDs      *[]uint    `json:"courseIds"`
+}
+
+// GeneratePromoCodesInput creates a batch of single-use codes, e.g. one per student of a school cohort
+type GeneratePromoCodesInput struct {
+	BatchLabel    string     `json:"batchLabel" binding:"required,max=100"`
+	Prefix        string     `json:"prefix" binding:"omitempty,alphanum,max=20"`
+	Count         int        `json:"count" binding:"required,min=1,max=1000"`
+	Description   string     `json:"description"`
+	DiscountType  string     `json:"discountType" binding:"required,oneof=percentage fixed"`
+	DiscountValue float64    `json:"discountValue" binding:"required,gt=0"`
+	MaxDiscount   *float64   `json:"maxDiscount" binding:"omitempty,gt=0"`
+	ValidFrom     *time.Time `json:"validFrom"`
+	ValidUntil    *time.Time `json:"validUntil"`
+	School        string     `json:"school" binding:"max=150"`
+	TryOutIDs     []uint     `json:"tryOutIds"`
+	CourseIDs     []uint     `json:"courseIds"`
+}
+
+// ValidatePromoCodeInput lets a user preview a code before registering
+type ValidatePromoCodeInput struct {
+	Code     string `json:"code" binding:"required"`
+	TryOutID *uint  `json:"tryOutId"`
+	CourseID *uint  `json:"courseId"`
+}
+
+// PromoCodeFilter narrows the admin promo code list
+type PromoCodeFilter struct {
+	BatchLabel string `form:"batch"`
+	Search     string `form:"q"`
+}
+
+// PromoCodeResponse is the admin view of a promo code
+type PromoCodeResponse struct {
+	ID             uint       `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description,omitempty"`
+	BatchLabel     string     `json:"batchLabel,omitempty"`
+	DiscountType   string     `json:"discountType"`
+	DiscountValue  float64    `json:"discountValue"`
+	MaxDiscount    *float64   `json:"maxDiscount,omitempty"`
+	MaxUses        *int       `json:"maxUses,omitempty"`
+	MaxUsesPerUser int        `json:"maxUsesPerUser"`
+	UsedCount      int        `json:"usedCount"`
+	ValidFrom      *time.Time `json:"validFrom,omitempty"`
+	ValidUntil     *time.Time `json:"validUntil,omitempty"`
+	IsActive       bool       `json:"isActive"`
+	School         string     `json:"school,omitempty"`
+	TryOutIDs      []uint     `json:"tryOutIds"`
+	CourseIDs      []uint     `json:"courseIds"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// RedemptionResponse is one use of a promo code
+type RedemptionResponse struct {
+	ID                   uint      `json:"id"`
+	UserID               uint      `json:"userId"`
+	Username             string    `json:"username,omitempty"`
+	Email                string    `json:"email,omitempty"`
+	TryOutRegistrationID *uint     `json:"tryOutRegistrationId,omitempty"`
+	CourseRegistrationID *uint     `json:"courseRegistrationId,omitempty"`
+	OriginalPrice        float64   `json:"originalPrice"`
+	DiscountAmount       float64   `json:"discountAmount"`
+	RedeemedAt           time.Time `json:"redeemedAt"`
+}
+
+// QuoteResponse is the price breakdown after applying a promo code
+type QuoteResponse struct {
+	Code           string  `json:"code"`
+	OriginalPrice  float64 `json:"originalPrice"`
+	DiscountAmount float64 `json:"discountAmount"`
+	FinalAmount    float64 `json:"finalAmount"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToPromoCodeResponse(p entities.PromoCode) PromoCodeResponse {
+	response := PromoCodeResponse{
+		ID:             p.ID,
+		Code:           p.Code,
+		Description:    p.Description,
+		BatchLabel:     p.BatchLabel,
+		DiscountType:   string(p.DiscountType),
+		DiscountValue:  p.DiscountValue,
+		MaxDiscount:    p.MaxDiscount,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		UsedCount:      p.UsedCount,
+		ValidFrom:      p.ValidFrom,
+		ValidUntil:     p.ValidUntil,
+		IsActive:       p.IsActive,
+		School:         p.School,
+		TryOutIDs:      []uint{},
+		CourseIDs:      []uint{},
+		CreatedAt:      p.CreatedAt,
+	}
+	for _, t := range p.TryOuts {
+		response.TryOutIDs = append(response.TryOutIDs, t.ID)
+	}
+	for _, c := range p.Courses {
+		response.CourseIDs = append(response.CourseIDs, c.ID)
+	}
+	return response
+}
+
+func ToRedemptionResponse(r entities.PromoRedemption) RedemptionResponse {
+	response := RedemptionResponse{
+		ID:                   r.ID,
+		UserID:               r.UserID,
+		TryOutRegistrationID: r.TryOutRegistrationID,
+		CourseRegistrationID: r.CourseRegistrationID,
+		OriginalPrice:        r.OriginalPrice,
+		DiscountAmount:       r.DiscountAmount,
+		RedeemedAt:           r.RedeemedAt,
+	}
+	if r.User.ID != 0 {
+		response.Username = r.User.Username
+		response.Email = r.User.Email
+	}
+	return response
+}
+
+func ToQuoteResponse(q Quote) QuoteResponse {
+	return QuoteResponse{
+		Code:           q.Code,
+		OriginalPrice:  q.OriginalPrice,
+		DiscountAmount: q.DiscountAmount,
+		FinalAmount:    q.FinalAmount,
+	}
+}
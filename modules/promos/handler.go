@@ -0,0 +1,240 @@
+package promos
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	// Admin endpoints
+	GetAllHandler(c *gin.Context)
+	GetByIDHandler(c *gin.Context)
+	CreateHandler(c *gin.Context)
+	GenerateHandler(c *gin.Context)
+	UpdateHandler(c *gin.Context)
+	DeleteHandler(c *gin.Context)
+	GetRedemptionsHandler(c *gin.Context)
+
+	// User endpoints
+	ValidateHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) GetAllHandler(c *gin.Context) {
+	var filter PromoCodeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid filter", err.Error(), nil))
+		return
+	}
+
+	promos, err := h.service.GetAll(filter, getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch promo codes", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Promo codes retrieved successfully", promos))
+}
+
+func (h *handler) GetByIDHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	promo, err := h.service.GetByID(id, getRequestID(c))
+	if err != nil {
+		if err.Error() == "promo code not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Promo code not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch promo code", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Promo code retrieved successfully", promo))
+}
+
+func (h *handler) CreateHandler(c *gin.Context) {
+	var input CreatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	promo, err := h.service.Create(input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "promo code already exists":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Duplicate code", err.Error(), nil))
+		case "percentage discount cannot exceed 100", "validUntil must be after validFrom",
+			"one or more try outs not found", "one or more courses not found":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to create promo code", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Promo code created successfully", promo))
+}
+
+func (h *handler) GenerateHandler(c *gin.Context) {
+	var input GeneratePromoCodesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	promos, err := h.service.Generate(input, getUserID(c), getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "percentage discount cannot exceed 100", "validUntil must be after validFrom",
+			"one or more try outs not found", "one or more courses not found":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to generate promo codes", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Promo codes generated successfully", promos))
+}
+
+func (h *handler) UpdateHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	promo, err := h.service.Update(id, input, getRequestID(c))
+	if err != nil {
+		switch err.Error() {
+		case "promo code not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Promo code not found", err.Error(), nil))
+		case "percentage discount cannot exceed 100", "validUntil must be after validFrom",
+			"one or more try outs not found", "one or more courses not found":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to update promo code", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Promo code updated successfully", promo))
+}
+
+func (h *handler) DeleteHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, getRequestID(c)); err != nil {
+		if err.Error() == "promo code not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Promo code not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete promo code", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Promo code deleted successfully", nil))
+}
+
+func (h *handler) GetRedemptionsHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	redemptions, err := h.service.GetRedemptions(id, getRequestID(c))
+	if err != nil {
+		if err.Error() == "promo code not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Promo code not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch redemptions", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Redemptions retrieved successfully", redemptions))
+}
+
+// ==========================================
+// User Handlers
+// ==========================================
+
+func (h *handler) ValidateHandler(c *gin.Context) {
+	var input ValidatePromoCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	quote, err := h.service.Validate(input, getUserID(c), getRequestID(c))
+	if err != nil {
+		if IsCodeError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+			return
+		}
+		switch err.Error() {
+		case "try out not found", "course not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Package not found", err.Error(), nil))
+		case "tryOutId or courseId is required":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to validate promo code", err.Error(), nil))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Promo code is valid", quote))
+}
@@ -0,0 +1,228 @@
+package promos
+
+import (
+	"strings"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	// Promo codes
+	FindAll(filter PromoCodeFilter) ([]entities.PromoCode, error)
+	FindByID(id uint) (entities.PromoCode, error)
+	FindByCode(code string) (entities.PromoCode, error)
+	FindExistingCodes(codes []string) ([]string, error)
+	Create(promo *entities.PromoCode) error
+	CreateBatch(promos []entities.PromoCode) error
+	Update(promo *entities.PromoCode) error
+	ReplaceTargets(promo *entities.PromoCode, tryOuts []entities.TryOut, courses []entities.Course) error
+	Delete(id uint) error
+
+	// Redemptions
+	FindRedemptionsByPromoID(promoID uint) ([]entities.PromoRedemption, error)
+	CountUserRedemptions(promoID, userID uint) (int64, error)
+
+	// Redeem locks the promo code row, runs check against the fresh row and the user's
+	// redemption count, then stores the redemption and increments the usage counter.
+	Redeem(promoID uint, redemption *entities.PromoRedemption, check func(promo entities.PromoCode, userUses int64) error) error
+	// ReleaseByRegistration removes redemptions tied to a registration and frees their usage slots.
+	// column is either "try_out_registration_id" or "course_registration_id".
+	ReleaseByRegistration(column string, registrationID uint) error
+
+	// Lookups
+	FindUserByID(id uint) (entities.User, error)
+	FindTryOutsByIDs(ids []uint) ([]entities.TryOut, error)
+	FindCoursesByIDs(ids []uint) ([]entities.Course, error)
+	FindTryOutByID(id uint) (entities.TryOut, error)
+	FindCourseByID(id uint) (entities.Course, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Promo Code Methods
+// ==========================================
+
+func (r *repository) FindAll(filter PromoCodeFilter) ([]entities.PromoCode, error) {
+	var promos []entities.PromoCode
+	query := r.db.Preload("TryOuts").Preload("Courses").Order("created_at DESC")
+	if filter.BatchLabel != "" {
+		query = query.Where("batch_label = ?", filter.BatchLabel)
+	}
+	if filter.Search != "" {
+		query = query.Where("code LIKE ?", "%"+strings.ToUpper(filter.Search)+"%")
+	}
+	err := query.Find(&promos).Error
+	return promos, err
+}
+
+func (r *repository) FindByID(id uint) (entities.PromoCode, error) {
+	var promo entities.PromoCode
+	err := r.db.Preload("TryOuts").Preload("Courses").First(&promo, id).Error
+	return promo, err
+}
+
+func (r *repository) FindByCode(code string) (entities.PromoCode, error) {
+	var promo entities.PromoCode
+	err := r.db.Preload("TryOuts").Preload("Courses").
+		Where("code = ?", code).
+		First(&promo).Error
+	return promo, err
+}
+
+func (r *repository) FindExistingCodes(codes []string) ([]string, error) {
+	var existing []string
+	if len(codes) == 0 {
+		return existing, nil
+	}
+	err := r.db.Unscoped().Model(&entities.PromoCode{}).Where("code IN ?", codes).Pluck("code", &existing).Error
+	return existing, err
+}
+
+func (r *repository) Create(promo *entities.PromoCode) error {
+	return r.db.Create(promo).Error
+}
+
+func (r *repository) CreateBatch(promos []entities.PromoCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&promos, 100).Error
+	})
+}
+
+func (r *repository) Update(promo *entities.PromoCode) error {
+	return r.db.Omit("TryOuts", "Courses").Save(promo).Error
+}
+
+func (r *repository) ReplaceTargets(promo *entities.PromoCode, tryOuts []entities.TryOut, courses []entities.Course) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(promo).Association("TryOuts").Replace(tryOuts); err != nil {
+			return err
+		}
+		return tx.Model(promo).Association("Courses").Replace(courses)
+	})
+}
+
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entities.PromoCode{}, id).Error
+}
+
+// ==========================================
+// Redemption Methods
+// ==========================================
+
+func (r *repository) FindRedemptionsByPromoID(promoID uint) ([]entities.PromoRedemption, error) {
+	var redemptions []entities.PromoRedemption
+	err := r.db.Where("promo_code_id = ?", promoID).
+		Preload("User").
+		Order("redeemed_at DESC").
+		Find(&redemptions).Error
+	return redemptions, err
+}
+
+func (r *repository) CountUserRedemptions(promoID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.PromoRedemption{}).
+		Where("promo_code_id = ? AND user_id = ?", promoID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) Redeem(promoID uint, redemption *entities.PromoRedemption, check func(promo entities.PromoCode, userUses int64) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var promo entities.PromoCode
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promo, promoID).Error; err != nil {
+			return err
+		}
+
+		var userUses int64
+		if err := tx.Model(&entities.PromoRedemption{}).
+			Where("promo_code_id = ? AND user_id = ?", promoID, redemption.UserID).
+			Count(&userUses).Error; err != nil {
+			return err
+		}
+
+		if err := check(promo, userUses); err != nil {
+			return err
+		}
+
+		redemption.PromoCodeID = promoID
+		if err := tx.Create(redemption).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.PromoCode{}).Where("id = ?", promoID).
+			UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error
+	})
+}
+
+func (r *repository) ReleaseByRegistration(column string, registrationID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return ReleaseRedemptions(tx, column, registrationID)
+	})
+}
+
+// ReleaseRedemptions is ReleaseByRegistration for callers already inside a transaction
+func ReleaseRedemptions(tx *gorm.DB, column string, registrationID uint) error {
+	var redemptions []entities.PromoRedemption
+	if err := tx.Where(column+" = ?", registrationID).Find(&redemptions).Error; err != nil {
+		return err
+	}
+	for _, redemption := range redemptions {
+		if err := tx.Delete(&redemption).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.PromoCode{}).
+			Where("id = ? AND used_count > 0", redemption.PromoCodeID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// Lookup Methods
+// ==========================================
+
+func (r *repository) FindUserByID(id uint) (entities.User, error) {
+	var user entities.User
+	err := r.db.First(&user, id).Error
+	return user, err
+}
+
+func (r *repository) FindTryOutsByIDs(ids []uint) ([]entities.TryOut, error) {
+	var tryOuts []entities.TryOut
+	if len(ids) == 0 {
+		return tryOuts, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tryOuts).Error
+	return tryOuts, err
+}
+
+func (r *repository) FindCoursesByIDs(ids []uint) ([]entities.Course, error) {
+	var courses []entities.Course
+	if len(ids) == 0 {
+		return courses, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&courses).Error
+	return courses, err
+}
+
+func (r *repository) FindTryOutByID(id uint) (entities.TryOut, error) {
+	var tryOut entities.TryOut
+	err := r.db.First(&tryOut, id).Error
+	return tryOut, err
+}
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
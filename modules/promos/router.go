@@ -0,0 +1,28 @@
+package promos
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func PromoRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+	repo := NewRepository(migrations.GetDB())
+	service := NewService(repo)
+	handler := NewHandler(service)
+
+	// User: preview the price after applying a code
+	router.POST("/promo-codes/validate", requireAuth, handler.ValidateHandler)
+
+	// Admin: manage promo codes
+	adminRoutes := router.Group("/promo-codes")
+	adminRoutes.Use(requireAuth, requireAdmin)
+	{
+		adminRoutes.GET("", handler.GetAllHandler)
+		adminRoutes.POST("", handler.CreateHandler)
+		adminRoutes.POST("/generate", handler.GenerateHandler)
+		adminRoutes.GET("/:id", handler.GetByIDHandler)
+		adminRoutes.PUT("/:id", handler.UpdateHandler)
+		adminRoutes.DELETE("/:id", handler.DeleteHandler)
+		adminRoutes.GET("/:id/redemptions", handler.GetRedemptionsHandler)
+	}
+}
@@ -0,0 +1,691 @@
+package promos
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+// TargetKind identifies what kind of package a promo code is applied to
+type TargetKind string
+
+const (
+	TargetTryOut TargetKind = "tryout"
+	TargetCourse TargetKind = "course"
+)
+
+// Target is the package being purchased and its undiscounted price
+type Target struct {
+	Kind  TargetKind
+	ID    uint
+	Price float64
+}
+
+// Quote is the result of applying a promo code to a target
+type Quote struct {
+	PromoCodeID    uint
+	Code           string
+	OriginalPrice  float64
+	DiscountAmount float64
+	FinalAmount    float64
+}
+
+// codeErrors are the messages returned when a code cannot be used; handlers map them to 400
+var codeErrors = map[string]bool{
+	"promo code not found":                           true,
+	"promo code is not active":                       true,
+	"promo code is not valid yet":                    true,
+	"promo code has expired":                         true,
+	"promo code usage limit reached":                 true,
+	"you have already used this promo code":          true,
+	"promo code is not valid for this package":       true,
+	"promo code is restricted to another school":     true,
+	"promo code cannot be applied to a free package": true,
+}
+
+// IsCodeError reports whether err explains why a promo code was refused
+func IsCodeError(err error) bool {
+	return err != nil && codeErrors[err.Error()]
+}
+
+// codeAlphabet omits characters that are easy to misread (0/O, 1/I/L)
+const codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// generatedCodeLength is the random part of bulk-generated codes
+const generatedCodeLength = 8
+
+type promoService struct {
+	repo Repository
+}
+
+type Service interface {
+	// Admin actions
+	GetAll(filter PromoCodeFilter, requestID string) ([]PromoCodeResponse, error)
+	GetByID(id uint, requestID string) (*PromoCodeResponse, error)
+	Create(input CreatePromoCodeInput, adminUserID uint, requestID string) (*PromoCodeResponse, error)
+	Generate(input GeneratePromoCodesInput, adminUserID uint, requestID string) ([]PromoCodeResponse, error)
+	Update(id uint, input UpdatePromoCodeInput, requestID string) (*PromoCodeResponse, error)
+	Delete(id uint, requestID string) error
+	GetRedemptions(id uint, requestID string) ([]RedemptionResponse, error)
+
+	// User actions
+	Validate(input ValidatePromoCodeInput, userID uint, requestID string) (*QuoteResponse, error)
+
+	// Used by registration services
+	Quote(code string, target Target, userID uint) (*Quote, error)
+	Redeem(code string, target Target, userID uint, registrationID uint) (*Quote, error)
+	Release(kind TargetKind, registrationID uint) error
+}
+
+func NewService(repo Repository) Service {
+	return &promoService{repo: repo}
+}
+
+// ==========================================
+// Admin Actions
+// ==========================================
+
+func (s *promoService) GetAll(filter PromoCodeFilter, requestID string) ([]PromoCodeResponse, error) {
+	promos, err := s.repo.FindAll(filter)
+	if err != nil {
+		utils.LogError("promos", "get_all", "Failed to fetch promo codes: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	responses := make([]PromoCodeResponse, 0, len(promos))
+	for _, p := range promos {
+		responses = append(responses, ToPromoCodeResponse(p))
+	}
+	return responses, nil
+}
+
+func (s *promoService) GetByID(id uint, requestID string) (*PromoCodeResponse, error) {
+	promo, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, err
+	}
+	response := ToPromoCodeResponse(promo)
+	return &response, nil
+}
+
+func (s *promoService) Create(input CreatePromoCodeInput, adminUserID uint, requestID string) (*PromoCodeResponse, error) {
+	utils.LogInfo("promos", "create", "Admin creating promo code", requestID, adminUserID, map[string]any{
+		"code": input.Code,
+	})
+
+	code := normalizeCode(input.Code)
+	if _, err := s.repo.FindByCode(code); err == nil {
+		return nil, errors.New("promo code already exists")
+	}
+	if err := validateDiscount(input.DiscountType, input.DiscountValue); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(input.ValidFrom, input.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	tryOuts, courses, err := s.loadTargets(input.TryOutIDs, input.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	promo := entities.PromoCode{
+		Code:            code,
+		Description:     input.Description,
+		DiscountType:    entities.DiscountType(input.DiscountType),
+		DiscountValue:   input.DiscountValue,
+		MaxDiscount:     input.MaxDiscount,
+		MaxUses:         input.MaxUses,
+		MaxUsesPerUser:  1,
+		ValidFrom:       input.ValidFrom,
+		ValidUntil:      input.ValidUntil,
+		IsActive:        true,
+		School:          strings.TrimSpace(input.School),
+		CreatedByUserID: adminUserID,
+		TryOuts:         tryOuts,
+		Courses:         courses,
+	}
+	if input.MaxUsesPerUser != nil {
+		promo.MaxUsesPerUser = *input.MaxUsesPerUser
+	}
+	if input.IsActive != nil {
+		promo.IsActive = *input.IsActive
+	}
+
+	if err := s.repo.Create(&promo); err != nil {
+		utils.LogError("promos", "create", "Failed to create promo code: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("promos", "create", "Promo code created", requestID, adminUserID, map[string]any{
+		"promo_code_id": promo.ID,
+		"code":          promo.Code,
+	})
+
+	response := ToPromoCodeResponse(promo)
+	return &response, nil
+}
+
+func (s *promoService) Generate(input GeneratePromoCodesInput, adminUserID uint, requestID string) ([]PromoCodeResponse, error) {
+	utils.LogInfo("promos", "generate", "Admin generating promo code batch", requestID, adminUserID, map[string]any{
+		"batch_label": input.BatchLabel,
+		"count":       input.Count,
+		"school":      input.School,
+	})
+
+	if err := validateDiscount(input.DiscountType, input.DiscountValue); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(input.ValidFrom, input.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	tryOuts, courses, err := s.loadTargets(input.TryOutIDs, input.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateUniqueCodes(normalizeCode(input.Prefix), input.Count)
+	if err != nil {
+		utils.LogError("promos", "generate", "Failed to generate codes: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	singleUse := 1
+	promos := make([]entities.PromoCode, 0, len(codes))
+	for _, code := range codes {
+		promos = append(promos, entities.PromoCode{
+			Code:            code,
+			Description:     input.Description,
+			BatchLabel:      input.BatchLabel,
+			DiscountType:    entities.DiscountType(input.DiscountType),
+			DiscountValue:   input.DiscountValue,
+			MaxDiscount:     input.MaxDiscount,
+			MaxUses:         &singleUse,
+			MaxUsesPerUser:  1,
+			ValidFrom:       input.ValidFrom,
+			ValidUntil:      input.ValidUntil,
+			IsActive:        true,
+			School:          strings.TrimSpace(input.School),
+			CreatedByUserID: adminUserID,
+			TryOuts:         tryOuts,
+			Courses:         courses,
+		})
+	}
+
+	if err := s.repo.CreateBatch(promos); err != nil {
+		utils.LogError("promos", "generate", "Failed to save promo code batch: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("promos", "generate", "Promo code batch generated", requestID, adminUserID, map[string]any{
+		"batch_label": input.BatchLabel,
+		"count":       len(promos),
+	})
+
+	responses := make([]PromoCodeResponse, 0, len(promos))
+	for _, p := range promos {
+		responses = append(responses, ToPromoCodeResponse(p))
+	}
+	return responses, nil
+}
+
+func (s *promoService) Update(id uint, input UpdatePromoCodeInput, requestID string) (*PromoCodeResponse, error) {
+	promo, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, err
+	}
+
+	if input.Description != nil {
+		promo.Description = *input.Description
+	}
+	if input.DiscountType != nil {
+		promo.DiscountType = entities.DiscountType(*input.DiscountType)
+	}
+	if input.DiscountValue != nil {
+		promo.DiscountValue = *input.DiscountValue
+	}
+	if input.MaxDiscount != nil {
+		promo.MaxDiscount = input.MaxDiscount
+		if *input.MaxDiscount == 0 {
+			promo.MaxDiscount = nil
+		}
+	}
+	if input.MaxUses != nil {
+		promo.MaxUses = input.MaxUses
+		if *input.MaxUses == 0 {
+			promo.MaxUses = nil
+		}
+	}
+	if input.MaxUsesPerUser != nil {
+		promo.MaxUsesPerUser = *input.MaxUsesPerUser
+	}
+	if input.ValidFrom != nil {
+		promo.ValidFrom = input.ValidFrom
+	}
+	if input.ValidUntil != nil {
+		promo.ValidUntil = input.ValidUntil
+	}
+	if input.IsActive != nil {
+		promo.IsActive = *input.IsActive
+	}
+	if input.School != nil {
+		promo.School = strings.TrimSpace(*input.School)
+	}
+
+	if err := validateDiscount(string(promo.DiscountType), promo.DiscountValue); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(promo.ValidFrom, promo.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&promo); err != nil {
+		utils.LogError("promos", "update", "Failed to update promo code: "+err.Error(), requestID, 0, map[string]any{
+			"promo_code_id": id,
+		})
+		return nil, err
+	}
+
+	if input.TryOutIDs != nil || input.CourseIDs != nil {
+		tryOutIDs := promoTryOutIDs(promo)
+		if input.TryOutIDs != nil {
+			tryOutIDs = *input.TryOutIDs
+		}
+		courseIDs := promoCourseIDs(promo)
+		if input.CourseIDs != nil {
+			courseIDs = *input.CourseIDs
+		}
+		tryOuts, courses, err := s.loadTargets(tryOutIDs, courseIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.ReplaceTargets(&promo, tryOuts, courses); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogSuccess("promos", "update", "Promo code updated", requestID, 0, map[string]any{
+		"promo_code_id": id,
+	})
+
+	response := ToPromoCodeResponse(updated)
+	return &response, nil
+}
+
+func (s *promoService) Delete(id uint, requestID string) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("promo code not found")
+		}
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		utils.LogError("promos", "delete", "Failed to delete promo code: "+err.Error(), requestID, 0, map[string]any{
+			"promo_code_id": id,
+		})
+		return err
+	}
+
+	utils.LogSuccess("promos", "delete", "Promo code deleted", requestID, 0, map[string]any{
+		"promo_code_id": id,
+	})
+	return nil
+}
+
+func (s *promoService) GetRedemptions(id uint, requestID string) ([]RedemptionResponse, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, err
+	}
+
+	redemptions, err := s.repo.FindRedemptionsByPromoID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]RedemptionResponse, 0, len(redemptions))
+	for _, r := range redemptions {
+		responses = append(responses, ToRedemptionResponse(r))
+	}
+	return responses, nil
+}
+
+// ==========================================
+// User Actions
+// ==========================================
+
+func (s *promoService) Validate(input ValidatePromoCodeInput, userID uint, requestID string) (*QuoteResponse, error) {
+	var target Target
+	switch {
+	case input.TryOutID != nil:
+		tryOut, err := s.repo.FindTryOutByID(*input.TryOutID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("try out not found")
+			}
+			return nil, err
+		}
+		target = Target{Kind: TargetTryOut, ID: tryOut.ID, Price: tryOut.Price}
+		if tryOut.IsFree {
+			target.Price = 0
+		}
+	case input.CourseID != nil:
+		course, err := s.repo.FindCourseByID(*input.CourseID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("course not found")
+			}
+			return nil, err
+		}
+		target = Target{Kind: TargetCourse, ID: course.ID, Price: course.Price}
+		if course.IsFree {
+			target.Price = 0
+		}
+	default:
+		return nil, errors.New("tryOutId or courseId is required")
+	}
+
+	quote, err := s.Quote(input.Code, target, userID)
+	if err != nil {
+		utils.LogWarning("promos", "validate", "Promo code refused: "+err.Error(), requestID, userID, map[string]any{
+			"code": input.Code,
+		})
+		return nil, err
+	}
+
+	response := ToQuoteResponse(*quote)
+	return &response, nil
+}
+
+// ==========================================
+// Redemption (used by registration services)
+// ==========================================
+
+// Quote checks that code can be used by userID on target and computes the discounted price.
+// It does not reserve the code; call Redeem once the registration exists.
+func (s *promoService) Quote(code string, target Target, userID uint) (*Quote, error) {
+	promo, err := s.repo.FindByCode(normalizeCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, err
+	}
+
+	if target.Price <= 0 {
+		return nil, errors.New("promo code cannot be applied to a free package")
+	}
+	if !appliesTo(promo, target) {
+		return nil, errors.New("promo code is not valid for this package")
+	}
+
+	if promo.School != "" {
+		user, err := s.repo.FindUserByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		// The profile school is self-declared, so only a verified school counts
+		if !strings.EqualFold(strings.TrimSpace(user.VerifiedSchool), promo.School) {
+			return nil, errors.New("promo code is restricted to another school")
+		}
+	}
+
+	userUses, err := s.repo.CountUserRedemptions(promo.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUsable(promo, userUses, time.Now()); err != nil {
+		return nil, err
+	}
+
+	discount := computeDiscount(promo, target.Price)
+	return &Quote{
+		PromoCodeID:    promo.ID,
+		Code:           promo.Code,
+		OriginalPrice:  target.Price,
+		DiscountAmount: discount,
+		FinalAmount:    roundAmount(target.Price - discount),
+	}, nil
+}
+
+// Redeem records the use of code for a registration. Usage limits are rechecked under a row lock
+// so concurrent registrations cannot exceed them.
+func (s *promoService) Redeem(code string, target Target, userID uint, registrationID uint) (*Quote, error) {
+	quote, err := s.Quote(code, target, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption := entities.PromoRedemption{
+		UserID:         userID,
+		OriginalPrice:  quote.OriginalPrice,
+		DiscountAmount: quote.DiscountAmount,
+	}
+	switch target.Kind {
+	case TargetTryOut:
+		redemption.TryOutRegistrationID = &registrationID
+	case TargetCourse:
+		redemption.CourseRegistrationID = &registrationID
+	}
+
+	err = s.repo.Redeem(quote.PromoCodeID, &redemption, func(promo entities.PromoCode, userUses int64) error {
+		return checkUsable(promo, userUses, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Release frees the promo code usage held by a registration, e.g. when it is deleted
+func (s *promoService) Release(kind TargetKind, registrationID uint) error {
+	column := "try_out_registration_id"
+	if kind == TargetCourse {
+		column = "course_registration_id"
+	}
+	return s.repo.ReleaseByRegistration(column, registrationID)
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (s *promoService) loadTargets(tryOutIDs, courseIDs []uint) ([]entities.TryOut, []entities.Course, error) {
+	tryOuts, err := s.repo.FindTryOutsByIDs(tryOutIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tryOuts) != len(uniqueIDs(tryOutIDs)) {
+		return nil, nil, errors.New("one or more try outs not found")
+	}
+
+	courses, err := s.repo.FindCoursesByIDs(courseIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(courses) != len(uniqueIDs(courseIDs)) {
+		return nil, nil, errors.New("one or more courses not found")
+	}
+	return tryOuts, courses, nil
+}
+
+func (s *promoService) generateUniqueCodes(prefix string, count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+
+	// Collisions are rare; a few rounds of regenerating the clashing codes is enough
+	for round := 0; round < 5 && len(codes) < count; round++ {
+		var candidates []string
+		for len(candidates) < count-len(codes) {
+			code, err := randomCode(prefix)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[code] {
+				seen[code] = true
+				candidates = append(candidates, code)
+			}
+		}
+
+		existing, err := s.repo.FindExistingCodes(candidates)
+		if err != nil {
+			return nil, err
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			taken[code] = true
+		}
+		for _, code := range candidates {
+			if !taken[code] {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	if len(codes) < count {
+		return nil, errors.New("failed to generate unique promo codes")
+	}
+	return codes, nil
+}
+
+func randomCode(prefix string) (string, error) {
+	var sb strings.Builder
+	if prefix != "" {
+		sb.WriteString(prefix)
+		sb.WriteString("-")
+	}
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := 0; i < generatedCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// checkUsable validates the parts of a code that change over time: status, validity window and usage limits
+func checkUsable(promo entities.PromoCode, userUses int64, now time.Time) error {
+	if !promo.IsActive {
+		return errors.New("promo code is not active")
+	}
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return errors.New("promo code is not valid yet")
+	}
+	if promo.ValidUntil != nil && now.After(*promo.ValidUntil) {
+		return errors.New("promo code has expired")
+	}
+	if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+		return errors.New("promo code usage limit reached")
+	}
+	if promo.MaxUsesPerUser > 0 && userUses >= int64(promo.MaxUsesPerUser) {
+		return errors.New("you have already used this promo code")
+	}
+	return nil
+}
+
+// appliesTo reports whether the code is restricted to packages that include target.
+// A code with no package restrictions applies to everything.
+func appliesTo(promo entities.PromoCode, target Target) bool {
+	if len(promo.TryOuts) == 0 && len(promo.Courses) == 0 {
+		return true
+	}
+	switch target.Kind {
+	case TargetTryOut:
+		for _, t := range promo.TryOuts {
+			if t.ID == target.ID {
+				return true
+			}
+		}
+	case TargetCourse:
+		for _, c := range promo.Courses {
+			if c.ID == target.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func computeDiscount(promo entities.PromoCode, price float64) float64 {
+	var discount float64
+	switch promo.DiscountType {
+	case entities.DiscountTypePercentage:
+		discount = price * promo.DiscountValue / 100
+		if promo.MaxDiscount != nil && discount > *promo.MaxDiscount {
+			discount = *promo.MaxDiscount
+		}
+	case entities.DiscountTypeFixed:
+		discount = promo.DiscountValue
+	}
+	return roundAmount(math.Min(discount, price))
+}
+
+func validateDiscount(discountType string, value float64) error {
+	if discountType == string(entities.DiscountTypePercentage) && value > 100 {
+		return errors.New("percentage discount cannot exceed 100")
+	}
+	return nil
+}
+
+func validateWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return errors.New("validUntil must be after validFrom")
+	}
+	return nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func uniqueIDs(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func promoTryOutIDs(promo entities.PromoCode) []uint {
+	ids := make([]uint, 0, len(promo.TryOuts))
+	for _, t := range promo.TryOuts {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func promoCourseIDs(promo entities.PromoCode) []uint {
+	ids := make([]uint, 0, len(promo.Courses))
+	for _, c := range promo.Courses {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
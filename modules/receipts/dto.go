@@ -0,0 +1,56 @@
+package receipts
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// RECEIPT DTOs
+// ==========================================
+
+// ReceiptResponse is the response DTO for an issued receipt
+type ReceiptResponse struct {
+	ID             uint       `json:"id"`
+	Number         string     `json:"number"`
+	ItemType       string     `json:"itemType"`
+	ItemName       string     `json:"itemName"`
+	BuyerName      string     `json:"buyerName"`
+	BuyerEmail     string     `json:"buyerEmail"`
+	Price          float64    `json:"price"`
+	DiscountAmount float64    `json:"discountAmount"`
+	PromoCode      string     `json:"promoCode,omitempty"`
+	Total          float64    `json:"total"`
+	PaymentMethod  string     `json:"paymentMethod"`
+	IssuedAt       time.Time  `json:"issuedAt"`
+	EmailedAt      *time.Time `json:"emailedAt,omitempty"`
+}
+
+// ReceiptFile is a generated PDF ready to be sent to the client
+type ReceiptFile struct {
+	FileName string
+	Content  []byte
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToReceiptResponse(r entities.Receipt) ReceiptResponse {
+	return ReceiptResponse{
+		ID:             r.ID,
+		Number:         r.Number,
+		ItemType:       string(r.ItemType),
+		ItemName:       r.ItemName,
+		BuyerName:      r.BuyerName,
+		BuyerEmail:     r.BuyerEmail,
+		Price:          r.Price,
+		DiscountAmount: r.DiscountAmount,
+		PromoCode:      r.PromoCode,
+		Total:          r.Total,
+		PaymentMethod:  r.PaymentMethod,
+		IssuedAt:       r.IssuedAt,
+		EmailedAt:      r.EmailedAt,
+	}
+}
@@ -0,0 +1,115 @@
+package receipts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	DownloadTryOutReceiptHandler(c *gin.Context)
+	DownloadCourseReceiptHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	if !exists {
+		return false
+	}
+	roleStr, ok := role.(string)
+	return ok && roleStr == "ADMIN"
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Download Handlers
+// ==========================================
+
+func (h *handler) DownloadTryOutReceiptHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.service.GetTryOutReceiptPDF(id, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondReceiptError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	c.Data(http.StatusOK, "application/pdf", file.Content)
+}
+
+func (h *handler) DownloadCourseReceiptHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.service.GetCourseReceiptPDF(id, getUserID(c), isAdmin(c), getRequestID(c))
+	if err != nil {
+		respondReceiptError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	c.Data(http.StatusOK, "application/pdf", file.Content)
+}
+
+func respondReceiptError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
+	case "you can only download your own receipt":
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
+	case "registration is not paid yet", "registration is free", "registration was paid through a bundle":
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("No receipt available", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to generate receipt", err.Error(), nil))
+	}
+}
@@ -0,0 +1,103 @@
+package receipts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	// Registrations
+	FindTryOutRegistrationByID(id uint) (entities.TryOutRegistration, error)
+	FindCourseRegistrationByID(id uint) (entities.CourseRegistration, error)
+	FindPaidInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error)
+
+	// Receipts
+	FindByTryOutRegistration(registrationID uint) (entities.Receipt, error)
+	FindByCourseRegistration(registrationID uint) (entities.Receipt, error)
+	// Issue assigns the next number of the issue year and stores the receipt in one transaction
+	Issue(receipt *entities.Receipt) error
+	MarkEmailed(id uint, at time.Time) error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Registration Methods
+// ==========================================
+
+func (r *repository) FindTryOutRegistrationByID(id uint) (entities.TryOutRegistration, error) {
+	var registration entities.TryOutRegistration
+	err := r.db.Preload("TryOutPackage").Preload("User").First(&registration, id).Error
+	return registration, err
+}
+
+func (r *repository) FindCourseRegistrationByID(id uint) (entities.CourseRegistration, error) {
+	var registration entities.CourseRegistration
+	err := r.db.Preload("Course").Preload("User").First(&registration, id).Error
+	return registration, err
+}
+
+func (r *repository) FindPaidInvoiceByRegistration(registrationID uint) (entities.PaymentInvoice, error) {
+	var invoice entities.PaymentInvoice
+	err := r.db.Where("registration_id = ? AND status = ?", registrationID, entities.InvoiceStatusPaid).
+		Order("paid_at DESC").
+		First(&invoice).Error
+	return invoice, err
+}
+
+// ==========================================
+// Receipt Methods
+// ==========================================
+
+func (r *repository) FindByTryOutRegistration(registrationID uint) (entities.Receipt, error) {
+	var receipt entities.Receipt
+	err := r.db.Where("try_out_registration_id = ?", registrationID).First(&receipt).Error
+	return receipt, err
+}
+
+func (r *repository) FindByCourseRegistration(registrationID uint) (entities.Receipt, error) {
+	var receipt entities.Receipt
+	err := r.db.Where("course_registration_id = ?", registrationID).First(&receipt).Error
+	return receipt, err
+}
+
+func (r *repository) Issue(receipt *entities.Receipt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		year := receipt.IssuedAt.Year()
+
+		// Make sure the year's counter exists, then lock it so numbers are gapless and unique
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.ReceiptSequence{Year: year}).Error; err != nil {
+			return err
+		}
+		var sequence entities.ReceiptSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&sequence, "year = ?", year).Error; err != nil {
+			return err
+		}
+
+		sequence.LastNumber++
+		if err := tx.Model(&sequence).Update("last_number", sequence.LastNumber).Error; err != nil {
+			return err
+		}
+
+		receipt.Year = year
+		receipt.Sequence = sequence.LastNumber
+		receipt.Number = fmt.Sprintf("INV-%d-%06d", year, sequence.LastNumber)
+		return tx.Omit("User").Create(receipt).Error
+	})
+}
+
+func (r *repository) MarkEmailed(id uint, at time.Time) error {
+	return r.db.Model(&entities.Receipt{}).Where("id = ?", id).Update("emailed_at", at).Error
+}
@@ -0,0 +1,16 @@
+package receipts
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func ReceiptRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc) {
+	repo := NewRepository(migrations.GetDB())
+	service := NewService(repo)
+	handler := NewHandler(service)
+
+	// Student (or admin): download the PDF receipt of a paid registration
+	router.GET("/users/me/tryout-registrations/:id/receipt", requireAuth, handler.DownloadTryOutReceiptHandler)
+	router.GET("/users/me/course-registrations/:id/receipt", requireAuth, handler.DownloadCourseReceiptHandler)
+}
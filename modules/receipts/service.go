@@ -0,0 +1,401 @@
+package receipts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+// manualPaymentMethod is shown when the payment was verified from an uploaded transfer proof
+const manualPaymentMethod = "Bank transfer (manual verification)"
+
+type receiptService struct {
+	repo Repository
+}
+
+type Service interface {
+	// Issuing (called when a paid registration is approved); both are idempotent
+	IssueTryOutReceipt(registrationID uint, requestID string) (*ReceiptResponse, error)
+	IssueCourseReceipt(registrationID uint, requestID string) (*ReceiptResponse, error)
+
+	// Downloads
+	GetTryOutReceiptPDF(registrationID uint, userID uint, isAdmin bool, requestID string) (*ReceiptFile, error)
+	GetCourseReceiptPDF(registrationID uint, userID uint, isAdmin bool, requestID string) (*ReceiptFile, error)
+}
+
+func NewService(repo Repository) Service {
+	return &receiptService{repo: repo}
+}
+
+// ==========================================
+// Issuing
+// ==========================================
+
+func (s *receiptService) IssueTryOutReceipt(registrationID uint, requestID string) (*ReceiptResponse, error) {
+	receipt, err := s.issueTryOutReceipt(registrationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToReceiptResponse(receipt)
+	return &response, nil
+}
+
+func (s *receiptService) IssueCourseReceipt(registrationID uint, requestID string) (*ReceiptResponse, error) {
+	receipt, err := s.issueCourseReceipt(registrationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToReceiptResponse(receipt)
+	return &response, nil
+}
+
+func (s *receiptService) issueTryOutReceipt(registrationID uint, requestID string) (entities.Receipt, error) {
+	if existing, err := s.repo.FindByTryOutRegistration(registrationID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return existing, err
+	}
+
+	registration, err := s.repo.FindTryOutRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Receipt{}, errors.New("registration not found")
+		}
+		return entities.Receipt{}, err
+	}
+	if registration.PaymentStatus != entities.PaymentStatusApproved {
+		return entities.Receipt{}, errors.New("registration is not paid yet")
+	}
+	if registration.BundlePurchaseID != nil {
+		return entities.Receipt{}, errors.New("registration was paid through a bundle")
+	}
+
+	// Registrations created before pricing snapshots existed fall back to the package price
+	price := registration.OriginalPrice
+	total := registration.FinalAmount
+	if registration.PromoCodeID == nil && registration.OriginalPrice == 0 {
+		price = registration.TryOutPackage.Price
+		total = price
+	}
+	if total <= 0 {
+		return entities.Receipt{}, errors.New("registration is free")
+	}
+
+	paymentMethod := manualPaymentMethod
+	if invoice, err := s.repo.FindPaidInvoiceByRegistration(registrationID); err == nil {
+		paymentMethod = invoice.PaymentMethod
+		if paymentMethod == "" {
+			paymentMethod = "Online payment"
+		}
+	}
+
+	receipt := entities.Receipt{
+		UserID:               registration.UserID,
+		ItemType:             entities.ReceiptItemTryOut,
+		TryOutRegistrationID: &registration.ID,
+		ItemName:             "Try Out: " + registration.TryOutPackage.Name,
+		Price:                price,
+		DiscountAmount:       registration.DiscountAmount,
+		PromoCode:            registration.PromoCode,
+		Total:                total,
+		PaymentMethod:        paymentMethod,
+	}
+	return s.issue(receipt, registration.User, requestID, func() (entities.Receipt, error) {
+		return s.repo.FindByTryOutRegistration(registrationID)
+	})
+}
+
+func (s *receiptService) issueCourseReceipt(registrationID uint, requestID string) (entities.Receipt, error) {
+	if existing, err := s.repo.FindByCourseRegistration(registrationID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return existing, err
+	}
+
+	registration, err := s.repo.FindCourseRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Receipt{}, errors.New("registration not found")
+		}
+		return entities.Receipt{}, err
+	}
+	if registration.Status != "approved" {
+		return entities.Receipt{}, errors.New("registration is not paid yet")
+	}
+	if registration.BundlePurchaseID != nil {
+		return entities.Receipt{}, errors.New("registration was paid through a bundle")
+	}
+	if registration.FinalAmount <= 0 {
+		return entities.Receipt{}, errors.New("registration is free")
+	}
+
+	receipt := entities.Receipt{
+		UserID:               registration.UserID,
+		ItemType:             entities.ReceiptItemCourse,
+		CourseRegistrationID: &registration.ID,
+		ItemName:             "Course: " + registration.Course.NameCourse,
+		Price:                registration.OriginalPrice,
+		DiscountAmount:       registration.DiscountAmount,
+		PromoCode:            registration.PromoCode,
+		Total:                registration.FinalAmount,
+		PaymentMethod:        manualPaymentMethod,
+	}
+	return s.issue(receipt, registration.User, requestID, func() (entities.Receipt, error) {
+		return s.repo.FindByCourseRegistration(registrationID)
+	})
+}
+
+// issue numbers and stores the receipt, then emails it to the buyer.
+// findExisting is used when a concurrent request issued the receipt first.
+func (s *receiptService) issue(receipt entities.Receipt, buyer entities.User, requestID string, findExisting func() (entities.Receipt, error)) (entities.Receipt, error) {
+	receipt.BuyerName = buyer.Username
+	receipt.BuyerEmail = buyer.Email
+	receipt.BuyerPhone = buyer.NoTelp
+	receipt.BuyerSchool = buyer.School
+	receipt.IssuedAt = time.Now()
+
+	if err := s.repo.Issue(&receipt); err != nil {
+		if existing, findErr := findExisting(); findErr == nil {
+			return existing, nil
+		}
+		utils.LogError("receipts", "issue", "Failed to issue receipt: "+err.Error(), requestID, receipt.UserID, nil)
+		return receipt, err
+	}
+
+	utils.LogSuccess("receipts", "issue", "Receipt issued", requestID, receipt.UserID, map[string]any{
+		"number":    receipt.Number,
+		"item_type": string(receipt.ItemType),
+		"total":     receipt.Total,
+	})
+
+	s.sendReceiptEmail(&receipt, requestID)
+	return receipt, nil
+}
+
+func (s *receiptService) sendReceiptEmail(receipt *entities.Receipt, requestID string) {
+	if receipt.BuyerEmail == "" {
+		return
+	}
+
+	downloadPath := "/api/v1/users/me/tryout-registrations/%d/receipt"
+	registrationID := uint(0)
+	if receipt.TryOutRegistrationID != nil {
+		registrationID = *receipt.TryOutRegistrationID
+	} else if receipt.CourseRegistrationID != nil {
+		downloadPath = "/api/v1/users/me/course-registrations/%d/receipt"
+		registrationID = *receipt.CourseRegistrationID
+	}
+	downloadURL := fmt.Sprintf(downloadPath, registrationID)
+	if baseURL := strings.TrimRight(os.Getenv("API_BASE_URL"), "/"); baseURL != "" {
+		downloadURL = baseURL + downloadURL
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s, thank you for your payment.<br><br>"+
+			"Receipt number: <b>%s</b><br>"+
+			"Item: %s<br>"+
+			"Total paid: Rp %s<br>"+
+			"Payment method: %s<br><br>"+
+			"You can download the PDF receipt at any time: %s",
+		receipt.BuyerName, receipt.Number, receipt.ItemName, formatAmount(receipt.Total), receipt.PaymentMethod, downloadURL,
+	)
+	if err := utils.SendEmail(receipt.BuyerEmail, "Payment Receipt "+receipt.Number, body); err != nil {
+		utils.LogWarning("receipts", "email", "Failed to send receipt: "+err.Error(), requestID, receipt.UserID, map[string]any{
+			"number": receipt.Number,
+		})
+		return
+	}
+
+	now := time.Now()
+	if err := s.repo.MarkEmailed(receipt.ID, now); err != nil {
+		utils.LogError("receipts", "email", "Failed to mark receipt as emailed: "+err.Error(), requestID, receipt.UserID, nil)
+		return
+	}
+	receipt.EmailedAt = &now
+}
+
+// ==========================================
+// Downloads
+// ==========================================
+
+func (s *receiptService) GetTryOutReceiptPDF(registrationID uint, userID uint, isAdmin bool, requestID string) (*ReceiptFile, error) {
+	utils.LogInfo("receipts", "download", "Downloading try out receipt", requestID, userID, map[string]any{
+		"registration_id": registrationID,
+	})
+
+	registration, err := s.repo.FindTryOutRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+	if !isAdmin && registration.UserID != userID {
+		return nil, errors.New("you can only download your own receipt")
+	}
+
+	// Registrations approved before receipts existed get theirs on first download
+	receipt, err := s.issueTryOutReceipt(registrationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return renderReceiptFile(receipt)
+}
+
+func (s *receiptService) GetCourseReceiptPDF(registrationID uint, userID uint, isAdmin bool, requestID string) (*ReceiptFile, error) {
+	utils.LogInfo("receipts", "download", "Downloading course receipt", requestID, userID, map[string]any{
+		"registration_id": registrationID,
+	})
+
+	registration, err := s.repo.FindCourseRegistrationByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+	if !isAdmin && registration.UserID != userID {
+		return nil, errors.New("you can only download your own receipt")
+	}
+
+	receipt, err := s.issueCourseReceipt(registrationID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return renderReceiptFile(receipt)
+}
+
+func renderReceiptFile(receipt entities.Receipt) (*ReceiptFile, error) {
+	content, err := renderReceiptPDF(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &ReceiptFile{
+		FileName: fmt.Sprintf("receipt-%s.pdf", receipt.Number),
+		Content:  content,
+	}, nil
+}
+
+// ==========================================
+// PDF Rendering
+// ==========================================
+
+func renderReceiptPDF(receipt entities.Receipt) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Receipt "+receipt.Number, true)
+	pdf.SetAuthor("Reduka", true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	// Header
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Reduka", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(0, 7, "Invoice / Payment Receipt", "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	meta := [][2]string{
+		{"Receipt number", receipt.Number},
+		{"Issued at", receipt.IssuedAt.Format("02 January 2006 15:04 MST")},
+		{"Status", "PAID"},
+	}
+	for _, row := range meta {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, row[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Buyer
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Billed To", "", 1, "L", false, 0, "")
+	buyer := [][2]string{
+		{"Name", receipt.BuyerName},
+		{"Email", receipt.BuyerEmail},
+		{"Phone", orDash(receipt.BuyerPhone)},
+		{"School", orDash(receipt.BuyerSchool)},
+	}
+	for _, row := range buyer {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Item
+	widths := []float64{110, 70}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(widths[0], 7, "Item", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(widths[1], 7, "Amount", "1", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(widths[0], 7, tr(truncate(receipt.ItemName, 60)), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(widths[1], 7, "Rp "+formatAmount(receipt.Price), "1", 1, "R", false, 0, "")
+
+	if receipt.DiscountAmount > 0 {
+		label := "Discount"
+		if receipt.PromoCode != "" {
+			label += " (" + receipt.PromoCode + ")"
+		}
+		pdf.CellFormat(widths[0], 7, tr(label), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, "- Rp "+formatAmount(receipt.DiscountAmount), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(widths[0], 7, "Total Paid", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(widths[1], 7, "Rp "+formatAmount(receipt.Total), "1", 1, "R", true, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, "Payment method: "+tr(receipt.PaymentMethod), "", 1, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.CellFormat(0, 5, "This receipt was generated electronically and is valid without a signature.", "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatAmount renders a rupiah amount with thousands separators, e.g. 150000 -> "150.000"
+func formatAmount(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteRune('.')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String()
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
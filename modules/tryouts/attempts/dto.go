@@ -29,6 +29,7 @@ type AttemptCurrentStateResponse struct {
 	Status         string                    `json:"status"`
 	CurrentSubtest *SubtestBriefResponse     `json:"currentSubtest,omitempty"`
 	TimeRemaining  *int                      `json:"timeRemaining,omitempty"` // in seconds
+	TimeMultiplier float64                   `json:"timeMultiplier"`
 	SubtestResults []SubtestProgressResponse `json:"subtestProgress,omitempty"`
 }
 
@@ -54,7 +55,8 @@ type SubtestProgressResponse struct {
 	Status           string `json:"status"` // not_started, in_progress, completed
 	AnsweredCount    int    `json:"answeredCount"`
 	TotalCount       int    `json:"totalCount"`
-	TimeLimitSeconds int    `json:"timeLimitSeconds"`
+	TimeLimitSeconds int    `json:"timeLimitSeconds"` // includes accommodation and extensions
+	ExtraSeconds     int    `json:"extraSeconds,omitempty"`
 }
 
 // SubtestBriefResponse is a minimal subtest info
@@ -133,6 +135,60 @@ type SubmitSubtestInput struct {
 	Answers []SubmitAnswerInput `json:"answers" binding:"required,dive"`
 }
 
+// ==========================================
+// TIME ADJUSTMENT DTOs
+// ==========================================
+
+// SetTimeAccommodationInput is the input for granting a time multiplier to a registration
+type SetTimeAccommodationInput struct {
+	Multiplier float64 `json:"multiplier" binding:"required,gt=1,lte=3"`
+	Reason     string  `json:"reason" binding:"required"`
+}
+
+// GrantTimeExtensionInput is the input for granting one-off extra time on a subtest
+type GrantTimeExtensionInput struct {
+	ExtraSeconds int    `json:"extraSeconds" binding:"required,min=1,max=7200"`
+	Reason       string `json:"reason" binding:"required"`
+}
+
+// TimeAccommodationResponse shows the time multiplier granted to a registration
+type TimeAccommodationResponse struct {
+	ID              uint      `json:"id"`
+	RegistrationID  uint      `json:"registrationId"`
+	Multiplier      float64   `json:"multiplier"`
+	Reason          string    `json:"reason"`
+	GrantedByUserID uint      `json:"grantedByUserId"`
+	GrantedAt       time.Time `json:"grantedAt"`
+}
+
+// TimeExtensionResponse shows a one-off extension granted on a subtest
+type TimeExtensionResponse struct {
+	ID              uint      `json:"id"`
+	AttemptID       uint      `json:"attemptId"`
+	SubtestID       uint      `json:"subtestId"`
+	ExtraSeconds    int       `json:"extraSeconds"`
+	Reason          string    `json:"reason"`
+	GrantedByUserID uint      `json:"grantedByUserId"`
+	GrantedAt       time.Time `json:"grantedAt"`
+}
+
+// SubtestTimeLimitResponse shows the base and effective time limit of a subtest for an attempt
+type SubtestTimeLimitResponse struct {
+	SubtestID        uint   `json:"subtestId"`
+	SubtestCode      string `json:"subtestCode"`
+	BaseSeconds      int    `json:"baseSeconds"`
+	EffectiveSeconds int    `json:"effectiveSeconds"`
+}
+
+// TimeAdjustmentsResponse lists every time adjustment applied to an attempt
+type TimeAdjustmentsResponse struct {
+	AttemptID      uint                       `json:"attemptId"`
+	RegistrationID uint                       `json:"registrationId"`
+	Accommodation  *TimeAccommodationResponse `json:"accommodation,omitempty"`
+	Extensions     []TimeExtensionResponse    `json:"extensions"`
+	TimeLimits     []SubtestTimeLimitResponse `json:"timeLimits"`
+}
+
 // ==========================================
 // Helper Functions
 // ==========================================
@@ -214,3 +270,26 @@ func ToQuestionForExamResponse(q entities.TryOutQuestion, selectedOption *string
 		SelectedOption:  selectedOption,
 	}
 }
+
+func ToTimeAccommodationResponse(a entities.TimeAccommodation) TimeAccommodationResponse {
+	return TimeAccommodationResponse{
+		ID:              a.ID,
+		RegistrationID:  a.RegistrationID,
+		Multiplier:      a.Multiplier,
+		Reason:          a.Reason,
+		GrantedByUserID: a.GrantedByUserID,
+		GrantedAt:       a.GrantedAt,
+	}
+}
+
+func ToTimeExtensionResponse(e entities.SubtestTimeExtension) TimeExtensionResponse {
+	return TimeExtensionResponse{
+		ID:              e.ID,
+		AttemptID:       e.AttemptID,
+		SubtestID:       e.SubtestID,
+		ExtraSeconds:    e.ExtraSeconds,
+		Reason:          e.Reason,
+		GrantedByUserID: e.GrantedByUserID,
+		GrantedAt:       e.GrantedAt,
+	}
+}
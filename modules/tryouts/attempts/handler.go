@@ -22,6 +22,12 @@ type Handler interface {
 	GetResultsHandler(c *gin.Context)
 	GetSubtestReviewHandler(c *gin.Context)
 	GetLeaderboardHandler(c *gin.Context)
+
+	// Admin: time adjustments
+	SetTimeAccommodationHandler(c *gin.Context)
+	RemoveTimeAccommodationHandler(c *gin.Context)
+	GrantTimeExtensionHandler(c *gin.Context)
+	GetTimeAdjustmentsHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -77,6 +83,8 @@ func (h *handler) StartAttemptHandler(c *gin.Context) {
 			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
 		case "payment must be approved before starting":
 			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Payment required", err.Error(), nil))
+		case "a cancellation request for this registration is pending":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Cancellation pending", err.Error(), nil))
 		default:
 			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to start attempt", err.Error(), nil))
 		}
@@ -138,7 +146,7 @@ func (h *handler) StartSubtestHandler(c *gin.Context) {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
 		case "you can only access your own attempt":
 			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
-		case "attempt is not in progress":
+		case "attempt is not in progress", "subtest time is over":
 			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid state", err.Error(), nil))
 		default:
 			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to start subtest", err.Error(), nil))
@@ -302,3 +310,123 @@ func (h *handler) GetLeaderboardHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Leaderboard retrieved successfully", leaderboard))
 }
+
+// ==========================================
+// Admin: Time Adjustment Handlers
+// ==========================================
+
+func (h *handler) SetTimeAccommodationHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	regIDStr := c.Param("id")
+
+	regID, err := strconv.ParseUint(regIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Registration ID", "ID must be a valid number", nil))
+		return
+	}
+
+	var input SetTimeAccommodationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	accommodation, err := h.service.SetTimeAccommodation(uint(regID), input, userID, requestID)
+	if err != nil {
+		if err.Error() == "registration not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to set time accommodation", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Time accommodation saved successfully", accommodation))
+}
+
+func (h *handler) RemoveTimeAccommodationHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	regIDStr := c.Param("id")
+
+	regID, err := strconv.ParseUint(regIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Registration ID", "ID must be a valid number", nil))
+		return
+	}
+
+	if err := h.service.RemoveTimeAccommodation(uint(regID), userID, requestID); err != nil {
+		if err.Error() == "time accommodation not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Time accommodation not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to remove time accommodation", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Time accommodation removed successfully", nil))
+}
+
+func (h *handler) GrantTimeExtensionHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	attemptIDStr := c.Param("attemptId")
+	subtestIDStr := c.Param("subtestId")
+
+	attemptID, err := strconv.ParseUint(attemptIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Attempt ID", "ID must be a valid number", nil))
+		return
+	}
+
+	subtestID, err := strconv.ParseUint(subtestIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Subtest ID", "ID must be a valid number", nil))
+		return
+	}
+
+	var input GrantTimeExtensionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	extension, err := h.service.GrantTimeExtension(uint(attemptID), uint(subtestID), input, userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "attempt not found", "subtest not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+		case "attempt is already completed", "subtest already submitted":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid state", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to grant time extension", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.BuildResponseSuccess("Time extension granted successfully", extension))
+}
+
+func (h *handler) GetTimeAdjustmentsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	attemptIDStr := c.Param("attemptId")
+
+	attemptID, err := strconv.ParseUint(attemptIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Attempt ID", "ID must be a valid number", nil))
+		return
+	}
+
+	adjustments, err := h.service.GetTimeAdjustments(uint(attemptID), requestID)
+	if err != nil {
+		if err.Error() == "attempt not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Attempt not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to get time adjustments", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Time adjustments retrieved successfully", adjustments))
+}
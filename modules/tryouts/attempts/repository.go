@@ -17,6 +17,7 @@ type Repository interface {
 	// Attempt
 	FindAttemptByID(id uint) (entities.TryOutAttempt, error)
 	FindAttemptByRegistrationID(registrationID uint) (entities.TryOutAttempt, error)
+	HasPendingCancellation(registrationID uint) (bool, error)
 	CreateAttempt(attempt *entities.TryOutAttempt) error
 	UpdateAttempt(attempt *entities.TryOutAttempt) error
 
@@ -43,6 +44,14 @@ type Repository interface {
 
 	// Leaderboard
 	FindLeaderboard(tryOutID uint, limit int) ([]entities.TryOutAttempt, error)
+
+	// Time adjustments
+	FindTimeAccommodationByRegistrationID(registrationID uint) (entities.TimeAccommodation, error)
+	FindTimeAccommodationByRegistrationIDUnscoped(registrationID uint) (entities.TimeAccommodation, error)
+	SaveTimeAccommodation(accommodation *entities.TimeAccommodation) error
+	DeleteTimeAccommodation(registrationID uint) error
+	FindTimeExtensionsByAttemptID(attemptID uint) ([]entities.SubtestTimeExtension, error)
+	CreateTimeExtension(extension *entities.SubtestTimeExtension) error
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -92,6 +101,14 @@ func (r *repository) FindAttemptByRegistrationID(registrationID uint) (entities.
 	return attempt, err
 }
 
+func (r *repository) HasPendingCancellation(registrationID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.CancellationRequest{}).
+		Where("registration_id = ? AND status = ?", registrationID, entities.CancellationStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *repository) CreateAttempt(attempt *entities.TryOutAttempt) error {
 	return r.db.Create(attempt).Error
 }
@@ -215,3 +232,39 @@ func (r *repository) FindLeaderboard(tryOutID uint, limit int) ([]entities.TryOu
 		Find(&attempts).Error
 	return attempts, err
 }
+
+// ==========================================
+// Time Adjustment Methods
+// ==========================================
+
+func (r *repository) FindTimeAccommodationByRegistrationID(registrationID uint) (entities.TimeAccommodation, error) {
+	var accommodation entities.TimeAccommodation
+	err := r.db.Where("registration_id = ?", registrationID).First(&accommodation).Error
+	return accommodation, err
+}
+
+func (r *repository) FindTimeAccommodationByRegistrationIDUnscoped(registrationID uint) (entities.TimeAccommodation, error) {
+	var accommodation entities.TimeAccommodation
+	err := r.db.Unscoped().Where("registration_id = ?", registrationID).First(&accommodation).Error
+	return accommodation, err
+}
+
+func (r *repository) SaveTimeAccommodation(accommodation *entities.TimeAccommodation) error {
+	return r.db.Unscoped().Omit("Registration", "GrantedBy").Save(accommodation).Error
+}
+
+func (r *repository) DeleteTimeAccommodation(registrationID uint) error {
+	return r.db.Where("registration_id = ?", registrationID).Delete(&entities.TimeAccommodation{}).Error
+}
+
+func (r *repository) FindTimeExtensionsByAttemptID(attemptID uint) ([]entities.SubtestTimeExtension, error) {
+	var extensions []entities.SubtestTimeExtension
+	err := r.db.Where("attempt_id = ?", attemptID).
+		Order("granted_at ASC").
+		Find(&extensions).Error
+	return extensions, err
+}
+
+func (r *repository) CreateTimeExtension(extension *entities.SubtestTimeExtension) error {
+	return r.db.Omit("Attempt", "Subtest", "GrantedBy").Create(extension).Error
+}
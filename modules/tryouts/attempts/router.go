@@ -3,9 +3,10 @@ package attempts
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/middleware"
 )
 
-func AttemptRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
+func AttemptRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc) {
 	repo := NewRepository(migrations.GetDB())
 	service := NewService(repo)
 	handler := NewHandler(service)
@@ -25,6 +26,17 @@ func AttemptRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, require
 		attemptRoutes.GET("/:attemptId/subtests/:subtestId/review", handler.GetSubtestReviewHandler)
 	}
 
+	// Admin: time accommodations and extensions. Tutors are deliberately left out so nobody
+	// grants extra time to an attempt they take part in.
+	adminRoutes := router.Group("/tryouts")
+	adminRoutes.Use(requireAuth, middleware.RequireAdmin())
+	{
+		adminRoutes.PUT("/registrations/:id/accommodation", handler.SetTimeAccommodationHandler)
+		adminRoutes.DELETE("/registrations/:id/accommodation", handler.RemoveTimeAccommodationHandler)
+		adminRoutes.GET("/attempts/:attemptId/time-adjustments", handler.GetTimeAdjustmentsHandler)
+		adminRoutes.POST("/attempts/:attemptId/subtests/:subtestId/extensions", handler.GrantTimeExtensionHandler)
+	}
+
 	// Public leaderboard
 	router.GET("/tryouts/:id/leaderboard", handler.GetLeaderboardHandler)
 }
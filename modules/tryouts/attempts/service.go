@@ -3,6 +3,7 @@ package attempts
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
@@ -17,6 +18,10 @@ const (
 	WeightHard   = 2.0
 )
 
+// SubmitGraceSeconds is how long after a subtest's effective time limit a submission is still graded,
+// to absorb network latency between the client timer running out and the request arriving.
+const SubmitGraceSeconds = 60
+
 type attemptService struct {
 	repo Repository
 }
@@ -39,6 +44,12 @@ type Service interface {
 
 	// Leaderboard
 	GetLeaderboard(tryOutID uint, requestID string) ([]LeaderboardEntryResponse, error)
+
+	// Time adjustments (admin)
+	SetTimeAccommodation(registrationID uint, input SetTimeAccommodationInput, adminUserID uint, requestID string) (*TimeAccommodationResponse, error)
+	RemoveTimeAccommodation(registrationID uint, adminUserID uint, requestID string) error
+	GrantTimeExtension(attemptID, subtestID uint, input GrantTimeExtensionInput, adminUserID uint, requestID string) (*TimeExtensionResponse, error)
+	GetTimeAdjustments(attemptID uint, requestID string) (*TimeAdjustmentsResponse, error)
 }
 
 func NewService(repo Repository) Service {
@@ -81,6 +92,15 @@ func (s *attemptService) StartAttempt(registrationID uint, userID uint, requestI
 		return &response, nil
 	}
 
+	// A refund may be on its way; the try out cannot be taken until the request is reviewed
+	pending, err := s.repo.HasPendingCancellation(registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("a cancellation request for this registration is pending")
+	}
+
 	// Create new attempt
 	now := time.Now()
 	subtests, err := s.repo.FindAllSubtests()
@@ -133,21 +153,28 @@ func (s *attemptService) GetCurrentState(attemptID uint, userID uint, requestID
 		return nil, errors.New("you can only view your own attempt")
 	}
 
+	adjustments, err := s.loadTimeAdjustments(attempt)
+	if err != nil {
+		return nil, err
+	}
+
 	response := &AttemptCurrentStateResponse{
-		ID:     attempt.ID,
-		Status: string(attempt.Status),
+		ID:             attempt.ID,
+		Status:         string(attempt.Status),
+		TimeMultiplier: adjustments.multiplier,
 	}
 
 	// Get current subtest info
 	if attempt.CurrentSubtest != nil {
 		subtest := ToSubtestBriefResponse(*attempt.CurrentSubtest)
+		subtest.TimeLimitSeconds = adjustments.limitFor(*attempt.CurrentSubtest)
 		response.CurrentSubtest = &subtest
 
 		// Calculate time remaining for current subtest
 		currentResult, err := s.repo.FindSubtestResultByAttemptAndSubtest(attemptID, attempt.CurrentSubtest.ID)
 		if err == nil && currentResult.StartedAt != nil {
 			elapsed := int(time.Since(*currentResult.StartedAt).Seconds())
-			remaining := attempt.CurrentSubtest.TimeLimitSeconds - elapsed
+			remaining := subtest.TimeLimitSeconds - elapsed
 			if remaining < 0 {
 				remaining = 0
 			}
@@ -164,7 +191,8 @@ func (s *attemptService) GetCurrentState(attemptID uint, userID uint, requestID
 			SubtestName:      subtest.Name,
 			Status:           "not_started",
 			TotalCount:       subtest.QuestionCount,
-			TimeLimitSeconds: subtest.TimeLimitSeconds,
+			TimeLimitSeconds: adjustments.limitFor(subtest),
+			ExtraSeconds:     adjustments.extraSeconds[subtest.ID],
 		}
 
 		result, err := s.repo.FindSubtestResultByAttemptAndSubtest(attemptID, subtest.ID)
@@ -257,6 +285,17 @@ func (s *attemptService) StartSubtest(attemptID, subtestID uint, userID uint, re
 
 	// Check if subtest result exists, create if not
 	result, err := s.repo.FindSubtestResultByAttemptAndSubtest(attemptID, subtestID)
+	if err == nil && result.StartedAt != nil && result.FinishedAt == nil {
+		// Questions are not served again once the (adjusted) time limit has run out
+		adjustments, err := s.loadTimeAdjustments(attempt)
+		if err != nil {
+			return nil, err
+		}
+		limit := adjustments.limitFor(subtest)
+		if time.Since(*result.StartedAt) > time.Duration(limit)*time.Second {
+			return nil, errors.New("subtest time is over")
+		}
+	}
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		now := time.Now()
 		result = entities.SubtestResult{
@@ -345,6 +384,26 @@ func (s *attemptService) SubmitSubtest(attemptID, subtestID uint, input SubmitSu
 		return nil, err
 	}
 
+	// Enforce the (adjusted) time limit: answers arriving after the grace period are dropped, but
+	// the subtest is still closed and scored so the attempt can move on to the next one
+	if result.StartedAt != nil {
+		adjustments, err := s.loadTimeAdjustments(attempt)
+		if err != nil {
+			return nil, err
+		}
+		limit := adjustments.limitFor(subtest)
+		deadline := result.StartedAt.Add(time.Duration(limit+SubmitGraceSeconds) * time.Second)
+		if time.Now().After(deadline) {
+			utils.LogWarning("attempts", "submit_subtest", "Late submission, closing subtest without its answers", requestID, userID, map[string]any{
+				"attempt_id":         attemptID,
+				"subtest_id":         subtestID,
+				"time_limit_seconds": limit,
+				"dropped_answers":    len(input.Answers),
+			})
+			input.Answers = nil
+		}
+	}
+
 	// Process answers
 	var correctCount, wrongCount, unansweredCount int
 	var rawScore float64
@@ -647,3 +706,212 @@ func (s *attemptService) GetLeaderboard(tryOutID uint, requestID string) ([]Lead
 
 	return responses, nil
 }
+
+// ==========================================
+// Time Adjustments
+// ==========================================
+
+// timeAdjustments holds the accommodation multiplier and per-subtest extensions of one attempt
+type timeAdjustments struct {
+	multiplier   float64
+	extraSeconds map[uint]int
+}
+
+// limitFor returns the effective time limit of a subtest in seconds
+func (t timeAdjustments) limitFor(subtest entities.Subtest) int {
+	limit := int(math.Ceil(float64(subtest.TimeLimitSeconds) * t.multiplier))
+	return limit + t.extraSeconds[subtest.ID]
+}
+
+// loadTimeAdjustments fails rather than falling back to the base limits, which would cut
+// short a student with an accommodation
+func (s *attemptService) loadTimeAdjustments(attempt entities.TryOutAttempt) (timeAdjustments, error) {
+	adjustments := timeAdjustments{
+		multiplier:   1,
+		extraSeconds: make(map[uint]int),
+	}
+
+	accommodation, err := s.repo.FindTimeAccommodationByRegistrationID(attempt.RegistrationID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return adjustments, err
+	}
+	if err == nil && accommodation.Multiplier > 0 {
+		adjustments.multiplier = accommodation.Multiplier
+	}
+
+	extensions, err := s.repo.FindTimeExtensionsByAttemptID(attempt.ID)
+	if err != nil {
+		return adjustments, err
+	}
+	for _, e := range extensions {
+		adjustments.extraSeconds[e.SubtestID] += e.ExtraSeconds
+	}
+
+	return adjustments, nil
+}
+
+func (s *attemptService) SetTimeAccommodation(registrationID uint, input SetTimeAccommodationInput, adminUserID uint, requestID string) (*TimeAccommodationResponse, error) {
+	utils.LogInfo("attempts", "set_time_accommodation", "Admin setting time accommodation", requestID, adminUserID, map[string]any{
+		"registration_id": registrationID,
+		"multiplier":      input.Multiplier,
+	})
+
+	if _, err := s.repo.FindRegistrationByID(registrationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("registration not found")
+		}
+		return nil, err
+	}
+
+	// Reuse the existing row (including a removed one) so the unique index on registration_id holds
+	accommodation, err := s.repo.FindTimeAccommodationByRegistrationIDUnscoped(registrationID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	accommodation.RegistrationID = registrationID
+	accommodation.Multiplier = input.Multiplier
+	accommodation.Reason = input.Reason
+	accommodation.GrantedByUserID = adminUserID
+	accommodation.GrantedAt = time.Now()
+	accommodation.DeletedAt = gorm.DeletedAt{}
+
+	if err := s.repo.SaveTimeAccommodation(&accommodation); err != nil {
+		utils.LogError("attempts", "set_time_accommodation", "Failed to save time accommodation: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("attempts", "set_time_accommodation", "Time accommodation saved", requestID, adminUserID, map[string]any{
+		"registration_id": registrationID,
+		"multiplier":      input.Multiplier,
+		"reason":          input.Reason,
+	})
+
+	response := ToTimeAccommodationResponse(accommodation)
+	return &response, nil
+}
+
+func (s *attemptService) RemoveTimeAccommodation(registrationID uint, adminUserID uint, requestID string) error {
+	utils.LogInfo("attempts", "remove_time_accommodation", "Admin removing time accommodation", requestID, adminUserID, map[string]any{
+		"registration_id": registrationID,
+	})
+
+	if _, err := s.repo.FindTimeAccommodationByRegistrationID(registrationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("time accommodation not found")
+		}
+		return err
+	}
+
+	if err := s.repo.DeleteTimeAccommodation(registrationID); err != nil {
+		utils.LogError("attempts", "remove_time_accommodation", "Failed to remove time accommodation: "+err.Error(), requestID, adminUserID, nil)
+		return err
+	}
+
+	utils.LogSuccess("attempts", "remove_time_accommodation", "Time accommodation removed", requestID, adminUserID, map[string]any{
+		"registration_id": registrationID,
+	})
+	return nil
+}
+
+func (s *attemptService) GrantTimeExtension(attemptID, subtestID uint, input GrantTimeExtensionInput, adminUserID uint, requestID string) (*TimeExtensionResponse, error) {
+	utils.LogInfo("attempts", "grant_time_extension", "Admin granting time extension", requestID, adminUserID, map[string]any{
+		"attempt_id":    attemptID,
+		"subtest_id":    subtestID,
+		"extra_seconds": input.ExtraSeconds,
+	})
+
+	attempt, err := s.repo.FindAttemptByID(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt not found")
+		}
+		return nil, err
+	}
+
+	if attempt.Status == entities.AttemptStatusCompleted {
+		return nil, errors.New("attempt is already completed")
+	}
+
+	if _, err := s.repo.FindSubtestByID(subtestID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("subtest not found")
+		}
+		return nil, err
+	}
+
+	result, err := s.repo.FindSubtestResultByAttemptAndSubtest(attemptID, subtestID)
+	if err == nil && result.FinishedAt != nil {
+		return nil, errors.New("subtest already submitted")
+	}
+
+	extension := entities.SubtestTimeExtension{
+		AttemptID:       attemptID,
+		SubtestID:       subtestID,
+		ExtraSeconds:    input.ExtraSeconds,
+		Reason:          input.Reason,
+		GrantedByUserID: adminUserID,
+		GrantedAt:       time.Now(),
+	}
+
+	if err := s.repo.CreateTimeExtension(&extension); err != nil {
+		utils.LogError("attempts", "grant_time_extension", "Failed to create time extension: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("attempts", "grant_time_extension", "Time extension granted", requestID, adminUserID, map[string]any{
+		"attempt_id":    attemptID,
+		"subtest_id":    subtestID,
+		"extra_seconds": input.ExtraSeconds,
+		"reason":        input.Reason,
+	})
+
+	response := ToTimeExtensionResponse(extension)
+	return &response, nil
+}
+
+func (s *attemptService) GetTimeAdjustments(attemptID uint, requestID string) (*TimeAdjustmentsResponse, error) {
+	attempt, err := s.repo.FindAttemptByID(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt not found")
+		}
+		return nil, err
+	}
+
+	response := &TimeAdjustmentsResponse{
+		AttemptID:      attempt.ID,
+		RegistrationID: attempt.RegistrationID,
+		Extensions:     []TimeExtensionResponse{},
+		TimeLimits:     []SubtestTimeLimitResponse{},
+	}
+
+	if accommodation, err := s.repo.FindTimeAccommodationByRegistrationID(attempt.RegistrationID); err == nil {
+		acc := ToTimeAccommodationResponse(accommodation)
+		response.Accommodation = &acc
+	}
+
+	extensions, err := s.repo.FindTimeExtensionsByAttemptID(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range extensions {
+		response.Extensions = append(response.Extensions, ToTimeExtensionResponse(e))
+	}
+
+	adjustments, err := s.loadTimeAdjustments(attempt)
+	if err != nil {
+		return nil, err
+	}
+	subtests, _ := s.repo.FindAllSubtests()
+	for _, subtest := range subtests {
+		response.TimeLimits = append(response.TimeLimits, SubtestTimeLimitResponse{
+			SubtestID:        subtest.ID,
+			SubtestCode:      subtest.Code,
+			BaseSeconds:      subtest.TimeLimitSeconds,
+			EffectiveSeconds: adjustments.limitFor(subtest),
+		})
+	}
+
+	return response, nil
+}
@@ -10,20 +10,25 @@ import (
 
 // TryOutResponse is the response DTO for Try Out
 type TryOutResponse struct {
-	ID                uint                  `json:"id"`
-	Name              string                `json:"name"`
-	Description       string                `json:"description,omitempty"`
-	ImageURL          string                `json:"imageUrl,omitempty"`
-	DriveLink         string                `json:"driveLink,omitempty"`
-	IsFree            bool                  `json:"isFree"`
-	Price             float64               `json:"price,omitempty"`
-	QrisImageURL      string                `json:"qrisImageUrl,omitempty"`
-	PaymentLink       string                `json:"paymentLink,omitempty"`
-	RegistrationStart time.Time             `json:"registrationStart"`
-	RegistrationEnd   time.Time             `json:"registrationEnd"`
-	IsPublished       bool                  `json:"isPublished"`
-	Creator           *CreatorBriefResponse `json:"creator,omitempty"`
-	CreatedAt         time.Time             `json:"createdAt"`
+	ID                   uint                  `json:"id"`
+	Name                 string                `json:"name"`
+	Description          string                `json:"description,omitempty"`
+	ImageURL             string                `json:"imageUrl,omitempty"`
+	DriveLink            string                `json:"driveLink,omitempty"`
+	IsFree               bool                  `json:"isFree"`
+	Price                float64               `json:"price,omitempty"`
+	QrisImageURL         string                `json:"qrisImageUrl,omitempty"`
+	PaymentLink          string                `json:"paymentLink,omitempty"`
+	PaymentDeadlineHours int                   `json:"paymentDeadlineHours"`
+	PaymentReminderHours int                   `json:"paymentReminderHours"`
+	Capacity             int                   `json:"capacity"`
+	RegistrationStart    time.Time             `json:"registrationStart"`
+	RegistrationEnd      time.Time             `json:"registrationEnd"`
+	ExamStart            *time.Time            `json:"examStart,omitempty"`
+	ExamEnd              *time.Time            `json:"examEnd,omitempty"`
+	IsPublished          bool                  `json:"isPublished"`
+	Creator              *CreatorBriefResponse `json:"creator,omitempty"`
+	CreatedAt            time.Time             `json:"createdAt"`
 }
 
 // TryOutBriefResponse is a minimal response for list views
@@ -33,6 +38,7 @@ type TryOutBriefResponse struct {
 	ImageURL          string    `json:"imageUrl,omitempty"`
 	IsFree            bool      `json:"isFree"`
 	Price             float64   `json:"price,omitempty"`
+	Capacity          int       `json:"capacity"`
 	RegistrationStart time.Time `json:"registrationStart"`
 	RegistrationEnd   time.Time `json:"registrationEnd"`
 	IsPublished       bool      `json:"isPublished"`
@@ -47,32 +53,44 @@ type CreatorBriefResponse struct {
 
 // CreateTryOutInput is the input for creating a new Try Out
 type CreateTryOutInput struct {
-	Name              string    `json:"name" binding:"required"`
-	Description       string    `json:"description"`
-	ImageURL          string    `json:"imageUrl"`
-	IsFree            bool      `json:"isFree"`
-	Price             float64   `json:"price"`
-	QrisImageURL      string    `json:"qrisImageUrl"`
-	PaymentLink       string    `json:"paymentLink"`
-	RegistrationStart time.Time `json:"registrationStart" binding:"required"`
-	RegistrationEnd   time.Time `json:"registrationEnd" binding:"required"`
-	IsPublished       bool      `json:"isPublished"`
-	DriveLink         string     `json:"driveLink" binding:"required"`
+	Name                 string    `json:"name" binding:"required"`
+	Description          string    `json:"description"`
+	ImageURL             string    `json:"imageUrl"`
+	IsFree               bool      `json:"isFree"`
+	Price                float64   `json:"price"`
+	QrisImageURL         string    `json:"qrisImageUrl"`
+	PaymentLink          string    `json:"paymentLink"`
+	PaymentDeadlineHours int       `json:"paymentDeadlineHours" binding:"gte=0"`
+	PaymentReminderHours *int      `json:"paymentReminderHours" binding:"omitempty,gte=0"` // Defaults to 24 when a deadline is set
+	Capacity             int       `json:"capacity" binding:"gte=0"`                       // 0 = unlimited
+	RegistrationStart    time.Time `json:"registrationStart" binding:"required"`
+	RegistrationEnd      time.Time `json:"registrationEnd" binding:"required"`
+	IsPublished          bool      `json:"isPublished"`
+	DriveLink            string    `json:"driveLink" binding:"required"`
+
+	// Optional exam window, shown on registered students' calendars
+	ExamStart *time.Time `json:"examStart"`
+	ExamEnd   *time.Time `json:"examEnd"`
 }
 
 // UpdateTryOutInput is the input for updating a Try Out
 type UpdateTryOutInput struct {
-	Name              *string    `json:"name"`
-	Description       *string    `json:"description"`
-	ImageURL          *string    `json:"imageUrl"`
-	IsFree            *bool      `json:"isFree"`
-	Price             *float64   `json:"price"`
-	QrisImageURL      *string    `json:"qrisImageUrl"`
-	PaymentLink       *string    `json:"paymentLink"`
-	RegistrationStart *time.Time `json:"registrationStart"`
-	RegistrationEnd   *time.Time `json:"registrationEnd"`
-	IsPublished       *bool      `json:"isPublished"`
-	DriveLink         *string    `json:"driveLink"`
+	Name                 *string    `json:"name"`
+	Description          *string    `json:"description"`
+	ImageURL             *string    `json:"imageUrl"`
+	IsFree               *bool      `json:"isFree"`
+	Price                *float64   `json:"price"`
+	QrisImageURL         *string    `json:"qrisImageUrl"`
+	PaymentLink          *string    `json:"paymentLink"`
+	PaymentDeadlineHours *int       `json:"paymentDeadlineHours" binding:"omitempty,gte=0"`
+	PaymentReminderHours *int       `json:"paymentReminderHours" binding:"omitempty,gte=0"`
+	Capacity             *int       `json:"capacity" binding:"omitempty,gte=0"`
+	RegistrationStart    *time.Time `json:"registrationStart"`
+	RegistrationEnd      *time.Time `json:"registrationEnd"`
+	ExamStart            *time.Time `json:"examStart"`
+	ExamEnd              *time.Time `json:"examEnd"`
+	IsPublished          *bool      `json:"isPublished"`
+	DriveLink            *string    `json:"driveLink"`
 }
 
 // ==========================================
@@ -4,11 +4,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
 	"github.com/redukasquad/be-reduka/middleware"
+	"github.com/redukasquad/be-reduka/modules/bundles"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 )
 
 func TryOutIndexRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
-	repo := NewRepository(migrations.GetDB())
-	service := NewService(repo)
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	waitlistService := waitlists.NewService(waitlists.NewRepository(db))
+	service := NewService(repo, bundles.NewService(bundles.NewRepository(db)), waitlistService)
 	handler := NewHandler(service)
 
 	// Public endpoints (anyone can view published try outs, admin sees all)
@@ -4,13 +4,20 @@ import (
 	"errors"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/bundles"
 	"github.com/redukasquad/be-reduka/modules/dto"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 	"github.com/redukasquad/be-reduka/packages/utils"
 	"gorm.io/gorm"
 )
 
+// defaultPaymentReminderHours is used when a payment deadline is set without an explicit reminder
+const defaultPaymentReminderHours = 24
+
 type tryOutService struct {
-	repo Repository
+	repo      Repository
+	bundles   bundles.Service
+	waitlists waitlists.Service
 }
 
 type Service interface {
@@ -28,8 +35,8 @@ type Service interface {
 	HasTutorPermission(tryOutID, userID uint) (bool, error)
 }
 
-func NewService(repo Repository) Service {
-	return &tryOutService{repo: repo}
+func NewService(repo Repository, bundleService bundles.Service, waitlistService waitlists.Service) Service {
+	return &tryOutService{repo: repo, bundles: bundleService, waitlists: waitlistService}
 }
 
 // ==========================================
@@ -122,20 +129,32 @@ func (s *tryOutService) Create(input CreateTryOutInput, requestID string, userID
 	if input.RegistrationEnd.Before(input.RegistrationStart) {
 		return nil, errors.New("registration end date must be after start date")
 	}
+	if input.ExamStart != nil && input.ExamEnd != nil && input.ExamEnd.Before(*input.ExamStart) {
+		return nil, errors.New("exam end date must be after start date")
+	}
 
 	tryOut := &entities.TryOut{
-		Name:              input.Name,
-		Description:       input.Description,
-		ImageURL:          input.ImageURL,
-		IsFree:            input.IsFree,
-		Price:             input.Price,
-		DriveLink:         input.DriveLink,
-		QrisImageURL:      input.QrisImageURL,
-		PaymentLink:       input.PaymentLink,
-		RegistrationStart: input.RegistrationStart,
-		RegistrationEnd:   input.RegistrationEnd,
-		IsPublished:       input.IsPublished,
-		CreatedByUserID:   userID,
+		Name:                 input.Name,
+		Description:          input.Description,
+		ImageURL:             input.ImageURL,
+		IsFree:               input.IsFree,
+		Price:                input.Price,
+		DriveLink:            input.DriveLink,
+		QrisImageURL:         input.QrisImageURL,
+		PaymentLink:          input.PaymentLink,
+		PaymentDeadlineHours: input.PaymentDeadlineHours,
+		Capacity:             input.Capacity,
+		RegistrationStart:    input.RegistrationStart,
+		RegistrationEnd:      input.RegistrationEnd,
+		ExamStart:            input.ExamStart,
+		ExamEnd:              input.ExamEnd,
+		IsPublished:          input.IsPublished,
+		CreatedByUserID:      userID,
+	}
+	if input.PaymentReminderHours != nil {
+		tryOut.PaymentReminderHours = *input.PaymentReminderHours
+	} else if input.PaymentDeadlineHours > 0 {
+		tryOut.PaymentReminderHours = defaultPaymentReminderHours
 	}
 
 	if err := s.repo.Create(tryOut); err != nil {
@@ -156,6 +175,10 @@ func (s *tryOutService) Create(input CreateTryOutInput, requestID string, userID
 		"try_out_name": tryOut.Name,
 	})
 
+	if tryOut.IsPublished {
+		s.grantBundleAccess(tryOut.ID, requestID)
+	}
+
 	response := toTryOutResponse(createdTryOut)
 	return &response, nil
 }
@@ -207,12 +230,29 @@ func (s *tryOutService) Update(id uint, input UpdateTryOutInput, requestID strin
 	if input.PaymentLink != nil {
 		tryOut.PaymentLink = *input.PaymentLink
 	}
+	if input.PaymentDeadlineHours != nil {
+		tryOut.PaymentDeadlineHours = *input.PaymentDeadlineHours
+	}
+	if input.PaymentReminderHours != nil {
+		tryOut.PaymentReminderHours = *input.PaymentReminderHours
+	}
+	previousCapacity := tryOut.Capacity
+	if input.Capacity != nil {
+		tryOut.Capacity = *input.Capacity
+	}
 	if input.RegistrationStart != nil {
 		tryOut.RegistrationStart = *input.RegistrationStart
 	}
 	if input.RegistrationEnd != nil {
 		tryOut.RegistrationEnd = *input.RegistrationEnd
 	}
+	if input.ExamStart != nil {
+		tryOut.ExamStart = input.ExamStart
+	}
+	if input.ExamEnd != nil {
+		tryOut.ExamEnd = input.ExamEnd
+	}
+	wasPublished := tryOut.IsPublished
 	if input.IsPublished != nil {
 		tryOut.IsPublished = *input.IsPublished
 	}
@@ -221,6 +261,9 @@ func (s *tryOutService) Update(id uint, input UpdateTryOutInput, requestID strin
 	if tryOut.RegistrationEnd.Before(tryOut.RegistrationStart) {
 		return nil, errors.New("registration end date must be after start date")
 	}
+	if tryOut.ExamStart != nil && tryOut.ExamEnd != nil && tryOut.ExamEnd.Before(*tryOut.ExamStart) {
+		return nil, errors.New("exam end date must be after start date")
+	}
 
 	if err := s.repo.Update(&tryOut); err != nil {
 		utils.LogError("tryouts", "update", "Failed to update try out: "+err.Error(), requestID, userID, map[string]any{
@@ -240,10 +283,33 @@ func (s *tryOutService) Update(id uint, input UpdateTryOutInput, requestID strin
 		"try_out_name": tryOut.Name,
 	})
 
+	if tryOut.IsPublished && !wasPublished {
+		s.grantBundleAccess(tryOut.ID, requestID)
+	}
+
+	// More seats (or no limit any more) lets the waitlist move up
+	if tryOut.Capacity != previousCapacity && (tryOut.Capacity == 0 || tryOut.Capacity > previousCapacity) {
+		if _, err := s.waitlists.PromoteTryOut(tryOut.ID, requestID); err != nil {
+			utils.LogError("tryouts", "update", "Failed to promote waitlist: "+err.Error(), requestID, userID, map[string]any{
+				"try_out_id": tryOut.ID,
+			})
+		}
+	}
+
 	response := toTryOutResponse(updatedTryOut)
 	return &response, nil
 }
 
+// grantBundleAccess registers holders of bundles and passes covering a newly published try out.
+// Failures are logged only; the publish itself already succeeded.
+func (s *tryOutService) grantBundleAccess(tryOutID uint, requestID string) {
+	if err := s.bundles.GrantPublishedTryOut(tryOutID, requestID); err != nil {
+		utils.LogError("tryouts", "publish", "Failed to grant bundle access: "+err.Error(), requestID, 0, map[string]any{
+			"try_out_id": tryOutID,
+		})
+	}
+}
+
 func (s *tryOutService) Delete(id uint, requestID string, userID uint) error {
 	utils.LogInfo("tryouts", "delete", "Attempting to delete try out", requestID, userID, map[string]any{
 		"try_out_id": id,
@@ -403,18 +469,23 @@ func (s *tryOutService) HasTutorPermission(tryOutID, userID uint) (bool, error)
 
 func toTryOutResponse(tryOut entities.TryOut) TryOutResponse {
 	response := TryOutResponse{
-		ID:                tryOut.ID,
-		Name:              tryOut.Name,
-		Description:       tryOut.Description,
-		ImageURL:          tryOut.ImageURL,
-		IsFree:            tryOut.IsFree,
-		Price:             tryOut.Price,
-		QrisImageURL:      tryOut.QrisImageURL,
-		PaymentLink:       tryOut.PaymentLink,
-		RegistrationStart: tryOut.RegistrationStart,
-		RegistrationEnd:   tryOut.RegistrationEnd,
-		IsPublished:       tryOut.IsPublished,
-		CreatedAt:         tryOut.CreatedAt,
+		ID:                   tryOut.ID,
+		Name:                 tryOut.Name,
+		Description:          tryOut.Description,
+		ImageURL:             tryOut.ImageURL,
+		IsFree:               tryOut.IsFree,
+		Price:                tryOut.Price,
+		QrisImageURL:         tryOut.QrisImageURL,
+		PaymentLink:          tryOut.PaymentLink,
+		PaymentDeadlineHours: tryOut.PaymentDeadlineHours,
+		PaymentReminderHours: tryOut.PaymentReminderHours,
+		Capacity:             tryOut.Capacity,
+		RegistrationStart:    tryOut.RegistrationStart,
+		RegistrationEnd:      tryOut.RegistrationEnd,
+		ExamStart:            tryOut.ExamStart,
+		ExamEnd:              tryOut.ExamEnd,
+		IsPublished:          tryOut.IsPublished,
+		CreatedAt:            tryOut.CreatedAt,
 	}
 
 	if tryOut.Creator.ID != 0 {
@@ -432,6 +503,7 @@ func toTryOutBriefResponse(tryOut entities.TryOut) TryOutBriefResponse {
 		ImageURL:          tryOut.ImageURL,
 		IsFree:            tryOut.IsFree,
 		Price:             tryOut.Price,
+		Capacity:          tryOut.Capacity,
 		RegistrationStart: tryOut.RegistrationStart,
 		RegistrationEnd:   tryOut.RegistrationEnd,
 		IsPublished:       tryOut.IsPublished,
@@ -88,6 +88,11 @@ type UpdateQuestionInput struct {
 	CorrectOption   *string `json:"correctOption" binding:"omitempty,oneof=A B C D E"`
 }
 
+// ReorderQuestionsInput lists every question of the subtest in its new order
+type ReorderQuestionsInput struct {
+	QuestionIDs []uint `json:"questionIds" binding:"required,min=1"`
+}
+
 // ==========================================
 // Helper Functions
 // ==========================================
@@ -20,6 +20,7 @@ type Handler interface {
 	CreateQuestionHandler(c *gin.Context)
 	UpdateQuestionHandler(c *gin.Context)
 	DeleteQuestionHandler(c *gin.Context)
+	ReorderQuestionsHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -258,3 +259,50 @@ func (h *handler) DeleteQuestionHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Question deleted successfully", nil))
 }
+
+func (h *handler) ReorderQuestionsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	tryOutIDStr := c.Param("id")
+	subtestIDStr := c.Param("subtestId")
+
+	tryOutID, err := strconv.ParseUint(tryOutIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Try Out ID", "ID must be a valid number", nil))
+		return
+	}
+
+	subtestID, err := strconv.ParseUint(subtestIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Subtest ID", "ID must be a valid number", nil))
+		return
+	}
+
+	// Check permission
+	if err := h.service.CheckTutorPermission(uint(tryOutID), userID); err != nil {
+		c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
+		return
+	}
+
+	var input ReorderQuestionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	questions, err := h.service.ReorderQuestions(uint(tryOutID), uint(subtestID), input, requestID, userID)
+	if err != nil {
+		if err.Error() == "try out not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Not found", err.Error(), nil))
+			return
+		}
+		if err.Error() == "question list must contain every question of the subtest exactly once" {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid order", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reorder questions", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Questions reordered successfully", questions))
+}
@@ -1,8 +1,12 @@
 package questions
 
 import (
+	"errors"
+
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/ordering"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -19,9 +23,13 @@ type Repository interface {
 	FindByTryOutAndSubtest(tryOutID, subtestID uint, difficulty string) ([]entities.TryOutQuestion, error)
 	FindByID(id uint) (entities.TryOutQuestion, error)
 	CountByTryOutAndSubtest(tryOutID, subtestID uint) (int64, error)
+	// Create, Update and Delete keep the subtest questions numbered 1..n: the question takes the
+	// position in OrderNumber (clamped to the end) and its siblings shift around it
 	Create(question *entities.TryOutQuestion) error
 	Update(question *entities.TryOutQuestion) error
 	Delete(id uint) error
+	// Reorder renumbers the subtest questions in the given order; ids must list every question once
+	Reorder(tryOutID, subtestID uint, ids []uint) error
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -71,7 +79,7 @@ func (r *repository) FindByTryOutAndSubtest(tryOutID, subtestID uint, difficulty
 	}
 
 	err := db.Preload("Subtest").
-		Order("order_number ASC").
+		Order("order_number ASC, id ASC").
 		Find(&questions).Error
 	return questions, err
 }
@@ -91,13 +99,104 @@ func (r *repository) CountByTryOutAndSubtest(tryOutID, subtestID uint) (int64, e
 }
 
 func (r *repository) Create(question *entities.TryOutQuestion) error {
-	return r.db.Create(question).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, question.TryOutPackageID, question.SubtestID)
+		if err != nil {
+			return err
+		}
+		position := question.OrderNumber
+
+		// Append first so the insert cannot collide with the unique order index
+		question.OrderNumber = ordering.Next(siblings)
+		if err := tx.Create(question).Error; err != nil {
+			return err
+		}
+
+		ordered := ordering.InsertAt(siblings, ordering.Slot{ID: question.ID, Position: question.OrderNumber}, position)
+		if err := questionList(question.TryOutPackageID, question.SubtestID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		question.OrderNumber = ordering.PositionOf(ordered, question.ID)
+		return nil
+	})
 }
 
 func (r *repository) Update(question *entities.TryOutQuestion) error {
-	return r.db.Save(question).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, question.TryOutPackageID, question.SubtestID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("OrderNumber").Save(question).Error; err != nil {
+			return err
+		}
+
+		ordered := ordering.Move(siblings, question.ID, question.OrderNumber)
+		if err := questionList(question.TryOutPackageID, question.SubtestID).Resequence(tx, ordered); err != nil {
+			return err
+		}
+		question.OrderNumber = ordering.PositionOf(ordered, question.ID)
+		return nil
+	})
 }
 
 func (r *repository) Delete(id uint) error {
-	return r.db.Delete(&entities.TryOutQuestion{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var question entities.TryOutQuestion
+		if err := tx.Select("id", "try_out_package_id", "subtest_id").First(&question, id).Error; err != nil {
+			return err
+		}
+		if _, err := lockSiblings(tx, question.TryOutPackageID, question.SubtestID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.TryOutQuestion{}, id).Error; err != nil {
+			return err
+		}
+
+		// Close the gap the question leaves behind
+		list := questionList(question.TryOutPackageID, question.SubtestID)
+		remaining, err := list.Load(tx)
+		if err != nil {
+			return err
+		}
+		return list.Resequence(tx, remaining)
+	})
+}
+
+func (r *repository) Reorder(tryOutID, subtestID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		siblings, err := lockSiblings(tx, tryOutID, subtestID)
+		if err != nil {
+			return err
+		}
+
+		ordered, ok := ordering.Arrange(siblings, ids)
+		if !ok {
+			return errors.New("question list must contain every question of the subtest exactly once")
+		}
+		return questionList(tryOutID, subtestID).Resequence(tx, ordered)
+	})
+}
+
+// questionList is the ordered list of questions of one subtest in a try out
+func questionList(tryOutID, subtestID uint) ordering.List {
+	return ordering.List{
+		Model:  &entities.TryOutQuestion{},
+		Column: "order_number",
+		Scope:  "try_out_package_id = ? AND subtest_id = ?",
+		Args:   []any{tryOutID, subtestID},
+	}
+}
+
+// lockSiblings locks the try out row, so concurrent changes to its questions run one after
+// another, and returns the subtest questions in their current order
+func lockSiblings(tx *gorm.DB, tryOutID, subtestID uint) ([]ordering.Slot, error) {
+	var tryOut entities.TryOut
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&tryOut, tryOutID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("try out not found")
+		}
+		return nil, err
+	}
+	return questionList(tryOutID, subtestID).Load(tx)
 }
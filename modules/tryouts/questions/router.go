@@ -28,6 +28,9 @@ func QuestionRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requir
 
 		// Create question for a subtest
 		questionsAdmin.POST("/:id/subtests/:subtestId/questions", handler.CreateQuestionHandler)
+
+		// Renumber the questions of a subtest in one go
+		questionsAdmin.PUT("/:id/subtests/:subtestId/questions/order", handler.ReorderQuestionsHandler)
 	}
 
 	// Question-specific endpoints
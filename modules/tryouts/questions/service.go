@@ -31,6 +31,7 @@ type Service interface {
 	CreateQuestion(tryOutID, subtestID uint, input CreateQuestionInput, requestID string, userID uint) (*QuestionResponse, error)
 	UpdateQuestion(id uint, input UpdateQuestionInput, requestID string, userID uint) (*QuestionResponse, error)
 	DeleteQuestion(id uint, requestID string, userID uint) error
+	ReorderQuestions(tryOutID, subtestID uint, input ReorderQuestionsInput, requestID string, userID uint) ([]QuestionResponse, error)
 
 	// Permission check
 	CheckTutorPermission(tryOutID, userID uint) error
@@ -315,3 +316,33 @@ func (s *questionService) DeleteQuestion(id uint, requestID string, userID uint)
 	})
 	return nil
 }
+
+func (s *questionService) ReorderQuestions(tryOutID, subtestID uint, input ReorderQuestionsInput, requestID string, userID uint) ([]QuestionResponse, error) {
+	utils.LogInfo("questions", "reorder", "Attempting to reorder questions", requestID, userID, map[string]any{
+		"try_out_id": tryOutID,
+		"subtest_id": subtestID,
+		"count":      len(input.QuestionIDs),
+	})
+
+	if err := s.repo.Reorder(tryOutID, subtestID, input.QuestionIDs); err != nil {
+		if err.Error() != "try out not found" && err.Error() != "question list must contain every question of the subtest exactly once" {
+			utils.LogError("questions", "reorder", "Failed to reorder questions: "+err.Error(), requestID, userID, nil)
+		}
+		return nil, err
+	}
+
+	questions, err := s.repo.FindByTryOutAndSubtest(tryOutID, subtestID, "")
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]QuestionResponse, 0, len(questions))
+	for _, q := range questions {
+		responses = append(responses, ToQuestionResponse(q))
+	}
+
+	utils.LogSuccess("questions", "reorder", "Questions reordered successfully", requestID, userID, map[string]any{
+		"try_out_id": tryOutID,
+		"subtest_id": subtestID,
+	})
+	return responses, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/xuri/excelize/v2"
 )
 
 // ==========================================
@@ -12,18 +13,25 @@ import (
 
 // RegistrationResponse is the response DTO for a registration
 type RegistrationResponse struct {
-	ID              uint                  `json:"id"`
-	TryOutID        uint                  `json:"tryOutId"`
-	TryOut          *TryOutBriefResponse  `json:"tryOut,omitempty"`
-	User            *UserBriefResponse    `json:"user,omitempty"`
-	PaymentProofURL string                `json:"paymentProofUrl,omitempty"`
-	PaymentStatus   string                `json:"paymentStatus"`
-	RejectionReason string                `json:"rejectionReason,omitempty"`
-	ApprovedBy      *UserBriefResponse    `json:"approvedBy,omitempty"`
-	ApprovedAt      *time.Time            `json:"approvedAt,omitempty"`
-	RegisteredAt    time.Time             `json:"registeredAt"`
-	HasAttempt      bool                  `json:"hasAttempt"`
-	Attempt         *AttemptBriefResponse `json:"attempt,omitempty"`
+	ID               uint                  `json:"id"`
+	TryOutID         uint                  `json:"tryOutId"`
+	TryOut           *TryOutBriefResponse  `json:"tryOut,omitempty"`
+	User             *UserBriefResponse    `json:"user,omitempty"`
+	PaymentProofURL  string                `json:"paymentProofUrl,omitempty"`
+	PaymentStatus    string                `json:"paymentStatus"`
+	RejectionReason  string                `json:"rejectionReason,omitempty"`
+	OriginalPrice    float64               `json:"originalPrice"`
+	DiscountAmount   float64               `json:"discountAmount"`
+	FinalAmount      float64               `json:"finalAmount"`
+	PromoCode        string                `json:"promoCode,omitempty"`
+	BundlePurchaseID *uint                 `json:"bundlePurchaseId,omitempty"`
+	ApprovedBy       *UserBriefResponse    `json:"approvedBy,omitempty"`
+	ApprovedAt       *time.Time            `json:"approvedAt,omitempty"`
+	PaymentDueAt     *time.Time            `json:"paymentDueAt,omitempty"`
+	WaitlistedAt     *time.Time            `json:"waitlistedAt,omitempty"`
+	RegisteredAt     time.Time             `json:"registeredAt"`
+	HasAttempt       bool                  `json:"hasAttempt"`
+	Attempt          *AttemptBriefResponse `json:"attempt,omitempty"`
 }
 
 // AttemptBriefResponse is a minimal attempt info for registration response
@@ -57,9 +65,15 @@ type PendingPaymentResponse struct {
 	TryOut          *TryOutBriefResponse `json:"tryOut,omitempty"`
 	User            *UserBriefResponse   `json:"user,omitempty"`
 	PaymentProofURL string               `json:"paymentProofUrl"`
+	UploadedAt      *time.Time           `json:"uploadedAt,omitempty"`
 	RegisteredAt    time.Time            `json:"registeredAt"`
 }
 
+// RegisterTryOutInput is the optional body when registering for a try out
+type RegisterTryOutInput struct {
+	PromoCode string `json:"promoCode"`
+}
+
 // UploadPaymentProofInput is the input for uploading payment proof
 type UploadPaymentProofInput struct {
 	PaymentProofURL string `json:"paymentProofUrl" binding:"required"`
@@ -70,20 +84,78 @@ type ApprovePaymentInput struct {
 	RejectionReason string `json:"rejectionReason"` // Only used when rejecting
 }
 
+// BulkReviewInput approves or rejects many pending payments in one transaction.
+// Registrations are selected by RegistrationIDs or, when empty, by Filter.
+type BulkReviewInput struct {
+	Action          string            `json:"action" binding:"required,oneof=approve reject"`
+	RegistrationIDs []uint            `json:"registrationIds"`
+	Filter          *BulkReviewFilter `json:"filter"`
+	RejectionReason string            `json:"rejectionReason"` // Only used when rejecting
+}
+
+// BulkReviewFilter selects pending proofs of one package and/or uploaded before a time
+type BulkReviewFilter struct {
+	TryOutID       uint       `json:"tryOutId"`
+	UploadedBefore *time.Time `json:"uploadedBefore"`
+}
+
+// BulkReviewItemResult is the outcome for one registration of a bulk review
+type BulkReviewItemResult struct {
+	RegistrationID uint   `json:"registrationId"`
+	Success        bool   `json:"success"`
+	PaymentStatus  string `json:"paymentStatus,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BulkReviewResponse summarises a bulk review
+type BulkReviewResponse struct {
+	Action    string                 `json:"action"`
+	Total     int                    `json:"total"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BulkReviewItemResult `json:"results"`
+}
+
+// ExportResultsFilter narrows the results export to one school and/or class
+type ExportResultsFilter struct {
+	School string `form:"school"`
+	Kelas  string `form:"kelas"`
+}
+
+// DeadlineRunResponse summarizes one run of the payment deadline job
+type DeadlineRunResponse struct {
+	RemindersSent int `json:"remindersSent"`
+	Expired       int `json:"expired"`
+	Failed        int `json:"failed"`
+}
+
+// ResultsExport is a generated workbook ready to be written to the client
+type ResultsExport struct {
+	FileName string
+	File     *excelize.File
+}
+
 // ==========================================
 // Helper Functions
 // ==========================================
 
 func ToRegistrationResponse(r entities.TryOutRegistration) RegistrationResponse {
 	response := RegistrationResponse{
-		ID:              r.ID,
-		TryOutID:        r.TryOutPackageID,
-		PaymentProofURL: r.PaymentProofURL,
-		PaymentStatus:   string(r.PaymentStatus),
-		RejectionReason: r.RejectionReason,
-		ApprovedAt:      r.ApprovedAt,
-		RegisteredAt:    r.RegisteredAt,
-		HasAttempt:      r.Attempt != nil,
+		ID:               r.ID,
+		TryOutID:         r.TryOutPackageID,
+		PaymentProofURL:  r.PaymentProofURL,
+		PaymentStatus:    string(r.PaymentStatus),
+		RejectionReason:  r.RejectionReason,
+		OriginalPrice:    r.OriginalPrice,
+		DiscountAmount:   r.DiscountAmount,
+		FinalAmount:      r.FinalAmount,
+		PromoCode:        r.PromoCode,
+		BundlePurchaseID: r.BundlePurchaseID,
+		ApprovedAt:       r.ApprovedAt,
+		PaymentDueAt:     r.PaymentDueAt,
+		WaitlistedAt:     r.WaitlistedAt,
+		RegisteredAt:     r.RegisteredAt,
+		HasAttempt:       r.Attempt != nil,
 	}
 
 	if r.Attempt != nil {
@@ -117,6 +189,7 @@ func ToPendingPaymentResponse(r entities.TryOutRegistration) PendingPaymentRespo
 	response := PendingPaymentResponse{
 		ID:              r.ID,
 		PaymentProofURL: r.PaymentProofURL,
+		UploadedAt:      r.PaymentProofUploadedAt,
 		RegisteredAt:    r.RegisteredAt,
 	}
 
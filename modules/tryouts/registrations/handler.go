@@ -1,12 +1,15 @@
 package registrations
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/modules/promos"
 	"github.com/redukasquad/be-reduka/packages/utils"
 )
 
@@ -25,7 +28,13 @@ type Handler interface {
 	GetRegistrationsByTryOutHandler(c *gin.Context)
 	ApprovePaymentHandler(c *gin.Context)
 	RejectPaymentHandler(c *gin.Context)
+	BulkReviewPaymentsHandler(c *gin.Context)
 	DeleteRegistrationHandler(c *gin.Context)
+	ExportResultsHandler(c *gin.Context)
+	ProcessPaymentDeadlinesHandler(c *gin.Context)
+
+	// Scheduler endpoints
+	PaymentDeadlinesCronHandler(c *gin.Context)
 }
 
 func NewHandler(service Service) Handler {
@@ -72,8 +81,21 @@ func (h *handler) RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	registration, err := h.service.Register(uint(tryOutID), userID, requestID)
+	// Body is optional; it only carries a promo code
+	var input RegisterTryOutInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+			return
+		}
+	}
+
+	registration, err := h.service.Register(uint(tryOutID), userID, input, requestID)
 	if err != nil {
+		if promos.IsCodeError(err) {
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid promo code", err.Error(), nil))
+			return
+		}
 		switch err.Error() {
 		case "try out not found":
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Try out not found", err.Error(), nil))
@@ -119,7 +141,7 @@ func (h *handler) UploadPaymentProofHandler(c *gin.Context) {
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
 		case "you can only upload payment proof for your own registration":
 			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
-		case "payment is already approved", "no payment required for free try out":
+		case "payment is already approved", "no payment required for free try out", "payment is handled by your school coordinator":
 			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
 		default:
 			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to upload payment proof", err.Error(), nil))
@@ -194,7 +216,7 @@ func (h *handler) ApprovePaymentHandler(c *gin.Context) {
 		switch err.Error() {
 		case "registration not found":
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
-		case "payment is already approved":
+		case "payment is already approved", "registration is on the waitlist":
 			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
 		default:
 			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to approve payment", err.Error(), nil))
@@ -227,7 +249,7 @@ func (h *handler) RejectPaymentHandler(c *gin.Context) {
 		switch err.Error() {
 		case "registration not found":
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
-		case "cannot reject an already approved payment":
+		case "cannot reject an already approved payment", "registration is on the waitlist":
 			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid operation", err.Error(), nil))
 		default:
 			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to reject payment", err.Error(), nil))
@@ -238,6 +260,30 @@ func (h *handler) RejectPaymentHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payment rejected", registration))
 }
 
+func (h *handler) BulkReviewPaymentsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+
+	var input BulkReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	result, err := h.service.BulkReviewPayments(input, userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "registrationIds or filter is required", "filter needs tryOutId or uploadedBefore", "too many registrations selected":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid selection", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to review payments", err.Error(), nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payments reviewed", result))
+}
+
 func (h *handler) DeleteRegistrationHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 	registrationIDStr := c.Param("id")
@@ -249,13 +295,81 @@ func (h *handler) DeleteRegistrationHandler(c *gin.Context) {
 	}
 
 	if err := h.service.DeleteRegistration(uint(registrationID), requestID); err != nil {
-		if err.Error() == "registration not found" {
+		switch err.Error() {
+		case "registration not found":
 			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Registration not found", err.Error(), nil))
-			return
+		case "registration has payments attached; refund it before deleting":
+			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Registration has payments", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete registration", err.Error(), nil))
 		}
-		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to delete registration", err.Error(), nil))
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Registration deleted successfully", nil))
 }
+
+func (h *handler) ExportResultsHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	tryOutIDStr := c.Param("id")
+
+	tryOutID, err := strconv.ParseUint(tryOutIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return
+	}
+
+	var filter ExportResultsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid query parameters", err.Error(), nil))
+		return
+	}
+
+	export, err := h.service.ExportResults(uint(tryOutID), filter, requestID)
+	if err != nil {
+		if err.Error() == "try out not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Try out not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to export results", err.Error(), nil))
+		return
+	}
+	defer export.File.Close()
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	c.Status(http.StatusOK)
+	if err := export.File.Write(c.Writer); err != nil {
+		utils.LogError("registrations", "export_results", "Failed to write export: "+err.Error(), requestID, 0, nil)
+	}
+}
+
+func (h *handler) ProcessPaymentDeadlinesHandler(c *gin.Context) {
+	result, err := h.service.ProcessPaymentDeadlines(getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to process payment deadlines", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payment deadlines processed", result))
+}
+
+// ==========================================
+// Scheduler Handlers
+// ==========================================
+
+// PaymentDeadlinesCronHandler is called by the hosting scheduler with "Authorization: Bearer <CRON_SECRET>"
+func (h *handler) PaymentDeadlinesCronHandler(c *gin.Context) {
+	secret := os.Getenv("CRON_SECRET")
+	expected := "Bearer " + secret
+	if secret == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) != 1 {
+		c.JSON(http.StatusUnauthorized, utils.BuildResponseFailed("Unauthorized", "invalid cron secret", nil))
+		return
+	}
+
+	result, err := h.service.ProcessPaymentDeadlines(getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to process payment deadlines", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Payment deadlines processed", result))
+}
@@ -1,7 +1,10 @@
 package registrations
 
 import (
+	"time"
+
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
 	"gorm.io/gorm"
 )
 
@@ -9,6 +12,14 @@ type repository struct {
 	db *gorm.DB
 }
 
+// reviewDecision is one approval or rejection of a bulk review
+type reviewDecision struct {
+	Registration    entities.TryOutRegistration
+	Approve         bool
+	Charge          float64 // Amount due, recorded in the ledger on approval
+	RejectionReason string
+}
+
 type Repository interface {
 	// Registrations
 	FindByID(id uint) (entities.TryOutRegistration, error)
@@ -24,6 +35,27 @@ type Repository interface {
 
 	// Try Out
 	FindTryOutByID(id uint) (entities.TryOut, error)
+
+	// Payment deadlines
+	FindDueForReminder(now time.Time) ([]entities.TryOutRegistration, error)
+	MarkReminderSent(id uint, at time.Time) error
+	FindOverdueUnpaid(now time.Time) ([]entities.TryOutRegistration, error)
+	// Expire marks an unpaid registration expired and soft-deletes it so Register can restore it later.
+	// It returns false when the registration was paid or changed in the meantime.
+	Expire(id uint, at time.Time) (bool, error)
+
+	// Bulk review
+	FindByIDs(ids []uint) ([]entities.TryOutRegistration, error)
+	FindPendingProofs(filter BulkReviewFilter, limit int) ([]entities.TryOutRegistration, error)
+	// Approve saves the approved registration and records the charge still owed in one transaction
+	Approve(registration *entities.TryOutRegistration, charge float64, adminUserID uint) error
+	// ApplyBulkReview applies all decisions in one transaction and returns the IDs that were still pending
+	ApplyBulkReview(decisions []reviewDecision, adminUserID uint, at time.Time) (map[uint]bool, error)
+
+	// Results export
+	FindForExportInBatches(tryOutID uint, filter ExportResultsFilter, batchSize int, fn func([]entities.TryOutRegistration) error) error
+	FindRankedAttemptIDs(tryOutID uint) ([]uint, error)
+	FindAllSubtests() ([]entities.Subtest, error)
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -108,6 +140,45 @@ func (r *repository) Update(registration *entities.TryOutRegistration) error {
 	return r.db.Save(registration).Error
 }
 
+func (r *repository) Approve(registration *entities.TryOutRegistration, charge float64, adminUserID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(registration).Error; err != nil {
+			return err
+		}
+		return recordCharge(tx, *registration, charge, adminUserID, time.Now())
+	})
+}
+
+// recordCharge books the part of charge not yet charged to the registration, so a charge
+// recorded earlier is not booked twice. Refunds and adjustments do not count as paid here.
+func recordCharge(tx *gorm.DB, registration entities.TryOutRegistration, charge float64, adminUserID uint, at time.Time) error {
+	if charge <= 0 {
+		return nil
+	}
+	var paid float64
+	if err := tx.Model(&entities.LedgerEntry{}).
+		Where("registration_id = ? AND type = ?", registration.ID, entities.LedgerEntryCharge).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&paid).Error; err != nil {
+		return err
+	}
+	if charge-paid <= 0 {
+		return nil
+	}
+	registrationID := registration.ID
+	tryOutID := registration.TryOutPackageID
+	return tx.Create(&entities.LedgerEntry{
+		Type:             entities.LedgerEntryCharge,
+		Amount:           charge - paid,
+		UserID:           registration.UserID,
+		RegistrationID:   &registrationID,
+		TryOutPackageID:  &tryOutID,
+		Source:           ledger.SourceManualProof,
+		RecordedByUserID: &adminUserID,
+		OccurredAt:       at,
+	}).Error
+}
+
 // ==========================================
 // Try Out Repository Methods
 // ==========================================
@@ -121,3 +192,172 @@ func (r *repository) FindTryOutByID(id uint) (entities.TryOut, error) {
 func (r *repository) Delete(id uint) error {
 	return r.db.Delete(&entities.TryOutRegistration{}, id).Error
 }
+
+// ==========================================
+// Payment Deadline Methods
+// ==========================================
+
+// unpaidCondition matches pending registrations without an uploaded payment proof
+const unpaidCondition = "try_out_registrations.payment_status = ? AND COALESCE(try_out_registrations.payment_proof_url, '') = ''"
+
+func (r *repository) FindDueForReminder(now time.Time) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	err := r.db.Joins("JOIN try_outs ON try_outs.id = try_out_registrations.try_out_package_id").
+		Where(unpaidCondition, entities.PaymentStatusPending).
+		Where("try_out_registrations.payment_reminder_sent_at IS NULL").
+		Where("try_out_registrations.payment_due_at > ?", now).
+		Where("try_outs.payment_reminder_hours > 0").
+		Where("try_out_registrations.payment_due_at <= ? + try_outs.payment_reminder_hours * INTERVAL '1 hour'", now).
+		Preload("TryOutPackage").
+		Preload("User").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) MarkReminderSent(id uint, at time.Time) error {
+	return r.db.Model(&entities.TryOutRegistration{}).Where("id = ?", id).
+		UpdateColumn("payment_reminder_sent_at", at).Error
+}
+
+func (r *repository) FindOverdueUnpaid(now time.Time) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	err := r.db.Where(unpaidCondition, entities.PaymentStatusPending).
+		Where("try_out_registrations.payment_due_at <= ?", now).
+		Preload("TryOutPackage").
+		Preload("User").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) Expire(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entities.TryOutRegistration{}).
+		Where("id = ?", id).
+		Where(unpaidCondition, entities.PaymentStatusPending).
+		UpdateColumns(map[string]any{
+			"payment_status": entities.PaymentStatusExpired,
+			"expired_at":     at,
+			"deleted_at":     at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ==========================================
+// Bulk Review Methods
+// ==========================================
+
+func (r *repository) FindByIDs(ids []uint) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	err := r.db.Where("id IN ?", ids).
+		Preload("TryOutPackage").
+		Preload("User").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+// FindPendingProofs uses the registration's last update as upload time for proofs uploaded before it was tracked
+func (r *repository) FindPendingProofs(filter BulkReviewFilter, limit int) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	query := r.db.Where("payment_status = ?", entities.PaymentStatusPending).
+		Where("payment_proof_url IS NOT NULL AND payment_proof_url != ''")
+	if filter.TryOutID != 0 {
+		query = query.Where("try_out_package_id = ?", filter.TryOutID)
+	}
+	if filter.UploadedBefore != nil {
+		query = query.Where("COALESCE(payment_proof_uploaded_at, updated_at) < ?", *filter.UploadedBefore)
+	}
+	err := query.Preload("TryOutPackage").
+		Preload("User").
+		Order("registered_at ASC").
+		Limit(limit).
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) ApplyBulkReview(decisions []reviewDecision, adminUserID uint, at time.Time) (map[uint]bool, error) {
+	applied := make(map[uint]bool, len(decisions))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, d := range decisions {
+			updates := map[string]any{
+				"payment_status":            entities.PaymentStatusRejected,
+				"rejection_reason":          d.RejectionReason,
+				"payment_proof_url":         "",
+				"payment_proof_uploaded_at": nil,
+			}
+			if d.Approve {
+				updates = map[string]any{
+					"payment_status":      entities.PaymentStatusApproved,
+					"rejection_reason":    "",
+					"approved_by_user_id": adminUserID,
+					"approved_at":         at,
+				}
+			}
+
+			// The status guard skips rows reviewed by someone else since they were loaded
+			result := tx.Model(&entities.TryOutRegistration{}).
+				Where("id = ? AND payment_status = ?", d.Registration.ID, entities.PaymentStatusPending).
+				Updates(updates)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			applied[d.Registration.ID] = true
+
+			if !d.Approve {
+				continue
+			}
+			if err := recordCharge(tx, d.Registration, d.Charge, adminUserID, at); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// ==========================================
+// Results Export Methods
+// ==========================================
+
+func (r *repository) FindForExportInBatches(tryOutID uint, filter ExportResultsFilter, batchSize int, fn func([]entities.TryOutRegistration) error) error {
+	query := r.db.Joins("JOIN users ON users.id = try_out_registrations.user_id").
+		Where("try_out_registrations.try_out_package_id = ?", tryOutID)
+
+	if filter.School != "" {
+		query = query.Where("LOWER(users.school) = LOWER(?)", filter.School)
+	}
+	if filter.Kelas != "" {
+		query = query.Where("users.kelas = ?", filter.Kelas)
+	}
+
+	var batch []entities.TryOutRegistration
+	return query.Preload("User").
+		Preload("Attempt.SubtestResults").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// FindRankedAttemptIDs returns completed attempt IDs in leaderboard order
+func (r *repository) FindRankedAttemptIDs(tryOutID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&entities.TryOutAttempt{}).
+		Joins("JOIN try_out_registrations ON try_out_registrations.id = try_out_attempts.registration_id").
+		Where("try_out_registrations.try_out_package_id = ?", tryOutID).
+		Where("try_out_attempts.status = ?", entities.AttemptStatusCompleted).
+		Where("try_out_attempts.total_score IS NOT NULL").
+		Order("try_out_attempts.total_score DESC, try_out_attempts.finished_at ASC").
+		Pluck("try_out_attempts.id", &ids).Error
+	return ids, err
+}
+
+func (r *repository) FindAllSubtests() ([]entities.Subtest, error) {
+	var subtests []entities.Subtest
+	err := r.db.Order("id ASC").Find(&subtests).Error
+	return subtests, err
+}
@@ -3,11 +3,18 @@ package registrations
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 )
 
 func RegistrationRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc) {
-	repo := NewRepository(migrations.GetDB())
-	service := NewService(repo)
+	db := migrations.GetDB()
+	repo := NewRepository(db)
+	promoService := promos.NewService(promos.NewRepository(db))
+	waitlistService := waitlists.NewService(waitlists.NewRepository(db))
+	service := NewService(repo, promoService, ledger.NewService(ledger.NewRepository(db), promoService, waitlistService), receipts.NewService(receipts.NewRepository(db)), waitlistService)
 	handler := NewHandler(service)
 
 	// User: register for a tryout
@@ -25,6 +32,7 @@ func RegistrationRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, re
 	adminRoutes.Use(requireAuth, requireAdmin)
 	{
 		adminRoutes.GET("/:id/registrations", handler.GetRegistrationsByTryOutHandler)
+		adminRoutes.GET("/:id/registrations/export", handler.ExportResultsHandler)
 	}
 
 	// User: upload payment proof — path /payment-proof/:id to avoid wildcard conflict with DELETE /:id
@@ -39,8 +47,13 @@ func RegistrationRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, re
 	adminRegRoutes.Use(requireAuth, requireAdmin)
 	{
 		adminRegRoutes.GET("/pending", handler.GetPendingPaymentsHandler)
+		adminRegRoutes.POST("/process-deadlines", handler.ProcessPaymentDeadlinesHandler)
+		adminRegRoutes.POST("/bulk-review", handler.BulkReviewPaymentsHandler)
 		adminRegRoutes.PUT("/:id/approve", handler.ApprovePaymentHandler)
 		adminRegRoutes.PUT("/:id/reject", handler.RejectPaymentHandler)
 		adminRegRoutes.DELETE("/:id", handler.DeleteRegistrationHandler)
 	}
+
+	// Scheduler: expire unpaid registrations and send reminders (authenticated by CRON_SECRET)
+	router.GET("/cron/payment-deadlines", handler.PaymentDeadlinesCronHandler)
 }
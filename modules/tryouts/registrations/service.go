@@ -2,20 +2,38 @@ package registrations
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
 	"github.com/redukasquad/be-reduka/packages/utils"
+	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
 )
 
+// bulkReviewLimit caps how many registrations one bulk review may touch
+const bulkReviewLimit = 1000
+
+// exportBatchSize is how many registrations are loaded per query while streaming the results export
+const exportBatchSize = 500
+
 type registrationService struct {
-	repo Repository
+	repo      Repository
+	promos    promos.Service
+	ledger    ledger.Service
+	receipts  receipts.Service
+	waitlists waitlists.Service
 }
 
 type Service interface {
 	// User actions
-	Register(tryOutID uint, userID uint, requestID string) (*RegistrationResponse, error)
+	Register(tryOutID uint, userID uint, input RegisterTryOutInput, requestID string) (*RegistrationResponse, error)
 	UploadPaymentProof(registrationID uint, input UploadPaymentProofInput, userID uint, requestID string) (*RegistrationResponse, error)
 	GetMyRegistrations(userID uint, requestID string) ([]RegistrationResponse, error)
 	GetRegistrationByID(id uint, requestID string) (*RegistrationResponse, error)
@@ -26,19 +44,25 @@ type Service interface {
 	ApprovePayment(registrationID uint, adminUserID uint, requestID string) (*RegistrationResponse, error)
 	RejectPayment(registrationID uint, input ApprovePaymentInput, adminUserID uint, requestID string) (*RegistrationResponse, error)
 	DeleteRegistration(registrationID uint, requestID string) error
+	BulkReviewPayments(input BulkReviewInput, adminUserID uint, requestID string) (*BulkReviewResponse, error)
+	ExportResults(tryOutID uint, filter ExportResultsFilter, requestID string) (*ResultsExport, error)
+
+	// Scheduled jobs
+	ProcessPaymentDeadlines(requestID string) (*DeadlineRunResponse, error)
 }
 
-func NewService(repo Repository) Service {
-	return &registrationService{repo: repo}
+func NewService(repo Repository, promoService promos.Service, ledgerService ledger.Service, receiptService receipts.Service, waitlistService waitlists.Service) Service {
+	return &registrationService{repo: repo, promos: promoService, ledger: ledgerService, receipts: receiptService, waitlists: waitlistService}
 }
 
 // ==========================================
 // User Actions
 // ==========================================
 
-func (s *registrationService) Register(tryOutID uint, userID uint, requestID string) (*RegistrationResponse, error) {
+func (s *registrationService) Register(tryOutID uint, userID uint, input RegisterTryOutInput, requestID string) (*RegistrationResponse, error) {
 	utils.LogInfo("registrations", "register", "User attempting to register for try out", requestID, userID, map[string]any{
 		"try_out_id": tryOutID,
+		"promo_code": input.PromoCode,
 	})
 
 	// Check if try out exists
@@ -59,66 +83,136 @@ func (s *registrationService) Register(tryOutID uint, userID uint, requestID str
 		return nil, errors.New("registration period has ended")
 	}
 
-	// Determine initial payment status
-	paymentStatus := entities.PaymentStatusPending
+	// Check if already registered (including soft-deleted)
+	existing, err := s.repo.FindByUserAndTryOutUnscoped(userID, tryOutID)
+	found := err == nil
+	if found && !existing.DeletedAt.Valid {
+		return nil, errors.New("you are already registered for this try out")
+	}
+
+	// Price the registration, applying the promo code if one was given
+	price := tryOut.Price
 	if tryOut.IsFree {
+		price = 0
+	}
+	target := promos.Target{Kind: promos.TargetTryOut, ID: tryOutID, Price: price}
+	var quote *promos.Quote
+	if input.PromoCode != "" {
+		quote, err = s.promos.Quote(input.PromoCode, target, userID)
+		if err != nil {
+			utils.LogWarning("registrations", "register", "Promo code refused: "+err.Error(), requestID, userID, map[string]any{
+				"try_out_id": tryOutID,
+				"promo_code": input.PromoCode,
+			})
+			return nil, err
+		}
+	}
+
+	// Determine initial payment status: nothing to pay means approved immediately
+	paymentStatus := entities.PaymentStatusPending
+	if tryOut.IsFree || (quote != nil && quote.FinalAmount == 0) {
 		paymentStatus = entities.PaymentStatusApproved
 	}
 
-	// Check if already registered (including soft-deleted)
-	existing, err := s.repo.FindByUserAndTryOutUnscoped(userID, tryOutID)
-	if err == nil {
-		// Record exists (active or soft-deleted)
-		if existing.DeletedAt.Valid {
-			// Was soft-deleted — restore it and reset status
-			if err := s.repo.Restore(existing.ID); err != nil {
-				return nil, err
-			}
-			existing.DeletedAt.Valid = false
-			existing.PaymentStatus = paymentStatus
-			existing.PaymentProofURL = ""
-			existing.RejectionReason = ""
-			existing.RegisteredAt = now
-			if err := s.repo.Update(&existing); err != nil {
-				return nil, err
-			}
-			createdReg, err := s.repo.FindByID(existing.ID)
-			if err != nil {
-				return nil, err
-			}
-			response := ToRegistrationResponse(createdReg)
-			return &response, nil
+	var registrationID uint
+	if found {
+		// Was soft-deleted — restore it and reset status
+		if err := s.repo.Restore(existing.ID); err != nil {
+			return nil, err
 		}
-		return nil, errors.New("you are already registered for this try out")
+		existing.DeletedAt.Valid = false
+		existing.PaymentStatus = paymentStatus
+		existing.PaymentProofURL = ""
+		existing.RejectionReason = ""
+		existing.ApprovedByUserID = nil
+		existing.ApprovedAt = nil
+		existing.RegisteredAt = now
+		existing.PaymentDueAt = paymentDueAt(tryOut, paymentStatus, now)
+		existing.PaymentReminderSentAt = nil
+		existing.ExpiredAt = nil
+		applyPricing(&existing, price, quote)
+		if err := s.repo.Update(&existing); err != nil {
+			return nil, err
+		}
+		registrationID = existing.ID
+	} else {
+		registration := &entities.TryOutRegistration{
+			UserID:          userID,
+			TryOutPackageID: tryOutID,
+			PaymentStatus:   paymentStatus,
+			RegisteredAt:    now,
+			PaymentDueAt:    paymentDueAt(tryOut, paymentStatus, now),
+		}
+		applyPricing(registration, price, quote)
+
+		if err := s.repo.Create(registration); err != nil {
+			utils.LogError("registrations", "register", "Failed to create registration: "+err.Error(), requestID, userID, nil)
+			return nil, err
+		}
+		registrationID = registration.ID
 	}
 
-	registration := &entities.TryOutRegistration{
-		UserID:          userID,
-		TryOutPackageID: tryOutID,
-		PaymentStatus:   paymentStatus,
-		RegisteredAt:    now,
+	// Claim the promo code; limits are rechecked under lock, so undo the registration if it was used up meanwhile
+	if quote != nil {
+		if _, err := s.promos.Redeem(input.PromoCode, target, userID, registrationID); err != nil {
+			utils.LogWarning("registrations", "register", "Failed to redeem promo code: "+err.Error(), requestID, userID, map[string]any{
+				"registration_id": registrationID,
+				"promo_code":      input.PromoCode,
+			})
+			if delErr := s.repo.Delete(registrationID); delErr != nil {
+				utils.LogError("registrations", "register", "Failed to roll back registration: "+delErr.Error(), requestID, userID, nil)
+			}
+			return nil, err
+		}
 	}
 
-	if err := s.repo.Create(registration); err != nil {
-		utils.LogError("registrations", "register", "Failed to create registration: "+err.Error(), requestID, userID, nil)
+	// A full try out puts the registration on the waitlist instead
+	waitlisted, err := s.waitlists.AdmitTryOutRegistration(registrationID, requestID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Fetch with preload
-	createdReg, err := s.repo.FindByID(registration.ID)
+	createdReg, err := s.repo.FindByID(registrationID)
 	if err != nil {
 		return nil, err
 	}
 
 	utils.LogSuccess("registrations", "register", "Registration created successfully", requestID, userID, map[string]any{
-		"registration_id": registration.ID,
+		"registration_id": registrationID,
 		"is_free":         tryOut.IsFree,
+		"final_amount":    createdReg.FinalAmount,
+		"waitlisted":      waitlisted,
 	})
 
 	response := ToRegistrationResponse(createdReg)
 	return &response, nil
 }
 
+// paymentDueAt returns when an unpaid registration expires, or nil if the package has no deadline
+func paymentDueAt(tryOut entities.TryOut, status entities.PaymentStatus, now time.Time) *time.Time {
+	if status != entities.PaymentStatusPending || tryOut.PaymentDeadlineHours <= 0 {
+		return nil
+	}
+	due := now.Add(time.Duration(tryOut.PaymentDeadlineHours) * time.Hour)
+	return &due
+}
+
+// applyPricing stores the price snapshot and promo code on the registration
+func applyPricing(registration *entities.TryOutRegistration, price float64, quote *promos.Quote) {
+	registration.OriginalPrice = price
+	registration.DiscountAmount = 0
+	registration.FinalAmount = price
+	registration.PromoCodeID = nil
+	registration.PromoCode = ""
+	if quote != nil {
+		registration.DiscountAmount = quote.DiscountAmount
+		registration.FinalAmount = quote.FinalAmount
+		registration.PromoCodeID = &quote.PromoCodeID
+		registration.PromoCode = quote.Code
+	}
+}
+
 func (s *registrationService) UploadPaymentProof(registrationID uint, input UploadPaymentProofInput, userID uint, requestID string) (*RegistrationResponse, error) {
 	utils.LogInfo("registrations", "upload_payment_proof", "User uploading payment proof", requestID, userID, map[string]any{
 		"registration_id": registrationID,
@@ -146,15 +240,31 @@ func (s *registrationService) UploadPaymentProof(registrationID uint, input Uplo
 	if registration.TryOutPackage.IsFree {
 		return nil, errors.New("no payment required for free try out")
 	}
+	if registration.GroupEnrollmentID != nil {
+		return nil, errors.New("payment is handled by your school coordinator")
+	}
 
+	now := time.Now()
 	registration.PaymentProofURL = input.PaymentProofURL
-	registration.PaymentStatus = entities.PaymentStatusPending
+	registration.PaymentProofUploadedAt = &now
+	// Waitlisted students keep their place; the proof is reviewed once they get a seat
+	resubmitted := registration.PaymentStatus == entities.PaymentStatusRejected
+	if registration.PaymentStatus != entities.PaymentStatusWaitlisted {
+		registration.PaymentStatus = entities.PaymentStatusPending
+	}
 
 	if err := s.repo.Update(&registration); err != nil {
 		utils.LogError("registrations", "upload_payment_proof", "Failed to update registration: "+err.Error(), requestID, userID, nil)
 		return nil, err
 	}
 
+	// A rejection gave the seat up, so a resubmission has to find a free one again
+	if resubmitted {
+		if _, err := s.waitlists.AdmitTryOutRegistration(registration.ID, requestID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Fetch with preload
 	updatedReg, err := s.repo.FindByID(registration.ID)
 	if err != nil {
@@ -259,6 +369,9 @@ func (s *registrationService) ApprovePayment(registrationID uint, adminUserID ui
 	if registration.PaymentStatus == entities.PaymentStatusApproved {
 		return nil, errors.New("payment is already approved")
 	}
+	if registration.PaymentStatus == entities.PaymentStatusWaitlisted {
+		return nil, errors.New("registration is on the waitlist")
+	}
 
 	now := time.Now()
 	registration.PaymentStatus = entities.PaymentStatusApproved
@@ -266,11 +379,21 @@ func (s *registrationService) ApprovePayment(registrationID uint, adminUserID ui
 	registration.ApprovedAt = &now
 	registration.RejectionReason = ""
 
-	if err := s.repo.Update(&registration); err != nil {
+	// The approval and the money received are stored together, so no approved registration lacks its charge
+	if err := s.repo.Approve(&registration, amountDue(registration), adminUserID); err != nil {
 		utils.LogError("registrations", "approve_payment", "Failed to approve payment: "+err.Error(), requestID, adminUserID, nil)
 		return nil, err
 	}
 
+	// Number the receipt and email it to the student
+	if amountDue(registration) > 0 && registration.BundlePurchaseID == nil {
+		if _, err := s.receipts.IssueTryOutReceipt(registration.ID, requestID); err != nil {
+			utils.LogError("registrations", "approve_payment", "Failed to issue receipt: "+err.Error(), requestID, adminUserID, map[string]any{
+				"registration_id": registrationID,
+			})
+		}
+	}
+
 	// Fetch with preload
 	updatedReg, err := s.repo.FindByID(registration.ID)
 	if err != nil {
@@ -301,10 +424,14 @@ func (s *registrationService) RejectPayment(registrationID uint, input ApprovePa
 	if registration.PaymentStatus == entities.PaymentStatusApproved {
 		return nil, errors.New("cannot reject an already approved payment")
 	}
+	if registration.PaymentStatus == entities.PaymentStatusWaitlisted {
+		return nil, errors.New("registration is on the waitlist")
+	}
 
 	registration.PaymentStatus = entities.PaymentStatusRejected
 	registration.RejectionReason = input.RejectionReason
 	registration.PaymentProofURL = "" // Clear proof so user can re-upload
+	registration.PaymentProofUploadedAt = nil
 
 	if err := s.repo.Update(&registration); err != nil {
 		utils.LogError("registrations", "reject_payment", "Failed to reject payment: "+err.Error(), requestID, adminUserID, nil)
@@ -322,16 +449,186 @@ func (s *registrationService) RejectPayment(registrationID uint, input ApprovePa
 		"reason":          input.RejectionReason,
 	})
 
+	// The rejected registration no longer holds a seat
+	s.promoteWaitlist(registration.TryOutPackageID, "reject_payment", requestID)
+
 	response := ToRegistrationResponse(updatedReg)
 	return &response, nil
 }
 
+func (s *registrationService) BulkReviewPayments(input BulkReviewInput, adminUserID uint, requestID string) (*BulkReviewResponse, error) {
+	utils.LogInfo("registrations", "bulk_review", "Admin bulk reviewing payments", requestID, adminUserID, map[string]any{
+		"action":   input.Action,
+		"ids":      len(input.RegistrationIDs),
+		"filtered": input.Filter != nil,
+	})
+
+	approve := input.Action == "approve"
+
+	// Select the registrations, keeping the requested order for the results
+	var ids []uint
+	var registrations []entities.TryOutRegistration
+	var err error
+	switch {
+	case len(input.RegistrationIDs) > 0:
+		if len(input.RegistrationIDs) > bulkReviewLimit {
+			return nil, errors.New("too many registrations selected")
+		}
+		seen := make(map[uint]bool, len(input.RegistrationIDs))
+		for _, id := range input.RegistrationIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		registrations, err = s.repo.FindByIDs(ids)
+	case input.Filter != nil:
+		if input.Filter.TryOutID == 0 && input.Filter.UploadedBefore == nil {
+			return nil, errors.New("filter needs tryOutId or uploadedBefore")
+		}
+		registrations, err = s.repo.FindPendingProofs(*input.Filter, bulkReviewLimit+1)
+		if len(registrations) > bulkReviewLimit {
+			return nil, errors.New("too many registrations selected")
+		}
+		for _, r := range registrations {
+			ids = append(ids, r.ID)
+		}
+	default:
+		return nil, errors.New("registrationIds or filter is required")
+	}
+	if err != nil {
+		utils.LogError("registrations", "bulk_review", "Failed to load registrations: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	byID := make(map[uint]entities.TryOutRegistration, len(registrations))
+	for _, r := range registrations {
+		byID[r.ID] = r
+	}
+
+	results := make([]BulkReviewItemResult, 0, len(ids))
+	var decisions []reviewDecision
+	for _, id := range ids {
+		reg, ok := byID[id]
+		switch {
+		case !ok:
+			results = append(results, BulkReviewItemResult{RegistrationID: id, Error: "registration not found"})
+		case reg.PaymentStatus != entities.PaymentStatusPending:
+			results = append(results, BulkReviewItemResult{RegistrationID: id, PaymentStatus: string(reg.PaymentStatus), Error: "registration is not pending"})
+		case reg.PaymentProofURL == "":
+			results = append(results, BulkReviewItemResult{RegistrationID: id, PaymentStatus: string(reg.PaymentStatus), Error: "payment proof has not been uploaded"})
+		default:
+			decisions = append(decisions, reviewDecision{
+				Registration:    reg,
+				Approve:         approve,
+				Charge:          amountDue(reg),
+				RejectionReason: input.RejectionReason,
+			})
+			results = append(results, BulkReviewItemResult{RegistrationID: id})
+		}
+	}
+
+	applied, err := s.repo.ApplyBulkReview(decisions, adminUserID, time.Now())
+	if err != nil {
+		utils.LogError("registrations", "bulk_review", "Bulk review rolled back: "+err.Error(), requestID, adminUserID, nil)
+		return nil, err
+	}
+
+	newStatus := entities.PaymentStatusRejected
+	if approve {
+		newStatus = entities.PaymentStatusApproved
+	}
+	response := &BulkReviewResponse{Action: input.Action, Total: len(results), Results: results}
+	var notify []entities.TryOutRegistration
+	for i := range response.Results {
+		item := &response.Results[i]
+		if item.Error != "" {
+			response.Failed++
+			continue
+		}
+		if !applied[item.RegistrationID] {
+			item.Error = "registration was reviewed by someone else"
+			response.Failed++
+			continue
+		}
+		item.Success = true
+		item.PaymentStatus = string(newStatus)
+		response.Succeeded++
+		notify = append(notify, byID[item.RegistrationID])
+	}
+
+	// Receipts and emails can take a while for hundreds of rows, so they don't hold up the response
+	if len(notify) > 0 {
+		go s.sendReviewNotifications(notify, approve, input.RejectionReason, requestID)
+	}
+
+	utils.LogSuccess("registrations", "bulk_review", "Bulk review applied", requestID, adminUserID, map[string]any{
+		"action":    input.Action,
+		"succeeded": response.Succeeded,
+		"failed":    response.Failed,
+	})
+
+	return response, nil
+}
+
+// sendReviewNotifications issues receipts for approvals and emails rejections after a bulk review
+func (s *registrationService) sendReviewNotifications(registrations []entities.TryOutRegistration, approve bool, reason string, requestID string) {
+	tryOutIDs := make(map[uint]bool)
+	for _, reg := range registrations {
+		tryOutIDs[reg.TryOutPackageID] = true
+		if approve {
+			if amountDue(reg) <= 0 || reg.BundlePurchaseID != nil {
+				continue
+			}
+			if _, err := s.receipts.IssueTryOutReceipt(reg.ID, requestID); err != nil {
+				utils.LogError("registrations", "bulk_review", "Failed to issue receipt: "+err.Error(), requestID, reg.UserID, map[string]any{
+					"registration_id": reg.ID,
+				})
+			}
+			continue
+		}
+
+		if reg.User.Email == "" {
+			continue
+		}
+		body := fmt.Sprintf(
+			"Hi %s, your payment proof for %s was rejected.",
+			reg.User.Username, reg.TryOutPackage.Name,
+		)
+		if reason != "" {
+			body += " Reason: " + reason + "."
+		}
+		body += " Please upload a new payment proof."
+		if err := utils.SendEmail(reg.User.Email, "Payment Rejected - "+reg.TryOutPackage.Name, body); err != nil {
+			utils.LogWarning("registrations", "bulk_review", "Failed to send rejection email: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+		}
+	}
+
+	// Rejected registrations free their seats
+	if !approve {
+		for tryOutID := range tryOutIDs {
+			s.promoteWaitlist(tryOutID, "bulk_review", requestID)
+		}
+	}
+}
+
+// promoteWaitlist hands free seats to waitlisted students. Failures are logged only; the caller's change already succeeded.
+func (s *registrationService) promoteWaitlist(tryOutID uint, action string, requestID string) {
+	if _, err := s.waitlists.PromoteTryOut(tryOutID, requestID); err != nil {
+		utils.LogError("registrations", action, "Failed to promote waitlist: "+err.Error(), requestID, 0, map[string]any{
+			"try_out_id": tryOutID,
+		})
+	}
+}
+
 func (s *registrationService) DeleteRegistration(registrationID uint, requestID string) error {
 	utils.LogInfo("registrations", "delete", "Admin deleting registration", requestID, 0, map[string]any{
 		"registration_id": registrationID,
 	})
 
-	_, err := s.repo.FindByID(registrationID)
+	registration, err := s.repo.FindByID(registrationID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("registration not found")
@@ -339,13 +636,271 @@ func (s *registrationService) DeleteRegistration(registrationID uint, requestID
 		return err
 	}
 
+	// Deleting would lose track of money already received
+	balance, err := s.ledger.RegistrationBalance(registrationID)
+	if err != nil {
+		return err
+	}
+	if balance != 0 {
+		return errors.New("registration has payments attached; refund it before deleting")
+	}
+
 	if err := s.repo.Delete(registrationID); err != nil {
 		utils.LogError("registrations", "delete", "Failed to delete registration: "+err.Error(), requestID, 0, nil)
 		return err
 	}
 
+	// Give the promo code usage back so the user can register again with it
+	if err := s.promos.Release(promos.TargetTryOut, registrationID); err != nil {
+		utils.LogError("registrations", "delete", "Failed to release promo code: "+err.Error(), requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+	}
+
+	s.promoteWaitlist(registration.TryOutPackageID, "delete", requestID)
+
 	utils.LogSuccess("registrations", "delete", "Registration deleted successfully", requestID, 0, map[string]any{
 		"registration_id": registrationID,
 	})
 	return nil
 }
+
+// ==========================================
+// Payment Deadlines
+// ==========================================
+
+// ProcessPaymentDeadlines sends reminders for registrations nearing their payment deadline
+// and expires the ones past it. It is safe to run repeatedly.
+func (s *registrationService) ProcessPaymentDeadlines(requestID string) (*DeadlineRunResponse, error) {
+	now := time.Now()
+	result := &DeadlineRunResponse{}
+
+	due, err := s.repo.FindDueForReminder(now)
+	if err != nil {
+		utils.LogError("registrations", "payment_deadlines", "Failed to fetch registrations to remind: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+	for _, reg := range due {
+		body := fmt.Sprintf(
+			"Hi %s, your registration for %s is still waiting for payment of Rp %s. Please pay and upload your payment proof before %s, otherwise the registration will expire.",
+			reg.User.Username, reg.TryOutPackage.Name, formatAmount(amountDue(reg)), reg.PaymentDueAt.Format("02 Jan 2006 15:04 MST"),
+		)
+		if err := utils.SendEmail(reg.User.Email, "Payment Reminder - "+reg.TryOutPackage.Name, body); err != nil {
+			utils.LogWarning("registrations", "payment_deadlines", "Failed to send reminder: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+			result.Failed++
+			continue
+		}
+		if err := s.repo.MarkReminderSent(reg.ID, now); err != nil {
+			return nil, err
+		}
+		result.RemindersSent++
+	}
+
+	overdue, err := s.repo.FindOverdueUnpaid(now)
+	if err != nil {
+		utils.LogError("registrations", "payment_deadlines", "Failed to fetch overdue registrations: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+	expiredTryOuts := make(map[uint]bool)
+	for _, reg := range overdue {
+		expired, err := s.repo.Expire(reg.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if !expired {
+			continue
+		}
+		result.Expired++
+		expiredTryOuts[reg.TryOutPackageID] = true
+
+		if err := s.promos.Release(promos.TargetTryOut, reg.ID); err != nil {
+			utils.LogError("registrations", "payment_deadlines", "Failed to release promo code: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+		}
+
+		body := fmt.Sprintf(
+			"Hi %s, your registration for %s has expired because no payment was received before the deadline. You can register again while registration is still open.",
+			reg.User.Username, reg.TryOutPackage.Name,
+		)
+		if err := utils.SendEmail(reg.User.Email, "Registration Expired - "+reg.TryOutPackage.Name, body); err != nil {
+			utils.LogWarning("registrations", "payment_deadlines", "Failed to send expiry notice: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+		}
+	}
+
+	// Expired registrations free their seats for the waitlist
+	for tryOutID := range expiredTryOuts {
+		s.promoteWaitlist(tryOutID, "payment_deadlines", requestID)
+	}
+
+	utils.LogSuccess("registrations", "payment_deadlines", "Processed payment deadlines", requestID, 0, map[string]any{
+		"reminders_sent": result.RemindersSent,
+		"expired":        result.Expired,
+		"failed":         result.Failed,
+	})
+	return result, nil
+}
+
+// amountDue is what the student still has to pay for a registration
+func amountDue(reg entities.TryOutRegistration) float64 {
+	if reg.PromoCodeID != nil || reg.OriginalPrice > 0 {
+		return reg.FinalAmount
+	}
+	return reg.TryOutPackage.Price
+}
+
+// formatAmount renders a rupiah amount with thousands separators, e.g. 150000 -> "150.000"
+func formatAmount(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String()
+}
+
+// ==========================================
+// Results Export
+// ==========================================
+
+var nonFileNameChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func (s *registrationService) ExportResults(tryOutID uint, filter ExportResultsFilter, requestID string) (*ResultsExport, error) {
+	utils.LogInfo("registrations", "export_results", "Exporting try out results", requestID, 0, map[string]any{
+		"try_out_id": tryOutID,
+		"school":     filter.School,
+		"kelas":      filter.Kelas,
+	})
+
+	tryOut, err := s.repo.FindTryOutByID(tryOutID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("try out not found")
+		}
+		return nil, err
+	}
+
+	subtests, err := s.repo.FindAllSubtests()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rank over every participant of the package, not only the filtered rows
+	rankedIDs, err := s.repo.FindRankedAttemptIDs(tryOutID)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[uint]int, len(rankedIDs))
+	for i, id := range rankedIDs {
+		ranks[id] = i + 1
+	}
+
+	f := excelize.NewFile()
+	sheet := "Results"
+	f.SetSheetName(f.GetSheetName(0), sheet)
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header := []any{"No", "Name", "Email", "Phone", "School", "Kelas", "Payment Status", "Registered At", "Attempt Status"}
+	for _, st := range subtests {
+		header = append(header, st.Code+" Correct", st.Code+" Wrong", st.Code+" Blank", st.Code+" Score")
+	}
+	header = append(header, "Total Score", "Rank")
+	if err := sw.SetRow("A1", header); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	rowNum := 1
+	err = s.repo.FindForExportInBatches(tryOutID, filter, exportBatchSize, func(batch []entities.TryOutRegistration) error {
+		for _, reg := range batch {
+			rowNum++
+
+			kelas := ""
+			if reg.User.Kelas != nil {
+				kelas = *reg.User.Kelas
+			}
+
+			row := []any{
+				rowNum - 1,
+				reg.User.Username,
+				reg.User.Email,
+				reg.User.NoTelp,
+				reg.User.School,
+				kelas,
+				string(reg.PaymentStatus),
+				reg.RegisteredAt.Format("2006-01-02 15:04"),
+			}
+
+			results := make(map[uint]entities.SubtestResult)
+			attemptStatus := "not_started"
+			if reg.Attempt != nil {
+				attemptStatus = string(reg.Attempt.Status)
+				for _, sr := range reg.Attempt.SubtestResults {
+					results[sr.SubtestID] = sr
+				}
+			}
+			row = append(row, attemptStatus)
+
+			for _, st := range subtests {
+				sr, ok := results[st.ID]
+				if !ok || sr.FinishedAt == nil {
+					row = append(row, nil, nil, nil, nil)
+					continue
+				}
+				var score any
+				if sr.FinalScore != nil {
+					score = *sr.FinalScore
+				}
+				row = append(row, sr.CorrectCount, sr.WrongCount, sr.UnansweredCount, score)
+			}
+
+			var total, rank any
+			if reg.Attempt != nil && reg.Attempt.TotalScore != nil {
+				total = *reg.Attempt.TotalScore
+				if r, ok := ranks[reg.Attempt.ID]; ok {
+					rank = r
+				}
+			}
+			row = append(row, total, rank)
+
+			cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+			if err := sw.SetRow(cell, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError("registrations", "export_results", "Failed to export results: "+err.Error(), requestID, 0, nil)
+		f.Close()
+		return nil, err
+	}
+
+	if err := sw.Flush(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	utils.LogSuccess("registrations", "export_results", "Try out results exported", requestID, 0, map[string]any{
+		"try_out_id": tryOutID,
+		"rows":       rowNum - 1,
+	})
+
+	name := strings.Trim(nonFileNameChars.ReplaceAllString(tryOut.Name, "-"), "-")
+	return &ResultsExport{
+		FileName: fmt.Sprintf("tryout-%d-%s-results.xlsx", tryOut.ID, strings.ToLower(name)),
+		File:     f,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package registrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/database/migrations"
+	"github.com/redukasquad/be-reduka/modules/ledger"
+	"github.com/redukasquad/be-reduka/modules/promos"
+	"github.com/redukasquad/be-reduka/modules/receipts"
+	"github.com/redukasquad/be-reduka/modules/waitlists"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+// StartPaymentDeadlineWorker runs the payment deadline job every interval in the background.
+// Serverless deployments call GET /cron/payment-deadlines from the platform scheduler instead.
+func StartPaymentDeadlineWorker(interval time.Duration) {
+	db := migrations.GetDB()
+	promoService := promos.NewService(promos.NewRepository(db))
+	waitlistService := waitlists.NewService(waitlists.NewRepository(db))
+	service := NewService(NewRepository(db), promoService, ledger.NewService(ledger.NewRepository(db), promoService, waitlistService), receipts.NewService(receipts.NewRepository(db)), waitlistService)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			requestID := uuid.New().String()
+			if _, err := service.ProcessPaymentDeadlines(requestID); err != nil {
+				utils.LogError("registrations", "payment_deadlines", "Scheduled run failed: "+err.Error(), requestID, 0, nil)
+			}
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// SCORE REPORT DTOs
+// ==========================================
+
+// ScoreReportFile is a generated PDF ready to be sent to the client
+type ScoreReportFile struct {
+	FileName string
+	Content  []byte
+}
+
+// ReportVerificationResponse is the public response when checking a verification code
+type ReportVerificationResponse struct {
+	Valid             bool      `json:"valid"`
+	VerificationCode  string    `json:"verificationCode"`
+	StudentName       string    `json:"studentName"`
+	TryOutName        string    `json:"tryOutName"`
+	TotalScore        float64   `json:"totalScore"`
+	Rank              *int      `json:"rank,omitempty"`
+	TotalParticipants *int      `json:"totalParticipants,omitempty"`
+	Percentile        *float64  `json:"percentile,omitempty"`
+	RankingFinal      bool      `json:"rankingFinal"`
+	IssuedAt          time.Time `json:"issuedAt"`
+}
+
+// targetComparison compares the attempt score with one of the student's target majors
+type targetComparison struct {
+	Priority     int
+	University   string
+	Major        string
+	PassingGrade float64
+	Passed       bool
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func ToReportVerificationResponse(r entities.ScoreReport) ReportVerificationResponse {
+	return ReportVerificationResponse{
+		Valid:             true,
+		VerificationCode:  r.VerificationCode,
+		StudentName:       r.StudentName,
+		TryOutName:        r.TryOutName,
+		TotalScore:        r.TotalScore,
+		Rank:              r.Rank,
+		TotalParticipants: r.TotalParticipants,
+		Percentile:        r.Percentile,
+		RankingFinal:      r.RankingFinal,
+		IssuedAt:          r.IssuedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	DownloadAttemptReportHandler(c *gin.Context)
+	VerifyReportHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+// ==========================================
+// Handlers
+// ==========================================
+
+func (h *handler) DownloadAttemptReportHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	userID := getUserID(c)
+	attemptIDStr := c.Param("attemptId")
+
+	attemptID, err := strconv.ParseUint(attemptIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid Attempt ID", "ID must be a valid number", nil))
+		return
+	}
+
+	file, err := h.service.GetAttemptReport(uint(attemptID), userID, requestID)
+	if err != nil {
+		switch err.Error() {
+		case "attempt not found":
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Attempt not found", err.Error(), nil))
+		case "you can only download your own report":
+			c.JSON(http.StatusForbidden, utils.BuildResponseFailed("Permission denied", err.Error(), nil))
+		case "attempt is not completed yet":
+			c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid state", err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to generate report", err.Error(), nil))
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	c.Data(http.StatusOK, "application/pdf", file.Content)
+}
+
+func (h *handler) VerifyReportHandler(c *gin.Context) {
+	requestID := getRequestID(c)
+	code := c.Param("code")
+
+	verification, err := h.service.VerifyReport(code, requestID)
+	if err != nil {
+		if err.Error() == "report not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Report not found", "verification code is not valid", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to verify report", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Report is valid", verification))
+}
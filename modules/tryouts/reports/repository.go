@@ -0,0 +1,129 @@
+package reports
+
+import (
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+type Repository interface {
+	// Attempts
+	FindAttemptByID(id uint) (entities.TryOutAttempt, error)
+	CountCompletedAttempts(tryOutID uint) (int64, error)
+	CountAttemptsRankedAbove(tryOutID uint, attempt entities.TryOutAttempt) (int64, error)
+	CountAttemptsScoredBelow(tryOutID uint, totalScore float64) (int64, error)
+
+	// Score reports
+	FindReportByAttemptID(attemptID uint) (entities.ScoreReport, error)
+	FindReportByCode(code string) (entities.ScoreReport, error)
+	CreateReport(report *entities.ScoreReport) error
+	UpdateReportRanking(report *entities.ScoreReport) error
+
+	// Targets
+	FindTargetsByUser(userID uint) ([]entities.UserTarget, error)
+
+	// Subtests
+	FindAllSubtests() ([]entities.Subtest, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Attempt Methods
+// ==========================================
+
+func (r *repository) FindAttemptByID(id uint) (entities.TryOutAttempt, error) {
+	var attempt entities.TryOutAttempt
+	err := r.db.Preload("Registration.TryOutPackage").
+		Preload("Registration.User").
+		Preload("SubtestResults.Subtest").
+		First(&attempt, id).Error
+	return attempt, err
+}
+
+func (r *repository) completedAttemptsQuery(tryOutID uint) *gorm.DB {
+	return r.db.Model(&entities.TryOutAttempt{}).
+		Joins("JOIN try_out_registrations ON try_out_registrations.id = try_out_attempts.registration_id").
+		Where("try_out_registrations.try_out_package_id = ?", tryOutID).
+		Where("try_out_attempts.status = ?", entities.AttemptStatusCompleted).
+		Where("try_out_attempts.total_score IS NOT NULL")
+}
+
+func (r *repository) CountCompletedAttempts(tryOutID uint) (int64, error) {
+	var count int64
+	err := r.completedAttemptsQuery(tryOutID).Count(&count).Error
+	return count, err
+}
+
+// CountAttemptsRankedAbove uses the same ordering as the leaderboard: higher score first, earlier finish breaks ties
+func (r *repository) CountAttemptsRankedAbove(tryOutID uint, attempt entities.TryOutAttempt) (int64, error) {
+	var count int64
+	err := r.completedAttemptsQuery(tryOutID).
+		Where("try_out_attempts.total_score > ? OR (try_out_attempts.total_score = ? AND try_out_attempts.finished_at < ?)",
+			*attempt.TotalScore, *attempt.TotalScore, attempt.FinishedAt).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CountAttemptsScoredBelow(tryOutID uint, totalScore float64) (int64, error) {
+	var count int64
+	err := r.completedAttemptsQuery(tryOutID).
+		Where("try_out_attempts.total_score < ?", totalScore).
+		Count(&count).Error
+	return count, err
+}
+
+// ==========================================
+// Score Report Methods
+// ==========================================
+
+func (r *repository) FindReportByAttemptID(attemptID uint) (entities.ScoreReport, error) {
+	var report entities.ScoreReport
+	err := r.db.Where("attempt_id = ?", attemptID).First(&report).Error
+	return report, err
+}
+
+func (r *repository) FindReportByCode(code string) (entities.ScoreReport, error) {
+	var report entities.ScoreReport
+	err := r.db.Where("verification_code = ?", code).First(&report).Error
+	return report, err
+}
+
+func (r *repository) CreateReport(report *entities.ScoreReport) error {
+	return r.db.Omit("Attempt").Create(report).Error
+}
+
+func (r *repository) UpdateReportRanking(report *entities.ScoreReport) error {
+	return r.db.Model(report).
+		Select("Rank", "TotalParticipants", "Percentile", "RankingFinal").
+		Updates(report).Error
+}
+
+// ==========================================
+// Target Methods
+// ==========================================
+
+func (r *repository) FindTargetsByUser(userID uint) ([]entities.UserTarget, error) {
+	var targets []entities.UserTarget
+	err := r.db.Where("user_id = ?", userID).
+		Preload("Major").
+		Preload("Major.University").
+		Order("priority asc").
+		Find(&targets).Error
+	return targets, err
+}
+
+// ==========================================
+// Subtest Methods
+// ==========================================
+
+func (r *repository) FindAllSubtests() ([]entities.Subtest, error) {
+	var subtests []entities.Subtest
+	err := r.db.Order("id ASC").Find(&subtests).Error
+	return subtests, err
+}
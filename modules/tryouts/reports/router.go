@@ -0,0 +1,18 @@
+package reports
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func ReportRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc) {
+	repo := NewRepository(migrations.GetDB())
+	service := NewService(repo)
+	handler := NewHandler(service)
+
+	// Student: download the PDF score report of a completed attempt
+	router.GET("/tryouts/attempts/:attemptId/report", requireAuth, handler.DownloadAttemptReportHandler)
+
+	// Public: verify a report by its verification code
+	router.GET("/tryouts/reports/verify/:code", handler.VerifyReportHandler)
+}
@@ -0,0 +1,377 @@
+package reports
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type reportService struct {
+	repo Repository
+}
+
+type Service interface {
+	GetAttemptReport(attemptID uint, userID uint, requestID string) (*ScoreReportFile, error)
+	VerifyReport(code string, requestID string) (*ReportVerificationResponse, error)
+}
+
+func NewService(repo Repository) Service {
+	return &reportService{repo: repo}
+}
+
+// ==========================================
+// Score Report
+// ==========================================
+
+func (s *reportService) GetAttemptReport(attemptID uint, userID uint, requestID string) (*ScoreReportFile, error) {
+	utils.LogInfo("reports", "get_attempt_report", "Generating score report", requestID, userID, map[string]any{
+		"attempt_id": attemptID,
+	})
+
+	attempt, err := s.repo.FindAttemptByID(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt not found")
+		}
+		return nil, err
+	}
+
+	if attempt.Registration.UserID != userID {
+		return nil, errors.New("you can only download your own report")
+	}
+
+	if attempt.Status != entities.AttemptStatusCompleted || attempt.TotalScore == nil {
+		return nil, errors.New("attempt is not completed yet")
+	}
+
+	report, err := s.findOrIssueReport(attempt)
+	if err != nil {
+		utils.LogError("reports", "get_attempt_report", "Failed to issue score report: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	subtests, err := s.repo.FindAllSubtests()
+	if err != nil {
+		return nil, err
+	}
+	var maxTotal float64
+	for _, st := range subtests {
+		maxTotal += st.MaxScore
+	}
+
+	var scorePercentage float64
+	if maxTotal > 0 {
+		scorePercentage = report.TotalScore / maxTotal * 100
+	}
+
+	// Passing grades are stored as 0-100, so targets are compared with the score percentage
+	targets, _ := s.repo.FindTargetsByUser(userID)
+	var comparisons []targetComparison
+	for _, t := range targets {
+		comparisons = append(comparisons, targetComparison{
+			Priority:     t.Priority,
+			University:   t.Major.University.Name,
+			Major:        t.Major.Name,
+			PassingGrade: t.Major.PassingGrade,
+			Passed:       scorePercentage >= t.Major.PassingGrade,
+		})
+	}
+
+	results := attempt.SubtestResults
+	sort.Slice(results, func(i, j int) bool { return results[i].SubtestID < results[j].SubtestID })
+
+	content, err := renderScoreReportPDF(report, attempt, results, comparisons, maxTotal, scorePercentage)
+	if err != nil {
+		utils.LogError("reports", "get_attempt_report", "Failed to render PDF: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	utils.LogSuccess("reports", "get_attempt_report", "Score report generated", requestID, userID, map[string]any{
+		"attempt_id":        attemptID,
+		"verification_code": report.VerificationCode,
+	})
+
+	return &ScoreReportFile{
+		FileName: fmt.Sprintf("score-report-%s.pdf", report.VerificationCode),
+		Content:  content,
+	}, nil
+}
+
+// findOrIssueReport returns the report of the attempt, issuing it on first download. Later
+// attempts can still change the ranking while the exam window is open, so rank and percentile
+// are only filled in, once and for good, after the window has closed.
+func (s *reportService) findOrIssueReport(attempt entities.TryOutAttempt) (entities.ScoreReport, error) {
+	report, err := s.repo.FindReportByAttemptID(attempt.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return report, err
+	}
+	issued := err == nil
+	final := rankingClosed(attempt.Registration.TryOutPackage, time.Now())
+
+	if issued {
+		if report.RankingFinal || !final {
+			return report, nil
+		}
+		if err := s.finalizeRanking(&report, attempt); err != nil {
+			return report, err
+		}
+		if err := s.repo.UpdateReportRanking(&report); err != nil {
+			return report, err
+		}
+		return report, nil
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return report, err
+	}
+
+	report = entities.ScoreReport{
+		AttemptID:        attempt.ID,
+		VerificationCode: code,
+		StudentName:      attempt.Registration.User.Username,
+		TryOutName:       attempt.Registration.TryOutPackage.Name,
+		TotalScore:       *attempt.TotalScore,
+		IssuedAt:         time.Now(),
+	}
+	if final {
+		if err := s.finalizeRanking(&report, attempt); err != nil {
+			return report, err
+		}
+	}
+
+	if err := s.repo.CreateReport(&report); err != nil {
+		// A concurrent request may have issued the report first
+		if existing, findErr := s.repo.FindReportByAttemptID(attempt.ID); findErr == nil {
+			return existing, nil
+		}
+		return report, err
+	}
+
+	return report, nil
+}
+
+// rankingClosed reports whether no more attempts can change the try out's ranking. Without an
+// exam window the ranking closes with registration.
+func rankingClosed(tryOut entities.TryOut, now time.Time) bool {
+	if tryOut.ExamEnd != nil {
+		return now.After(*tryOut.ExamEnd)
+	}
+	return now.After(tryOut.RegistrationEnd)
+}
+
+// finalizeRanking stores the attempt's rank among all completed attempts on the report
+func (s *reportService) finalizeRanking(report *entities.ScoreReport, attempt entities.TryOutAttempt) error {
+	rank, participants, percentile, err := s.rankAttempt(attempt)
+	if err != nil {
+		return err
+	}
+	report.Rank = &rank
+	report.TotalParticipants = &participants
+	report.Percentile = &percentile
+	report.RankingFinal = true
+	return nil
+}
+
+// rankAttempt places the attempt among the completed attempts of its try out
+func (s *reportService) rankAttempt(attempt entities.TryOutAttempt) (rank, participants int, percentile float64, err error) {
+	tryOutID := attempt.Registration.TryOutPackageID
+	total, err := s.repo.CountCompletedAttempts(tryOutID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	above, err := s.repo.CountAttemptsRankedAbove(tryOutID, attempt)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	below, err := s.repo.CountAttemptsScoredBelow(tryOutID, *attempt.TotalScore)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if total > 0 {
+		percentile = float64(below) / float64(total) * 100
+	}
+	return int(above) + 1, int(total), percentile, nil
+}
+
+func (s *reportService) VerifyReport(code string, requestID string) (*ReportVerificationResponse, error) {
+	utils.LogInfo("reports", "verify", "Verifying score report", requestID, 0, map[string]any{
+		"verification_code": code,
+	})
+
+	report, err := s.repo.FindReportByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+
+	response := ToReportVerificationResponse(report)
+	return &response, nil
+}
+
+// generateVerificationCode returns a code such as RDK-3F9A-C21B-77E0
+func generateVerificationCode() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	h := strings.ToUpper(hex.EncodeToString(b))
+	return fmt.Sprintf("RDK-%s-%s-%s", h[0:4], h[4:8], h[8:12]), nil
+}
+
+// ==========================================
+// PDF Rendering
+// ==========================================
+
+func renderScoreReportPDF(report entities.ScoreReport, attempt entities.TryOutAttempt, results []entities.SubtestResult, targets []targetComparison, maxTotal, scorePercentage float64) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Try Out Score Report", true)
+	pdf.SetAuthor("Reduka", true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	// Header
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Reduka", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(0, 7, "Official Try Out Score Report", "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	// Student & package
+	user := attempt.Registration.User
+	kelas := "-"
+	if user.Kelas != nil && *user.Kelas != "" {
+		kelas = *user.Kelas
+	}
+	finishedAt := "-"
+	if attempt.FinishedAt != nil {
+		finishedAt = attempt.FinishedAt.Format("02 January 2006 15:04")
+	}
+
+	info := [][2]string{
+		{"Student", report.StudentName},
+		{"Email", user.Email},
+		{"Class", kelas},
+		{"Try Out", report.TryOutName},
+		{"Completed at", finishedAt},
+	}
+	for _, row := range info {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Subtest breakdown
+	widths := []float64{20, 70, 20, 20, 20, 30}
+	headers := []string{"Code", "Subtest", "Correct", "Wrong", "Blank", "Score"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range headers {
+		pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, r := range results {
+		finalScore := 0.0
+		if r.FinalScore != nil {
+			finalScore = *r.FinalScore
+		}
+		pdf.CellFormat(widths[0], 7, r.Subtest.Code, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 7, tr(r.Subtest.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, fmt.Sprintf("%d", r.CorrectCount), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", r.WrongCount), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[4], 7, fmt.Sprintf("%d", r.UnansweredCount), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[5], 7, fmt.Sprintf("%.2f", finalScore), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3]+widths[4], 7, "Total", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(widths[5], 7, fmt.Sprintf("%.2f", report.TotalScore), "1", 1, "R", true, 0, "")
+	pdf.Ln(4)
+
+	// Ranking
+	pdf.SetFont("Helvetica", "", 10)
+	if report.RankingFinal {
+		pdf.CellFormat(0, 6, fmt.Sprintf("Rank: %d of %d participants", *report.Rank, *report.TotalParticipants), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 6, fmt.Sprintf("Percentile: %.2f (scored higher than %.2f%% of participants)", *report.Percentile, *report.Percentile), "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(0, 6, fmt.Sprintf("Score percentage: %.2f%% of maximum %.2f", scorePercentage, maxTotal), "", 1, "L", false, 0, "")
+	if !report.RankingFinal {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.MultiCell(0, 5, "Rank and percentile are not included while the try out is still open. Download the report again after it closes.", "", "L", false)
+	}
+	pdf.Ln(4)
+
+	// Target majors
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Target Majors", "", 1, "L", false, 0, "")
+	if len(targets) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 6, "No target majors selected.", "", 1, "L", false, 0, "")
+	} else {
+		tWidths := []float64{15, 65, 55, 25, 20}
+		tHeaders := []string{"#", "University", "Major", "Passing", "Status"}
+		pdf.SetFont("Helvetica", "B", 10)
+		for i, h := range tHeaders {
+			pdf.CellFormat(tWidths[i], 7, h, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 9)
+		for _, t := range targets {
+			status := "Below"
+			if t.Passed {
+				status = "Above"
+			}
+			pdf.CellFormat(tWidths[0], 7, fmt.Sprintf("%d", t.Priority), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(tWidths[1], 7, tr(truncate(t.University, 38)), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(tWidths[2], 7, tr(truncate(t.Major, 32)), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(tWidths[3], 7, fmt.Sprintf("%.2f", t.PassingGrade), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(tWidths[4], 7, status, "1", 1, "C", false, 0, "")
+		}
+	}
+	pdf.Ln(8)
+
+	// Verification
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, "Verification code: "+report.VerificationCode, "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	verifyPath := "/api/v1/tryouts/reports/verify/" + report.VerificationCode
+	if baseURL := strings.TrimRight(os.Getenv("API_BASE_URL"), "/"); baseURL != "" {
+		verifyPath = baseURL + verifyPath
+	}
+	pdf.CellFormat(0, 5, "Verify this document at "+verifyPath, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, "Issued at "+report.IssuedAt.Format("02 January 2006 15:04 MST"), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
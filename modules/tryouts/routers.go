@@ -7,11 +7,13 @@ import (
 	tryouts "github.com/redukasquad/be-reduka/modules/tryouts/index"
 	"github.com/redukasquad/be-reduka/modules/tryouts/questions"
 	"github.com/redukasquad/be-reduka/modules/tryouts/registrations"
+	"github.com/redukasquad/be-reduka/modules/tryouts/reports"
 )
 
 func TryOutsRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
 	tryouts.TryOutIndexRouter(router, requireAuth, requireAdminOrTutor)
 	questions.QuestionRouter(router, requireAuth, requireAdminOrTutor)
 	registrations.RegistrationRouter(router, requireAuth, requireAdminOrTutor)
-	attempts.AttemptRouter(router, requireAuth, requireAdminOrTutor)
+	attempts.AttemptRouter(router, requireAuth)
+	reports.ReportRouter(router, requireAuth)
 }
@@ -15,5 +15,6 @@ type ImageResponse struct {
 	ID        uint      `json:"id"`
 	URL       string    `json:"url"`
 	Fileid    string    `json:"fileId"`
+	UserID    *uint     `json:"userId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 }
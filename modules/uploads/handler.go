@@ -29,6 +29,23 @@ func getRequestID(c *gin.Context) string {
 	return requestID
 }
 
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
 func (h *handler) CreateImageHandler(c *gin.Context) {
 	requestID := getRequestID(c)
 
@@ -38,7 +55,7 @@ func (h *handler) CreateImageHandler(c *gin.Context) {
 		return
 	}
 
-	image, err := h.service.Create(input, requestID)
+	image, err := h.service.Create(input, getUserID(c), requestID)
 	if err != nil {
 		if err.Error() == "image with this URL already exists" {
 			c.JSON(http.StatusConflict, utils.BuildResponseFailed("Image already exists", err.Error(), nil))
@@ -13,7 +13,8 @@ type uploadService struct {
 }
 
 type Service interface {
-	Create(input CreateImageInput, requestID string) (*ImageResponse, error)
+	Create(input CreateImageInput, userID uint, requestID string) (*ImageResponse, error)
+	GetByURL(url string, requestID string) (*ImageResponse, error)
 	DeleteByURL(url string, requestID string) (string, error)
 }
 
@@ -21,8 +22,8 @@ func NewService(repo Repository) Service {
 	return &uploadService{repo: repo}
 }
 
-func (s *uploadService) Create(input CreateImageInput, requestID string) (*ImageResponse, error) {
-	utils.LogInfo("uploads", "create", "Attempting to create image record", requestID, 0, map[string]any{
+func (s *uploadService) Create(input CreateImageInput, userID uint, requestID string) (*ImageResponse, error) {
+	utils.LogInfo("uploads", "create", "Attempting to create image record", requestID, userID, map[string]any{
 		"url":    input.URL,
 		"fileId": input.Fileid,
 	})
@@ -30,7 +31,7 @@ func (s *uploadService) Create(input CreateImageInput, requestID string) (*Image
 	// Check if URL already exists
 	_, err := s.repo.FindByURL(input.URL)
 	if err == nil {
-		utils.LogWarning("uploads", "create", "Image with this URL already exists", requestID, 0, map[string]any{
+		utils.LogWarning("uploads", "create", "Image with this URL already exists", requestID, userID, map[string]any{
 			"url": input.URL,
 		})
 		return nil, errors.New("image with this URL already exists")
@@ -39,16 +40,17 @@ func (s *uploadService) Create(input CreateImageInput, requestID string) (*Image
 	image := &entities.Image{
 		URL:    input.URL,
 		Fileid: input.Fileid,
+		UserID: &userID,
 	}
 
 	if err := s.repo.Create(image); err != nil {
-		utils.LogError("uploads", "create", "Failed to create image: "+err.Error(), requestID, 0, map[string]any{
+		utils.LogError("uploads", "create", "Failed to create image: "+err.Error(), requestID, userID, map[string]any{
 			"url": input.URL,
 		})
 		return nil, err
 	}
 
-	utils.LogSuccess("uploads", "create", "Image created successfully", requestID, 0, map[string]any{
+	utils.LogSuccess("uploads", "create", "Image created successfully", requestID, userID, map[string]any{
 		"image_id": image.ID,
 		"url":      image.URL,
 	})
@@ -57,11 +59,34 @@ func (s *uploadService) Create(input CreateImageInput, requestID string) (*Image
 		ID:        image.ID,
 		URL:       image.URL,
 		Fileid:    image.Fileid,
+		UserID:    image.UserID,
 		CreatedAt: image.CreatedAt,
 	}
 	return response, nil
 }
 
+// GetByURL looks up a file registered through the uploads endpoint, e.g. to accept it as a form answer
+func (s *uploadService) GetByURL(url string, requestID string) (*ImageResponse, error) {
+	image, err := s.repo.FindByURL(url)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		utils.LogError("uploads", "get_by_url", "Failed to find image: "+err.Error(), requestID, 0, map[string]any{
+			"url": url,
+		})
+		return nil, err
+	}
+
+	return &ImageResponse{
+		ID:        image.ID,
+		URL:       image.URL,
+		Fileid:    image.Fileid,
+		UserID:    image.UserID,
+		CreatedAt: image.CreatedAt,
+	}, nil
+}
+
 func (s *uploadService) DeleteByURL(url string, requestID string) (string, error) {
 	utils.LogInfo("uploads", "delete", "Attempting to delete image by URL", requestID, 0, map[string]any{
 		"url": url,
@@ -5,10 +5,16 @@ type UpdateUserInput struct {
 	NoTelp       *string `json:"no_telp"`
 	JenisKelamin *bool   `json:"jenis_kelamin"`
 	Kelas        *string `json:"kelas" binding:"omitempty,oneof='Kelas 10' 'Kelas 11' 'Kelas 12' 'Gapyear (Alumni)'"`
+	School       *string `json:"school"`
 	ProfileImage *string `json:"profile_image"`
 }
 
+// SetVerifiedSchoolInput is used by admin to confirm a user's school; an empty school clears it
+type SetVerifiedSchoolInput struct {
+	School string `json:"school" binding:"max=150"`
+}
+
 // SetRoleInput is used by admin to set user roles
 type SetRoleInput struct {
-	Role string `json:"role" binding:"required,oneof=STUDENT TUTOR ADMIN"`
+	Role string `json:"role" binding:"required,oneof=STUDENT TUTOR ADMIN COORDINATOR"`
 }
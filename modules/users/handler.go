@@ -17,6 +17,7 @@ type Handler interface {
 	GetUserByIDHandler(c *gin.Context)
 	UpdateUserHandler(c *gin.Context)
 	SetRoleHandler(c *gin.Context)
+	SetVerifiedSchoolHandler(c *gin.Context)
 	DeleteUserHandler(c *gin.Context)
 }
 
@@ -105,6 +106,34 @@ func (h *handler) SetRoleHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.BuildResponseSuccess("User role updated", user))
 }
 
+// SetVerifiedSchoolHandler allows admin to confirm a user's school (ADMIN only)
+func (h *handler) SetVerifiedSchoolHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a number", nil))
+		return
+	}
+
+	var input SetVerifiedSchoolInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid input", err.Error(), nil))
+		return
+	}
+
+	user, err := h.service.SetVerifiedSchool(id, input.School)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("User not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to set verified school", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("User verified school updated", user))
+}
+
 func (h *handler) DeleteUserHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -21,6 +21,7 @@ func UserRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdm
 
 		// Admin only routes
 		users.PATCH("/:id/role", requireAdmin, userHandler.SetRoleHandler)
+		users.PATCH("/:id/verified-school", requireAdmin, userHandler.SetVerifiedSchoolHandler)
 		users.DELETE("/:id", requireAdmin, userHandler.DeleteUserHandler)
 	}
 }
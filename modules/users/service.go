@@ -2,6 +2,7 @@ package users
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/redukasquad/be-reduka/database/entities"
 	"gorm.io/gorm"
@@ -16,6 +17,7 @@ type Service interface {
 	GetByID(id int) (*entities.User, error)
 	Update(id int, input UpdateUserInput) (*entities.User, error)
 	SetRole(userID int, role string) (*entities.User, error)
+	SetVerifiedSchool(userID int, school string) (*entities.User, error)
 	Delete(id int) error
 }
 
@@ -61,6 +63,9 @@ func (s *userService) Update(id int, input UpdateUserInput) (*entities.User, err
 	if input.Kelas != nil {
 		user.Kelas = input.Kelas
 	}
+	if input.School != nil {
+		user.School = *input.School
+	}
 	if input.ProfileImage != nil {
 		user.ProfileImage = *input.ProfileImage
 	}
@@ -93,6 +98,26 @@ func (s *userService) SetRole(userID int, role string) (*entities.User, error) {
 	return &user, nil
 }
 
+// SetVerifiedSchool allows admin to confirm the school a user belongs to
+func (s *userService) SetVerifiedSchool(userID int, school string) (*entities.User, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	user.VerifiedSchool = strings.TrimSpace(school)
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	user.Password = "" // Don't expose password
+	return &user, nil
+}
+
 func (s *userService) Delete(id int) error {
 	_, err := s.repo.FindByID(id)
 	if err != nil {
@@ -0,0 +1,62 @@
+package waitlists
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+)
+
+// ==========================================
+// WAITLIST DTOs
+// ==========================================
+
+// AvailabilityResponse tells how many seats a try out or course has left
+type AvailabilityResponse struct {
+	Capacity   int   `json:"capacity"`  // 0 = unlimited
+	Taken      int64 `json:"taken"`     // approved and pending registrations
+	Remaining  *int  `json:"remaining"` // nil when unlimited
+	Waitlisted int64 `json:"waitlisted"`
+	IsFull     bool  `json:"isFull"`
+}
+
+// WaitlistEntryResponse is one registration waiting for a seat
+type WaitlistEntryResponse struct {
+	RegistrationID uint       `json:"registrationId"`
+	Type           string     `json:"type"` // tryout or course
+	PackageID      uint       `json:"packageId"`
+	PackageName    string     `json:"packageName"`
+	Position       int64      `json:"position"`
+	WaitlistedAt   *time.Time `json:"waitlistedAt"`
+	User           *UserBrief `json:"user,omitempty"`
+}
+
+// UserBrief is minimal user info for admin waitlist views
+type UserBrief struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ==========================================
+// Helper Functions
+// ==========================================
+
+func toAvailabilityResponse(capacity int, taken, waitlisted int64) AvailabilityResponse {
+	response := AvailabilityResponse{Capacity: capacity, Taken: taken, Waitlisted: waitlisted}
+	if capacity > 0 {
+		remaining := capacity - int(taken)
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.Remaining = &remaining
+		response.IsFull = remaining == 0 || waitlisted > 0
+	}
+	return response
+}
+
+func toUserBrief(user entities.User) *UserBrief {
+	if user.ID == 0 {
+		return nil
+	}
+	return &UserBrief{ID: user.ID, Username: user.Username, Email: user.Email}
+}
@@ -0,0 +1,150 @@
+package waitlists
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redukasquad/be-reduka/packages/utils"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	// Public endpoints
+	GetTryOutAvailabilityHandler(c *gin.Context)
+	GetCourseAvailabilityHandler(c *gin.Context)
+
+	// User endpoints
+	GetMyWaitlistHandler(c *gin.Context)
+
+	// Admin endpoints
+	GetTryOutWaitlistHandler(c *gin.Context)
+	GetCourseWaitlistHandler(c *gin.Context)
+}
+
+func NewHandler(service Service) Handler {
+	return &handler{service: service}
+}
+
+func getRequestID(c *gin.Context) string {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
+
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+
+	switch id := userID.(type) {
+	case int:
+		return uint(id)
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.BuildResponseFailed("Invalid ID", "ID must be a valid number", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ==========================================
+// Public Handlers
+// ==========================================
+
+func (h *handler) GetTryOutAvailabilityHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	availability, err := h.service.GetTryOutAvailability(id, getRequestID(c))
+	if err != nil {
+		if err.Error() == "try out not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Try out not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch availability", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Availability retrieved successfully", availability))
+}
+
+func (h *handler) GetCourseAvailabilityHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	availability, err := h.service.GetCourseAvailability(id, getRequestID(c))
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, utils.BuildResponseFailed("Course not found", err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch availability", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Availability retrieved successfully", availability))
+}
+
+// ==========================================
+// User Handlers
+// ==========================================
+
+func (h *handler) GetMyWaitlistHandler(c *gin.Context) {
+	entries, err := h.service.GetMyWaitlist(getUserID(c), getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch waitlist", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Waitlist retrieved successfully", entries))
+}
+
+// ==========================================
+// Admin Handlers
+// ==========================================
+
+func (h *handler) GetTryOutWaitlistHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.GetTryOutWaitlist(id, getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch waitlist", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Waitlist retrieved successfully", entries))
+}
+
+func (h *handler) GetCourseWaitlistHandler(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.GetCourseWaitlist(id, getRequestID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.BuildResponseFailed("Failed to fetch waitlist", err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.BuildResponseSuccess("Waitlist retrieved successfully", entries))
+}
@@ -0,0 +1,420 @@
+package waitlists
+
+import (
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// Seats are held by approved and pending registrations
+var (
+	tryOutSeatStatuses = []entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusPending}
+	courseSeatStatuses = []string{"approved", "pending"}
+)
+
+type Repository interface {
+	// Packages
+	FindTryOutByID(id uint) (entities.TryOut, error)
+	FindCourseByID(id uint) (entities.Course, error)
+
+	// Seats
+	CountTryOutSeats(tryOutID uint) (taken int64, waitlisted int64, err error)
+	CountCourseSeats(courseID uint) (taken int64, waitlisted int64, err error)
+
+	// Admission: moves a fresh registration to the waitlist when the package is full.
+	// Both lock the package row so concurrent registrations cannot overfill it. A seat a
+	// concurrent registration counted as taken may be free again by then, so admission also
+	// promotes the head of the waitlist and returns who was promoted.
+	AdmitTryOutRegistration(registrationID uint, at time.Time) (waitlisted bool, promoted []uint, err error)
+	AdmitCourseRegistration(registrationID uint, at time.Time) (waitlisted bool, promoted []uint, err error)
+
+	// Promotion: gives free seats to the head of the waitlist and returns the promoted registrations
+	PromoteTryOut(tryOutID uint, at time.Time) ([]uint, error)
+	PromoteCourse(courseID uint, at time.Time) ([]uint, error)
+	FindTryOutRegistrationsByIDs(ids []uint) ([]entities.TryOutRegistration, error)
+	FindCourseRegistrationsByIDs(ids []uint) ([]entities.CourseRegistration, error)
+
+	// Queues
+	FindTryOutWaitlist(tryOutID uint) ([]entities.TryOutRegistration, error)
+	FindCourseWaitlist(courseID uint) ([]entities.CourseRegistration, error)
+	FindTryOutWaitlistByUser(userID uint) ([]entities.TryOutRegistration, error)
+	FindCourseWaitlistByUser(userID uint) ([]entities.CourseRegistration, error)
+	CountTryOutAhead(registration entities.TryOutRegistration) (int64, error)
+	CountCourseAhead(registration entities.CourseRegistration) (int64, error)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ==========================================
+// Package Methods
+// ==========================================
+
+func (r *repository) FindTryOutByID(id uint) (entities.TryOut, error) {
+	var tryOut entities.TryOut
+	err := r.db.First(&tryOut, id).Error
+	return tryOut, err
+}
+
+func (r *repository) FindCourseByID(id uint) (entities.Course, error) {
+	var course entities.Course
+	err := r.db.First(&course, id).Error
+	return course, err
+}
+
+// ==========================================
+// Seat Methods
+// ==========================================
+
+func (r *repository) CountTryOutSeats(tryOutID uint) (int64, int64, error) {
+	return countTryOutSeats(r.db, tryOutID, 0)
+}
+
+func (r *repository) CountCourseSeats(courseID uint) (int64, int64, error) {
+	return countCourseSeats(r.db, courseID, 0)
+}
+
+// countTryOutSeats counts held seats and waitlisted registrations, leaving out excludeID
+func countTryOutSeats(db *gorm.DB, tryOutID uint, excludeID uint) (int64, int64, error) {
+	var taken, waitlisted int64
+	if err := db.Model(&entities.TryOutRegistration{}).
+		Where("try_out_package_id = ? AND payment_status IN ? AND id <> ?", tryOutID, tryOutSeatStatuses, excludeID).
+		Count(&taken).Error; err != nil {
+		return 0, 0, err
+	}
+	err := db.Model(&entities.TryOutRegistration{}).
+		Where("try_out_package_id = ? AND payment_status = ? AND id <> ?", tryOutID, entities.PaymentStatusWaitlisted, excludeID).
+		Count(&waitlisted).Error
+	return taken, waitlisted, err
+}
+
+// countCourseSeats counts held seats and waitlisted registrations, leaving out excludeID
+func countCourseSeats(db *gorm.DB, courseID uint, excludeID uint) (int64, int64, error) {
+	var taken, waitlisted int64
+	if err := db.Model(&entities.CourseRegistration{}).
+		Where("course_id = ? AND status IN ? AND id <> ?", courseID, courseSeatStatuses, excludeID).
+		Count(&taken).Error; err != nil {
+		return 0, 0, err
+	}
+	err := db.Model(&entities.CourseRegistration{}).
+		Where("course_id = ? AND status = ? AND id <> ?", courseID, "waitlisted", excludeID).
+		Count(&waitlisted).Error
+	return taken, waitlisted, err
+}
+
+// TryOutIsFull reports whether a registration for the locked try out has to join the waitlist.
+// Nobody jumps the queue, even if a seat is free for a moment. excludeID leaves the registration
+// itself out of the count.
+func TryOutIsFull(tx *gorm.DB, tryOut entities.TryOut, excludeID uint) (bool, error) {
+	if tryOut.Capacity <= 0 {
+		return false, nil
+	}
+	taken, queued, err := countTryOutSeats(tx, tryOut.ID, excludeID)
+	if err != nil {
+		return false, err
+	}
+	return taken >= int64(tryOut.Capacity) || queued > 0, nil
+}
+
+// CourseIsFull is TryOutIsFull for a locked course
+func CourseIsFull(tx *gorm.DB, course entities.Course, excludeID uint) (bool, error) {
+	if course.Capacity <= 0 {
+		return false, nil
+	}
+	taken, queued, err := countCourseSeats(tx, course.ID, excludeID)
+	if err != nil {
+		return false, err
+	}
+	return taken >= int64(course.Capacity) || queued > 0, nil
+}
+
+// ==========================================
+// Admission Methods
+// ==========================================
+
+func (r *repository) AdmitTryOutRegistration(registrationID uint, at time.Time) (bool, []uint, error) {
+	waitlisted := false
+	var promoted []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var registration entities.TryOutRegistration
+		if err := tx.First(&registration, registrationID).Error; err != nil {
+			return err
+		}
+		var tryOut entities.TryOut
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tryOut, registration.TryOutPackageID).Error; err != nil {
+			return err
+		}
+		if tryOut.Capacity <= 0 || (registration.PaymentStatus != entities.PaymentStatusPending && registration.PaymentStatus != entities.PaymentStatusApproved) {
+			return nil
+		}
+
+		full, err := TryOutIsFull(tx, tryOut, registration.ID)
+		if err != nil || !full {
+			return err
+		}
+
+		if err := tx.Model(&entities.TryOutRegistration{}).Where("id = ?", registration.ID).Updates(map[string]any{
+			"payment_status":           entities.PaymentStatusWaitlisted,
+			"waitlisted_at":            at,
+			"promoted_at":              nil,
+			"payment_due_at":           nil,
+			"payment_reminder_sent_at": nil,
+			"approved_by_user_id":      nil,
+			"approved_at":              nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		ids, err := promoteTryOut(tx, tryOut, at)
+		if err != nil {
+			return err
+		}
+		promoted, waitlisted = withoutID(ids, registration.ID)
+		return nil
+	})
+	return waitlisted, promoted, err
+}
+
+func (r *repository) AdmitCourseRegistration(registrationID uint, at time.Time) (bool, []uint, error) {
+	waitlisted := false
+	var promoted []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var registration entities.CourseRegistration
+		if err := tx.First(&registration, registrationID).Error; err != nil {
+			return err
+		}
+		var course entities.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, registration.CourseID).Error; err != nil {
+			return err
+		}
+		if course.Capacity <= 0 || registration.Status != "pending" {
+			return nil
+		}
+
+		full, err := CourseIsFull(tx, course, registration.ID)
+		if err != nil || !full {
+			return err
+		}
+
+		if err := tx.Model(&entities.CourseRegistration{}).Where("id = ?", registration.ID).Updates(map[string]any{
+			"status":        "waitlisted",
+			"waitlisted_at": at,
+			"promoted_at":   nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		ids, err := promoteCourse(tx, course, at)
+		if err != nil {
+			return err
+		}
+		promoted, waitlisted = withoutID(ids, registration.ID)
+		return nil
+	})
+	return waitlisted, promoted, err
+}
+
+// withoutID drops id from the promoted IDs and reports whether it stays on the waitlist
+func withoutID(promoted []uint, id uint) ([]uint, bool) {
+	others := make([]uint, 0, len(promoted))
+	for _, p := range promoted {
+		if p != id {
+			others = append(others, p)
+		}
+	}
+	return others, len(others) == len(promoted)
+}
+
+// ==========================================
+// Promotion Methods
+// ==========================================
+
+func (r *repository) PromoteTryOut(tryOutID uint, at time.Time) ([]uint, error) {
+	var promoted []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var tryOut entities.TryOut
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tryOut, tryOutID).Error; err != nil {
+			return err
+		}
+		var err error
+		promoted, err = promoteTryOut(tx, tryOut, at)
+		return err
+	})
+	return promoted, err
+}
+
+func (r *repository) PromoteCourse(courseID uint, at time.Time) ([]uint, error) {
+	var promoted []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var course entities.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, courseID).Error; err != nil {
+			return err
+		}
+		var err error
+		promoted, err = promoteCourse(tx, course, at)
+		return err
+	})
+	return promoted, err
+}
+
+// promoteTryOut fills the free seats of a locked try out from the head of its waitlist
+func promoteTryOut(tx *gorm.DB, tryOut entities.TryOut, at time.Time) ([]uint, error) {
+	query := tx.Where("try_out_package_id = ? AND payment_status = ?", tryOut.ID, entities.PaymentStatusWaitlisted).
+		Order("waitlisted_at ASC, id ASC")
+	if tryOut.Capacity > 0 {
+		taken, _, err := countTryOutSeats(tx, tryOut.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		free := tryOut.Capacity - int(taken)
+		if free <= 0 {
+			return nil, nil
+		}
+		query = query.Limit(free)
+	}
+
+	var registrations []entities.TryOutRegistration
+	if err := query.Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	var promoted []uint
+	for _, registration := range registrations {
+		updates := map[string]any{
+			"payment_status": entities.PaymentStatusPending,
+			"promoted_at":    at,
+		}
+		// Nothing to pay means the seat is confirmed right away, as on Register
+		if tryOut.IsFree || registration.BundlePurchaseID != nil || (registration.PromoCodeID != nil && registration.FinalAmount == 0) {
+			updates["payment_status"] = entities.PaymentStatusApproved
+			updates["approved_at"] = at
+		} else if tryOut.PaymentDeadlineHours > 0 {
+			updates["payment_due_at"] = at.Add(time.Duration(tryOut.PaymentDeadlineHours) * time.Hour)
+		}
+		if err := tx.Model(&entities.TryOutRegistration{}).Where("id = ?", registration.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, registration.ID)
+	}
+	return promoted, nil
+}
+
+// promoteCourse fills the free seats of a locked course from the head of its waitlist
+func promoteCourse(tx *gorm.DB, course entities.Course, at time.Time) ([]uint, error) {
+	query := tx.Where("course_id = ? AND status = ?", course.ID, "waitlisted").
+		Order("waitlisted_at ASC, id ASC")
+	if course.Capacity > 0 {
+		taken, _, err := countCourseSeats(tx, course.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		free := course.Capacity - int(taken)
+		if free <= 0 {
+			return nil, nil
+		}
+		query = query.Limit(free)
+	}
+
+	var registrations []entities.CourseRegistration
+	if err := query.Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	var promoted []uint
+	for _, registration := range registrations {
+		status := "pending"
+		// A bundle already paid for the seat
+		if registration.BundlePurchaseID != nil {
+			status = "approved"
+		}
+		if err := tx.Model(&entities.CourseRegistration{}).Where("id = ?", registration.ID).Updates(map[string]any{
+			"status":      status,
+			"promoted_at": at,
+		}).Error; err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, registration.ID)
+	}
+	return promoted, nil
+}
+
+func (r *repository) FindTryOutRegistrationsByIDs(ids []uint) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	if len(ids) == 0 {
+		return registrations, nil
+	}
+	err := r.db.Preload("User").Preload("TryOutPackage").Where("id IN ?", ids).Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindCourseRegistrationsByIDs(ids []uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	if len(ids) == 0 {
+		return registrations, nil
+	}
+	err := r.db.Preload("User").Preload("Course").Where("id IN ?", ids).Find(&registrations).Error
+	return registrations, err
+}
+
+// ==========================================
+// Queue Methods
+// ==========================================
+
+func (r *repository) FindTryOutWaitlist(tryOutID uint) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	err := r.db.Preload("User").Preload("TryOutPackage").
+		Where("try_out_package_id = ? AND payment_status = ?", tryOutID, entities.PaymentStatusWaitlisted).
+		Order("waitlisted_at ASC, id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindCourseWaitlist(courseID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Preload("User").Preload("Course").
+		Where("course_id = ? AND status = ?", courseID, "waitlisted").
+		Order("waitlisted_at ASC, id ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindTryOutWaitlistByUser(userID uint) ([]entities.TryOutRegistration, error) {
+	var registrations []entities.TryOutRegistration
+	err := r.db.Preload("TryOutPackage").
+		Where("user_id = ? AND payment_status = ?", userID, entities.PaymentStatusWaitlisted).
+		Order("waitlisted_at ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) FindCourseWaitlistByUser(userID uint) ([]entities.CourseRegistration, error) {
+	var registrations []entities.CourseRegistration
+	err := r.db.Preload("Course").
+		Where("user_id = ? AND status = ?", userID, "waitlisted").
+		Order("waitlisted_at ASC").
+		Find(&registrations).Error
+	return registrations, err
+}
+
+func (r *repository) CountTryOutAhead(registration entities.TryOutRegistration) (int64, error) {
+	var ahead int64
+	err := r.db.Model(&entities.TryOutRegistration{}).
+		Where("try_out_package_id = ? AND payment_status = ?", registration.TryOutPackageID, entities.PaymentStatusWaitlisted).
+		Where("waitlisted_at < ? OR (waitlisted_at = ? AND id < ?)", registration.WaitlistedAt, registration.WaitlistedAt, registration.ID).
+		Count(&ahead).Error
+	return ahead, err
+}
+
+func (r *repository) CountCourseAhead(registration entities.CourseRegistration) (int64, error) {
+	var ahead int64
+	err := r.db.Model(&entities.CourseRegistration{}).
+		Where("course_id = ? AND status = ?", registration.CourseID, "waitlisted").
+		Where("waitlisted_at < ? OR (waitlisted_at = ? AND id < ?)", registration.WaitlistedAt, registration.WaitlistedAt, registration.ID).
+		Count(&ahead).Error
+	return ahead, err
+}
@@ -0,0 +1,23 @@
+package waitlists
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redukasquad/be-reduka/database/migrations"
+)
+
+func WaitlistRouter(router *gin.RouterGroup, requireAuth gin.HandlerFunc, requireAdmin gin.HandlerFunc, requireAdminOrTutor gin.HandlerFunc) {
+	repo := NewRepository(migrations.GetDB())
+	service := NewService(repo)
+	handler := NewHandler(service)
+
+	// Public: seats left before new registrations join the waitlist
+	router.GET("/tryouts/:id/availability", handler.GetTryOutAvailabilityHandler)
+	router.GET("/courses/:id/availability", handler.GetCourseAvailabilityHandler)
+
+	// User: my waitlist positions
+	router.GET("/users/me/waitlist", requireAuth, handler.GetMyWaitlistHandler)
+
+	// Admin / tutor: the queue, in promotion order
+	router.GET("/tryouts/:id/waitlist", requireAuth, requireAdmin, handler.GetTryOutWaitlistHandler)
+	router.GET("/courses/:id/waitlist", requireAuth, requireAdminOrTutor, handler.GetCourseWaitlistHandler)
+}
@@ -0,0 +1,306 @@
+package waitlists
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redukasquad/be-reduka/database/entities"
+	"github.com/redukasquad/be-reduka/packages/utils"
+	"gorm.io/gorm"
+)
+
+type waitlistService struct {
+	repo Repository
+}
+
+type Service interface {
+	// Admission and promotion, called by the registration flows
+	AdmitTryOutRegistration(registrationID uint, requestID string) (bool, error)
+	AdmitCourseRegistration(registrationID uint, requestID string) (bool, error)
+	PromoteTryOut(tryOutID uint, requestID string) (int, error)
+	PromoteCourse(courseID uint, requestID string) (int, error)
+
+	// Public
+	GetTryOutAvailability(tryOutID uint, requestID string) (*AvailabilityResponse, error)
+	GetCourseAvailability(courseID uint, requestID string) (*AvailabilityResponse, error)
+
+	// User
+	GetMyWaitlist(userID uint, requestID string) ([]WaitlistEntryResponse, error)
+
+	// Admin
+	GetTryOutWaitlist(tryOutID uint, requestID string) ([]WaitlistEntryResponse, error)
+	GetCourseWaitlist(courseID uint, requestID string) ([]WaitlistEntryResponse, error)
+}
+
+func NewService(repo Repository) Service {
+	return &waitlistService{repo: repo}
+}
+
+// ==========================================
+// Admission & Promotion
+// ==========================================
+
+func (s *waitlistService) AdmitTryOutRegistration(registrationID uint, requestID string) (bool, error) {
+	waitlisted, promoted, err := s.repo.AdmitTryOutRegistration(registrationID, time.Now())
+	if err != nil {
+		utils.LogError("waitlists", "admit_tryout", "Failed to check capacity: "+err.Error(), requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+		return false, err
+	}
+	if waitlisted {
+		utils.LogInfo("waitlists", "admit_tryout", "Try out is full, registration waitlisted", requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+	}
+	if len(promoted) > 0 {
+		if err := s.notifyTryOutPromotions(promoted, requestID); err != nil {
+			utils.LogError("waitlists", "admit_tryout", "Failed to notify promoted registrations: "+err.Error(), requestID, 0, nil)
+		}
+	}
+	return waitlisted, nil
+}
+
+func (s *waitlistService) AdmitCourseRegistration(registrationID uint, requestID string) (bool, error) {
+	waitlisted, promoted, err := s.repo.AdmitCourseRegistration(registrationID, time.Now())
+	if err != nil {
+		utils.LogError("waitlists", "admit_course", "Failed to check capacity: "+err.Error(), requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+		return false, err
+	}
+	if waitlisted {
+		utils.LogInfo("waitlists", "admit_course", "Course is full, registration waitlisted", requestID, 0, map[string]any{
+			"registration_id": registrationID,
+		})
+	}
+	if len(promoted) > 0 {
+		if err := s.notifyCoursePromotions(promoted, requestID); err != nil {
+			utils.LogError("waitlists", "admit_course", "Failed to notify promoted registrations: "+err.Error(), requestID, 0, nil)
+		}
+	}
+	return waitlisted, nil
+}
+
+func (s *waitlistService) PromoteTryOut(tryOutID uint, requestID string) (int, error) {
+	ids, err := s.repo.PromoteTryOut(tryOutID, time.Now())
+	if err != nil {
+		utils.LogError("waitlists", "promote_tryout", "Failed to promote waitlist: "+err.Error(), requestID, 0, map[string]any{
+			"try_out_id": tryOutID,
+		})
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := s.notifyTryOutPromotions(ids, requestID); err != nil {
+		return len(ids), err
+	}
+
+	utils.LogSuccess("waitlists", "promote_tryout", "Promoted waitlisted registrations", requestID, 0, map[string]any{
+		"try_out_id": tryOutID,
+		"promoted":   len(ids),
+	})
+	return len(ids), nil
+}
+
+// notifyTryOutPromotions emails the students whose registrations just got a seat
+func (s *waitlistService) notifyTryOutPromotions(ids []uint, requestID string) error {
+	registrations, err := s.repo.FindTryOutRegistrationsByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, reg := range registrations {
+		body := fmt.Sprintf("Hi %s, a seat has opened up in %s and it is now yours.", reg.User.Username, reg.TryOutPackage.Name)
+		if reg.PaymentStatus == entities.PaymentStatusApproved {
+			body += " Your registration is confirmed."
+		} else {
+			body += " Please complete your payment and upload the payment proof"
+			if reg.PaymentDueAt != nil {
+				body += " before " + reg.PaymentDueAt.Format("02 Jan 2006 15:04 MST") + ", otherwise the seat goes to the next student on the waitlist"
+			}
+			body += "."
+		}
+		if err := utils.SendEmail(reg.User.Email, "A Seat Is Available - "+reg.TryOutPackage.Name, body); err != nil {
+			utils.LogWarning("waitlists", "promote_tryout", "Failed to send promotion email: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *waitlistService) PromoteCourse(courseID uint, requestID string) (int, error) {
+	ids, err := s.repo.PromoteCourse(courseID, time.Now())
+	if err != nil {
+		utils.LogError("waitlists", "promote_course", "Failed to promote waitlist: "+err.Error(), requestID, 0, map[string]any{
+			"course_id": courseID,
+		})
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := s.notifyCoursePromotions(ids, requestID); err != nil {
+		return len(ids), err
+	}
+
+	utils.LogSuccess("waitlists", "promote_course", "Promoted waitlisted registrations", requestID, 0, map[string]any{
+		"course_id": courseID,
+		"promoted":  len(ids),
+	})
+	return len(ids), nil
+}
+
+// notifyCoursePromotions emails the students whose registrations just got a seat
+func (s *waitlistService) notifyCoursePromotions(ids []uint, requestID string) error {
+	registrations, err := s.repo.FindCourseRegistrationsByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, reg := range registrations {
+		body := fmt.Sprintf(
+			"Hi %s, a seat has opened up in %s and it is now yours. Your registration is waiting for review by our team.",
+			reg.User.Username, reg.Course.NameCourse,
+		)
+		if err := utils.SendEmail(reg.User.Email, "A Seat Is Available - "+reg.Course.NameCourse, body); err != nil {
+			utils.LogWarning("waitlists", "promote_course", "Failed to send promotion email: "+err.Error(), requestID, reg.UserID, map[string]any{
+				"registration_id": reg.ID,
+			})
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// Availability
+// ==========================================
+
+func (s *waitlistService) GetTryOutAvailability(tryOutID uint, requestID string) (*AvailabilityResponse, error) {
+	tryOut, err := s.repo.FindTryOutByID(tryOutID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("try out not found")
+		}
+		return nil, err
+	}
+
+	taken, waitlisted, err := s.repo.CountTryOutSeats(tryOutID)
+	if err != nil {
+		utils.LogError("waitlists", "tryout_availability", "Failed to count seats: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+	response := toAvailabilityResponse(tryOut.Capacity, taken, waitlisted)
+	return &response, nil
+}
+
+func (s *waitlistService) GetCourseAvailability(courseID uint, requestID string) (*AvailabilityResponse, error) {
+	course, err := s.repo.FindCourseByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	taken, waitlisted, err := s.repo.CountCourseSeats(courseID)
+	if err != nil {
+		utils.LogError("waitlists", "course_availability", "Failed to count seats: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+	response := toAvailabilityResponse(course.Capacity, taken, waitlisted)
+	return &response, nil
+}
+
+// ==========================================
+// Queues
+// ==========================================
+
+func (s *waitlistService) GetMyWaitlist(userID uint, requestID string) ([]WaitlistEntryResponse, error) {
+	tryOutRegs, err := s.repo.FindTryOutWaitlistByUser(userID)
+	if err != nil {
+		utils.LogError("waitlists", "get_my_waitlist", "Failed to fetch waitlist: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+	courseRegs, err := s.repo.FindCourseWaitlistByUser(userID)
+	if err != nil {
+		utils.LogError("waitlists", "get_my_waitlist", "Failed to fetch waitlist: "+err.Error(), requestID, userID, nil)
+		return nil, err
+	}
+
+	entries := make([]WaitlistEntryResponse, 0, len(tryOutRegs)+len(courseRegs))
+	for _, reg := range tryOutRegs {
+		ahead, err := s.repo.CountTryOutAhead(reg)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, WaitlistEntryResponse{
+			RegistrationID: reg.ID,
+			Type:           "tryout",
+			PackageID:      reg.TryOutPackageID,
+			PackageName:    reg.TryOutPackage.Name,
+			Position:       ahead + 1,
+			WaitlistedAt:   reg.WaitlistedAt,
+		})
+	}
+	for _, reg := range courseRegs {
+		ahead, err := s.repo.CountCourseAhead(reg)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, WaitlistEntryResponse{
+			RegistrationID: reg.ID,
+			Type:           "course",
+			PackageID:      reg.CourseID,
+			PackageName:    reg.Course.NameCourse,
+			Position:       ahead + 1,
+			WaitlistedAt:   reg.WaitlistedAt,
+		})
+	}
+	return entries, nil
+}
+
+func (s *waitlistService) GetTryOutWaitlist(tryOutID uint, requestID string) ([]WaitlistEntryResponse, error) {
+	registrations, err := s.repo.FindTryOutWaitlist(tryOutID)
+	if err != nil {
+		utils.LogError("waitlists", "get_tryout_waitlist", "Failed to fetch waitlist: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	entries := make([]WaitlistEntryResponse, 0, len(registrations))
+	for i, reg := range registrations {
+		entries = append(entries, WaitlistEntryResponse{
+			RegistrationID: reg.ID,
+			Type:           "tryout",
+			PackageID:      reg.TryOutPackageID,
+			PackageName:    reg.TryOutPackage.Name,
+			Position:       int64(i + 1),
+			WaitlistedAt:   reg.WaitlistedAt,
+			User:           toUserBrief(reg.User),
+		})
+	}
+	return entries, nil
+}
+
+func (s *waitlistService) GetCourseWaitlist(courseID uint, requestID string) ([]WaitlistEntryResponse, error) {
+	registrations, err := s.repo.FindCourseWaitlist(courseID)
+	if err != nil {
+		utils.LogError("waitlists", "get_course_waitlist", "Failed to fetch waitlist: "+err.Error(), requestID, 0, nil)
+		return nil, err
+	}
+
+	entries := make([]WaitlistEntryResponse, 0, len(registrations))
+	for i, reg := range registrations {
+		entries = append(entries, WaitlistEntryResponse{
+			RegistrationID: reg.ID,
+			Type:           "course",
+			PackageID:      reg.CourseID,
+			PackageName:    reg.Course.NameCourse,
+			Position:       int64(i + 1),
+			WaitlistedAt:   reg.WaitlistedAt,
+			User:           toUserBrief(reg.User),
+		})
+	}
+	return entries, nil
+}
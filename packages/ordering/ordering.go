@@ -0,0 +1,114 @@
+package ordering
+
+import "gorm.io/gorm"
+
+// Slot is one row of an ordered list: its id and the position currently stored for it
+type Slot struct {
+	ID       uint
+	Position int
+}
+
+// List is a set of sibling rows numbered 1..n in one column under a unique index, e.g. the
+// lessons of a class. Callers lock the parent row before touching the list.
+type List struct {
+	Model  any
+	Column string
+	Scope  string
+	Args   []any
+}
+
+// Load returns the slots of the list in their stored order
+func (l List) Load(tx *gorm.DB) ([]Slot, error) {
+	var slots []Slot
+	err := tx.Model(l.Model).Select("id", l.Column+" AS position").Where(l.Scope, l.Args...).
+		Order(l.Column + " ASC, id ASC").Scan(&slots).Error
+	return slots, err
+}
+
+// Resequence numbers the rows 1..n in the given order. Rows that move are parked on negative
+// numbers first, so no statement ever collides with the unique order index.
+func (l List) Resequence(tx *gorm.DB, ordered []Slot) error {
+	moved := false
+	for i, slot := range ordered {
+		if slot.Position == i+1 {
+			continue
+		}
+		moved = true
+		if err := tx.Model(l.Model).Where("id = ?", slot.ID).Update(l.Column, -(i + 1)).Error; err != nil {
+			return err
+		}
+	}
+	if !moved {
+		return nil
+	}
+	return tx.Model(l.Model).Where(l.Scope, l.Args...).Where(l.Column+" < 0").
+		Update(l.Column, gorm.Expr("-"+l.Column)).Error
+}
+
+// Next is the position just past the last slot, where a new row can be inserted safely
+func Next(slots []Slot) int {
+	if len(slots) == 0 {
+		return 1
+	}
+	return slots[len(slots)-1].Position + 1
+}
+
+// InsertAt places slot at the 1-based position, clamped to the list
+func InsertAt(slots []Slot, slot Slot, position int) []Slot {
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(slots) {
+		index = len(slots)
+	}
+	ordered := make([]Slot, 0, len(slots)+1)
+	ordered = append(ordered, slots[:index]...)
+	ordered = append(ordered, slot)
+	return append(ordered, slots[index:]...)
+}
+
+// Move takes the slot with the given id out of the list and puts it back at position
+func Move(slots []Slot, id uint, position int) []Slot {
+	var current Slot
+	others := make([]Slot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.ID == id {
+			current = slot
+		} else {
+			others = append(others, slot)
+		}
+	}
+	return InsertAt(others, current, position)
+}
+
+// Arrange orders the slots as listed in ids; ok is false unless ids names every slot once
+func Arrange(slots []Slot, ids []uint) (ordered []Slot, ok bool) {
+	if len(ids) != len(slots) {
+		return nil, false
+	}
+	byID := make(map[uint]Slot, len(slots))
+	for _, slot := range slots {
+		byID[slot.ID] = slot
+	}
+	ordered = make([]Slot, 0, len(ids))
+	for _, id := range ids {
+		slot, found := byID[id]
+		if !found {
+			return nil, false
+		}
+		delete(byID, id)
+		ordered = append(ordered, slot)
+	}
+	return ordered, true
+}
+
+// PositionOf is the 1-based position of id in the list, or 0 when it is missing
+func PositionOf(slots []Slot, id uint) int {
+	for i, slot := range slots {
+		if slot.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
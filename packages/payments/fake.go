@@ -0,0 +1,85 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// FakeSignatureHeader carries the HMAC-SHA256 of the raw webhook body for the fake provider
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// fakeProvider is a local provider for development and testing. It never calls out to the
+// network; payments are settled by posting a signed callback to the webhook endpoint.
+type fakeProvider struct {
+	secret string
+}
+
+// ErrMissingFakeSecret is returned when the fake provider is selected without a webhook secret.
+// A built-in default would let anyone who reads the source sign settlements.
+var ErrMissingFakeSecret = errors.New("fake payment provider requires FAKE_PAYMENT_SECRET")
+
+// NewFakeProvider creates the local fake provider; it refuses to start without a secret
+func NewFakeProvider(secret string) (Provider, error) {
+	if secret == "" {
+		return nil, ErrMissingFakeSecret
+	}
+	return &fakeProvider{secret: secret}, nil
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) CreateInvoice(req InvoiceRequest) (*Invoice, error) {
+	return &Invoice{
+		ExternalID: "fake-" + req.OrderID,
+		PaymentURL: "https://fake-payments.local/pay/" + req.OrderID,
+	}, nil
+}
+
+// FakeWebhookPayload is the callback body understood by the fake provider
+type FakeWebhookPayload struct {
+	OrderID       string        `json:"orderId"`
+	TransactionID string        `json:"transactionId"`
+	Status        InvoiceStatus `json:"status"`
+	Amount        float64       `json:"amount"`
+	PaymentMethod string        `json:"paymentMethod"`
+}
+
+// SignFakeWebhook returns the signature header value for a fake webhook body
+func SignFakeWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *fakeProvider) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	expected := SignFakeWebhook(p.secret, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(FakeSignatureHeader))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload FakeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	switch payload.Status {
+	case InvoiceStatusPending, InvoiceStatusPaid, InvoiceStatusFailed, InvoiceStatusExpired:
+	default:
+		return nil, fmt.Errorf("unknown payment status %q", payload.Status)
+	}
+
+	return &WebhookEvent{
+		OrderID:       payload.OrderID,
+		TransactionID: payload.TransactionID,
+		Status:        payload.Status,
+		Amount:        payload.Amount,
+		PaymentMethod: payload.PaymentMethod,
+	}, nil
+}
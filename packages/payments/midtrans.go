@@ -0,0 +1,184 @@
+package payments
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	midtransSandboxURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransProductionURL = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+type midtransProvider struct {
+	serverKey string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewMidtransProvider creates a provider backed by Midtrans Snap
+func NewMidtransProvider(serverKey string, production bool) Provider {
+	baseURL := midtransSandboxURL
+	if production {
+		baseURL = midtransProductionURL
+	}
+	return &midtransProvider{
+		serverKey: serverKey,
+		baseURL:   baseURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *midtransProvider) Name() string {
+	return "midtrans"
+}
+
+type midtransTransactionRequest struct {
+	TransactionDetails struct {
+		OrderID     string `json:"order_id"`
+		GrossAmount int64  `json:"gross_amount"`
+	} `json:"transaction_details"`
+	ItemDetails []midtransItem `json:"item_details"`
+	Customer    struct {
+		FirstName string `json:"first_name"`
+		Email     string `json:"email"`
+		Phone     string `json:"phone,omitempty"`
+	} `json:"customer_details"`
+}
+
+type midtransItem struct {
+	ID       string `json:"id"`
+	Price    int64  `json:"price"`
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
+}
+
+type midtransTransactionResponse struct {
+	Token         string   `json:"token"`
+	RedirectURL   string   `json:"redirect_url"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+func (p *midtransProvider) CreateInvoice(req InvoiceRequest) (*Invoice, error) {
+	if p.serverKey == "" {
+		return nil, fmt.Errorf("MIDTRANS_SERVER_KEY is not set")
+	}
+
+	// Midtrans only accepts whole rupiah amounts
+	amount := int64(math.Round(req.Amount))
+
+	var body midtransTransactionRequest
+	body.TransactionDetails.OrderID = req.OrderID
+	body.TransactionDetails.GrossAmount = amount
+	body.ItemDetails = []midtransItem{{ID: req.OrderID, Price: amount, Quantity: 1, Name: truncateName(req.ItemName)}}
+	body.Customer.FirstName = req.CustomerName
+	body.Customer.Email = req.CustomerEmail
+	body.Customer.Phone = req.CustomerPhone
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", p.baseURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.SetBasicAuth(p.serverKey, "")
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result midtransTransactionResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("Midtrans error: status %d", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || result.Token == "" {
+		return nil, fmt.Errorf("Midtrans error: status %d %v", resp.StatusCode, result.ErrorMessages)
+	}
+
+	return &Invoice{
+		ExternalID: result.Token,
+		PaymentURL: result.RedirectURL,
+	}, nil
+}
+
+type midtransNotification struct {
+	OrderID           string `json:"order_id"`
+	TransactionID     string `json:"transaction_id"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	PaymentType       string `json:"payment_type"`
+}
+
+// ParseWebhook verifies signature_key = SHA512(order_id + status_code + gross_amount + server_key)
+func (p *midtransProvider) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	var n midtransNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+
+	sum := sha512.Sum512([]byte(n.OrderID + n.StatusCode + n.GrossAmount + p.serverKey))
+	expected := hex.EncodeToString(sum[:])
+	if p.serverKey == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(n.SignatureKey)) != 1 {
+		return nil, ErrInvalidSignature
+	}
+
+	amount, err := strconv.ParseFloat(n.GrossAmount, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gross_amount: %w", err)
+	}
+
+	status := InvoiceStatusPending
+	switch n.TransactionStatus {
+	case "settlement":
+		status = InvoiceStatusPaid
+	case "capture":
+		if n.FraudStatus == "" || n.FraudStatus == "accept" {
+			status = InvoiceStatusPaid
+		}
+	case "deny", "cancel", "failure":
+		status = InvoiceStatusFailed
+	case "expire":
+		status = InvoiceStatusExpired
+	}
+
+	return &WebhookEvent{
+		OrderID:       n.OrderID,
+		TransactionID: n.TransactionID,
+		Status:        status,
+		Amount:        amount,
+		PaymentMethod: n.PaymentType,
+	}, nil
+}
+
+func truncateName(name string) string {
+	// Midtrans rejects item names longer than 50 characters
+	r := []rune(name)
+	if len(r) > 50 {
+		return string(r[:50])
+	}
+	return name
+}
@@ -0,0 +1,73 @@
+package payments
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// InvoiceStatus is the normalized status of a gateway invoice, independent of the provider
+type InvoiceStatus string
+
+const (
+	InvoiceStatusPending InvoiceStatus = "pending"
+	InvoiceStatusPaid    InvoiceStatus = "paid"
+	InvoiceStatusFailed  InvoiceStatus = "failed"
+	InvoiceStatusExpired InvoiceStatus = "expired"
+)
+
+// ErrInvalidSignature is returned when a webhook callback cannot be authenticated
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// InvoiceRequest describes what the customer is asked to pay
+type InvoiceRequest struct {
+	OrderID       string
+	Amount        float64
+	ItemName      string
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+}
+
+// Invoice is what the provider returns after creating a payment
+type Invoice struct {
+	ExternalID string
+	PaymentURL string
+}
+
+// WebhookEvent is a verified, normalized payment notification
+type WebhookEvent struct {
+	OrderID       string
+	TransactionID string
+	Status        InvoiceStatus
+	Amount        float64
+	PaymentMethod string
+}
+
+// Provider is implemented by every payment gateway
+type Provider interface {
+	Name() string
+	CreateInvoice(req InvoiceRequest) (*Invoice, error)
+	// ParseWebhook authenticates a callback and returns the normalized event
+	ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// NewProviderFromEnv returns the provider selected by PAYMENT_PROVIDER, or nil when the
+// gateway is disabled and only manual payment proof upload is available. The fake provider
+// is never picked implicitly and stays disabled when its secret is missing.
+func NewProviderFromEnv() Provider {
+	switch strings.ToLower(os.Getenv("PAYMENT_PROVIDER")) {
+	case "midtrans":
+		return NewMidtransProvider(os.Getenv("MIDTRANS_SERVER_KEY"), os.Getenv("MIDTRANS_ENV") == "production")
+	case "fake":
+		provider, err := NewFakeProvider(os.Getenv("FAKE_PAYMENT_SECRET"))
+		if err != nil {
+			log.Println("Payment gateway disabled:", err)
+			return nil
+		}
+		return provider
+	}
+	return nil
+}